@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/config"
+	"github.com/adf-code/beta-payment-api/internal/apikey"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	pkgDatabase "github.com/adf-code/beta-payment-api/internal/pkg/database"
+	pkgLogger "github.com/adf-code/beta-payment-api/internal/pkg/logger"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/google/uuid"
+)
+
+const apiKeyUsage = `Usage:
+  go run cmd/api_key.go issue -merchant <uuid> -name <name> [-type BEARER|HMAC] [-scopes admin,payments:read | -operator] [-expires 720h]
+  go run cmd/api_key.go revoke -merchant <uuid> <api key uuid>
+  go run cmd/api_key.go list -merchant <uuid>`
+
+// api_key manages API keys without an existing admin key, for example to issue the first
+// one. Like the admin endpoints it works on the keys of one merchant at a time. It is also
+// the only way to issue an operator key, which changes platform data such as fx rates and
+// fee rules; issue those to the platform's own merchant. The plaintext of an issued key is
+// printed once and cannot be recovered later.
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(apiKeyUsage)
+	}
+	cfg := config.LoadConfig()
+	pkgLogger.InitLogger(cfg.Env)
+	logger := pkgLogger.Log
+	postgresClient := pkgDatabase.NewPostgresClient(cfg, logger)
+	db := postgresClient.InitPostgresDB()
+	defer db.Close()
+
+	// HMAC secrets are derived from the server secret, so issuing them needs the same one
+	var signatures *apikey.SignatureVerifier
+	if cfg.HMACSigningSecret != "" {
+		signatures = apikey.NewSignatureVerifier([]byte(cfg.HMACSigningSecret), 0)
+	}
+	apiKeyUC := usecase.NewAPIKeyUseCase(repository.NewAPIKeyRepo(db), apikey.NewCache(0), signatures, logger)
+
+	switch os.Args[1] {
+	case "issue":
+		flags := flag.NewFlagSet("issue", flag.ExitOnError)
+		merchant := flags.String("merchant", "", "merchant UUID the key authenticates as")
+		name := flags.String("name", "", "name of the key owner")
+		keyType := flags.String("type", "BEARER", "BEARER or HMAC")
+		scopes := flags.String("scopes", "", "comma separated scopes")
+		operator := flags.Bool("operator", false, "issue a platform operator key, which holds no other scope")
+		expires := flags.Duration("expires", 0, "lifetime of the key, none when zero")
+		_ = flags.Parse(os.Args[2:])
+
+		ctx := merchantContext(*merchant)
+		req := request.IssueAPIKeyRequest{Name: *name, Type: *keyType}
+		for _, scope := range strings.Split(*scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				req.Scopes = append(req.Scopes, scope)
+			}
+		}
+		if *expires > 0 {
+			expiresAt := time.Now().Add(*expires)
+			req.ExpiresAt = &expiresAt
+		}
+		if err := req.Validate(); err != nil {
+			log.Fatalf("Invalid api key: %v", err)
+		}
+		if *operator {
+			if len(req.Scopes) > 0 {
+				log.Fatal("Operator keys hold no other scope, drop -scopes")
+			}
+			req.Scopes = []string{entity.ScopeOperator}
+		}
+		issued, err := apiKeyUC.Issue(ctx, &req)
+		if err != nil {
+			log.Fatalf("Failed to issue api key: %v", err)
+		}
+		fmt.Printf("Issued %s api key %s for merchant %s with scopes [%s]\n", issued.Type, issued.ID, issued.MerchantID, strings.Join(issued.Scopes, ","))
+		if issued.Type == entity.APIKeyTypeHMAC {
+			fmt.Printf("keyId: %s\n", issued.Prefix)
+			fmt.Println("Store this signing secret now, it is not shown again:")
+		} else {
+			fmt.Println("Store this key now, it is not shown again:")
+		}
+		fmt.Println(issued.Key)
+	case "revoke":
+		flags := flag.NewFlagSet("revoke", flag.ExitOnError)
+		merchant := flags.String("merchant", "", "merchant UUID the key belongs to")
+		_ = flags.Parse(os.Args[2:])
+		if flags.NArg() < 1 {
+			log.Fatal(apiKeyUsage)
+		}
+
+		ctx := merchantContext(*merchant)
+		id, err := uuid.Parse(flags.Arg(0))
+		if err != nil {
+			log.Fatalf("Invalid api key id: %v", err)
+		}
+		key, err := apiKeyUC.Revoke(ctx, id)
+		if err != nil {
+			log.Fatalf("Failed to revoke api key: %v", err)
+		}
+		fmt.Printf("Revoked api key %s (%s)\n", key.ID, key.Prefix)
+	case "list":
+		flags := flag.NewFlagSet("list", flag.ExitOnError)
+		merchant := flags.String("merchant", "", "merchant UUID whose keys to list")
+		_ = flags.Parse(os.Args[2:])
+
+		ctx := merchantContext(*merchant)
+		keys, err := apiKeyUC.GetAll(ctx, request.APIKeyListQueryParams{})
+		if err != nil {
+			log.Fatalf("Failed to list api keys: %v", err)
+		}
+		for _, k := range keys {
+			state := "active"
+			if !k.IsActive(time.Now()) {
+				state = "inactive"
+			}
+			fmt.Printf("%s  %s  %-6s  %s  %-8s  %s  [%s]\n", k.ID, k.Prefix, k.Type, k.MerchantID, state, k.Name, strings.Join(k.Scopes, ","))
+		}
+	default:
+		log.Fatal(apiKeyUsage)
+	}
+}
+
+// merchantContext scopes the command to the merchant given with -merchant.
+func merchantContext(merchant string) context.Context {
+	merchantID, err := uuid.Parse(merchant)
+	if err != nil {
+		log.Fatalf("Invalid -merchant: %v", err)
+	}
+	return tenant.WithMerchantID(context.Background(), merchantID)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/config"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	pkgDatabase "github.com/adf-code/beta-payment-api/internal/pkg/database"
+	pkgLogger "github.com/adf-code/beta-payment-api/internal/pkg/logger"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+const importBatchSize = 1000
+
+// fx_import loads exchange rates from a CSV file with the header
+// base_currency,quote_currency,rate,effective_at[,source]
+// where effective_at is RFC 3339. Importing the same file twice is a no-op.
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("Usage: go run cmd/fx_import.go <rates.csv>")
+	}
+
+	file, err := os.Open(os.Args[1])
+	if err != nil {
+		log.Fatalf("Failed to open CSV: %v", err)
+	}
+	defer file.Close()
+
+	items, err := readRates(file)
+	if err != nil {
+		log.Fatalf("Failed to read CSV: %v", err)
+	}
+
+	// Loads are bounded per request, so larger files are imported in batches
+	var batches []*request.LoadFXRatesRequest
+	for start := 0; start < len(items); start += importBatchSize {
+		end := min(start+importBatchSize, len(items))
+		batch := &request.LoadFXRatesRequest{Rates: items[start:end]}
+		if err := batch.Validate(); err != nil {
+			log.Fatalf("Invalid rates in rows %d-%d: %v", start+1, end, err)
+		}
+		batches = append(batches, batch)
+	}
+	if len(batches) == 0 {
+		log.Fatal("No rates found in CSV")
+	}
+
+	cfg := config.LoadConfig()
+	pkgLogger.InitLogger(cfg.Env)
+	logger := pkgLogger.Log
+	postgresClient := pkgDatabase.NewPostgresClient(cfg, logger)
+	db := postgresClient.InitPostgresDB()
+	defer db.Close()
+
+	fxRateUC := usecase.NewFXRateUseCase(repository.NewFXRateRepo(db), logger)
+	imported := 0
+	for _, batch := range batches {
+		rates, err := fxRateUC.Load(context.Background(), batch)
+		if err != nil {
+			log.Fatalf("Failed to import rates after %d rows: %v", imported, err)
+		}
+		imported += len(rates)
+	}
+	log.Printf("Imported %d fx rates", imported)
+}
+
+func readRates(r io.Reader) ([]request.FXRateItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"base_currency", "quote_currency", "rate", "effective_at"} {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.New("missing column " + name)
+		}
+	}
+
+	var items []request.FXRateItem
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		rate, err := valueobject.ParseDecimal(field("rate"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		effectiveAt, err := time.Parse(time.RFC3339, field("effective_at"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid effective_at: %w", line, err)
+		}
+		items = append(items, request.FXRateItem{
+			BaseCurrency:  field("base_currency"),
+			QuoteCurrency: field("quote_currency"),
+			Rate:          rate,
+			EffectiveAt:   effectiveAt,
+			Source:        field("source"),
+		})
+	}
+	return items, nil
+}
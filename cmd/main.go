@@ -16,22 +16,42 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"github.com/adf-code/beta-payment-api/config"
 	_ "github.com/adf-code/beta-payment-api/docs"
+	"github.com/adf-code/beta-payment-api/internal/apikey"
 	deliveryHttp "github.com/adf-code/beta-payment-api/internal/delivery/http"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/fees"
+	"github.com/adf-code/beta-payment-api/internal/fieldcrypt"
+	"github.com/adf-code/beta-payment-api/internal/fx"
+	"github.com/adf-code/beta-payment-api/internal/job"
+	"github.com/adf-code/beta-payment-api/internal/jwt"
+	"github.com/adf-code/beta-payment-api/internal/paymentlink"
 	pkgDatabase "github.com/adf-code/beta-payment-api/internal/pkg/database"
 	pkgLogger "github.com/adf-code/beta-payment-api/internal/pkg/logger"
+	"github.com/adf-code/beta-payment-api/internal/provider"
+	"github.com/adf-code/beta-payment-api/internal/provider/simulator"
+	"github.com/adf-code/beta-payment-api/internal/ratelimit"
 	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/risk"
+	"github.com/adf-code/beta-payment-api/internal/settlement"
 	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/adf-code/beta-payment-api/internal/virtualaccount"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	// Settlement cutoffs are in a configured time zone; the runtime image has no zoneinfo
+	_ "time/tzdata"
 )
 
 func main() {
@@ -44,10 +64,42 @@ func main() {
 	postgresClient := pkgDatabase.NewPostgresClient(cfg, logger)
 	db := postgresClient.InitPostgresDB()
 
+	// Payment providers
+	gateways := initGateways(cfg, logger)
+
 	// Repository and HTTP handler
-	paymentRepo := repository.NewPaymentRepo(db)
-	paymentUC := usecase.NewPaymentUseCase(paymentRepo, db, logger)
-	handler := deliveryHttp.SetupHandler(paymentUC, logger)
+	rowLevelSecurity := cfg.DBRowLevelSecurity == "true"
+	fields := initFieldEncryptor(cfg, logger)
+	paymentRepo := repository.NewPaymentRepo(db, rowLevelSecurity, fields)
+	customerRepo := repository.NewCustomerRepo(db, rowLevelSecurity, fields)
+	fxRateRepo := repository.NewFXRateRepo(db)
+	fxRounding, err := valueobject.ParseRoundingMode(cfg.FXRounding)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid FX_ROUNDING: %v", err)
+	}
+	converter := fx.NewConverter(fxRateRepo, cfg.SettlementCurrency, fxRounding)
+	feeRuleRepo := repository.NewFeeRuleRepo(db)
+	paymentFeeRepo := repository.NewPaymentFeeRepo(db, rowLevelSecurity)
+	feeEngine := initFeeEngine(cfg, feeRuleRepo, logger)
+	paymentSplitRepo := repository.NewPaymentSplitRepo(db, rowLevelSecurity)
+	riskRepo := repository.NewRiskRepo(db, rowLevelSecurity)
+	riskEngine, riskLoader := initRiskEngine(cfg, riskRepo, logger)
+	paymentUC := usecase.NewPaymentUseCase(paymentRepo, customerRepo, paymentFeeRepo, paymentSplitRepo, riskRepo, riskEngine, gateways, converter, feeEngine, strings.ToUpper(cfg.SettlementCurrency), db, logger)
+	customerUC := usecase.NewCustomerUseCase(customerRepo, paymentRepo, db, logger)
+	providerCallbackRepo := repository.NewProviderCallbackRepo(db, rowLevelSecurity)
+	providerCallbackUC := usecase.NewProviderCallbackUseCase(providerCallbackRepo, paymentUC, gateways, logger)
+	fxRateUC := usecase.NewFXRateUseCase(fxRateRepo, logger)
+	feeUC := usecase.NewFeeUseCase(feeRuleRepo, feeEngine, logger)
+	disputeUC := initDisputeUseCase(cfg, db, paymentRepo, rowLevelSecurity, logger)
+	paymentLinkUC := initPaymentLinkUseCase(cfg, db, paymentUC, rowLevelSecurity, logger)
+	qrisUC := usecase.NewQRISUseCase(paymentUC, repository.NewMerchantRepo(db), logger)
+	virtualAccountUC := initVirtualAccountUseCase(cfg, db, paymentUC, rowLevelSecurity, logger)
+	invoiceUC := initInvoiceUseCase(cfg, db, paymentRepo, customerRepo, paymentUC, rowLevelSecurity, logger)
+	settlementUC := initSettlementUseCase(cfg, db, rowLevelSecurity, logger)
+	apiKeyUC := initAPIKeyUseCase(cfg, db, logger)
+	jwtVerifier, jwtLoader := initJWTVerifier(cfg, logger)
+	limiter := initRateLimiter(cfg, db, logger)
+	handler := deliveryHttp.SetupHandler(paymentUC, customerUC, providerCallbackUC, fxRateUC, feeUC, disputeUC, paymentLinkUC, qrisUC, virtualAccountUC, invoiceUC, settlementUC, apiKeyUC, jwtVerifier, limiter, logger)
 
 	// HTTP server config
 	server := &http.Server{
@@ -55,6 +107,15 @@ func main() {
 		Handler: handler,
 	}
 
+	// Background jobs stop with the server
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	startDisputeDeadlineJob(jobCtx, cfg, disputeUC, logger)
+	startVirtualAccountExpiryJob(jobCtx, cfg, virtualAccountUC, logger)
+	startSettlementJob(jobCtx, cfg, settlementUC, logger)
+	startRiskRulesReloadJob(jobCtx, cfg, riskLoader, logger)
+	startJWTKeysReloadJob(jobCtx, cfg, jwtLoader, logger)
+	startRateLimitSweepJob(jobCtx, limiter, logger)
+
 	// Run server in goroutine
 	go func() {
 		logger.Info().Msgf("🟢 Server running on http://localhost:%s", cfg.Port)
@@ -70,6 +131,7 @@ func main() {
 	<-quit
 
 	logger.Info().Msgf("🛑 Gracefully shutting down server...")
+	stopJobs()
 
 	// Graceful shutdown context
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -86,6 +148,391 @@ func main() {
 	logger.Info().Msgf("✅ Server shutdown completed.")
 }
 
+func initGateways(cfg *config.AppConfig, logger zerolog.Logger) *provider.Router {
+	latency, err := time.ParseDuration(cfg.SimulatorLatency)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid SIMULATOR_LATENCY: %v", err)
+	}
+	rules, err := provider.ParseRules(cfg.ProviderRoutes)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid PROVIDER_ROUTES: %v", err)
+	}
+
+	var callbackVerifier provider.SignatureVerifier = provider.HMACVerifier{
+		Header: simulator.SignatureHeader,
+		Secret: []byte(cfg.SimulatorWebhookSecret),
+	}
+	if cfg.SimulatorWebhookPublicKeyFile != "" {
+		publicKey, err := provider.LoadRSAPublicKey(cfg.SimulatorWebhookPublicKeyFile)
+		if err != nil {
+			logger.Fatal().Err(err).Msgf("❌ Invalid SIMULATOR_WEBHOOK_PUBLIC_KEY_FILE: %v", err)
+		}
+		callbackVerifier = provider.RSAVerifier{Header: simulator.SignatureHeader, PublicKey: publicKey}
+	}
+
+	gateways, err := provider.NewRouter(cfg.ProviderDefault, rules,
+		simulator.New(simulator.Config{Latency: latency, CallbackVerifier: callbackVerifier}),
+	)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Failed to configure payment providers: %v", err)
+	}
+	logger.Info().Msgf("💳 Payment providers configured, default: %s, routes: %d", cfg.ProviderDefault, len(rules))
+	return gateways
+}
+
+func initFeeEngine(cfg *config.AppConfig, feeRuleRepo repository.FeeRuleRepository, logger zerolog.Logger) *fees.Engine {
+	rounding, err := valueobject.ParseRoundingMode(cfg.FeeRounding)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid FEE_ROUNDING: %v", err)
+	}
+	ttl, err := time.ParseDuration(cfg.FeeRuleCacheTTL)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid FEE_RULE_CACHE_TTL: %v", err)
+	}
+	return fees.NewEngine(feeRuleRepo, ttl, rounding)
+}
+
+func initDisputeUseCase(cfg *config.AppConfig, db *sql.DB, paymentRepo repository.PaymentRepository, rowLevelSecurity bool, logger zerolog.Logger) usecase.DisputeUseCase {
+	evidenceWindow, err := time.ParseDuration(cfg.DisputeEvidenceWindow)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid DISPUTE_EVIDENCE_WINDOW: %v", err)
+	}
+	return usecase.NewDisputeUseCase(
+		repository.NewDisputeRepo(db, rowLevelSecurity),
+		paymentRepo,
+		repository.NewPaymentReversalRepo(db, rowLevelSecurity),
+		repository.NewMerchantRepo(db),
+		evidenceWindow,
+		db,
+		logger,
+	)
+}
+
+func initPaymentLinkUseCase(cfg *config.AppConfig, db *sql.DB, paymentUC usecase.PaymentUseCase, rowLevelSecurity bool, logger zerolog.Logger) usecase.PaymentLinkUseCase {
+	ttl, err := time.ParseDuration(cfg.PaymentLinkTTL)
+	if err != nil || ttl <= 0 || ttl > request.MaxPaymentLinkLifetime {
+		logger.Fatal().Err(err).Msgf("❌ Invalid PAYMENT_LINK_TTL: %q", cfg.PaymentLinkTTL)
+	}
+	secret := []byte(cfg.PaymentLinkSecret)
+	if len(secret) == 0 {
+		// Links still work, but only until the next restart
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logger.Fatal().Err(err).Msgf("❌ Failed to generate payment link secret: %v", err)
+		}
+		logger.Warn().Msg("⚠️ PAYMENT_LINK_SECRET not set, payment links will not survive a restart")
+	}
+	return usecase.NewPaymentLinkUseCase(
+		repository.NewPaymentLinkRepo(db, rowLevelSecurity),
+		paymentUC,
+		paymentlink.NewSigner(secret),
+		ttl,
+		strings.TrimRight(cfg.PaymentLinkBaseURL, "/"),
+		logger,
+	)
+}
+
+func initVirtualAccountUseCase(cfg *config.AppConfig, db *sql.DB, paymentUC usecase.PaymentUseCase, rowLevelSecurity bool, logger zerolog.Logger) usecase.VirtualAccountUseCase {
+	prefixes, err := virtualaccount.ParsePrefixes(cfg.VirtualAccountBankPrefixes)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid VIRTUAL_ACCOUNT_BANK_PREFIXES: %v", err)
+	}
+	length, err := strconv.Atoi(cfg.VirtualAccountNumberLength)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid VIRTUAL_ACCOUNT_NUMBER_LENGTH: %v", err)
+	}
+	issuer, err := virtualaccount.NewIssuer(prefixes, length)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid virtual account numbering: %v", err)
+	}
+	ttl, err := time.ParseDuration(cfg.VirtualAccountTTL)
+	if err != nil || ttl <= 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid VIRTUAL_ACCOUNT_TTL: %q", cfg.VirtualAccountTTL)
+	}
+	lateGrace, err := time.ParseDuration(cfg.VirtualAccountLateGrace)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid VIRTUAL_ACCOUNT_LATE_PAYMENT_GRACE: %v", err)
+	}
+	policy := virtualaccount.Policy{
+		Overpayment:  strings.ToUpper(cfg.VirtualAccountOverpayment),
+		Underpayment: strings.ToUpper(cfg.VirtualAccountUnderpayment),
+		LateGrace:    lateGrace,
+	}
+	if err := policy.Validate(); err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid virtual account policy: %v", err)
+	}
+	return usecase.NewVirtualAccountUseCase(
+		repository.NewVirtualAccountRepo(db, rowLevelSecurity),
+		repository.NewMerchantRepo(db),
+		paymentUC,
+		issuer,
+		policy,
+		ttl,
+		db,
+		logger,
+	)
+}
+
+func initInvoiceUseCase(cfg *config.AppConfig, db *sql.DB, paymentRepo repository.PaymentRepository, customerRepo repository.CustomerRepository, paymentUC usecase.PaymentUseCase, rowLevelSecurity bool, logger zerolog.Logger) usecase.InvoiceUseCase {
+	taxRate, err := valueobject.ParseDecimal(cfg.InvoiceDefaultTaxRate)
+	if err != nil || taxRate.Sign() < 0 || taxRate.Cmp(valueobject.DecimalFromInt(100)) > 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid INVOICE_DEFAULT_TAX_RATE: %q", cfg.InvoiceDefaultTaxRate)
+	}
+	return usecase.NewInvoiceUseCase(
+		repository.NewInvoiceRepo(db, rowLevelSecurity),
+		paymentRepo,
+		customerRepo,
+		paymentUC,
+		taxRate,
+		db,
+		logger,
+	)
+}
+
+// initAPIKeyUseCase enables HMAC signed requests only when HMAC_SIGNING_SECRET is set.
+func initAPIKeyUseCase(cfg *config.AppConfig, db *sql.DB, logger zerolog.Logger) usecase.APIKeyUseCase {
+	ttl, err := time.ParseDuration(cfg.APIKeyCacheTTL)
+	if err != nil || ttl < 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid API_KEY_CACHE_TTL: %q", cfg.APIKeyCacheTTL)
+	}
+	return usecase.NewAPIKeyUseCase(repository.NewAPIKeyRepo(db), apikey.NewCache(ttl), initSignatureVerifier(cfg, logger), logger)
+}
+
+func initSignatureVerifier(cfg *config.AppConfig, logger zerolog.Logger) *apikey.SignatureVerifier {
+	if cfg.HMACSigningSecret == "" {
+		return nil
+	}
+	if len(cfg.HMACSigningSecret) < 32 {
+		logger.Fatal().Msg("❌ HMAC_SIGNING_SECRET must be at least 32 characters")
+	}
+	maxSkew, err := time.ParseDuration(cfg.HMACMaxSkew)
+	if err != nil || maxSkew <= 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid HMAC_MAX_SKEW: %q", cfg.HMACMaxSkew)
+	}
+	return apikey.NewSignatureVerifier([]byte(cfg.HMACSigningSecret), maxSkew)
+}
+
+// initRateLimiter keeps buckets in memory, or in Postgres when replicas must share them.
+// RATE_LIMIT_STORE=off disables rate limiting.
+func initRateLimiter(cfg *config.AppConfig, db *sql.DB, logger zerolog.Logger) *ratelimit.Limiter {
+	limiter := &ratelimit.Limiter{}
+	switch cfg.RateLimitStore {
+	case "off":
+		logger.Warn().Msg("⚠️ Rate limiting is disabled")
+		return nil
+	case "memory":
+		limiter.Store = ratelimit.NewMemoryStore()
+	case "postgres":
+		limiter.Store = repository.NewRateLimitRepo(db)
+	default:
+		logger.Fatal().Msgf("❌ Invalid RATE_LIMIT_STORE: %q, use memory, postgres or off", cfg.RateLimitStore)
+	}
+
+	var err error
+	if limiter.PerKey, err = ratelimit.ParseLimit(cfg.RateLimitPerKey); err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid RATE_LIMIT_PER_KEY: %v", err)
+	}
+	if limiter.PerIP, err = ratelimit.ParseLimit(cfg.RateLimitPerIP); err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid RATE_LIMIT_PER_IP: %v", err)
+	}
+	if limiter.Classes, err = ratelimit.ParseClassLimits(cfg.RateLimitClasses); err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid RATE_LIMIT_CLASSES: %v", err)
+	}
+	return limiter
+}
+
+// startRateLimitSweepJob drops buckets idle for the longest window; they are full again
+// and cost nothing to recreate.
+func startRateLimitSweepJob(ctx context.Context, limiter *ratelimit.Limiter, logger zerolog.Logger) {
+	if limiter == nil || limiter.MaxWindow() == 0 {
+		return
+	}
+	idle := limiter.MaxWindow()
+	go job.RunPeriodically(ctx, "rate_limit_sweep", max(idle, time.Minute), logger, func(ctx context.Context) error {
+		return limiter.Store.Sweep(ctx, idle)
+	})
+}
+
+// initJWTVerifier sets up JWT bearer authentication from exactly one key source. Without
+// one, only API keys are accepted.
+func initJWTVerifier(cfg *config.AppConfig, logger zerolog.Logger) (*jwt.Verifier, *jwt.FileLoader) {
+	sources := 0
+	for _, v := range []string{cfg.JWTSecret, cfg.JWTPublicKeyFile, cfg.JWTJWKSFile} {
+		if v != "" {
+			sources++
+		}
+	}
+	if sources == 0 {
+		return nil, nil
+	}
+	if sources > 1 {
+		logger.Fatal().Msg("❌ Set only one of JWT_SECRET, JWT_PUBLIC_KEY_FILE and JWT_JWKS_FILE")
+	}
+	if cfg.JWTIssuer == "" || cfg.JWTAudience == "" {
+		logger.Fatal().Msg("❌ JWT_ISSUER and JWT_AUDIENCE are required for JWT authentication")
+	}
+	skew, err := time.ParseDuration(cfg.JWTClockSkew)
+	if err != nil || skew < 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid JWT_CLOCK_SKEW: %q", cfg.JWTClockSkew)
+	}
+
+	if cfg.JWTSecret != "" {
+		keys, err := jwt.SecretKeySet([]byte(cfg.JWTSecret))
+		if err != nil {
+			logger.Fatal().Err(err).Msgf("❌ Invalid JWT_SECRET: %v", err)
+		}
+		return jwt.NewVerifier(keys, cfg.JWTIssuer, cfg.JWTAudience, skew), nil
+	}
+
+	verifier := jwt.NewVerifier(nil, cfg.JWTIssuer, cfg.JWTAudience, skew)
+	loader, file := jwt.NewJWKSFileLoader(cfg.JWTJWKSFile, verifier), cfg.JWTJWKSFile
+	if cfg.JWTPublicKeyFile != "" {
+		loader, file = jwt.NewPEMFileLoader(cfg.JWTPublicKeyFile, verifier), cfg.JWTPublicKeyFile
+	}
+	if _, err := loader.Reload(); err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid JWT key file %s: %v", file, err)
+	}
+	logger.Info().Str("file", file).Msg("✅ JWT keys loaded")
+	return verifier, loader
+}
+
+func startJWTKeysReloadJob(ctx context.Context, cfg *config.AppConfig, loader *jwt.FileLoader, logger zerolog.Logger) {
+	if loader == nil {
+		return
+	}
+	interval, err := time.ParseDuration(cfg.JWTKeysReloadInterval)
+	if err != nil || interval <= 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid JWT_KEYS_RELOAD_INTERVAL: %q", cfg.JWTKeysReloadInterval)
+	}
+	// A broken key file keeps the previous keys in force until the file is fixed
+	go job.RunPeriodically(ctx, "jwt_keys_reload", interval, logger, func(ctx context.Context) error {
+		reloaded, err := loader.Reload()
+		if reloaded {
+			logger.Info().Msg("✅ JWT keys reloaded")
+		}
+		return err
+	})
+}
+
+// initRiskEngine loads the risk rules file, if one is configured. Without one every
+// payment is allowed.
+// initFieldEncryptor encrypts payment descriptions and customer contact details at rest
+// when master keys are configured; without keys they are stored in plaintext.
+func initFieldEncryptor(cfg *config.AppConfig, logger zerolog.Logger) *fieldcrypt.Encryptor {
+	fields, err := fieldcrypt.Load(cfg.FieldEncryptionKeys, cfg.FieldEncryptionKeysFile, cfg.FieldEncryptionIndexKey, cfg.FieldEncryptionActiveKey)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid field encryption keys: %v", err)
+	}
+	if fields == nil {
+		logger.Warn().Msg("⚠️ FIELD_ENCRYPTION_KEYS not set, payment and customer PII is stored in plaintext")
+		return nil
+	}
+	logger.Info().Uint32("key_version", fields.ActiveVersion()).Msg("✅ Field encryption enabled")
+	return fields
+}
+
+func initRiskEngine(cfg *config.AppConfig, riskRepo repository.RiskRepository, logger zerolog.Logger) (*risk.Engine, *risk.FileLoader) {
+	engine := risk.NewEngine(riskRepo)
+	if cfg.RiskRulesFile == "" {
+		logger.Warn().Msg("⚠️ RISK_RULES_FILE not set, payments are created without risk checks")
+		return engine, nil
+	}
+	loader := risk.NewFileLoader(cfg.RiskRulesFile, engine)
+	if _, err := loader.Reload(); err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid RISK_RULES_FILE: %v", err)
+	}
+	logger.Info().Str("file", cfg.RiskRulesFile).Msg("✅ Risk rules loaded")
+	return engine, loader
+}
+
+func startRiskRulesReloadJob(ctx context.Context, cfg *config.AppConfig, loader *risk.FileLoader, logger zerolog.Logger) {
+	if loader == nil {
+		return
+	}
+	interval, err := time.ParseDuration(cfg.RiskRulesReloadInterval)
+	if err != nil || interval <= 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid RISK_RULES_RELOAD_INTERVAL: %q", cfg.RiskRulesReloadInterval)
+	}
+	// A broken edit keeps the previous rules in force until the file is fixed
+	go job.RunPeriodically(ctx, "risk_rules_reload", interval, logger, func(ctx context.Context) error {
+		reloaded, err := loader.Reload()
+		if reloaded {
+			logger.Info().Str("file", cfg.RiskRulesFile).Msg("✅ Risk rules reloaded")
+		}
+		return err
+	})
+}
+
+func initSettlementUseCase(cfg *config.AppConfig, db *sql.DB, rowLevelSecurity bool, logger zerolog.Logger) usecase.SettlementUseCase {
+	schedule, err := settlement.ParseSchedule(cfg.SettlementCutoffTime, cfg.SettlementTimezone)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid settlement schedule: %v", err)
+	}
+	header, err := strconv.ParseBool(cfg.SettlementCSVHeader)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid SETTLEMENT_CSV_HEADER: %v", err)
+	}
+	csvFormat, err := settlement.ParseCSVFormat(cfg.SettlementCSVColumns, cfg.SettlementCSVDelimiter, header)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid settlement CSV format: %v", err)
+	}
+	debtor := settlement.Debtor{
+		Name:     cfg.SettlementDebtorName,
+		Account:  cfg.SettlementDebtorAccount,
+		BankCode: cfg.SettlementDebtorBankCode,
+	}
+	if debtor.Account == "" || debtor.BankCode == "" {
+		logger.Warn().Msg("⚠️ SETTLEMENT_DEBTOR_ACCOUNT or SETTLEMENT_DEBTOR_BANK_CODE not set, pain.001 payout files will be rejected by banks")
+	}
+	return usecase.NewSettlementUseCase(
+		repository.NewSettlementRepo(db, rowLevelSecurity),
+		repository.NewMerchantRepo(db),
+		schedule,
+		debtor,
+		csvFormat,
+		db,
+		logger,
+	)
+}
+
+func startSettlementJob(ctx context.Context, cfg *config.AppConfig, settlementUC usecase.SettlementUseCase, logger zerolog.Logger) {
+	interval, err := time.ParseDuration(cfg.SettlementCheckInterval)
+	if err != nil || interval <= 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid SETTLEMENT_CHECK_INTERVAL: %q", cfg.SettlementCheckInterval)
+	}
+	// Each run batches up to the last cutoff; runs after the first in a day find nothing new
+	go job.RunPeriodically(ctx, "settlement", interval, logger, func(ctx context.Context) error {
+		_, err := settlementUC.GenerateAll(ctx)
+		return err
+	})
+}
+
+func startVirtualAccountExpiryJob(ctx context.Context, cfg *config.AppConfig, virtualAccountUC usecase.VirtualAccountUseCase, logger zerolog.Logger) {
+	interval, err := time.ParseDuration(cfg.VirtualAccountExpiryInterval)
+	if err != nil || interval <= 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid VIRTUAL_ACCOUNT_EXPIRY_CHECK_INTERVAL: %q", cfg.VirtualAccountExpiryInterval)
+	}
+	go job.RunPeriodically(ctx, "virtual_account_expiry", interval, logger, func(ctx context.Context) error {
+		_, err := virtualAccountUC.ExpireOverdue(ctx)
+		return err
+	})
+}
+
+func startDisputeDeadlineJob(ctx context.Context, cfg *config.AppConfig, disputeUC usecase.DisputeUseCase, logger zerolog.Logger) {
+	warning, err := time.ParseDuration(cfg.DisputeDeadlineWarning)
+	if err != nil {
+		logger.Fatal().Err(err).Msgf("❌ Invalid DISPUTE_DEADLINE_WARNING: %v", err)
+	}
+	interval, err := time.ParseDuration(cfg.DisputeDeadlineCheckInterval)
+	if err != nil || interval <= 0 {
+		logger.Fatal().Err(err).Msgf("❌ Invalid DISPUTE_DEADLINE_CHECK_INTERVAL: %q", cfg.DisputeDeadlineCheckInterval)
+	}
+	go job.RunPeriodically(ctx, "dispute_deadlines", interval, logger, func(ctx context.Context) error {
+		_, err := disputeUC.FlagApproachingDeadlines(ctx, warning)
+		return err
+	})
+}
+
 func closePostgres(db *sql.DB, logger zerolog.Logger) {
 	if err := db.Close(); err != nil {
 		logger.Info().Msgf("⚠️ Failed to close PostgreSQL connection: %v", err)
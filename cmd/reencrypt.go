@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/adf-code/beta-payment-api/config"
+	"github.com/adf-code/beta-payment-api/internal/fieldcrypt"
+	pkgDatabase "github.com/adf-code/beta-payment-api/internal/pkg/database"
+	pkgLogger "github.com/adf-code/beta-payment-api/internal/pkg/logger"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+// reencrypt moves encrypted payment and customer fields to the active key version and
+// encrypts rows stored before encryption was enabled. Rows already on the active key are
+// skipped, so an interrupted run can simply be started again. Once it completes, retired
+// key versions can be removed from FIELD_ENCRYPTION_KEYS.
+//
+// Usage: go run cmd/reencrypt.go [-batch 500]
+func main() {
+	batch := flag.Int("batch", 500, "rows rewritten per transaction")
+	flag.Parse()
+	if *batch <= 0 {
+		log.Fatal("Invalid -batch: must be positive")
+	}
+
+	cfg := config.LoadConfig()
+	pkgLogger.InitLogger(cfg.Env)
+	logger := pkgLogger.Log
+	fields, err := fieldcrypt.Load(cfg.FieldEncryptionKeys, cfg.FieldEncryptionKeysFile, cfg.FieldEncryptionIndexKey, cfg.FieldEncryptionActiveKey)
+	if err != nil {
+		log.Fatalf("Invalid field encryption keys: %v", err)
+	}
+	if fields == nil {
+		log.Fatal("FIELD_ENCRYPTION_KEYS is not set, nothing to encrypt with")
+	}
+
+	postgresClient := pkgDatabase.NewPostgresClient(cfg, logger)
+	db := postgresClient.InitPostgresDB()
+	defer db.Close()
+
+	rowLevelSecurity := cfg.DBRowLevelSecurity == "true"
+	paymentRepo := repository.NewPaymentRepo(db, rowLevelSecurity, fields)
+	customerRepo := repository.NewCustomerRepo(db, rowLevelSecurity, fields)
+
+	// Rows are tenant scoped, so the run goes merchant by merchant
+	ctx := context.Background()
+	merchantIDs, err := repository.NewMerchantRepo(db).FetchIDs(ctx)
+	if err != nil {
+		log.Fatalf("Failed to list merchants: %v", err)
+	}
+
+	var payments, customers int
+	for _, merchantID := range merchantIDs {
+		merchantCtx := tenant.WithMerchantID(ctx, merchantID)
+		n, err := reencryptAll(merchantCtx, paymentRepo.ReEncrypt, *batch)
+		if err != nil {
+			log.Fatalf("Failed to re-encrypt payments of merchant %s: %v", merchantID, err)
+		}
+		payments += n
+		n, err = reencryptAll(merchantCtx, customerRepo.ReEncrypt, *batch)
+		if err != nil {
+			log.Fatalf("Failed to re-encrypt customers of merchant %s: %v", merchantID, err)
+		}
+		customers += n
+	}
+	fmt.Printf("Re-encrypted %d payments and %d customers of %d merchants with key version %d\n", payments, customers, len(merchantIDs), fields.ActiveVersion())
+}
+
+// reencryptAll calls reencrypt batch by batch until every row has been looked at.
+func reencryptAll(ctx context.Context, reencrypt func(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error), batch int) (int, error) {
+	total := 0
+	after := uuid.Nil
+	for {
+		last, updated, err := reencrypt(ctx, after, batch)
+		if err != nil {
+			return total, err
+		}
+		total += updated
+		if last == uuid.Nil {
+			return total, nil
+		}
+		after = last
+	}
+}
@@ -7,17 +7,70 @@ import (
 )
 
 type AppConfig struct {
-	Port              string
-	DBHost            string
-	DBPort            string
-	DBUser            string
-	DBPassword        string
-	DBName            string
-	DBSSLMode         string
-	Env               string
-	TelemetryEnabled  string
-	TelemetryAPIKey   string
-	TelemetryEndpoint string
+	Port                          string
+	DBHost                        string
+	DBPort                        string
+	DBUser                        string
+	DBPassword                    string
+	DBName                        string
+	DBSSLMode                     string
+	Env                           string
+	TelemetryEnabled              string
+	TelemetryAPIKey               string
+	TelemetryEndpoint             string
+	ProviderDefault               string
+	ProviderRoutes                string
+	SimulatorLatency              string
+	SimulatorWebhookSecret        string
+	SimulatorWebhookPublicKeyFile string
+	APIKeyCacheTTL                string
+	DBRowLevelSecurity            string
+	SettlementCurrency            string
+	FXRounding                    string
+	FeeRounding                   string
+	FeeRuleCacheTTL               string
+	DisputeEvidenceWindow         string
+	DisputeDeadlineWarning        string
+	DisputeDeadlineCheckInterval  string
+	PaymentLinkSecret             string
+	PaymentLinkTTL                string
+	PaymentLinkBaseURL            string
+	VirtualAccountBankPrefixes    string
+	VirtualAccountNumberLength    string
+	VirtualAccountTTL             string
+	VirtualAccountOverpayment     string
+	VirtualAccountUnderpayment    string
+	VirtualAccountLateGrace       string
+	VirtualAccountExpiryInterval  string
+	InvoiceDefaultTaxRate         string
+	SettlementCutoffTime          string
+	SettlementTimezone            string
+	SettlementCheckInterval       string
+	SettlementDebtorName          string
+	SettlementDebtorAccount       string
+	SettlementDebtorBankCode      string
+	SettlementCSVColumns          string
+	SettlementCSVDelimiter        string
+	SettlementCSVHeader           string
+	RiskRulesFile                 string
+	RiskRulesReloadInterval       string
+	JWTSecret                     string
+	JWTPublicKeyFile              string
+	JWTJWKSFile                   string
+	JWTIssuer                     string
+	JWTAudience                   string
+	JWTClockSkew                  string
+	JWTKeysReloadInterval         string
+	HMACSigningSecret             string
+	HMACMaxSkew                   string
+	RateLimitStore                string
+	RateLimitPerKey               string
+	RateLimitPerIP                string
+	RateLimitClasses              string
+	FieldEncryptionKeys           string
+	FieldEncryptionKeysFile       string
+	FieldEncryptionIndexKey       string
+	FieldEncryptionActiveKey      string
 }
 
 func LoadConfig() *AppConfig {
@@ -27,17 +80,70 @@ func LoadConfig() *AppConfig {
 	}
 
 	return &AppConfig{
-		Env:               getEnv("ENV", "development"),
-		Port:              getEnv("APP_PORT", "8080"),
-		DBHost:            getEnv("DB_HOST", "localhost"),
-		DBPort:            getEnv("DB_PORT", "5432"),
-		DBUser:            getEnv("DB_USER", "postgres"),
-		DBPassword:        getEnv("DB_PASSWORD", ""),
-		DBName:            getEnv("DB_NAME", "paymentdb"),
-		DBSSLMode:         getEnv("DB_SSLMODE", "disable"),
-		TelemetryEnabled:  getEnv("TELEMETRY_ENABLED", "false"),
-		TelemetryAPIKey:   getEnv("TELEMETRY_API_KEY", "not_set"),
-		TelemetryEndpoint: getEnv("TELEMETRY_ENDPOINT", "not_set"),
+		Env:                           getEnv("ENV", "development"),
+		Port:                          getEnv("APP_PORT", "8080"),
+		DBHost:                        getEnv("DB_HOST", "localhost"),
+		DBPort:                        getEnv("DB_PORT", "5432"),
+		DBUser:                        getEnv("DB_USER", "postgres"),
+		DBPassword:                    getEnv("DB_PASSWORD", ""),
+		DBName:                        getEnv("DB_NAME", "paymentdb"),
+		DBSSLMode:                     getEnv("DB_SSLMODE", "disable"),
+		TelemetryEnabled:              getEnv("TELEMETRY_ENABLED", "false"),
+		TelemetryAPIKey:               getEnv("TELEMETRY_API_KEY", "not_set"),
+		TelemetryEndpoint:             getEnv("TELEMETRY_ENDPOINT", "not_set"),
+		ProviderDefault:               getEnv("PROVIDER_DEFAULT", "simulator"),
+		ProviderRoutes:                getEnv("PROVIDER_ROUTES", ""),
+		SimulatorLatency:              getEnv("SIMULATOR_LATENCY", "0s"),
+		SimulatorWebhookSecret:        getEnv("SIMULATOR_WEBHOOK_SECRET", ""),
+		SimulatorWebhookPublicKeyFile: getEnv("SIMULATOR_WEBHOOK_PUBLIC_KEY_FILE", ""),
+		APIKeyCacheTTL:                getEnv("API_KEY_CACHE_TTL", "1m"),
+		DBRowLevelSecurity:            getEnv("DB_ROW_LEVEL_SECURITY", "true"),
+		SettlementCurrency:            getEnv("SETTLEMENT_CURRENCY", "IDR"),
+		FXRounding:                    getEnv("FX_ROUNDING", "HALF_EVEN"),
+		FeeRounding:                   getEnv("FEE_ROUNDING", "HALF_UP"),
+		FeeRuleCacheTTL:               getEnv("FEE_RULE_CACHE_TTL", "60s"),
+		DisputeEvidenceWindow:         getEnv("DISPUTE_EVIDENCE_WINDOW", "168h"),
+		DisputeDeadlineWarning:        getEnv("DISPUTE_DEADLINE_WARNING", "48h"),
+		DisputeDeadlineCheckInterval:  getEnv("DISPUTE_DEADLINE_CHECK_INTERVAL", "15m"),
+		PaymentLinkSecret:             getEnv("PAYMENT_LINK_SECRET", ""),
+		PaymentLinkTTL:                getEnv("PAYMENT_LINK_TTL", "24h"),
+		PaymentLinkBaseURL:            getEnv("PAYMENT_LINK_BASE_URL", "http://localhost:8080"),
+		VirtualAccountBankPrefixes:    getEnv("VIRTUAL_ACCOUNT_BANK_PREFIXES", "BCA:39358,BNI:8808,BRI:26215,MANDIRI:89608"),
+		VirtualAccountNumberLength:    getEnv("VIRTUAL_ACCOUNT_NUMBER_LENGTH", "16"),
+		VirtualAccountTTL:             getEnv("VIRTUAL_ACCOUNT_TTL", "24h"),
+		VirtualAccountOverpayment:     getEnv("VIRTUAL_ACCOUNT_OVERPAYMENT_POLICY", "ACCEPT"),
+		VirtualAccountUnderpayment:    getEnv("VIRTUAL_ACCOUNT_UNDERPAYMENT_POLICY", "REJECT"),
+		VirtualAccountLateGrace:       getEnv("VIRTUAL_ACCOUNT_LATE_PAYMENT_GRACE", "0s"),
+		VirtualAccountExpiryInterval:  getEnv("VIRTUAL_ACCOUNT_EXPIRY_CHECK_INTERVAL", "1m"),
+		InvoiceDefaultTaxRate:         getEnv("INVOICE_DEFAULT_TAX_RATE", "11"),
+		SettlementCutoffTime:          getEnv("SETTLEMENT_CUTOFF_TIME", "00:00"),
+		SettlementTimezone:            getEnv("SETTLEMENT_TIMEZONE", "Asia/Jakarta"),
+		SettlementCheckInterval:       getEnv("SETTLEMENT_CHECK_INTERVAL", "15m"),
+		SettlementDebtorName:          getEnv("SETTLEMENT_DEBTOR_NAME", "Beta Payment"),
+		SettlementDebtorAccount:       getEnv("SETTLEMENT_DEBTOR_ACCOUNT", ""),
+		SettlementDebtorBankCode:      getEnv("SETTLEMENT_DEBTOR_BANK_CODE", ""),
+		SettlementCSVColumns:          getEnv("SETTLEMENT_CSV_COLUMNS", "reference,beneficiary_name,account_number,bank_code,currency,amount,execution_date"),
+		SettlementCSVDelimiter:        getEnv("SETTLEMENT_CSV_DELIMITER", ","),
+		SettlementCSVHeader:           getEnv("SETTLEMENT_CSV_HEADER", "true"),
+		RiskRulesFile:                 getEnv("RISK_RULES_FILE", ""),
+		RiskRulesReloadInterval:       getEnv("RISK_RULES_RELOAD_INTERVAL", "30s"),
+		JWTSecret:                     getEnv("JWT_SECRET", ""),
+		JWTPublicKeyFile:              getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTJWKSFile:                   getEnv("JWT_JWKS_FILE", ""),
+		JWTIssuer:                     getEnv("JWT_ISSUER", ""),
+		JWTAudience:                   getEnv("JWT_AUDIENCE", "beta-payment-api"),
+		JWTClockSkew:                  getEnv("JWT_CLOCK_SKEW", "60s"),
+		JWTKeysReloadInterval:         getEnv("JWT_KEYS_RELOAD_INTERVAL", "5m"),
+		HMACSigningSecret:             getEnv("HMAC_SIGNING_SECRET", ""),
+		HMACMaxSkew:                   getEnv("HMAC_MAX_SKEW", "5m"),
+		RateLimitStore:                getEnv("RATE_LIMIT_STORE", "memory"),
+		RateLimitPerKey:               getEnv("RATE_LIMIT_PER_KEY", "600/1m"),
+		RateLimitPerIP:                getEnv("RATE_LIMIT_PER_IP", "1200/1m"),
+		RateLimitClasses:              getEnv("RATE_LIMIT_CLASSES", "write=300/1m,delete=60/1m,admin=60/1m"),
+		FieldEncryptionKeys:           getEnv("FIELD_ENCRYPTION_KEYS", ""),
+		FieldEncryptionKeysFile:       getEnv("FIELD_ENCRYPTION_KEYS_FILE", ""),
+		FieldEncryptionIndexKey:       getEnv("FIELD_ENCRYPTION_INDEX_KEY", ""),
+		FieldEncryptionActiveKey:      getEnv("FIELD_ENCRYPTION_ACTIVE_KEY", ""),
 	}
 }
 
@@ -0,0 +1,65 @@
+package apikey
+
+import (
+	"sync"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+)
+
+// maxCachedKeys bounds the cache; when it is full, expired entries are dropped first and
+// the whole cache after that.
+const maxCachedKeys = 10000
+
+// Cache keeps recently validated keys by prefix so most requests skip the database. A key
+// revoked by another replica keeps working here for at most ttl.
+type Cache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	key      entity.APIKey
+	cachedAt time.Time
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+func (c *Cache) Get(prefix string, now time.Time) (entity.APIKey, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[prefix]
+	if !ok || now.Sub(e.cachedAt) >= c.ttl {
+		return entity.APIKey{}, false
+	}
+	return e.key, true
+}
+
+func (c *Cache) Put(key entity.APIKey, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedKeys {
+		for prefix, e := range c.entries {
+			if now.Sub(e.cachedAt) >= c.ttl {
+				delete(c.entries, prefix)
+			}
+		}
+		if len(c.entries) >= maxCachedKeys {
+			clear(c.entries)
+		}
+	}
+	c.entries[key.Prefix] = cacheEntry{key: key, cachedAt: now}
+}
+
+func (c *Cache) Remove(prefix string) {
+	c.mu.Lock()
+	delete(c.entries, prefix)
+	c.mu.Unlock()
+}
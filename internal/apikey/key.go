@@ -0,0 +1,72 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+)
+
+// A key reads "bpk_<prefix>_<secret>". The prefix is stored in the clear to find the key
+// row, the secret only as SHA-256 over a per-key salt followed by the secret. Secrets are
+// 256 random bits, so a fast hash is enough; nothing is gained from a password KDF.
+const (
+	keyMarker    = "bpk"
+	prefixBytes  = 6
+	secretBytes  = 32
+	saltBytes    = 16
+	prefixLength = prefixBytes * 2
+)
+
+// Generated is a new key: the plaintext for the caller and what the database stores.
+type Generated struct {
+	Key    string
+	Prefix string
+	Salt   []byte
+	Hash   []byte
+}
+
+func Generate() (Generated, error) {
+	buf := make([]byte, prefixBytes+secretBytes+saltBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return Generated{}, err
+	}
+	prefix := hex.EncodeToString(buf[:prefixBytes])
+	secret := base64.RawURLEncoding.EncodeToString(buf[prefixBytes : prefixBytes+secretBytes])
+	salt := buf[prefixBytes+secretBytes:]
+	return Generated{
+		Key:    keyMarker + "_" + prefix + "_" + secret,
+		Prefix: prefix,
+		Salt:   salt,
+		Hash:   hash(salt, secret),
+	}, nil
+}
+
+// Parse splits a presented key into its lookup prefix and secret.
+func Parse(key string) (prefix, secret string, err error) {
+	marker, rest, ok := strings.Cut(key, "_")
+	if !ok || marker != keyMarker {
+		return "", "", entity.ErrInvalidAPIKey
+	}
+	prefix, secret, ok = strings.Cut(rest, "_")
+	if !ok || len(prefix) != prefixLength || secret == "" {
+		return "", "", entity.ErrInvalidAPIKey
+	}
+	return prefix, secret, nil
+}
+
+// Matches compares secret with a stored hash in constant time.
+func Matches(secret string, salt, stored []byte) bool {
+	return subtle.ConstantTimeCompare(hash(salt, secret), stored) == 1
+}
+
+func hash(salt []byte, secret string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(secret))
+	return h.Sum(nil)
+}
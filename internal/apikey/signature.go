@@ -0,0 +1,82 @@
+package apikey
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/pkg/hmacsign"
+)
+
+// maxNonces bounds the replay cache. Only correctly signed requests add nonces, so a full
+// cache means a partner is far over any sane request rate and further requests are refused.
+const maxNonces = 100000
+
+// SignatureVerifier checks HMAC-SHA256 signed requests. The signing secret of a key is
+// derived from a server secret and the key prefix, so the database holds nothing that
+// could sign requests. Seen nonces are kept per process: with several replicas a request
+// can be replayed against another replica within the allowed skew.
+type SignatureVerifier struct {
+	master  []byte
+	maxSkew time.Duration
+
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func NewSignatureVerifier(master []byte, maxSkew time.Duration) *SignatureVerifier {
+	return &SignatureVerifier{master: master, maxSkew: maxSkew, nonces: make(map[string]time.Time)}
+}
+
+// Secret is the signing secret handed out for the key with prefix.
+func (v *SignatureVerifier) Secret(prefix string) string {
+	mac := hmac.New(sha256.New, v.master)
+	mac.Write([]byte("hmac-key:" + prefix))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CheckTimestamp rejects requests signed outside the allowed skew before any key lookup.
+func (v *SignatureVerifier) CheckTimestamp(p hmacsign.Params, now time.Time) error {
+	signedAt := time.Unix(p.Timestamp, 0)
+	if signedAt.Before(now.Add(-v.maxSkew)) || signedAt.After(now.Add(v.maxSkew)) {
+		return entity.ErrInvalidSignature
+	}
+	return nil
+}
+
+// Check verifies the signature of stringToSign for the key with prefix and then claims
+// the nonce, so each signed request is accepted once.
+func (v *SignatureVerifier) Check(prefix string, p hmacsign.Params, stringToSign string, now time.Time) error {
+	expected := hmacsign.Signature(v.Secret(prefix), stringToSign)
+	if !hmac.Equal([]byte(expected), []byte(p.Signature)) {
+		return entity.ErrInvalidSignature
+	}
+	if !v.claimNonce(prefix+":"+p.Nonce, now) {
+		return entity.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (v *SignatureVerifier) claimNonce(nonce string, now time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if expiresAt, ok := v.nonces[nonce]; ok && now.Before(expiresAt) {
+		return false
+	}
+	if len(v.nonces) >= maxNonces {
+		for n, expiresAt := range v.nonces {
+			if !now.Before(expiresAt) {
+				delete(v.nonces, n)
+			}
+		}
+		if len(v.nonces) >= maxNonces {
+			return false
+		}
+	}
+	// A nonce only has to be remembered while its timestamp is still acceptable
+	v.nonces[nonce] = now.Add(2 * v.maxSkew)
+	return true
+}
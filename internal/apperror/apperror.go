@@ -0,0 +1,77 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Kind classifies an error by what the caller can do about it, independent of the
+// transport; the delivery layer maps each kind to a status code. VALIDATION is input the
+// caller has to correct, REJECTED a valid request the business rules refuse.
+type Kind string
+
+const (
+	KindInternal     Kind = "INTERNAL"
+	KindNotFound     Kind = "NOT_FOUND"
+	KindConflict     Kind = "CONFLICT"
+	KindBadRequest   Kind = "BAD_REQUEST"
+	KindValidation   Kind = "VALIDATION"
+	KindRejected     Kind = "REJECTED"
+	KindUnauthorized Kind = "UNAUTHORIZED"
+	KindForbidden    Kind = "FORBIDDEN"
+	KindRateLimited  Kind = "RATE_LIMITED"
+	KindDeclined     Kind = "DECLINED"
+	KindUpstream     Kind = "UPSTREAM"
+	KindTimeout      Kind = "TIMEOUT"
+)
+
+// Error is a domain error with a kind and a stable snake_case code clients can branch on.
+// Sentinels are declared once with the constructors below and matched with errors.Is;
+// wrap them with fmt.Errorf("%w: ...") to add detail.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error     { return New(KindNotFound, code, message) }
+func Conflict(code, message string) *Error     { return New(KindConflict, code, message) }
+func BadRequest(code, message string) *Error   { return New(KindBadRequest, code, message) }
+func Validation(code, message string) *Error   { return New(KindValidation, code, message) }
+func Rejected(code, message string) *Error     { return New(KindRejected, code, message) }
+func Unauthorized(code, message string) *Error { return New(KindUnauthorized, code, message) }
+func Forbidden(code, message string) *Error    { return New(KindForbidden, code, message) }
+func RateLimited(code, message string) *Error  { return New(KindRateLimited, code, message) }
+func Declined(code, message string) *Error     { return New(KindDeclined, code, message) }
+func Upstream(code, message string) *Error     { return New(KindUpstream, code, message) }
+func Timeout(code, message string) *Error      { return New(KindTimeout, code, message) }
+
+// KindOf classifies err. Rows a repository did not find are NOT_FOUND, so use cases can
+// pass sql.ErrNoRows through; anything unclassified is INTERNAL.
+func KindOf(err error) Kind {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e.Kind
+	case errors.Is(err, sql.ErrNoRows):
+		return KindNotFound
+	}
+	return KindInternal
+}
+
+// CodeOf is the code of the domain error in err's chain, or "" when there is none.
+func CodeOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
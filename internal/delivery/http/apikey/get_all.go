@@ -0,0 +1,33 @@
+package apikey
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetAllAPIKeys godoc
+// @Summary      Get list of API keys
+// @Description  List the API keys of the merchant of the caller, newest first. Only prefixes are shown, never the keys. Requires the admin scope
+// @Tags         admin
+// @Produce      json
+// @Param        revoked      query    bool     false  "Filter by revocation"
+// @Param        page         query    int      false  "Page number"
+// @Param        per_page     query    int      false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      403     {object}  response.APIResponse
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/admin/api-keys [get]
+func (h *APIKeyHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetAll api keys request")
+	params := request.ParseAPIKeyQueryParams(r)
+	keys, err := h.APIKeyUC.GetAll(r.Context(), params)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to fetch api keys, general")
+		response.FailedWithMeta(w, 500, "apiKeys", "getAllAPIKeys", "Error Get All API Keys", nil)
+		return
+	}
+	h.Logger.Info().Int("count", len(keys)).Msg("✅ Successfully fetched api keys")
+	response.SuccessWithMeta(w, 200, "apiKeys", "getAllAPIKeys", "Success Get All API Keys", &params, keys)
+}
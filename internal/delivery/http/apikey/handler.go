@@ -0,0 +1,30 @@
+package apikey
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"net/http"
+)
+
+type APIKeyHandler struct {
+	APIKeyUC usecase.APIKeyUseCase
+	Logger   zerolog.Logger
+}
+
+func NewAPIKeyHandler(apiKeyUC usecase.APIKeyUseCase, logger zerolog.Logger) *APIKeyHandler {
+	return &APIKeyHandler{APIKeyUC: apiKeyUC, Logger: logger}
+}
+
+// parseAPIKeyID reads and validates the {id} path parameter.
+func (h *APIKeyHandler) parseAPIKeyID(w http.ResponseWriter, r *http.Request, state, action string) (uuid.UUID, bool) {
+	id, err := router.ParamsOf(r).UUID("id")
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to " + action + ", invalid UUID parameter")
+		response.Failed(w, 422, "apiKeys", state, "Invalid UUID, "+action)
+		return uuid.Nil, false
+	}
+	return id, true
+}
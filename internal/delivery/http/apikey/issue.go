@@ -0,0 +1,47 @@
+package apikey
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// IssueAPIKey godoc
+// @Summary      Issue an API key
+// @Description  Issues a BEARER or HMAC API key for the merchant of the caller. The plaintext key, or for HMAC keys the signing secret, is in this response only; store it right away, it cannot be shown again. Requires the admin scope
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.IssueAPIKeyRequest  true  "API key to issue"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      403      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/admin/api-keys [post]
+func (h *APIKeyHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Issue api key request")
+	var req request.IssueAPIKeyRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "apiKeys", "issueAPIKey", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "apiKeys", "issueAPIKey", err)
+		return
+	}
+
+	issued, err := h.APIKeyUC.Issue(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "apiKeys", "issueAPIKey", "Issue API Key", err)
+		return
+	}
+	// The response carries the only copy of the plaintext key
+	w.Header().Set("Cache-Control", "no-store")
+	h.Logger.Info().Str("data", issued.ID.String()).Msg("✅ Successfully issued api key")
+	response.Success(w, 201, "apiKeys", "issueAPIKey", "Success Issue API Key", issued)
+}
@@ -0,0 +1,35 @@
+package apikey
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  Revokes an API key of the merchant of the caller. Other replicas may accept it until their key cache expires. Requires the admin scope
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the API key"
+// @Success      200  {object}  response.APIResponse
+// @Failure      403  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse  "API key not found"
+// @Failure      409  {object}  response.APIResponse  "API key already revoked"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID"
+// @Failure      500  {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/admin/api-keys/{id}/revoke [post]
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Revoke api key request")
+	id, ok := h.parseAPIKeyID(w, r, "revokeAPIKey", "Revoke API Key")
+	if !ok {
+		return
+	}
+
+	key, err := h.APIKeyUC.Revoke(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "apiKeys", "revokeAPIKey", "Revoke API Key", err)
+		return
+	}
+	h.Logger.Info().Str("data", key.ID.String()).Msg("✅ Successfully revoked api key")
+	response.Success(w, 200, "apiKeys", "revokeAPIKey", "Success Revoke API Key", key)
+}
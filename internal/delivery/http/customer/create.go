@@ -0,0 +1,47 @@
+package customer
+
+import (
+	"fmt"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// CreateCustomer godoc
+// @Summary      Create a new customer
+// @Description  Creates a new customer (payer) that payments can be linked to
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.CustomerRequest  true  "Customer data to create"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      401      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse  "External reference already exists"
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/customers [post]
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Create customer request")
+	var req request.CustomerRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "customers", "createCustomer", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "customers", "createCustomer", err)
+		return
+	}
+
+	customer, err := h.CustomerUC.Create(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "customers", "createCustomer", "Create Customer", err)
+		return
+	}
+	h.Logger.Info().Str("data", fmt.Sprint(customer.ID)).Msg("✅ Successfully stored customer")
+	response.Success(w, 201, "customers", "createCustomer", "Success Create Customer", customer)
+}
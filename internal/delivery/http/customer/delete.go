@@ -0,0 +1,33 @@
+package customer
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// DeleteCustomerByID godoc
+// @Summary      Delete a customer by ID
+// @Description  Soft-deletes a customer; refused while the customer has pending or authorized payments
+// @Tags         customers
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the customer to delete"
+// @Success      202  {object}  response.APIResponse
+// @Failure      401  {object}  response.APIResponse  "Unauthorized"
+// @Failure      404  {object}  response.APIResponse  "Customer not found"
+// @Failure      409  {object}  response.APIResponse  "Customer has payments in progress"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID"
+// @Failure      500  {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/customers/{id} [delete]
+func (h *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Delete customer request")
+	id, ok := h.parseCustomerID(w, r, "deleteCustomerByID", "Delete Customer by ID")
+	if !ok {
+		return
+	}
+	if err := h.CustomerUC.Delete(r.Context(), id); err != nil {
+		response.ErrorLogged(w, h.Logger, "customers", "deleteCustomerByID", "Delete Customer", err)
+		return
+	}
+	h.Logger.Info().Msg("✅ Successfully removed customer")
+	response.Success(w, 202, "customers", "deleteCustomerByID", "Success Delete Customer", nil)
+}
@@ -0,0 +1,33 @@
+package customer
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetAllCustomers godoc
+// @Summary      Get list of customers
+// @Description  List customers with optional email and external reference filters
+// @Tags         customers
+// @Produce      json
+// @Param        email               query    string   false  "Filter by email"
+// @Param        external_reference  query    string   false  "Filter by external reference"
+// @Param        page                query    int      false  "Page number"
+// @Param        per_page            query    int      false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/customers [get]
+func (h *CustomerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetAll customers request")
+	params := request.ParseCustomerQueryParams(r)
+	customers, err := h.CustomerUC.GetAll(r.Context(), params)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to fetch customers, general")
+		response.FailedWithMeta(w, 500, "customers", "getAllCustomers", "Error Get All Customers", nil)
+		return
+	}
+	h.Logger.Info().Int("count", len(customers)).Msg("✅ Successfully fetched customers")
+	response.SuccessWithMeta(w, 200, "customers", "getAllCustomers", "Success Get All Customers", &params, customers)
+}
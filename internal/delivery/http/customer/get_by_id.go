@@ -0,0 +1,53 @@
+package customer
+
+import (
+	"fmt"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// GetCustomerByID godoc
+// @Summary      Get customer by ID
+// @Description  Retrieve a customer entity using its UUID
+// @Tags         customers
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the customer"
+// @Success      200  {object}  response.APIResponse
+// @Failure      401  {object}  response.APIResponse  "Unauthorized"
+// @Failure      404  {object}  response.APIResponse  "Customer not found"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID"
+// @Failure      500  {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/customers/{id} [get]
+func (h *CustomerHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetByID customer request")
+	id, ok := h.parseCustomerID(w, r, "getCustomerByID", "Get Customer by ID")
+	if !ok {
+		return
+	}
+	customer, err := h.CustomerUC.GetByID(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "customers", "getCustomerByID", "Get Customer by ID", err)
+		return
+	}
+	h.Logger.Info().Str("data", fmt.Sprint(customer.ID)).Msg("✅ Successfully get customer by id")
+	response.Success(w, 200, "customers", "getCustomerByID", "Success Get Customer by ID", customer)
+}
+
+// parseCustomerID reads and validates the {id} path parameter.
+func (h *CustomerHandler) parseCustomerID(w http.ResponseWriter, r *http.Request, state, action string) (uuid.UUID, bool) {
+	idStr := router.GetParam(r, "id")
+	if idStr == "" {
+		h.Logger.Error().Msg("❌ Failed to " + action + ", missing ID parameter")
+		response.Failed(w, 422, "customers", state, "Missing ID Parameter, "+action)
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to " + action + ", invalid UUID parameter")
+		response.Failed(w, 422, "customers", state, "Invalid UUID, "+action)
+		return uuid.Nil, false
+	}
+	return id, true
+}
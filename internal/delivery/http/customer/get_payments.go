@@ -0,0 +1,45 @@
+package customer
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetCustomerPayments godoc
+// @Summary      Get payments of a customer
+// @Description  List the payments linked to a customer, with the same sort and pagination as the payment list
+// @Tags         customers
+// @Produce      json
+// @Param        id                path     string   true   "UUID of the customer"
+// @Param        sort_field        query    string   false  "Sort field"
+// @Param        sort_direction    query    string   false  "Sort direction ASC/DESC"
+// @Param        page              query    int      false  "Page number"
+// @Param        per_page          query    int      false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse  "Customer not found"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID or query"
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/customers/{id}/payments [get]
+func (h *CustomerHandler) GetPayments(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetPayments customer request")
+	id, ok := h.parseCustomerID(w, r, "getCustomerPayments", "Get Customer Payments")
+	if !ok {
+		return
+	}
+	params, err := request.ParsePaymentQueryParams(r)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "customers", "getCustomerPayments", err)
+		return
+	}
+	payments, err := h.CustomerUC.GetPayments(r.Context(), id, params)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "customers", "getCustomerPayments", "Get Customer Payments", err)
+		return
+	}
+	params.CustomerID = &id
+	h.Logger.Info().Int("count", len(payments)).Msg("✅ Successfully fetched customer payments")
+	response.SuccessWithMeta(w, 200, "customers", "getCustomerPayments", "Success Get Customer Payments", &params, payments)
+}
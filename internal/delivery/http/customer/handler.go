@@ -0,0 +1,15 @@
+package customer
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/rs/zerolog"
+)
+
+type CustomerHandler struct {
+	CustomerUC usecase.CustomerUseCase
+	Logger     zerolog.Logger
+}
+
+func NewCustomerHandler(customerUC usecase.CustomerUseCase, logger zerolog.Logger) *CustomerHandler {
+	return &CustomerHandler{CustomerUC: customerUC, Logger: logger}
+}
@@ -0,0 +1,52 @@
+package customer
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// UpdateCustomerByID godoc
+// @Summary      Update customer by ID
+// @Description  Replace the details of a customer using its UUID
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                   true  "UUID of the customer"
+// @Param        request  body      request.CustomerRequest  true  "Customer data"
+// @Success      200      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse  "Invalid request body"
+// @Failure      404      {object}  response.APIResponse  "Customer not found"
+// @Failure      409      {object}  response.APIResponse  "External reference already exists"
+// @Failure      422      {object}  response.APIResponse  "Validation error"
+// @Failure      500      {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/customers/{id} [put]
+func (h *CustomerHandler) UpdateByID(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming UpdateByID customer request")
+	id, ok := h.parseCustomerID(w, r, "updateCustomerByID", "Update Customer by ID")
+	if !ok {
+		return
+	}
+
+	var req request.CustomerRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "customers", "updateCustomerByID", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "customers", "updateCustomerByID", err)
+		return
+	}
+
+	customer, err := h.CustomerUC.UpdateByID(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "customers", "updateCustomerByID", "Update Customer", err)
+		return
+	}
+	h.Logger.Info().Str("id", id.String()).Msg("✅ Successfully updated customer")
+	response.Success(w, 200, "customers", "updateCustomerByID", "Customer Updated", customer)
+}
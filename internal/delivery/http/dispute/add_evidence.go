@@ -0,0 +1,55 @@
+package dispute
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// maxEvidenceBodySize allows for the base64 overhead of the largest attachment.
+const maxEvidenceBodySize = request.MaxDisputeEvidenceSize*4/3 + 64<<10
+
+// AddDisputeEvidence godoc
+// @Summary      Attach dispute evidence
+// @Description  Attaches a document (base64 content) or a written statement while the dispute is OPENED or EVIDENCE_REQUIRED and before its deadline
+// @Tags         disputes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                             true  "UUID of the dispute"
+// @Param        request  body      request.AddDisputeEvidenceRequest  true  "Evidence to attach"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      413      {object}  response.APIResponse  "Request body too large"
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/disputes/{id}/evidence [post]
+func (h *DisputeHandler) AddEvidence(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Add dispute evidence request")
+	id, ok := h.parseUUIDParam(w, r, "id", "addDisputeEvidence", "Add Dispute Evidence")
+	if !ok {
+		return
+	}
+	var req request.AddDisputeEvidenceRequest
+	if err := validation.DecodeLimit(w, r, &req, maxEvidenceBodySize); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "disputes", "addDisputeEvidence", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "disputes", "addDisputeEvidence", err)
+		return
+	}
+
+	evidence, err := h.DisputeUC.AddEvidence(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "disputes", "addDisputeEvidence", "Add Dispute Evidence", err)
+		return
+	}
+	h.Logger.Info().Str("data", evidence.ID.String()).Msg("✅ Successfully added dispute evidence")
+	response.Success(w, 201, "disputes", "addDisputeEvidence", "Success Add Dispute Evidence", evidence)
+}
@@ -0,0 +1,34 @@
+package dispute
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetAllDisputes godoc
+// @Summary      Get list of disputes
+// @Description  List disputes ordered by evidence deadline
+// @Tags         disputes
+// @Produce      json
+// @Param        payment_id        query    string   false  "Filter by payment UUID"
+// @Param        status            query    string   false  "Filter by dispute status"
+// @Param        deadline_flagged  query    bool     false  "Filter by approaching deadline flag"
+// @Param        page              query    int      false  "Page number"
+// @Param        per_page          query    int      false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/disputes [get]
+func (h *DisputeHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetAll disputes request")
+	params := request.ParseDisputeQueryParams(r)
+	disputes, err := h.DisputeUC.GetAll(r.Context(), params)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to fetch disputes, general")
+		response.FailedWithMeta(w, 500, "disputes", "getAllDisputes", "Error Get All Disputes", nil)
+		return
+	}
+	h.Logger.Info().Int("count", len(disputes)).Msg("✅ Successfully fetched disputes")
+	response.SuccessWithMeta(w, 200, "disputes", "getAllDisputes", "Success Get All Disputes", &params, disputes)
+}
@@ -0,0 +1,33 @@
+package dispute
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetDisputeByID godoc
+// @Summary      Get dispute by ID
+// @Description  Retrieve a dispute with its evidence list and, when lost, its payment reversal
+// @Tags         disputes
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the dispute"
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse  "Dispute not found"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID"
+// @Failure      500  {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/disputes/{id} [get]
+func (h *DisputeHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetByID dispute request")
+	id, ok := h.parseUUIDParam(w, r, "id", "getDisputeByID", "Get Dispute by ID")
+	if !ok {
+		return
+	}
+
+	dispute, err := h.DisputeUC.GetByID(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "disputes", "getDisputeByID", "Get Dispute by ID", err)
+		return
+	}
+	h.Logger.Info().Str("data", dispute.ID.String()).Msg("✅ Successfully get dispute by id")
+	response.Success(w, 200, "disputes", "getDisputeByID", "Success Get Dispute by ID", dispute)
+}
@@ -0,0 +1,51 @@
+package dispute
+
+import (
+	"fmt"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+	"strconv"
+)
+
+// GetDisputeEvidence godoc
+// @Summary      Download dispute evidence
+// @Description  Returns the attached file, or the evidence record when it has no file
+// @Tags         disputes
+// @Produce      octet-stream
+// @Security     BearerAuth
+// @Param        id           path      string  true  "UUID of the dispute"
+// @Param        evidence_id  path      string  true  "UUID of the evidence"
+// @Success      200          {file}    file
+// @Failure      404          {object}  response.APIResponse
+// @Failure      422          {object}  response.APIResponse
+// @Failure      500          {object}  response.APIResponse
+// @Router       /api/v1/disputes/{id}/evidence/{evidence_id} [get]
+func (h *DisputeHandler) GetEvidence(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Get dispute evidence request")
+	id, ok := h.parseUUIDParam(w, r, "id", "getDisputeEvidence", "Get Dispute Evidence")
+	if !ok {
+		return
+	}
+	evidenceID, ok := h.parseUUIDParam(w, r, "evidence_id", "getDisputeEvidence", "Get Dispute Evidence")
+	if !ok {
+		return
+	}
+
+	evidence, err := h.DisputeUC.GetEvidence(r.Context(), id, evidenceID)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "disputes", "getDisputeEvidence", "Get Dispute Evidence", err)
+		return
+	}
+	if len(evidence.Content) == 0 {
+		response.Success(w, 200, "disputes", "getDisputeEvidence", "Success Get Dispute Evidence", evidence)
+		return
+	}
+
+	h.Logger.Info().Str("data", evidence.ID.String()).Msg("✅ Successfully get dispute evidence")
+	w.Header().Set("Content-Type", evidence.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(evidence.Content)))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", evidence.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(evidence.Content)
+}
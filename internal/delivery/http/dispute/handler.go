@@ -0,0 +1,30 @@
+package dispute
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"net/http"
+)
+
+type DisputeHandler struct {
+	DisputeUC usecase.DisputeUseCase
+	Logger    zerolog.Logger
+}
+
+func NewDisputeHandler(disputeUC usecase.DisputeUseCase, logger zerolog.Logger) *DisputeHandler {
+	return &DisputeHandler{DisputeUC: disputeUC, Logger: logger}
+}
+
+// parseUUIDParam reads and validates a UUID path parameter.
+func (h *DisputeHandler) parseUUIDParam(w http.ResponseWriter, r *http.Request, name, state, action string) (uuid.UUID, bool) {
+	id, err := router.ParamsOf(r).UUID(name)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to " + action + ", invalid UUID parameter")
+		response.Failed(w, 422, "disputes", state, "Invalid UUID, "+action)
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,46 @@
+package dispute
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// OpenDispute godoc
+// @Summary      Open a dispute
+// @Description  Records a cardholder dispute and moves the payment to DISPUTED
+// @Tags         disputes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.OpenDisputeRequest  true  "Dispute to open"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/disputes [post]
+func (h *DisputeHandler) Open(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Open dispute request")
+	var req request.OpenDisputeRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "disputes", "openDispute", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "disputes", "openDispute", err)
+		return
+	}
+
+	dispute, err := h.DisputeUC.Open(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "disputes", "openDispute", "Open Dispute", err)
+		return
+	}
+	h.Logger.Info().Str("data", dispute.ID.String()).Msg("✅ Successfully opened dispute")
+	response.Success(w, 201, "disputes", "openDispute", "Success Open Dispute", dispute)
+}
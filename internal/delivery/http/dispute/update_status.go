@@ -0,0 +1,51 @@
+package dispute
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// UpdateDisputeStatus godoc
+// @Summary      Update dispute status
+// @Description  Moves a dispute OPENED → EVIDENCE_REQUIRED → SUBMITTED → WON/LOST. WON restores the payment status, LOST charges the payment back and posts a reversal
+// @Tags         disputes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                              true  "UUID of the dispute"
+// @Param        request  body      request.UpdateDisputeStatusRequest  true  "Target status"
+// @Success      200      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/disputes/{id}/status [put]
+func (h *DisputeHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Update dispute status request")
+	id, ok := h.parseUUIDParam(w, r, "id", "updateDisputeStatus", "Update Dispute Status")
+	if !ok {
+		return
+	}
+	var req request.UpdateDisputeStatusRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "disputes", "updateDisputeStatus", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "disputes", "updateDisputeStatus", err)
+		return
+	}
+
+	dispute, err := h.DisputeUC.UpdateStatus(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "disputes", "updateDisputeStatus", "Update Dispute Status", err)
+		return
+	}
+	h.Logger.Info().Str("data", dispute.ID.String()).Str("status", dispute.Status).Msg("✅ Successfully updated dispute status")
+	response.Success(w, 200, "disputes", "updateDisputeStatus", "Success Update Dispute Status", dispute)
+}
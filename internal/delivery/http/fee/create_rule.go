@@ -0,0 +1,45 @@
+package fee
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// CreateFeeRule godoc
+// @Summary      Create a fee rule
+// @Description  Creates a STANDARD (fixed + percentage) or TIERED (fixed + graduated percentages) fee rule; operator keys only
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.FeeRuleRequest  true  "Fee rule to create"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      403      {object}  response.APIResponse  "Not an operator key"
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/fee-rules [post]
+func (h *FeeHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Create fee rule request")
+	var req request.FeeRuleRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "feeRules", "createFeeRule", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "feeRules", "createFeeRule", err)
+		return
+	}
+
+	rule, err := h.FeeUC.CreateRule(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "feeRules", "createFeeRule", "Create Fee Rule", err)
+		return
+	}
+	h.Logger.Info().Str("data", rule.ID.String()).Msg("✅ Successfully stored fee rule")
+	response.Success(w, 201, "feeRules", "createFeeRule", "Success Create Fee Rule", rule)
+}
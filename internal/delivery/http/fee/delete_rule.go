@@ -0,0 +1,34 @@
+package fee
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// DeleteFeeRule godoc
+// @Summary      Delete a fee rule
+// @Description  Deletes a fee rule; fee lines already charged keep their amounts. Operator keys only
+// @Tags         fees
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the fee rule"
+// @Success      200  {object}  response.APIResponse
+// @Failure      403  {object}  response.APIResponse  "Not an operator key"
+// @Failure      404  {object}  response.APIResponse
+// @Failure      422  {object}  response.APIResponse
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/fee-rules/{id} [delete]
+func (h *FeeHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Delete fee rule request")
+	id, ok := h.parseFeeRuleID(w, r, "deleteFeeRule", "Delete Fee Rule")
+	if !ok {
+		return
+	}
+
+	if err := h.FeeUC.DeleteRule(r.Context(), id); err != nil {
+		response.ErrorLogged(w, h.Logger, "feeRules", "deleteFeeRule", "Delete Fee Rule", err)
+		return
+	}
+	h.Logger.Info().Str("data", id.String()).Msg("✅ Successfully deleted fee rule")
+	response.Success(w, 200, "feeRules", "deleteFeeRule", "Success Delete Fee Rule", nil)
+}
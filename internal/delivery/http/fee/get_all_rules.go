@@ -0,0 +1,34 @@
+package fee
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetAllFeeRules godoc
+// @Summary      Get list of fee rules
+// @Description  List fee rules in selection order (priority, then creation time); operator keys only, as the rules price every merchant
+// @Tags         fees
+// @Produce      json
+// @Param        merchant_id  query    string   false  "Filter by merchant UUID"
+// @Param        active       query    bool     false  "Filter by active flag"
+// @Param        page         query    int      false  "Page number"
+// @Param        per_page     query    int      false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      403     {object}  response.APIResponse  "Not an operator key"
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/fee-rules [get]
+func (h *FeeHandler) GetAllRules(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetAll fee rules request")
+	params := request.ParseFeeRuleQueryParams(r)
+	rules, err := h.FeeUC.GetAllRules(r.Context(), params)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to fetch fee rules, general")
+		response.FailedWithMeta(w, 500, "feeRules", "getAllFeeRules", "Error Get All Fee Rules", nil)
+		return
+	}
+	h.Logger.Info().Int("count", len(rules)).Msg("✅ Successfully fetched fee rules")
+	response.SuccessWithMeta(w, 200, "feeRules", "getAllFeeRules", "Success Get All Fee Rules", &params, rules)
+}
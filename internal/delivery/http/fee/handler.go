@@ -0,0 +1,30 @@
+package fee
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"net/http"
+)
+
+type FeeHandler struct {
+	FeeUC  usecase.FeeUseCase
+	Logger zerolog.Logger
+}
+
+func NewFeeHandler(feeUC usecase.FeeUseCase, logger zerolog.Logger) *FeeHandler {
+	return &FeeHandler{FeeUC: feeUC, Logger: logger}
+}
+
+// parseFeeRuleID reads and validates the {id} path parameter.
+func (h *FeeHandler) parseFeeRuleID(w http.ResponseWriter, r *http.Request, state, action string) (uuid.UUID, bool) {
+	id, err := router.ParamsOf(r).UUID("id")
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to " + action + ", invalid UUID parameter")
+		response.Failed(w, 422, "feeRules", state, "Invalid UUID, "+action)
+		return uuid.Nil, false
+	}
+	return id, true
+}
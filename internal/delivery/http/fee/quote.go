@@ -0,0 +1,44 @@
+package fee
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// QuoteFee godoc
+// @Summary      Quote a fee
+// @Description  Previews the fee line items for a hypothetical payment of the caller's merchant
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.FeeQuoteRequest  true  "Hypothetical payment"
+// @Success      200      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/fees/quote [post]
+func (h *FeeHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Quote fee request")
+	var req request.FeeQuoteRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "fees", "quoteFee", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "fees", "quoteFee", err)
+		return
+	}
+
+	quote, err := h.FeeUC.Quote(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "fees", "quoteFee", "Quote Fee", err)
+		return
+	}
+	h.Logger.Info().Str("total", quote.Total.String()).Msg("✅ Successfully quoted fee")
+	response.Success(w, 200, "fees", "quoteFee", "Success Quote Fee", quote)
+}
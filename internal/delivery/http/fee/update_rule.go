@@ -0,0 +1,51 @@
+package fee
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// UpdateFeeRule godoc
+// @Summary      Update a fee rule
+// @Description  Replaces a fee rule; quotes and captures use the new definition immediately. Operator keys only
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                  true  "UUID of the fee rule"
+// @Param        request  body      request.FeeRuleRequest  true  "Fee rule definition"
+// @Success      200      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      403      {object}  response.APIResponse  "Not an operator key"
+// @Failure      404      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/fee-rules/{id} [put]
+func (h *FeeHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Update fee rule request")
+	id, ok := h.parseFeeRuleID(w, r, "updateFeeRule", "Update Fee Rule")
+	if !ok {
+		return
+	}
+	var req request.FeeRuleRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "feeRules", "updateFeeRule", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "feeRules", "updateFeeRule", err)
+		return
+	}
+
+	rule, err := h.FeeUC.UpdateRule(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "feeRules", "updateFeeRule", "Update Fee Rule", err)
+		return
+	}
+	h.Logger.Info().Str("data", rule.ID.String()).Msg("✅ Successfully updated fee rule")
+	response.Success(w, 200, "feeRules", "updateFeeRule", "Success Update Fee Rule", rule)
+}
@@ -0,0 +1,33 @@
+package fxrate
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetAllFXRates godoc
+// @Summary      Get list of fx rates
+// @Description  List loaded exchange rates, newest effective time first
+// @Tags         fx-rates
+// @Produce      json
+// @Param        base_currency   query    string   false  "Filter by base currency"
+// @Param        quote_currency  query    string   false  "Filter by quote currency"
+// @Param        page            query    int      false  "Page number"
+// @Param        per_page        query    int      false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/fx-rates [get]
+func (h *FXRateHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetAll fx rates request")
+	params := request.ParseFXRateQueryParams(r)
+	rates, err := h.FXRateUC.GetAll(r.Context(), params)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to fetch fx rates, general")
+		response.FailedWithMeta(w, 500, "fxRates", "getAllFXRates", "Error Get All FX Rates", nil)
+		return
+	}
+	h.Logger.Info().Int("count", len(rates)).Msg("✅ Successfully fetched fx rates")
+	response.SuccessWithMeta(w, 200, "fxRates", "getAllFXRates", "Success Get All FX Rates", &params, rates)
+}
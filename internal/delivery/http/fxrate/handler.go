@@ -0,0 +1,15 @@
+package fxrate
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/rs/zerolog"
+)
+
+type FXRateHandler struct {
+	FXRateUC usecase.FXRateUseCase
+	Logger   zerolog.Logger
+}
+
+func NewFXRateHandler(fxRateUC usecase.FXRateUseCase, logger zerolog.Logger) *FXRateHandler {
+	return &FXRateHandler{FXRateUC: fxRateUC, Logger: logger}
+}
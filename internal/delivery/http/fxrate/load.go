@@ -0,0 +1,47 @@
+package fxrate
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// LoadFXRates godoc
+// @Summary      Load fx rates
+// @Description  Upserts a batch of exchange rates; a rate for an existing pair and effective time replaces it. Rates are shared by every merchant, so only operator keys may load them
+// @Tags         fx-rates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.LoadFXRatesRequest  true  "Rates to load"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      401      {object}  response.APIResponse
+// @Failure      403      {object}  response.APIResponse  "Not an operator key"
+// @Failure      413      {object}  response.APIResponse  "Request body too large"
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/fx-rates [post]
+func (h *FXRateHandler) Load(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Load fx rates request")
+	var req request.LoadFXRatesRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "fxRates", "loadFXRates", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "fxRates", "loadFXRates", err)
+		return
+	}
+
+	rates, err := h.FXRateUC.Load(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "fxRates", "loadFXRates", "Load FX Rates", err)
+		return
+	}
+	h.Logger.Info().Int("count", len(rates)).Msg("✅ Successfully loaded fx rates")
+	response.Success(w, 201, "fxRates", "loadFXRates", "Success Load FX Rates", rates)
+}
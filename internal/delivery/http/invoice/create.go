@@ -0,0 +1,44 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// CreateInvoice godoc
+// @Summary      Create a draft invoice
+// @Description  Stores a DRAFT invoice. Line subtotals, discounts, tax (PPN by default) and the total are computed from the items
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.InvoiceRequest  true  "Invoice to create"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/invoices [post]
+func (h *InvoiceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Create invoice request")
+	var req request.InvoiceRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "invoices", "createInvoice", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "invoices", "createInvoice", err)
+		return
+	}
+
+	invoice, err := h.InvoiceUC.Create(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "invoices", "createInvoice", "Create Invoice", err)
+		return
+	}
+	h.Logger.Info().Str("data", invoice.ID.String()).Msg("✅ Successfully stored invoice")
+	response.Success(w, 201, "invoices", "createInvoice", "Success Create Invoice", invoice)
+}
@@ -0,0 +1,51 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// CreateInvoicePayment godoc
+// @Summary      Pay an invoice
+// @Description  Creates a PENDING payment linked to the invoice, for the outstanding amount unless a smaller amount is given. The invoice moves to PARTIALLY_PAID or PAID as linked payments are captured
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                               true  "UUID of the invoice"
+// @Param        request  body      request.CreateInvoicePaymentRequest  true  "Payment to create"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/invoices/{id}/payments [post]
+func (h *InvoiceHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Create invoice payment request")
+	id, ok := h.parseID(w, r, "createInvoicePayment", "Create Invoice Payment")
+	if !ok {
+		return
+	}
+	var req request.CreateInvoicePaymentRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "invoices", "createInvoicePayment", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "invoices", "createInvoicePayment", err)
+		return
+	}
+
+	payment, err := h.InvoiceUC.CreatePayment(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "invoices", "createInvoicePayment", "Create Invoice Payment", err)
+		return
+	}
+	h.Logger.Info().Str("data", payment.ID.String()).Msg("✅ Successfully created invoice payment")
+	response.Success(w, 201, "invoices", "createInvoicePayment", "Success Create Invoice Payment", payment)
+}
@@ -0,0 +1,34 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// FinalizeInvoice godoc
+// @Summary      Finalize an invoice
+// @Description  Issues a DRAFT invoice, moving it to OPEN so payments can be made against it
+// @Tags         invoices
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the invoice"
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse
+// @Failure      409  {object}  response.APIResponse
+// @Failure      422  {object}  response.APIResponse
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/invoices/{id}/finalize [post]
+func (h *InvoiceHandler) Finalize(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Finalize invoice request")
+	id, ok := h.parseID(w, r, "finalizeInvoice", "Finalize Invoice")
+	if !ok {
+		return
+	}
+	invoice, err := h.InvoiceUC.Finalize(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "invoices", "finalizeInvoice", "Finalize Invoice", err)
+		return
+	}
+	h.Logger.Info().Str("data", invoice.ID.String()).Msg("✅ Successfully finalized invoice")
+	response.Success(w, 200, "invoices", "finalizeInvoice", "Success Finalize Invoice", invoice)
+}
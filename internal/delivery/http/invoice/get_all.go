@@ -0,0 +1,34 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetAllInvoices godoc
+// @Summary      Get list of invoices
+// @Description  List invoices, newest first
+// @Tags         invoices
+// @Produce      json
+// @Param        customer_id  query    string  false  "Filter by customer UUID"
+// @Param        status       query    string  false  "Filter by invoice status"
+// @Param        overdue      query    bool    false  "Filter by overdue flag"
+// @Param        page         query    int     false  "Page number"
+// @Param        per_page     query    int     false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/invoices [get]
+func (h *InvoiceHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetAll invoices request")
+	params := request.ParseInvoiceQueryParams(r)
+	invoices, err := h.InvoiceUC.GetAll(r.Context(), params)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to fetch invoices, general")
+		response.FailedWithMeta(w, 500, "invoices", "getAllInvoices", "Error Get All Invoices", nil)
+		return
+	}
+	h.Logger.Info().Int("count", len(invoices)).Msg("✅ Successfully fetched invoices")
+	response.SuccessWithMeta(w, 200, "invoices", "getAllInvoices", "Success Get All Invoices", &params, invoices)
+}
@@ -0,0 +1,33 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetInvoiceByID godoc
+// @Summary      Get invoice by ID
+// @Description  Get an invoice with its items and the payments made against it
+// @Tags         invoices
+// @Produce      json
+// @Param        id   path      string  true  "UUID of the invoice"
+// @Security     BearerAuth
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse
+// @Failure      422  {object}  response.APIResponse
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/invoices/{id} [get]
+func (h *InvoiceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetByID invoice request")
+	id, ok := h.parseID(w, r, "getInvoiceByID", "Get Invoice by ID")
+	if !ok {
+		return
+	}
+	invoice, err := h.InvoiceUC.GetByID(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "invoices", "getInvoiceByID", "Get Invoice by ID", err)
+		return
+	}
+	h.Logger.Info().Str("data", invoice.ID.String()).Msg("✅ Successfully get invoice by id")
+	response.Success(w, 200, "invoices", "getInvoiceByID", "Success Get Invoice by ID", invoice)
+}
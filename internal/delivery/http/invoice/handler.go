@@ -0,0 +1,30 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"net/http"
+)
+
+type InvoiceHandler struct {
+	InvoiceUC usecase.InvoiceUseCase
+	Logger    zerolog.Logger
+}
+
+func NewInvoiceHandler(invoiceUC usecase.InvoiceUseCase, logger zerolog.Logger) *InvoiceHandler {
+	return &InvoiceHandler{InvoiceUC: invoiceUC, Logger: logger}
+}
+
+// parseID reads and validates the invoice UUID path parameter.
+func (h *InvoiceHandler) parseID(w http.ResponseWriter, r *http.Request, state, action string) (uuid.UUID, bool) {
+	id, err := router.ParamsOf(r).UUID("id")
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to " + action + ", invalid UUID parameter")
+		response.Failed(w, 422, "invoices", state, "Invalid UUID, "+action)
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,51 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// UpdateInvoice godoc
+// @Summary      Update a draft invoice
+// @Description  Replaces the details and items of a DRAFT invoice and recomputes its totals
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                  true  "UUID of the invoice"
+// @Param        request  body      request.InvoiceRequest  true  "Invoice details"
+// @Success      200      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/invoices/{id} [put]
+func (h *InvoiceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Update invoice request")
+	id, ok := h.parseID(w, r, "updateInvoice", "Update Invoice")
+	if !ok {
+		return
+	}
+	var req request.InvoiceRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "invoices", "updateInvoice", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "invoices", "updateInvoice", err)
+		return
+	}
+
+	invoice, err := h.InvoiceUC.Update(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "invoices", "updateInvoice", "Update Invoice", err)
+		return
+	}
+	h.Logger.Info().Str("data", invoice.ID.String()).Msg("✅ Successfully updated invoice")
+	response.Success(w, 200, "invoices", "updateInvoice", "Success Update Invoice", invoice)
+}
@@ -0,0 +1,34 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// VoidInvoice godoc
+// @Summary      Void an invoice
+// @Description  Cancels a DRAFT or OPEN invoice that has no payment received or in progress
+// @Tags         invoices
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the invoice"
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse
+// @Failure      409  {object}  response.APIResponse
+// @Failure      422  {object}  response.APIResponse
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/invoices/{id}/void [post]
+func (h *InvoiceHandler) Void(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Void invoice request")
+	id, ok := h.parseID(w, r, "voidInvoice", "Void Invoice")
+	if !ok {
+		return
+	}
+	invoice, err := h.InvoiceUC.Void(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "invoices", "voidInvoice", "Void Invoice", err)
+		return
+	}
+	h.Logger.Info().Str("data", invoice.ID.String()).Msg("✅ Successfully voided invoice")
+	response.Success(w, 200, "invoices", "voidInvoice", "Success Void Invoice", invoice)
+}
@@ -1,29 +1,172 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/adf-code/beta-payment-api/internal/apperror"
 	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/jwt"
+	"github.com/adf-code/beta-payment-api/internal/pkg/auth"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/pkg/hmacsign"
 	"github.com/rs/zerolog"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
-func AuthMiddleware(logger zerolog.Logger) Middleware {
+// maxSignedBodySize bounds the body read into memory to check an HMAC signature; it
+// leaves room for a base64 encoded dispute evidence file.
+const maxSignedBodySize = 16 << 20
+
+var (
+	errMissingCredentials = apperror.Unauthorized("missing_credentials", "no API key, token or request signature")
+	errMalformedSignature = apperror.Unauthorized("malformed_signature", "request signature is malformed")
+)
+
+// APIKeyAuthenticator resolves a presented bearer API key, or the HMAC key of a signed
+// request, to the stored key.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*entity.APIKey, error)
+	AuthenticateSigned(ctx context.Context, params hmacsign.Params, stringToSign string) (*entity.APIKey, error)
+}
+
+// TokenVerifier verifies a JWT bearer token.
+type TokenVerifier interface {
+	Verify(token string, now time.Time) (*jwt.Claims, error)
+}
+
+// AuthMiddleware authenticates an HMAC signed request, a bearer API key or, when tokens
+// is not nil, a bearer JWT, and scopes the request context to the caller's merchant.
+func AuthMiddleware(apiKeys APIKeyAuthenticator, tokens TokenVerifier, logger zerolog.Logger) Middleware {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+			var principal auth.Principal
+			switch {
+			case strings.HasPrefix(authHeader, hmacsign.Scheme+" "):
+				params, err := hmacsign.ParseAuthorization(authHeader)
+				if err != nil {
+					logger.Warn().Err(err).Msg("‼️ Request signature malformed")
+					response.Error(w, "authentication", "tryAuthentication", "Authentication", errMalformedSignature)
+					return
+				}
+				body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodySize))
+				if err != nil {
+					logger.Warn().Err(err).Msg("‼️ Failed to read signed request body")
+					response.Failed(w, 413, "authentication", "tryAuthentication", "Request Body Too Large")
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				stringToSign := hmacsign.StringToSign(r.Method, r.URL.EscapedPath(), r.URL.RawQuery, hmacsign.BodyDigest(body), params.Timestamp, params.Nonce)
+				key, err := apiKeys.AuthenticateSigned(r.Context(), params, stringToSign)
+				if !authenticated(w, err, logger) {
+					return
+				}
+				principal = keyPrincipal(key)
+			case strings.HasPrefix(authHeader, "Bearer "):
+				bearer := strings.TrimPrefix(authHeader, "Bearer ")
+				if tokens != nil && strings.Count(bearer, ".") == 2 {
+					claims, err := tokens.Verify(bearer, time.Now())
+					if err != nil {
+						logger.Warn().Err(err).Msg("‼️ Bearer JWT not authorized")
+						response.Error(w, "authentication", "tryAuthentication", "Authentication", jwt.ErrInvalidToken)
+						return
+					}
+					principal = auth.Principal{
+						Method:     auth.MethodJWT,
+						Subject:    claims.Subject,
+						Issuer:     claims.Issuer,
+						MerchantID: claims.MerchantID,
+						Scopes:     merchantScopes(claims.Scopes),
+					}
+					break
+				}
+				key, err := apiKeys.Authenticate(r.Context(), bearer)
+				if !authenticated(w, err, logger) {
+					return
+				}
+				principal = keyPrincipal(key)
+			default:
 				logger.Warn().Msg("‼️ Bearer token not found in header")
-				response.Failed(w, 401, "authentication", "tryAuthentication", "Unauthorized")
+				response.Error(w, "authentication", "tryAuthentication", "Authentication", errMissingCredentials)
 				return
 			}
 
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			if token != "connect123" {
-				logger.Warn().Msg("‼️ Bearer token not authorized")
-				response.Failed(w, 403, "authentication", "tryAuthentication", "Forbidden")
+			logger.Debug().
+				Str("auth_method", principal.Method).
+				Str("subject", principal.Subject).
+				Str("merchant_id", principal.MerchantID.String()).
+				Strs("scopes", principal.Scopes).
+				Msg("🔐 Request authenticated")
+			ctx := tenant.WithMerchantID(r.Context(), principal.MerchantID)
+			ctx = auth.WithPrincipal(ctx, principal)
+			next(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// authenticated answers the request when an API key lookup failed and reports whether
+// it succeeded.
+func authenticated(w http.ResponseWriter, err error, logger zerolog.Logger) bool {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, entity.ErrInvalidAPIKey) {
+		logger.Warn().Msg("‼️ API key not authorized")
+		response.Error(w, "authentication", "tryAuthentication", "Authentication", entity.ErrInvalidAPIKey)
+		return false
+	}
+	if errors.Is(err, entity.ErrInvalidSignature) {
+		logger.Warn().Msg("‼️ Request signature not authorized")
+		response.Error(w, "authentication", "tryAuthentication", "Authentication", entity.ErrInvalidSignature)
+		return false
+	}
+	logger.Error().Err(err).Msg("❌ Failed to authenticate API key")
+	response.Error(w, "authentication", "tryAuthentication", "Authentication", err)
+	return false
+}
+
+func keyPrincipal(key *entity.APIKey) auth.Principal {
+	method := auth.MethodAPIKey
+	if key.Type == entity.APIKeyTypeHMAC {
+		method = auth.MethodHMAC
+	}
+	return auth.Principal{
+		Method:     method,
+		Subject:    key.ID.String(),
+		Name:       key.Name,
+		MerchantID: key.MerchantID,
+		Scopes:     key.Scopes,
+	}
+}
+
+// merchantScopes drops the operator scope from the scopes of a token. Tokens are issued
+// to a merchant's users by an identity provider, so only operator API keys carry it.
+func merchantScopes(scopes []string) []string {
+	kept := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if scope != entity.ScopeOperator {
+			kept = append(kept, scope)
+		}
+	}
+	return kept
+}
+
+// ScopeMiddleware lets through callers granted scope and answers 403 naming the missing
+// scope otherwise. It runs after AuthMiddleware.
+func ScopeMiddleware(scope string, logger zerolog.Logger) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok || !principal.HasScope(scope) {
+				logger.Warn().Str("subject", principal.Subject).Str("scope", scope).Msg("‼️ Caller lacks the required scope")
+				response.Error(w, "authorization", "tryAuthorization", "Authorization", apperror.Forbidden("missing_scope", "missing scope "+scope))
 				return
 			}
-
 			next(w, r)
 		}
 	}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/apikey"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/adf-code/beta-payment-api/pkg/hmacsign"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// stubKeyRepo serves keys by prefix; the other methods are never called.
+type stubKeyRepo struct {
+	repository.APIKeyRepository
+	keys map[string]*entity.APIKey
+}
+
+func (s stubKeyRepo) FetchByPrefix(_ context.Context, prefix string) (*entity.APIKey, error) {
+	if k, ok := s.keys[prefix]; ok {
+		return k, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s stubKeyRepo) ModifyLastUsed(context.Context, uuid.UUID) error {
+	return nil
+}
+
+const testMaxSkew = 5 * time.Minute
+
+func TestSignedRequests(t *testing.T) {
+	signatures := apikey.NewSignatureVerifier([]byte("test-master-secret-of-32-bytes!!"), testMaxSkew)
+	key := &entity.APIKey{ID: uuid.New(), MerchantID: uuid.New(), Type: entity.APIKeyTypeHMAC, Prefix: "bpk_hmac1", Scopes: []string{entity.ScopePaymentsWrite}}
+	apiKeys := usecase.NewAPIKeyUseCase(stubKeyRepo{keys: map[string]*entity.APIKey{key.Prefix: key}}, apikey.NewCache(time.Minute), signatures, zerolog.Nop())
+	secret := signatures.Secret(key.Prefix)
+
+	var served []string
+	handler := AuthMiddleware(apiKeys, nil, zerolog.Nop())(func(w http.ResponseWriter, r *http.Request) {
+		served = append(served, r.Header.Get("Authorization"))
+	})
+	serve := func(req *http.Request) int {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+	newRequest := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/api/v1/payments?b=2&a=1", strings.NewReader(body))
+	}
+	// signedAt signs a request as of a chosen time, which SignRequest cannot do.
+	signedAt := func(body string, at time.Time) *http.Request {
+		req := newRequest(body)
+		p := hmacsign.Params{KeyID: key.Prefix, Timestamp: at.Unix(), Nonce: uuid.NewString()}
+		p.Signature = hmacsign.Signature(secret, hmacsign.StringToSign(req.Method, req.URL.EscapedPath(), req.URL.RawQuery, hmacsign.BodyDigest([]byte(body)), p.Timestamp, p.Nonce))
+		req.Header.Set("Authorization", p.String())
+		return req
+	}
+
+	t.Run("signed request", func(t *testing.T) {
+		req := newRequest(`{"amount":"15000"}`)
+		if err := hmacsign.SignRequest(req, key.Prefix, secret); err != nil {
+			t.Fatalf("SignRequest: %v", err)
+		}
+		if code := serve(req); code != http.StatusOK {
+			t.Fatalf("status %d, want 200", code)
+		}
+
+		// The same signed request sent again reuses its nonce
+		replay := newRequest(`{"amount":"15000"}`)
+		replay.Header.Set("Authorization", req.Header.Get("Authorization"))
+		if code := serve(replay); code != http.StatusUnauthorized {
+			t.Errorf("replayed request: status %d, want 401", code)
+		}
+	})
+
+	t.Run("body tampered after signing", func(t *testing.T) {
+		req := newRequest(`{"amount":"15000"}`)
+		if err := hmacsign.SignRequest(req, key.Prefix, secret); err != nil {
+			t.Fatalf("SignRequest: %v", err)
+		}
+		tampered := newRequest(`{"amount":"95000"}`)
+		tampered.Header.Set("Authorization", req.Header.Get("Authorization"))
+		if code := serve(tampered); code != http.StatusUnauthorized {
+			t.Errorf("status %d, want 401", code)
+		}
+	})
+
+	t.Run("query tampered after signing", func(t *testing.T) {
+		req := newRequest("")
+		if err := hmacsign.SignRequest(req, key.Prefix, secret); err != nil {
+			t.Fatalf("SignRequest: %v", err)
+		}
+		tampered := httptest.NewRequest(http.MethodPost, "/api/v1/payments?b=3&a=1", nil)
+		tampered.Header.Set("Authorization", req.Header.Get("Authorization"))
+		if code := serve(tampered); code != http.StatusUnauthorized {
+			t.Errorf("status %d, want 401", code)
+		}
+	})
+
+	now := time.Now()
+	for _, tc := range []struct {
+		name   string
+		at     time.Time
+		status int
+	}{
+		{name: "signed within skew in the past", at: now.Add(-testMaxSkew / 2), status: http.StatusOK},
+		{name: "signed within skew in the future", at: now.Add(testMaxSkew / 2), status: http.StatusOK},
+		{name: "signed beyond skew in the past", at: now.Add(-2 * testMaxSkew), status: http.StatusUnauthorized},
+		{name: "signed beyond skew in the future", at: now.Add(2 * testMaxSkew), status: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := serve(signedAt(`{"amount":"15000"}`, tc.at)); code != tc.status {
+				t.Errorf("status %d, want %d", code, tc.status)
+			}
+		})
+	}
+
+	if len(served) != 3 {
+		t.Errorf("served %d requests, want the 3 accepted ones", len(served))
+	}
+}
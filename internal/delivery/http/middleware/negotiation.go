@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+)
+
+// NegotiationMiddleware lets clients ask for RFC 9457 Problem Details with
+// "Accept: application/problem+json"; error responses default to the envelope.
+func NegotiationMiddleware() Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			next(response.Negotiate(w, r), r)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/pkg/auth"
+	"github.com/adf-code/beta-payment-api/internal/ratelimit"
+	"github.com/rs/zerolog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+var errRateLimited = apperror.RateLimited("rate_limited", "rate limit exceeded, retry after the Retry-After seconds")
+
+type rateLimitBucket struct {
+	key   string
+	limit *ratelimit.Limit
+}
+
+// IPRateLimitMiddleware limits requests per client IP, before authentication costs a
+// database lookup. Public routes pass their class, which is then also counted per IP.
+func IPRateLimitMiddleware(limiter *ratelimit.Limiter, class string, logger zerolog.Logger) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		if limiter == nil {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) {
+			ip := remoteIP(r)
+			buckets := []rateLimitBucket{{key: "ip:" + ip, limit: limiter.PerIP}}
+			if limit, ok := limiter.Classes[class]; ok && class != "" {
+				buckets = append(buckets, rateLimitBucket{key: "class:" + class + ":ip:" + ip, limit: &limit})
+			}
+			if takeRateLimit(w, r, limiter, buckets, logger) {
+				next(w, r)
+			}
+		}
+	}
+}
+
+// ClientRateLimitMiddleware limits requests per authenticated caller, overall and per
+// route class. It runs after AuthMiddleware.
+func ClientRateLimitMiddleware(limiter *ratelimit.Limiter, class string, logger zerolog.Logger) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		if limiter == nil {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				next(w, r)
+				return
+			}
+			client := principal.Method + ":" + principal.Subject
+			buckets := []rateLimitBucket{{key: "key:" + client, limit: limiter.PerKey}}
+			if limit, ok := limiter.Classes[class]; ok {
+				buckets = append(buckets, rateLimitBucket{key: "class:" + class + ":" + client, limit: &limit})
+			}
+			if takeRateLimit(w, r, limiter, buckets, logger) {
+				next(w, r)
+			}
+		}
+	}
+}
+
+// takeRateLimit takes a token from every bucket and reports whether the request may go
+// on. A request denied by one bucket gets back the tokens it took from the others, so it
+// only counts against the bucket that refused it. The RateLimit headers describe
+// whichever bucket has the fewest requests left. When the store fails, requests are let
+// through rather than failing the whole API.
+func takeRateLimit(w http.ResponseWriter, r *http.Request, limiter *ratelimit.Limiter, buckets []rateLimitBucket, logger zerolog.Logger) bool {
+	taken := make([]rateLimitBucket, 0, len(buckets))
+	for _, b := range buckets {
+		if b.limit == nil {
+			continue
+		}
+		res, err := limiter.Store.Take(r.Context(), b.key, *b.limit)
+		if err != nil {
+			logger.Error().Err(err).Msg("❌ Failed to check rate limit, letting request through")
+			continue
+		}
+		setRateLimitHeaders(w, res)
+		if !res.Allowed {
+			refundRateLimit(r, limiter, taken, logger)
+			retryAfter := int(math.Ceil(res.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			logger.Warn().Str("bucket", b.key).Msg("‼️ Rate limit exceeded")
+			response.Error(w, "rateLimit", "tryRateLimit", "Rate Limit", errRateLimited)
+			return false
+		}
+		taken = append(taken, b)
+	}
+	return true
+}
+
+func refundRateLimit(r *http.Request, limiter *ratelimit.Limiter, taken []rateLimitBucket, logger zerolog.Logger) {
+	for _, b := range taken {
+		if err := limiter.Store.Refund(r.Context(), b.key, *b.limit); err != nil {
+			logger.Error().Err(err).Str("bucket", b.key).Msg("❌ Failed to refund rate limit token")
+		}
+	}
+}
+
+func setRateLimitHeaders(w http.ResponseWriter, res ratelimit.Result) {
+	if current, err := strconv.Atoi(w.Header().Get("RateLimit-Remaining")); err == nil && current <= res.Remaining && res.Allowed {
+		return
+	}
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil(res.Reset.Seconds()))))
+}
+
+// remoteIP is the peer address of the request. Forwarding headers are ignored because
+// clients can set them freely.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/ratelimit"
+	"github.com/rs/zerolog"
+)
+
+func TestRateLimitRefundsBucketsOfDeniedRequests(t *testing.T) {
+	limiter := &ratelimit.Limiter{
+		Store:   ratelimit.NewMemoryStore(),
+		PerIP:   &ratelimit.Limit{Requests: 5, Window: time.Hour},
+		Classes: map[string]ratelimit.Limit{"public": {Requests: 1, Window: time.Hour}},
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	public := IPRateLimitMiddleware(limiter, "public", zerolog.Nop())(ok)
+	plain := IPRateLimitMiddleware(limiter, "", zerolog.Nop())(ok)
+
+	for _, tc := range []struct {
+		name      string
+		handler   http.HandlerFunc
+		status    int
+		remaining string
+	}{
+		{name: "first public request", handler: public, status: 200, remaining: "0"},
+		{name: "public class exhausted", handler: public, status: 429, remaining: "0"},
+		{name: "public class still exhausted", handler: public, status: 429, remaining: "0"},
+		// Only the first request counts against the IP: the denied ones were refunded
+		{name: "ip bucket", handler: plain, status: 200, remaining: "3"},
+	} {
+		rec := httptest.NewRecorder()
+		tc.handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != tc.status {
+			t.Errorf("%s: status %d, want %d", tc.name, rec.Code, tc.status)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != tc.remaining {
+			t.Errorf("%s: RateLimit-Remaining %q, want %q", tc.name, got, tc.remaining)
+		}
+	}
+}
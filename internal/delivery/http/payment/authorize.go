@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// AuthorizePayment godoc
+// @Summary      Authorize a payment
+// @Description  Sends a pending payment to the provider selected by the routing rules and reserves the amount
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                           true   "UUID of the payment"
+// @Param        request  body      request.AuthorizePaymentRequest  false  "Payment instrument"
+// @Success      200      {object}  response.APIResponse
+// @Failure      402      {object}  response.APIResponse  "Payment declined"
+// @Failure      404      {object}  response.APIResponse  "Payment not found"
+// @Failure      409      {object}  response.APIResponse  "Invalid payment status"
+// @Failure      422      {object}  response.APIResponse
+// @Failure      502      {object}  response.APIResponse  "Provider error"
+// @Failure      504      {object}  response.APIResponse  "Provider timeout"
+// @Router       /api/v1/payments/{id}/authorize [post]
+func (h *PaymentHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Authorize request")
+	id, ok := h.parsePaymentID(w, r, "authorizePayment", "Authorize Payment")
+	if !ok {
+		return
+	}
+
+	var req request.AuthorizePaymentRequest
+	if err := validation.DecodeOptional(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "payments", "authorizePayment", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "payments", "authorizePayment", err)
+		return
+	}
+
+	payment, err := h.PaymentUC.Authorize(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "authorizePayment", "Authorize Payment", err)
+		return
+	}
+	h.Logger.Info().Str("id", id.String()).Str("status", payment.Status).Msg("✅ Successfully authorized payment")
+	response.Success(w, 200, "payments", "authorizePayment", "Success Authorize Payment", payment)
+}
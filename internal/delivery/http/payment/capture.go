@@ -0,0 +1,37 @@
+package payment
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// CapturePayment godoc
+// @Summary      Capture a payment
+// @Description  Captures the authorized amount of a payment, records its settlement amount and marks it as paid
+// @Tags         payments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the payment"
+// @Success      200  {object}  response.APIResponse
+// @Failure      402  {object}  response.APIResponse  "Capture declined"
+// @Failure      404  {object}  response.APIResponse  "Payment not found"
+// @Failure      409  {object}  response.APIResponse  "Invalid payment status"
+// @Failure      422  {object}  response.APIResponse  "No fx rate for settlement currency"
+// @Failure      502  {object}  response.APIResponse  "Provider error"
+// @Failure      504  {object}  response.APIResponse  "Provider timeout"
+// @Router       /api/v1/payments/{id}/capture [post]
+func (h *PaymentHandler) Capture(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Capture request")
+	id, ok := h.parsePaymentID(w, r, "capturePayment", "Capture Payment")
+	if !ok {
+		return
+	}
+
+	payment, err := h.PaymentUC.Capture(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "capturePayment", "Capture Payment", err)
+		return
+	}
+	h.Logger.Info().Str("id", id.String()).Msg("✅ Successfully captured payment")
+	response.Success(w, 200, "payments", "capturePayment", "Success Capture Payment", payment)
+}
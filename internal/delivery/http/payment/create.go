@@ -1,21 +1,21 @@
 package payment
 
 import (
-	"encoding/json"
 	"fmt"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
 	"github.com/adf-code/beta-payment-api/internal/delivery/response"
-	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
 	"net/http"
 )
 
 // CreatePayment godoc
 // @Summary      Create a new payment
-// @Description  Creates a new payment with the status auto generate to pending
+// @Description  Creates a new payment with the status auto generate to pending, or REVIEW when the risk rules hold it. Payments denied by the risk rules fail with 422 and their reason code
 // @Tags         payments
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        request  body      entity.Payment  true  "Payment data to create"
+// @Param        request  body      request.CreatePaymentRequest  true  "Payment data to create"
 // @Success      201      {object}  response.APIResponse
 // @Failure      400      {object}  response.APIResponse
 // @Failure      401      {object}  response.APIResponse
@@ -24,17 +24,24 @@ import (
 // @Router       /api/v1/payments [post]
 func (h *PaymentHandler) Create(w http.ResponseWriter, r *http.Request) {
 	h.Logger.Info().Msg("📥 Incoming Create request")
-	var payment entity.Payment
-	if err := json.NewDecoder(r.Body).Decode(&payment); err != nil {
-		h.Logger.Error().Err(err).Msg("❌ Failed to store payment, invalid data")
-		response.Failed(w, 422, "payments", "createPayment", "Invalid Data, Create Payment")
+	var req request.CreatePaymentRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "payments", "createPayment", err)
 		return
 	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "payments", "createPayment", err)
+		return
+	}
+
+	payment := req.ToEntity()
+	payment.ClientIP = clientIP(r)
 
 	newPayment, err := h.PaymentUC.Create(r.Context(), payment)
 	if err != nil {
-		h.Logger.Error().Err(err).Msg("❌ Failed to store payment, general")
-		response.Failed(w, 500, "payments", "createPayment", "Error Create Payment")
+		response.ErrorLogged(w, h.Logger, "payments", "createPayment", "Create Payment", err)
 		return
 	}
 	h.Logger.Info().Str("data", fmt.Sprint(newPayment)).Msg("✅ Successfully stored payment")
@@ -34,8 +34,7 @@ func (h *PaymentHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.PaymentUC.Delete(r.Context(), id); err != nil {
-		h.Logger.Error().Err(err).Msg("❌ Failed to remove payment, general")
-		response.Failed(w, 500, "payments", "deletePaymentByID", "Error Delete Payment")
+		response.ErrorLogged(w, h.Logger, "payments", "deletePaymentByID", "Delete Payment", err)
 		return
 	}
 	h.Logger.Info().Msg("✅ Successfully removed payment")
@@ -0,0 +1,26 @@
+package payment
+
+import (
+	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/google/uuid"
+)
+
+// parsePaymentID reads and validates the {id} path parameter.
+func (h *PaymentHandler) parsePaymentID(w http.ResponseWriter, r *http.Request, state, action string) (uuid.UUID, bool) {
+	idStr := router.GetParam(r, "id")
+	if idStr == "" {
+		h.Logger.Error().Msg("❌ Failed to " + action + ", missing ID parameter")
+		response.Failed(w, 422, "payments", state, "Missing ID Parameter, "+action)
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to " + action + ", invalid UUID parameter")
+		response.Failed(w, 422, "payments", state, "Invalid UUID, "+action)
+		return uuid.Nil, false
+	}
+	return id, true
+}
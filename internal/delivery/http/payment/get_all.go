@@ -13,9 +13,15 @@ import (
 // @Accept       json
 // @Produce      json
 //
+// --- Customer Query ---
+// @Param        customer_id       query    string   false  "Filter by customer UUID"
+//
+// --- Metadata Query ---
+// @Param        metadata.{key}    query    string   false  "Filter by metadata value, e.g. metadata.order_id=123"
+//
 // --- Search Query ---
-// @Param        search_field      query    string   false  "Search field (e.g., title)"
-// @Param        search_value      query    string   false  "Search value (e.g., golang)"
+// @Param        search_field      query    string   false  "Search field: tag, description or provider_reference"
+// @Param        search_value      query    string   false  "Search value"
 //
 // --- Filter Search Query ---
 // @Param filter_field query []string false "Filter field" collectionFormat(multi) explode(true)
@@ -27,7 +33,7 @@ import (
 // @Param to          query []string false "Range upper bound" collectionFormat(multi) explode(true)
 //
 // --- Pagination & Sort ---
-// @Param        sort_field        query    string   false  "Sort field"
+// @Param        sort_field        query    string   false  "Sort field: amount, currency, method, status, tag, created_at or updated_at"
 // @Param        sort_direction    query    string   false  "Sort direction ASC/DESC"
 // @Param        page              query    int      false  "Page number"
 // @Param        per_page          query    int      false  "Limit per page"
@@ -35,11 +41,17 @@ import (
 // @Security     BearerAuth
 //
 // @Success      200     {object}  response.APIResponse
+// @Failure      422     {object}  response.APIResponse
 // @Failure      500     {object}  response.APIResponse
 // @Router       /api/v1/payments [get]
 func (h *PaymentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	h.Logger.Info().Msg("📥 Incoming GetAll request")
-	params := request.ParsePaymentQueryParams(r)
+	params, err := request.ParsePaymentQueryParams(r)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "payments", "getAllPayments", err)
+		return
+	}
 	payments, err := h.PaymentUC.GetAll(r.Context(), params)
 	if err != nil {
 		h.Logger.Error().Err(err).Msg("❌ Failed to fetch payments, general")
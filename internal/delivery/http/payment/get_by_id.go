@@ -1,8 +1,6 @@
 package payment
 
 import (
-	"database/sql"
-	"errors"
 	"fmt"
 	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
 	"github.com/adf-code/beta-payment-api/internal/delivery/response"
@@ -39,13 +37,7 @@ func (h *PaymentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 	payment, err := h.PaymentUC.GetByID(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			h.Logger.Info().Msg("✅ Successfully get payment by id, data not found")
-			response.Success(w, 404, "payments", "getPaymentByID", "Payment not Found", nil)
-			return
-		}
-		h.Logger.Error().Err(err).Msg("❌ Failed to get payment by ID, general")
-		response.Failed(w, 500, "payments", "getPaymentByID", "Error Get Payment by ID")
+		response.ErrorLogged(w, h.Logger, "payments", "getPaymentByID", "Get Payment by ID", err)
 		return
 	}
 	h.Logger.Info().Str("data", fmt.Sprint(payment.ID)).Msg("✅ Successfully get payment by id")
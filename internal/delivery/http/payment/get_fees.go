@@ -0,0 +1,33 @@
+package payment
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetPaymentFees godoc
+// @Summary      Get fees of a payment
+// @Description  List the fee line items charged when the payment was captured
+// @Tags         payments
+// @Produce      json
+// @Param        id   path      string  true  "UUID of the payment"
+// @Security     BearerAuth
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse  "Payment not found"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID"
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/payments/{id}/fees [get]
+func (h *PaymentHandler) GetFees(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetFees payment request")
+	id, ok := h.parsePaymentID(w, r, "getPaymentFees", "Get Payment Fees")
+	if !ok {
+		return
+	}
+	fees, err := h.PaymentUC.GetFees(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "getPaymentFees", "Get Payment Fees", err)
+		return
+	}
+	h.Logger.Info().Int("count", len(fees)).Msg("✅ Successfully fetched payment fees")
+	response.Success(w, 200, "payments", "getPaymentFees", "Success Get Payment Fees", fees)
+}
@@ -3,6 +3,8 @@ package payment
 import (
 	"github.com/adf-code/beta-payment-api/internal/usecase"
 	"github.com/rs/zerolog"
+	"net"
+	"net/http"
 )
 
 type PaymentHandler struct {
@@ -13,3 +15,12 @@ type PaymentHandler struct {
 func NewPaymentHandler(paymentUC usecase.PaymentUseCase, logger zerolog.Logger) *PaymentHandler {
 	return &PaymentHandler{PaymentUC: paymentUC, Logger: logger}
 }
+
+// clientIP is the address of the peer that sent the request, which risk rules key on.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
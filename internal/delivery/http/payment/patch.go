@@ -0,0 +1,51 @@
+package payment
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// PatchPaymentByID godoc
+// @Summary      Patch payment by ID
+// @Description  Partially update the description and metadata of a payment. Metadata keys are merged; a null value removes the key
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                       true  "UUID of the payment"
+// @Param        request  body      request.PatchPaymentRequest  true  "Fields to update"
+// @Success      200      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse  "Invalid request body"
+// @Failure      404      {object}  response.APIResponse  "Payment not found"
+// @Failure      422      {object}  response.APIResponse  "Validation error"
+// @Failure      500      {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/payments/{id} [patch]
+func (h *PaymentHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Patch request")
+	id, ok := h.parsePaymentID(w, r, "patchPaymentByID", "Patch Payment by ID")
+	if !ok {
+		return
+	}
+
+	var req request.PatchPaymentRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "payments", "patchPaymentByID", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "payments", "patchPaymentByID", err)
+		return
+	}
+
+	payment, err := h.PaymentUC.Patch(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "patchPaymentByID", "Patch Payment", err)
+		return
+	}
+	h.Logger.Info().Str("id", id.String()).Msg("✅ Successfully patched payment")
+	response.Success(w, 200, "payments", "patchPaymentByID", "Payment Patched", payment)
+}
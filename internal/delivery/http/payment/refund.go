@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// RefundPayment godoc
+// @Summary      Refund a payment
+// @Description  Refunds a captured payment fully, or partially when an amount is given
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                        true   "UUID of the payment"
+// @Param        request  body      request.RefundPaymentRequest  false  "Refund amount"
+// @Success      200      {object}  response.APIResponse
+// @Failure      402      {object}  response.APIResponse  "Refund declined"
+// @Failure      404      {object}  response.APIResponse  "Payment not found"
+// @Failure      409      {object}  response.APIResponse  "Invalid payment status"
+// @Failure      422      {object}  response.APIResponse
+// @Failure      502      {object}  response.APIResponse  "Provider error"
+// @Failure      504      {object}  response.APIResponse  "Provider timeout"
+// @Router       /api/v1/payments/{id}/refund [post]
+func (h *PaymentHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Refund request")
+	id, ok := h.parsePaymentID(w, r, "refundPayment", "Refund Payment")
+	if !ok {
+		return
+	}
+
+	var req request.RefundPaymentRequest
+	if err := validation.DecodeOptional(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "payments", "refundPayment", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "payments", "refundPayment", err)
+		return
+	}
+
+	payment, err := h.PaymentUC.Refund(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "refundPayment", "Refund Payment", err)
+		return
+	}
+	h.Logger.Info().Str("id", id.String()).Str("status", payment.Status).Msg("✅ Successfully refunded payment")
+	response.Success(w, 200, "payments", "refundPayment", "Success Refund Payment", payment)
+}
@@ -0,0 +1,51 @@
+package payment
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// ReviewPayment godoc
+// @Summary      Review a held payment
+// @Description  Approves a payment held in REVIEW by the risk rules, moving it to PENDING, or rejects it, moving it to CANCELLED
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                        true  "UUID of the payment"
+// @Param        request  body      request.ReviewPaymentRequest  true  "Review decision"
+// @Success      200      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse  "Payment not found"
+// @Failure      409      {object}  response.APIResponse  "Payment is not in review"
+// @Failure      422      {object}  response.APIResponse
+// @Router       /api/v1/payments/{id}/review [post]
+func (h *PaymentHandler) Review(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Review request")
+	id, ok := h.parsePaymentID(w, r, "reviewPayment", "Review Payment")
+	if !ok {
+		return
+	}
+
+	var req request.ReviewPaymentRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "payments", "reviewPayment", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "payments", "reviewPayment", err)
+		return
+	}
+
+	payment, err := h.PaymentUC.Review(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "reviewPayment", "Review Payment", err)
+		return
+	}
+	h.Logger.Info().Str("id", id.String()).Str("status", payment.Status).Msg("✅ Successfully reviewed payment")
+	response.Success(w, 200, "payments", "reviewPayment", "Success Review Payment", payment)
+}
@@ -0,0 +1,40 @@
+package payment
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// SummaryPayments godoc
+// @Summary      Summarize payments
+// @Description  Totals payments per currency and status and split shares per recipient, optionally converted to a reporting currency
+// @Tags         payments
+// @Produce      json
+// @Param        customer_id         query    string   false  "Filter by customer UUID"
+// @Param        from                query    string   false  "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param        to                  query    string   false  "Created at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param        reporting_currency  query    string   false  "Currency to report totals in"
+// @Param        as_of               query    string   false  "Time the reporting rates are taken at, defaults to now"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      422     {object}  response.APIResponse
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/payments/summary [get]
+func (h *PaymentHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Summary payments request")
+	params, err := request.ParsePaymentSummaryQueryParams(r)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Failed(w, 422, "payments", "summaryPayments", "Validation Error, "+err.Error())
+		return
+	}
+
+	summary, err := h.PaymentUC.Summary(r.Context(), params)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "summaryPayments", "Summary Payments", err)
+		return
+	}
+	h.Logger.Info().Int("groups", len(summary.Groups)).Msg("✅ Successfully summarized payments")
+	response.Success(w, 200, "payments", "summaryPayments", "Success Summary Payments", summary)
+}
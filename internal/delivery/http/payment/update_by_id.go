@@ -1,12 +1,10 @@
 package payment
 
 import (
-	"database/sql"
-	"encoding/json"
-	"errors"
 	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
 	"github.com/adf-code/beta-payment-api/internal/delivery/request"
 	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
 	"github.com/google/uuid"
 	"net/http"
 )
@@ -41,27 +39,21 @@ func (h *PaymentHandler) UpdateByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req request.UpdatePaymentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := validation.Decode(w, r, &req); err != nil {
 		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
-		response.Failed(w, 400, "payments", "updatePaymentByID", "Invalid Request Body")
+		response.Invalid(w, "payments", "updatePaymentByID", err)
 		return
 	}
 
 	if err := req.Validate(); err != nil {
 		h.Logger.Error().Err(err).Msg("❌ Validation error")
-		response.Failed(w, 422, "payments", "updatePaymentByID", "Validation Error")
+		response.Invalid(w, "payments", "updatePaymentByID", err)
 		return
 	}
 
 	updatedPayment, err := h.PaymentUC.UpdateByID(r.Context(), id, &req)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			h.Logger.Info().Msg("✅ Payment not found for update")
-			response.Success(w, 404, "payments", "updatePaymentByID", "Payment not Found", nil)
-			return
-		}
-		h.Logger.Error().Err(err).Msg("❌ Update failed")
-		response.Failed(w, 500, "payments", "updatePaymentByID", "Failed to Update Payment")
+		response.ErrorLogged(w, h.Logger, "payments", "updatePaymentByID", "Update Payment", err)
 		return
 	}
 
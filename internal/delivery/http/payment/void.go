@@ -0,0 +1,36 @@
+package payment
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// VoidPayment godoc
+// @Summary      Void a payment
+// @Description  Releases an authorized payment that has not been captured yet
+// @Tags         payments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the payment"
+// @Success      200  {object}  response.APIResponse
+// @Failure      402  {object}  response.APIResponse  "Void declined"
+// @Failure      404  {object}  response.APIResponse  "Payment not found"
+// @Failure      409  {object}  response.APIResponse  "Invalid payment status"
+// @Failure      502  {object}  response.APIResponse  "Provider error"
+// @Failure      504  {object}  response.APIResponse  "Provider timeout"
+// @Router       /api/v1/payments/{id}/void [post]
+func (h *PaymentHandler) Void(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Void request")
+	id, ok := h.parsePaymentID(w, r, "voidPayment", "Void Payment")
+	if !ok {
+		return
+	}
+
+	payment, err := h.PaymentUC.Void(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "voidPayment", "Void Payment", err)
+		return
+	}
+	h.Logger.Info().Str("id", id.String()).Msg("✅ Successfully voided payment")
+	response.Success(w, 200, "payments", "voidPayment", "Success Void Payment", payment)
+}
@@ -0,0 +1,48 @@
+package paymentlink
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+)
+
+// Checkout renders the hosted checkout page of a payment link. It is public: the signed
+// token in the path is the only credential.
+func (h *PaymentLinkHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Checkout page request")
+	token := router.GetParam(r, "token")
+	link, err := h.PaymentLinkUC.Checkout(r.Context(), token)
+	if err != nil {
+		h.renderLinkError(w, err)
+		return
+	}
+
+	data := newPageData("Checkout", link.Payment)
+	data.Token = token
+	data.ExpiresAt = link.ExpiresAt.UTC().Format(time.RFC1123)
+	data.NeedsCard = link.Payment.Method == entity.PaymentMethodCard
+	h.renderPage(w, http.StatusOK, "checkout.html", data)
+}
+
+// renderLinkError shows why a link cannot be used without telling apart a forged token
+// from a link that never existed.
+func (h *PaymentLinkHandler) renderLinkError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidPaymentLinkToken), errors.Is(err, sql.ErrNoRows):
+		h.Logger.Warn().Err(err).Msg("‼️ Invalid payment link token")
+		h.renderFailure(w, http.StatusNotFound, "Link not found", "This payment link is not valid.")
+	case errors.Is(err, entity.ErrPaymentLinkExpired):
+		h.Logger.Info().Msg("⚠️ Payment link expired")
+		h.renderFailure(w, http.StatusGone, "Link expired", "This payment link has expired. Please ask the merchant for a new one.")
+	case errors.Is(err, entity.ErrPaymentLinkUnavailable):
+		h.Logger.Info().Msg("⚠️ Payment link already used or revoked")
+		h.renderFailure(w, http.StatusGone, "Link no longer available", "This payment link has already been used or was cancelled.")
+	default:
+		h.Logger.Error().Err(err).Msg("❌ Failed to resolve payment link, general")
+		h.renderFailure(w, http.StatusInternalServerError, "Something went wrong", "We could not load this payment. Please try again later.")
+	}
+}
@@ -0,0 +1,56 @@
+package paymentlink
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/provider"
+)
+
+// Confirm pays the link's payment with the details posted from the checkout page and
+// renders the outcome.
+func (h *PaymentLinkHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Confirm payment link request")
+	token := router.GetParam(r, "token")
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := r.ParseForm(); err != nil {
+		h.renderFailure(w, http.StatusBadRequest, "Payment not completed", "The payment details could not be read.")
+		return
+	}
+	req := request.AuthorizePaymentRequest{CardNumber: r.PostForm.Get("card_number")}
+	if err := req.Validate(); err != nil {
+		h.renderFailure(w, http.StatusUnprocessableEntity, "Payment not completed", "Please check your card number and try again.")
+		return
+	}
+
+	link, err := h.PaymentLinkUC.Confirm(r.Context(), token, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, provider.ErrDeclined):
+			h.Logger.Warn().Err(err).Msg("⚠️ Payment link payment declined")
+			h.renderFailure(w, http.StatusPaymentRequired, "Payment declined", "Your payment was declined.")
+		case errors.Is(err, entity.ErrInvalidStatusTransition):
+			h.Logger.Warn().Err(err).Msg("⚠️ Payment link payment no longer payable")
+			h.renderFailure(w, http.StatusConflict, "Payment not completed", "This payment can no longer be paid.")
+		case errors.Is(err, entity.ErrInvalidPaymentLinkToken), errors.Is(err, entity.ErrPaymentLinkExpired), errors.Is(err, entity.ErrPaymentLinkUnavailable), errors.Is(err, sql.ErrNoRows):
+			h.renderLinkError(w, err)
+		default:
+			h.Logger.Error().Err(err).Msg("❌ Failed to confirm payment link, general")
+			h.renderFailure(w, http.StatusBadGateway, "Payment not completed", "We could not reach the payment provider. Please try again.")
+		}
+		return
+	}
+
+	data := newPageData("Thank you", link.Payment)
+	data.Message = "Your payment has been received."
+	if link.Payment.Status != entity.PaymentStatusPaid {
+		data.Title = "Payment processing"
+		data.Message = "Your payment is being processed. You can close this page."
+	}
+	h.Logger.Info().Str("data", link.ID.String()).Str("status", link.Payment.Status).Msg("✅ Successfully confirmed payment link")
+	h.renderPage(w, http.StatusOK, "result.html", data)
+}
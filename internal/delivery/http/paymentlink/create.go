@@ -0,0 +1,44 @@
+package paymentlink
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// CreatePaymentLink godoc
+// @Summary      Create a payment link
+// @Description  Creates a PENDING payment and returns a signed, expiring, single-use checkout URL for it
+// @Tags         payment-links
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.CreatePaymentLinkRequest  true  "Payment to collect"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/payment-links [post]
+func (h *PaymentLinkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Create payment link request")
+	var req request.CreatePaymentLinkRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "paymentLinks", "createPaymentLink", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "paymentLinks", "createPaymentLink", err)
+		return
+	}
+
+	link, err := h.PaymentLinkUC.Create(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "paymentLinks", "createPaymentLink", "Create Payment Link", err)
+		return
+	}
+	h.Logger.Info().Str("data", link.ID.String()).Msg("✅ Successfully stored payment link")
+	response.Success(w, 201, "paymentLinks", "createPaymentLink", "Success Create Payment Link", link)
+}
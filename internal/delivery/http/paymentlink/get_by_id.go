@@ -0,0 +1,33 @@
+package paymentlink
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetPaymentLinkByID godoc
+// @Summary      Get payment link by ID
+// @Description  Retrieve a payment link with its payment; the URL is included while the link is usable
+// @Tags         payment-links
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the payment link"
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse  "Payment link not found"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID"
+// @Failure      500  {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/payment-links/{id} [get]
+func (h *PaymentLinkHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetByID payment link request")
+	id, ok := h.parsePaymentLinkID(w, r, "getPaymentLinkByID", "Get Payment Link by ID")
+	if !ok {
+		return
+	}
+
+	link, err := h.PaymentLinkUC.GetByID(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "paymentLinks", "getPaymentLinkByID", "Get Payment Link by ID", err)
+		return
+	}
+	h.Logger.Info().Str("data", link.ID.String()).Msg("✅ Successfully get payment link by id")
+	response.Success(w, 200, "paymentLinks", "getPaymentLinkByID", "Success Get Payment Link by ID", link)
+}
@@ -0,0 +1,85 @@
+package paymentlink
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var pages = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+type PaymentLinkHandler struct {
+	PaymentLinkUC usecase.PaymentLinkUseCase
+	Logger        zerolog.Logger
+}
+
+func NewPaymentLinkHandler(paymentLinkUC usecase.PaymentLinkUseCase, logger zerolog.Logger) *PaymentLinkHandler {
+	return &PaymentLinkHandler{PaymentLinkUC: paymentLinkUC, Logger: logger}
+}
+
+// parsePaymentLinkID reads and validates the {id} path parameter.
+func (h *PaymentLinkHandler) parsePaymentLinkID(w http.ResponseWriter, r *http.Request, state, action string) (uuid.UUID, bool) {
+	id, err := router.ParamsOf(r).UUID("id")
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to " + action + ", invalid UUID parameter")
+		response.Failed(w, 422, "paymentLinks", state, "Invalid UUID, "+action)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// pageData is what the checkout and result templates render.
+type pageData struct {
+	Title       string
+	Token       string
+	Description string
+	Currency    string
+	Amount      string
+	ExpiresAt   string
+	NeedsCard   bool
+	Message     string
+	Failed      bool
+}
+
+func newPageData(title string, payment *entity.Payment) pageData {
+	data := pageData{Title: title}
+	if payment != nil {
+		data.Description = payment.Description
+		data.Currency = payment.Currency
+		if payment.Amount.Float != nil {
+			data.Amount = payment.Amount.Text('f', 2)
+		}
+	}
+	return data
+}
+
+// renderPage writes a public checkout page. The token sits in the URL, so pages are never
+// cached and never leak it through the Referer header or third-party resources.
+func (h *PaymentLinkHandler) renderPage(w http.ResponseWriter, code int, name string, data pageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; form-action 'self'; frame-ancestors 'none'")
+	w.WriteHeader(code)
+	if err := pages.ExecuteTemplate(w, name, data); err != nil {
+		h.Logger.Error().Err(err).Str("template", name).Msg("❌ Failed to render checkout page")
+	}
+}
+
+func (h *PaymentLinkHandler) renderFailure(w http.ResponseWriter, code int, title, message string) {
+	data := newPageData(title, nil)
+	data.Message = message
+	data.Failed = true
+	h.renderPage(w, code, "result.html", data)
+}
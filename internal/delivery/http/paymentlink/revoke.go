@@ -0,0 +1,34 @@
+package paymentlink
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// RevokePaymentLink godoc
+// @Summary      Revoke a payment link
+// @Description  Disables an active payment link; its URL stops working immediately
+// @Tags         payment-links
+// @Security     BearerAuth
+// @Param        id   path      string  true  "UUID of the payment link"
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse  "Payment link not found"
+// @Failure      409  {object}  response.APIResponse  "Payment link already used or revoked"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID"
+// @Failure      500  {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/payment-links/{id}/revoke [post]
+func (h *PaymentLinkHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Revoke payment link request")
+	id, ok := h.parsePaymentLinkID(w, r, "revokePaymentLink", "Revoke Payment Link")
+	if !ok {
+		return
+	}
+
+	link, err := h.PaymentLinkUC.Revoke(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "paymentLinks", "revokePaymentLink", "Revoke Payment Link", err)
+		return
+	}
+	h.Logger.Info().Str("data", link.ID.String()).Msg("✅ Successfully revoked payment link")
+	response.Success(w, 200, "paymentLinks", "revokePaymentLink", "Success Revoke Payment Link", link)
+}
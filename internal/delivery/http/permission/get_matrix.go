@@ -0,0 +1,20 @@
+package permission
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetPermissionMatrix godoc
+// @Summary      Get the route permission matrix
+// @Description  Lists every route with the scope it requires, and the routes each scope opens. Requires the admin scope
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.APIResponse
+// @Failure      403  {object}  response.APIResponse
+// @Router       /api/v1/admin/permissions [get]
+func (h *PermissionHandler) GetMatrix(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetMatrix permissions request")
+	response.Success(w, 200, "permissions", "getPermissionMatrix", "Success Get Permission Matrix", h.Matrix)
+}
@@ -0,0 +1,27 @@
+package permission
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/rs/zerolog"
+)
+
+type PermissionHandler struct {
+	Matrix entity.PermissionMatrix
+	Logger zerolog.Logger
+}
+
+func NewPermissionHandler(logger zerolog.Logger) *PermissionHandler {
+	return &PermissionHandler{
+		Matrix: entity.PermissionMatrix{Routes: []entity.RoutePermission{}, Scopes: make(map[string][]string)},
+		Logger: logger,
+	}
+}
+
+// Add records a route while SetupHandler registers it; the matrix is read-only once the
+// server runs.
+func (h *PermissionHandler) Add(route entity.RoutePermission) {
+	h.Matrix.Routes = append(h.Matrix.Routes, route)
+	if !route.Public {
+		h.Matrix.Scopes[route.Scope] = append(h.Matrix.Scopes[route.Scope], route.Method+" "+route.Path)
+	}
+}
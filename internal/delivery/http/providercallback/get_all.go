@@ -0,0 +1,34 @@
+package providercallback
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetAllProviderCallbacks godoc
+// @Summary      Get list of provider callbacks
+// @Description  List the provider callbacks matched to the merchant's payments, for inspection. Callbacks that were rejected, invalid or unmatched belong to no merchant and are not listed.
+// @Tags         provider-callbacks
+// @Produce      json
+// @Param        provider         query    string   false  "Provider name"
+// @Param        outcome          query    string   false  "Outcome (APPLIED, UNCHANGED, STALE)"
+// @Param        signature_valid  query    bool     false  "Filter by signature verification result"
+// @Param        page             query    int      false  "Page number"
+// @Param        per_page         query    int      false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/provider-callbacks [get]
+func (h *ProviderCallbackHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetAll provider callbacks request")
+	params := request.ParseProviderCallbackQueryParams(r)
+	callbacks, err := h.ProviderCallbackUC.GetAll(r.Context(), params)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to fetch provider callbacks, general")
+		response.FailedWithMeta(w, 500, "providerCallbacks", "getAllProviderCallbacks", "Error Get All Provider Callbacks", nil)
+		return
+	}
+	h.Logger.Info().Int("count", len(callbacks)).Msg("✅ Successfully fetched provider callbacks")
+	response.SuccessWithMeta(w, 200, "providerCallbacks", "getAllProviderCallbacks", "Success Get All Provider Callbacks", &params, callbacks)
+}
@@ -0,0 +1,15 @@
+package providercallback
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/rs/zerolog"
+)
+
+type ProviderCallbackHandler struct {
+	ProviderCallbackUC usecase.ProviderCallbackUseCase
+	Logger             zerolog.Logger
+}
+
+func NewProviderCallbackHandler(providerCallbackUC usecase.ProviderCallbackUseCase, logger zerolog.Logger) *ProviderCallbackHandler {
+	return &ProviderCallbackHandler{ProviderCallbackUC: providerCallbackUC, Logger: logger}
+}
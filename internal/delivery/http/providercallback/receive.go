@@ -0,0 +1,51 @@
+package providercallback
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"io"
+	"net/http"
+)
+
+const maxCallbackBodyBytes = 1 << 20
+
+// ReceiveProviderCallback godoc
+// @Summary      Receive a provider callback
+// @Description  Verifies the provider signature, deduplicates by provider event ID and applies the reported status to the payment
+// @Tags         provider-callbacks
+// @Accept       json
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name (e.g., simulator)"
+// @Success      200       {object}  response.APIResponse
+// @Failure      400       {object}  response.APIResponse  "Invalid callback payload"
+// @Failure      401       {object}  response.APIResponse  "Invalid signature"
+// @Failure      404       {object}  response.APIResponse  "Unknown provider or payment"
+// @Failure      500       {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/provider-callbacks/{provider} [post]
+func (h *ProviderCallbackHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming provider callback")
+	providerName := router.GetParam(r, "provider")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCallbackBodyBytes))
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to read callback body")
+		response.Failed(w, 400, "providerCallbacks", "receiveProviderCallback", "Invalid Request Body")
+		return
+	}
+
+	callback, err := h.ProviderCallbackUC.Handle(r.Context(), providerName, r.Header, body)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "providerCallbacks", "receiveProviderCallback", "Process Provider Callback", err)
+		return
+	}
+
+	if callback.Outcome == entity.CallbackOutcomeUnmatched {
+		// Not found lets the provider retry once the payment reference is known.
+		h.Logger.Warn().Str("provider", providerName).Msg("⚠️ Callback does not match any payment")
+		response.Success(w, 404, "providerCallbacks", "receiveProviderCallback", "Payment not Found", callback)
+		return
+	}
+	h.Logger.Info().Str("outcome", callback.Outcome).Msg("✅ Successfully processed provider callback")
+	response.Success(w, 200, "providerCallbacks", "receiveProviderCallback", "Success Process Provider Callback", callback)
+}
@@ -0,0 +1,76 @@
+package qris
+
+import (
+	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/qr"
+)
+
+// pngScale is the pixel width of one module; a typical payload renders at roughly 400 pixels.
+const pngScale = 8
+
+// GetPaymentQR godoc
+// @Summary      Get QRIS code of a payment
+// @Description  Render the EMVCo merchant-presented QRIS payload of a pending QRIS payment with its merchant's QRIS profile, as text or as a PNG image
+// @Tags         payments
+// @Produce      plain
+// @Produce      png
+// @Param        id      path      string  true   "UUID of the payment"
+// @Param        format  query     string  false  "text (default) or png"
+// @Security     BearerAuth
+// @Success      200  {string}  string  "EMVCo payload or PNG image"
+// @Failure      404  {object}  response.APIResponse  "Payment not found"
+// @Failure      409  {object}  response.APIResponse  "Payment is not a pending QRIS payment, or its merchant has no QRIS profile"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID or format"
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/payments/{id}/qr [get]
+func (h *QRISHandler) GetPaymentQR(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetPaymentQR request")
+	id, err := router.ParamsOf(r).UUID("id")
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to get payment QR, invalid UUID parameter")
+		response.Failed(w, 422, "payments", "getPaymentQR", "Invalid UUID, Get Payment QR")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "text"
+	}
+	if format != "text" && format != "png" {
+		h.Logger.Error().Str("format", format).Msg("❌ Failed to get payment QR, unsupported format")
+		response.Failed(w, 422, "payments", "getPaymentQR", "Format must be text or png, Get Payment QR")
+		return
+	}
+
+	payload, err := h.QRISUC.GetPaymentPayload(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "payments", "getPaymentQR", "Get Payment QR", err)
+		return
+	}
+
+	// The payload embeds a single-use amount, so nothing in between should keep a copy
+	w.Header().Set("Cache-Control", "no-store")
+	if format == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(payload))
+		h.Logger.Info().Str("payment_id", id.String()).Msg("✅ Successfully rendered payment QR payload")
+		return
+	}
+
+	code, err := qr.Encode([]byte(payload), qr.LevelM)
+	if err == nil {
+		var image []byte
+		if image, err = code.PNG(pngScale); err == nil {
+			w.Header().Set("Content-Type", "image/png")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(image)
+			h.Logger.Info().Str("payment_id", id.String()).Int("version", code.Version).Msg("✅ Successfully rendered payment QR image")
+			return
+		}
+	}
+	h.Logger.Error().Err(err).Msg("❌ Failed to encode payment QR image")
+	response.Failed(w, 500, "payments", "getPaymentQR", "Error Get Payment QR")
+}
@@ -0,0 +1,15 @@
+package qris
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/rs/zerolog"
+)
+
+type QRISHandler struct {
+	QRISUC usecase.QRISUseCase
+	Logger zerolog.Logger
+}
+
+func NewQRISHandler(qrisUC usecase.QRISUseCase, logger zerolog.Logger) *QRISHandler {
+	return &QRISHandler{QRISUC: qrisUC, Logger: logger}
+}
@@ -1,10 +1,25 @@
 package http
 
 import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/apikey"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/customer"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/dispute"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/fee"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/fxrate"
 	"github.com/adf-code/beta-payment-api/internal/delivery/http/health"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/invoice"
 	"github.com/adf-code/beta-payment-api/internal/delivery/http/middleware"
 	"github.com/adf-code/beta-payment-api/internal/delivery/http/payment"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/paymentlink"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/permission"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/providercallback"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/qris"
 	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/settlement"
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/virtualaccount"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/jwt"
+	"github.com/adf-code/beta-payment-api/internal/ratelimit"
 	"github.com/adf-code/beta-payment-api/internal/usecase"
 	"github.com/rs/zerolog"
 
@@ -12,23 +27,138 @@ import (
 	"net/http"
 )
 
-func SetupHandler(paymentUC usecase.PaymentUseCase, logger zerolog.Logger) http.Handler {
+func SetupHandler(paymentUC usecase.PaymentUseCase, customerUC usecase.CustomerUseCase, providerCallbackUC usecase.ProviderCallbackUseCase, fxRateUC usecase.FXRateUseCase, feeUC usecase.FeeUseCase, disputeUC usecase.DisputeUseCase, paymentLinkUC usecase.PaymentLinkUseCase, qrisUC usecase.QRISUseCase, virtualAccountUC usecase.VirtualAccountUseCase, invoiceUC usecase.InvoiceUseCase, settlementUC usecase.SettlementUseCase, apiKeyUC usecase.APIKeyUseCase, jwtVerifier *jwt.Verifier, limiter *ratelimit.Limiter, logger zerolog.Logger) http.Handler {
 	paymentHandler := payment.NewPaymentHandler(paymentUC, logger)
+	customerHandler := customer.NewCustomerHandler(customerUC, logger)
+	providerCallbackHandler := providercallback.NewProviderCallbackHandler(providerCallbackUC, logger)
+	fxRateHandler := fxrate.NewFXRateHandler(fxRateUC, logger)
+	feeHandler := fee.NewFeeHandler(feeUC, logger)
+	disputeHandler := dispute.NewDisputeHandler(disputeUC, logger)
+	paymentLinkHandler := paymentlink.NewPaymentLinkHandler(paymentLinkUC, logger)
+	qrisHandler := qris.NewQRISHandler(qrisUC, logger)
+	virtualAccountHandler := virtualaccount.NewVirtualAccountHandler(virtualAccountUC, logger)
+	invoiceHandler := invoice.NewInvoiceHandler(invoiceUC, logger)
+	settlementHandler := settlement.NewSettlementHandler(settlementUC, logger)
+	apiKeyHandler := apikey.NewAPIKeyHandler(apiKeyUC, logger)
 	healthHandler := health.NewHealthHandler(logger)
-	auth := middleware.AuthMiddleware(logger)
+	permissionHandler := permission.NewPermissionHandler(logger)
+	// JWT bearer tokens are only accepted when a verifier is configured
+	var tokens middleware.TokenVerifier
+	if jwtVerifier != nil {
+		tokens = jwtVerifier
+	}
+	auth := middleware.AuthMiddleware(apiKeyUC, tokens, logger)
 	log := middleware.LoggingMiddleware(logger)
+	negotiate := middleware.NegotiationMiddleware()
 
 	r := router.NewRouter()
+	// Paths match exactly; /api/v1/payments/ is redirected to /api/v1/payments
+	r.RedirectTrailingSlash = true
+
+	// Every route is registered through secure or public, so each one either names a
+	// known scope or is explicitly public, and shows up in the permission matrix. The
+	// action of the scope (read, write, delete or admin) is the route's rate limit class.
+	base := r.Group("", log, negotiate)
+	api := base.Group("/api/v1", middleware.IPRateLimitMiddleware(limiter, "", logger), auth)
+	open := base.Group("", middleware.IPRateLimitMiddleware(limiter, "public", logger))
+	// Requests no route handles are still counted against their IP and carry its headers
+	base.Group("", middleware.IPRateLimitMiddleware(limiter, "", logger)).HandleUnmatched()
+
+	secure := func(method, path, scope string, handler http.HandlerFunc) {
+		if !entity.IsValidScope(scope) {
+			panic("route " + method + " " + api.Prefix() + path + " has no known scope: " + scope)
+		}
+		permissionHandler.Add(entity.RoutePermission{Method: method, Path: api.Prefix() + path, Scope: scope})
+		class := entity.ScopeAction(scope)
+		api.Handle(method, path, middleware.Chain(
+			middleware.ClientRateLimitMiddleware(limiter, class, logger),
+			middleware.ScopeMiddleware(scope, logger),
+		)(handler))
+	}
+	public := func(method, path string, handler http.HandlerFunc) {
+		permissionHandler.Add(entity.RoutePermission{Method: method, Path: path, Public: true})
+		open.Handle(method, path, handler)
+	}
 
 	r.HandlePrefix(http.MethodGet, "/swagger/", httpSwagger.WrapHandler)
+	permissionHandler.Add(entity.RoutePermission{Method: http.MethodGet, Path: "/swagger/*", Public: true})
+
+	public("GET", "/healthz", healthHandler.Check)
+
+	secure("PUT", "/payments/status/{id:uuid}", entity.ScopePaymentsWrite, paymentHandler.UpdateByID)
+	secure("POST", "/payments/{id:uuid}/authorize", entity.ScopePaymentsWrite, paymentHandler.Authorize)
+	secure("POST", "/payments/{id:uuid}/capture", entity.ScopePaymentsWrite, paymentHandler.Capture)
+	secure("POST", "/payments/{id:uuid}/refund", entity.ScopePaymentsWrite, paymentHandler.Refund)
+	secure("POST", "/payments/{id:uuid}/void", entity.ScopePaymentsWrite, paymentHandler.Void)
+	secure("POST", "/payments/{id:uuid}/review", entity.ScopePaymentsWrite, paymentHandler.Review)
+	secure("GET", "/payments/{id:uuid}/fees", entity.ScopePaymentsRead, paymentHandler.GetFees)
+	secure("GET", "/payments/{id:uuid}/qr", entity.ScopePaymentsRead, qrisHandler.GetPaymentQR)
+	secure("GET", "/payments/summary", entity.ScopePaymentsRead, paymentHandler.Summary)
+	secure("GET", "/payments/{id:uuid}", entity.ScopePaymentsRead, paymentHandler.GetByID)
+	secure("GET", "/payments", entity.ScopePaymentsRead, paymentHandler.GetAll)
+	secure("POST", "/payments", entity.ScopePaymentsWrite, paymentHandler.Create)
+	secure("PATCH", "/payments/{id:uuid}", entity.ScopePaymentsWrite, paymentHandler.Patch)
+	secure("DELETE", "/payments/{id:uuid}", entity.ScopePaymentsDelete, paymentHandler.Delete)
+
+	secure("GET", "/customers/{id:uuid}/payments", entity.ScopeCustomersRead, customerHandler.GetPayments)
+	secure("GET", "/customers/{id:uuid}", entity.ScopeCustomersRead, customerHandler.GetByID)
+	secure("PUT", "/customers/{id:uuid}", entity.ScopeCustomersWrite, customerHandler.UpdateByID)
+	secure("DELETE", "/customers/{id:uuid}", entity.ScopeCustomersDelete, customerHandler.Delete)
+	secure("GET", "/customers", entity.ScopeCustomersRead, customerHandler.GetAll)
+	secure("POST", "/customers", entity.ScopeCustomersWrite, customerHandler.Create)
+
+	// Providers authenticate callbacks with signatures instead of bearer tokens
+	public("POST", "/api/v1/provider-callbacks/{provider}", providerCallbackHandler.Receive)
+	secure("GET", "/provider-callbacks", entity.ScopeProviderCallbacksRead, providerCallbackHandler.GetAll)
+
+	secure("GET", "/fx-rates", entity.ScopeFXRatesRead, fxRateHandler.GetAll)
+	// Rates and fee rules are shared by every merchant, so only operators change them
+	secure("POST", "/fx-rates", entity.ScopeOperator, fxRateHandler.Load)
+
+	secure("POST", "/fees/quote", entity.ScopeFeesRead, feeHandler.Quote)
+	// Fee rules hold every merchant's pricing, so merchants do not even list them
+	secure("PUT", "/fee-rules/{id:uuid}", entity.ScopeOperator, feeHandler.UpdateRule)
+	secure("DELETE", "/fee-rules/{id:uuid}", entity.ScopeOperator, feeHandler.DeleteRule)
+	secure("GET", "/fee-rules", entity.ScopeOperator, feeHandler.GetAllRules)
+	secure("POST", "/fee-rules", entity.ScopeOperator, feeHandler.CreateRule)
+
+	secure("GET", "/disputes/{id:uuid}/evidence/{evidence_id:uuid}", entity.ScopeDisputesRead, disputeHandler.GetEvidence)
+	secure("POST", "/disputes/{id:uuid}/evidence", entity.ScopeDisputesWrite, disputeHandler.AddEvidence)
+	secure("PUT", "/disputes/{id:uuid}/status", entity.ScopeDisputesWrite, disputeHandler.UpdateStatus)
+	secure("GET", "/disputes/{id:uuid}", entity.ScopeDisputesRead, disputeHandler.GetByID)
+	secure("GET", "/disputes", entity.ScopeDisputesRead, disputeHandler.GetAll)
+	secure("POST", "/disputes", entity.ScopeDisputesWrite, disputeHandler.Open)
+
+	secure("POST", "/payment-links/{id:uuid}/revoke", entity.ScopePaymentLinksWrite, paymentLinkHandler.Revoke)
+	secure("GET", "/payment-links/{id:uuid}", entity.ScopePaymentLinksRead, paymentLinkHandler.GetByID)
+	secure("POST", "/payment-links", entity.ScopePaymentLinksWrite, paymentLinkHandler.Create)
+
+	secure("POST", "/virtual-accounts/{number}/credit", entity.ScopeVirtualAccountsWrite, virtualAccountHandler.Credit)
+	secure("GET", "/virtual-accounts/{number}", entity.ScopeVirtualAccountsRead, virtualAccountHandler.GetByNumber)
+	secure("POST", "/virtual-accounts", entity.ScopeVirtualAccountsWrite, virtualAccountHandler.Issue)
+
+	secure("POST", "/invoices/{id:uuid}/payments", entity.ScopeInvoicesWrite, invoiceHandler.CreatePayment)
+	secure("POST", "/invoices/{id:uuid}/finalize", entity.ScopeInvoicesWrite, invoiceHandler.Finalize)
+	secure("POST", "/invoices/{id:uuid}/void", entity.ScopeInvoicesWrite, invoiceHandler.Void)
+	secure("GET", "/invoices/{id:uuid}", entity.ScopeInvoicesRead, invoiceHandler.GetByID)
+	secure("PUT", "/invoices/{id:uuid}", entity.ScopeInvoicesWrite, invoiceHandler.Update)
+	secure("GET", "/invoices", entity.ScopeInvoicesRead, invoiceHandler.GetAll)
+	secure("POST", "/invoices", entity.ScopeInvoicesWrite, invoiceHandler.Create)
+
+	secure("GET", "/settlement-batches/{id:uuid}/payout-file", entity.ScopeSettlementsRead, settlementHandler.GetPayoutFile)
+	secure("PUT", "/settlement-batches/{id:uuid}/status", entity.ScopeSettlementsWrite, settlementHandler.UpdateStatus)
+	secure("GET", "/settlement-batches/{id:uuid}", entity.ScopeSettlementsRead, settlementHandler.GetByID)
+	secure("GET", "/settlement-batches", entity.ScopeSettlementsRead, settlementHandler.GetAll)
+	secure("POST", "/settlement-batches", entity.ScopeSettlementsWrite, settlementHandler.Generate)
 
-	r.Handle("GET", "/healthz", middleware.Chain(log)(healthHandler.Check))
+	secure("GET", "/admin/permissions", entity.ScopeAdmin, permissionHandler.GetMatrix)
+	secure("POST", "/admin/api-keys/{id:uuid}/revoke", entity.ScopeAdmin, apiKeyHandler.Revoke)
+	secure("GET", "/admin/api-keys", entity.ScopeAdmin, apiKeyHandler.GetAll)
+	secure("POST", "/admin/api-keys", entity.ScopeAdmin, apiKeyHandler.Issue)
 
-	r.Handle("PUT", "/api/v1/payments/status/{id}", middleware.Chain(log, auth)(paymentHandler.UpdateByID))
-	r.Handle("GET", "/api/v1/payments/{id}", middleware.Chain(log, auth)(paymentHandler.GetByID))
-	r.Handle("GET", "/api/v1/payments", middleware.Chain(log, auth)(paymentHandler.GetAll))
-	r.Handle("POST", "/api/v1/payments", middleware.Chain(log, auth)(paymentHandler.Create))
-	r.Handle("DELETE", "/api/v1/payments/{id}", middleware.Chain(log, auth)(paymentHandler.Delete))
+	// Hosted checkout is public; the signed link token is the credential
+	public("POST", "/pay/{token}/confirm", paymentLinkHandler.Confirm)
+	public("GET", "/pay/{token}", paymentLinkHandler.Checkout)
 
 	return r
 }
@@ -0,0 +1,310 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/ratelimit"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// stubAPIKeys authenticates the bearer keys in keys; the other methods are never called.
+type stubAPIKeys struct {
+	usecase.APIKeyUseCase
+	keys map[string]*entity.APIKey
+}
+
+func (s stubAPIKeys) Authenticate(_ context.Context, key string) (*entity.APIKey, error) {
+	if k, ok := s.keys[key]; ok {
+		return k, nil
+	}
+	return nil, entity.ErrInvalidAPIKey
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// samplePath fills every path param of a route pattern with a UUID, which satisfies any
+// constraint a param can declare.
+func samplePath(pattern string) string {
+	return pathParam.ReplaceAllString(pattern, uuid.NewString())
+}
+
+// permissionMatrix reads the matrix the routes recorded while they were registered.
+func permissionMatrix(t *testing.T, handler http.Handler, adminKey string) entity.PermissionMatrix {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/permissions", nil)
+	req.Header.Set("Authorization", "Bearer "+adminKey)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/v1/admin/permissions: status %d, body %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Data entity.PermissionMatrix `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode permission matrix: %v", err)
+	}
+	if len(body.Data.Routes) == 0 {
+		t.Fatal("permission matrix lists no routes")
+	}
+	return body.Data
+}
+
+// grantableScopes is every scope a merchant key can hold.
+func grantableScopes(matrix entity.PermissionMatrix) []string {
+	var scopes []string
+	for scope := range matrix.Scopes {
+		if entity.IsGrantableScope(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func TestEveryRouteHasADeclaredScope(t *testing.T) {
+	merchantID := uuid.New()
+	apiKeys := stubAPIKeys{keys: map[string]*entity.APIKey{
+		"admin-key":    {ID: uuid.New(), MerchantID: merchantID, Type: entity.APIKeyTypeBearer, Scopes: []string{entity.ScopeAdmin}},
+		"no-scope-key": {ID: uuid.New(), MerchantID: merchantID, Type: entity.APIKeyTypeBearer, Scopes: []string{}},
+	}}
+	handler := SetupHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, apiKeys, nil, nil, zerolog.Nop())
+	matrix := permissionMatrix(t, handler, "admin-key")
+
+	for _, route := range matrix.Routes {
+		name := route.Method + " " + route.Path
+		if route.Public {
+			if route.Scope != "" {
+				t.Errorf("%s is public but names scope %q", name, route.Scope)
+			}
+			continue
+		}
+		if !entity.IsValidScope(route.Scope) {
+			t.Errorf("%s names unknown scope %q", name, route.Scope)
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			path := samplePath(route.Path)
+
+			// Without credentials the route is not reachable at all
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(route.Method, path, nil))
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("without credentials: status %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+
+			// A valid key without the scope is refused, naming the scope it lacks
+			req := httptest.NewRequest(route.Method, path, nil)
+			req.Header.Set("Authorization", "Bearer no-scope-key")
+			rec = httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("without scope: status %d, want %d", rec.Code, http.StatusForbidden)
+			}
+			if want := "missing scope " + route.Scope; !strings.Contains(rec.Body.String(), want) {
+				t.Errorf("without scope: body %s does not contain %q", rec.Body, want)
+			}
+		})
+	}
+}
+
+// Requests the router answers itself count against the IP limit like routed ones.
+func TestUnmatchedRequestsAreRateLimited(t *testing.T) {
+	limiter := &ratelimit.Limiter{
+		Store: ratelimit.NewMemoryStore(),
+		PerIP: &ratelimit.Limit{Requests: 4, Window: time.Hour},
+	}
+	handler := SetupHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, stubAPIKeys{}, nil, limiter, zerolog.Nop())
+
+	for _, tc := range []struct {
+		method    string
+		path      string
+		status    int
+		remaining string
+	}{
+		{method: "GET", path: "/api/v1/unknown", status: http.StatusNotFound, remaining: "3"},
+		{method: "DELETE", path: "/healthz", status: http.StatusMethodNotAllowed, remaining: "2"},
+		{method: "OPTIONS", path: "/api/v1/payments", status: http.StatusNoContent, remaining: "1"},
+		{method: "GET", path: "/api/v1/payments/", status: http.StatusMovedPermanently, remaining: "0"},
+		{method: "GET", path: "/api/v1/unknown", status: http.StatusTooManyRequests, remaining: "0"},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		if rec.Code != tc.status {
+			t.Errorf("%s %s: status %d, want %d", tc.method, tc.path, rec.Code, tc.status)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != tc.remaining {
+			t.Errorf("%s %s: RateLimit-Remaining %q, want %q", tc.method, tc.path, got, tc.remaining)
+		}
+		if rec.Header().Get("RateLimit-Limit") != "4" {
+			t.Errorf("%s %s: RateLimit-Limit %q, want 4", tc.method, tc.path, rec.Header().Get("RateLimit-Limit"))
+		}
+	}
+}
+
+// Platform routes change data every merchant shares: not even a merchant key holding every
+// scope it can be granted reaches them, and the key issuer refuses the operator scope.
+func TestPlatformRoutesRequireAnOperator(t *testing.T) {
+	merchantID := uuid.New()
+	keys := map[string]*entity.APIKey{
+		"admin-key":    {ID: uuid.New(), MerchantID: merchantID, Type: entity.APIKeyTypeBearer, Scopes: []string{entity.ScopeAdmin}},
+		"merchant-key": {ID: uuid.New(), MerchantID: merchantID, Type: entity.APIKeyTypeBearer},
+	}
+	handler := SetupHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, stubAPIKeys{keys: keys}, nil, nil, zerolog.Nop())
+	matrix := permissionMatrix(t, handler, "admin-key")
+	keys["merchant-key"].Scopes = grantableScopes(matrix)
+
+	operatorRoutes := map[string]bool{}
+	for _, route := range matrix.Routes {
+		if route.Scope == entity.ScopeOperator {
+			operatorRoutes[route.Method+" "+route.Path] = true
+		}
+	}
+	for _, want := range []string{
+		"POST /api/v1/fx-rates",
+		"POST /api/v1/fee-rules",
+		"PUT /api/v1/fee-rules/{id:uuid}",
+		"DELETE /api/v1/fee-rules/{id:uuid}",
+	} {
+		if !operatorRoutes[want] {
+			t.Errorf("%s does not require the operator scope", want)
+		}
+	}
+
+	for name := range operatorRoutes {
+		method, path, _ := strings.Cut(name, " ")
+		req := httptest.NewRequest(method, samplePath(path), nil)
+		req.Header.Set("Authorization", "Bearer merchant-key")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "missing scope "+entity.ScopeOperator) {
+			t.Errorf("%s with a merchant admin key: status %d, body %s", name, rec.Code, rec.Body)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/api-keys", strings.NewReader(`{"name":"escalate","scopes":["operator"]}`))
+	req.Header.Set("Authorization", "Bearer admin-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("issuing an operator key as a merchant admin: status %d, body %s", rec.Code, rec.Body)
+	}
+}
+
+// stubFXRates counts the loads that reach it.
+type stubFXRates struct {
+	usecase.FXRateUseCase
+	loads *int
+}
+
+func (s stubFXRates) Load(_ context.Context, _ *request.LoadFXRatesRequest) ([]entity.FXRate, error) {
+	*s.loads++
+	return []entity.FXRate{}, nil
+}
+
+func TestOnlyOperatorsLoadFXRates(t *testing.T) {
+	keys := map[string]*entity.APIKey{
+		"admin-key":    {ID: uuid.New(), MerchantID: uuid.New(), Type: entity.APIKeyTypeBearer, Scopes: []string{entity.ScopeAdmin}},
+		"merchant-key": {ID: uuid.New(), MerchantID: uuid.New(), Type: entity.APIKeyTypeBearer},
+		"operator-key": {ID: uuid.New(), MerchantID: uuid.New(), Type: entity.APIKeyTypeBearer, Scopes: []string{entity.ScopeOperator}},
+	}
+	var loads int
+	handler := SetupHandler(nil, nil, nil, stubFXRates{loads: &loads}, nil, nil, nil, nil, nil, nil, nil, stubAPIKeys{keys: keys}, nil, nil, zerolog.Nop())
+	keys["merchant-key"].Scopes = grantableScopes(permissionMatrix(t, handler, "admin-key"))
+
+	load := func(key string) int {
+		body := `{"rates":[{"base_currency":"USD","quote_currency":"IDR","rate":"15600.25","effective_at":"2025-08-10T00:00:00Z"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/fx-rates", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+key)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := load("merchant-key"); code != http.StatusForbidden {
+		t.Errorf("merchant key loading rates: status %d, want 403", code)
+	}
+	if loads != 0 {
+		t.Fatalf("a merchant key reached the rate loader %d times", loads)
+	}
+	if code := load("operator-key"); code != http.StatusCreated {
+		t.Errorf("operator key loading rates: status %d, want 201", code)
+	}
+	if loads != 1 {
+		t.Errorf("rate loader called %d times, want 1", loads)
+	}
+}
+
+// stubFees counts the fee rule calls that reach it.
+type stubFees struct {
+	usecase.FeeUseCase
+	calls *int
+}
+
+func (s stubFees) GetAllRules(context.Context, request.FeeRuleListQueryParams) ([]entity.FeeRule, error) {
+	*s.calls++
+	return []entity.FeeRule{}, nil
+}
+
+func (s stubFees) CreateRule(context.Context, *request.FeeRuleRequest) (*entity.FeeRule, error) {
+	*s.calls++
+	return &entity.FeeRule{}, nil
+}
+
+func (s stubFees) UpdateRule(context.Context, uuid.UUID, *request.FeeRuleRequest) (*entity.FeeRule, error) {
+	*s.calls++
+	return &entity.FeeRule{}, nil
+}
+
+func (s stubFees) DeleteRule(context.Context, uuid.UUID) error {
+	*s.calls++
+	return nil
+}
+
+// Fee rules are not tenant scoped, so a merchant must neither see nor change another
+// merchant's rule even when it knows the rule's id.
+func TestMerchantsCannotReachEachOthersFeeRules(t *testing.T) {
+	merchantA, merchantB := uuid.New(), uuid.New()
+	keys := map[string]*entity.APIKey{
+		"admin-key": {ID: uuid.New(), MerchantID: merchantA, Type: entity.APIKeyTypeBearer, Scopes: []string{entity.ScopeAdmin}},
+		"a-key":     {ID: uuid.New(), MerchantID: merchantA, Type: entity.APIKeyTypeBearer},
+	}
+	var calls int
+	handler := SetupHandler(nil, nil, nil, nil, stubFees{calls: &calls}, nil, nil, nil, nil, nil, nil, stubAPIKeys{keys: keys}, nil, nil, zerolog.Nop())
+	keys["a-key"].Scopes = grantableScopes(permissionMatrix(t, handler, "admin-key"))
+
+	ruleB := uuid.NewString()
+	body := `{"merchant_id":"` + merchantB.String() + `","name":"B pricing","type":"STANDARD","fixed_amount":"0","percentage":"0"}`
+	for _, tc := range []struct {
+		method, path, body string
+	}{
+		{http.MethodGet, "/api/v1/fee-rules?merchant_id=" + merchantB.String(), ""},
+		{http.MethodPost, "/api/v1/fee-rules", body},
+		{http.MethodPut, "/api/v1/fee-rules/" + ruleB, body},
+		{http.MethodDelete, "/api/v1/fee-rules/" + ruleB, ""},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Authorization", "Bearer a-key")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s with merchant A's key: status %d, want 403", tc.method, tc.path, rec.Code)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("merchant A's key reached the fee rules %d times", calls)
+	}
+}
@@ -0,0 +1,43 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/middleware"
+)
+
+// Group is a subrouter: routes registered on it are prefixed with its path and wrapped in
+// its middleware, outermost first, after the middleware of the groups it is nested in.
+type Group struct {
+	router      *Router
+	prefix      string
+	middlewares []middleware.Middleware
+}
+
+// Group returns a subrouter for the routes under prefix.
+func (r *Router) Group(prefix string, middlewares ...middleware.Middleware) *Group {
+	return &Group{router: r, prefix: prefix, middlewares: middlewares}
+}
+
+// Group returns a subrouter nested in g.
+func (g *Group) Group(prefix string, middlewares ...middleware.Middleware) *Group {
+	chain := make([]middleware.Middleware, 0, len(g.middlewares)+len(middlewares))
+	chain = append(append(chain, g.middlewares...), middlewares...)
+	return &Group{router: g.router, prefix: g.prefix + prefix, middlewares: chain}
+}
+
+// Prefix is the path every route of g starts with.
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+func (g *Group) Handle(method, path string, handler http.HandlerFunc) {
+	g.router.Handle(method, g.prefix+path, middleware.Chain(g.middlewares...)(handler))
+}
+
+// HandleUnmatched sends the requests no route handles, whatever their path, through the
+// middleware of g before the router answers them with 404, 405, OPTIONS or a trailing
+// slash redirect, so they are logged and rate limited like routed requests.
+func (g *Group) HandleUnmatched() {
+	g.router.unmatched = middleware.Chain(g.middlewares...)
+}
@@ -0,0 +1,114 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/middleware"
+)
+
+// trace records the name of each middleware a request passes, in order.
+func trace(name string, calls *[]string) middleware.Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name)
+			next(w, r)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var calls []string
+	r := NewRouter()
+	base := r.Group("", trace("log", &calls))
+	api := base.Group("/api/v1", trace("auth", &calls))
+	payments := api.Group("/payments", trace("payments", &calls), trace("audit", &calls))
+	public := base.Group("/pay", trace("public", &calls))
+
+	payments.Handle(http.MethodGet, "/{id}", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+	api.Handle(http.MethodGet, "/health", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+	public.Handle(http.MethodGet, "/{token}", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+	r.Handle(http.MethodGet, "/healthz", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+
+	if got := payments.Prefix(); got != "/api/v1/payments" {
+		t.Errorf("Prefix %q, want /api/v1/payments", got)
+	}
+
+	for _, tc := range []struct {
+		path  string
+		calls string
+	}{
+		{path: "/api/v1/payments/" + testID, calls: "log,auth,payments,audit,handler"},
+		{path: "/api/v1/health", calls: "log,auth,handler"},
+		{path: "/pay/token", calls: "log,public,handler"},
+		{path: "/healthz", calls: "handler"},
+		{path: "/api/v1/payments", calls: ""},
+	} {
+		calls = nil
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if got := strings.Join(calls, ","); got != tc.calls {
+			t.Errorf("GET %s passed %q, want %q", tc.path, got, tc.calls)
+		}
+	}
+}
+
+// Nesting a group must not share its middleware slice with sibling groups.
+func TestGroupSiblingsDoNotShareMiddleware(t *testing.T) {
+	var calls []string
+	r := NewRouter()
+	parent := r.Group("/a", trace("parent", &calls))
+	first := parent.Group("/first", trace("first", &calls))
+	second := parent.Group("/second", trace("second", &calls))
+	noop := func(http.ResponseWriter, *http.Request) {}
+	first.Handle(http.MethodGet, "", noop)
+	second.Handle(http.MethodGet, "", noop)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a/first", nil))
+	if got := strings.Join(calls, ","); got != "parent,first" {
+		t.Errorf("GET /a/first passed %q, want parent,first", got)
+	}
+}
+
+func TestGroupHandleUnmatched(t *testing.T) {
+	var calls []string
+	r := NewRouter()
+	r.RedirectTrailingSlash = true
+	base := r.Group("", trace("log", &calls))
+	base.Group("/api/v1", trace("auth", &calls)).Handle(http.MethodGet, "/payments", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+	base.Group("", trace("limit", &calls)).HandleUnmatched()
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		status int
+		calls  string
+	}{
+		{method: "GET", path: "/api/v1/payments", status: 200, calls: "log,auth,handler"},
+		{method: "GET", path: "/api/v1/unknown", status: 404, calls: "log,limit"},
+		{method: "POST", path: "/api/v1/payments", status: 405, calls: "log,limit"},
+		{method: "OPTIONS", path: "/api/v1/payments", status: 204, calls: "log,limit"},
+		{method: "GET", path: "/api/v1/payments/", status: 301, calls: "log,limit"},
+	} {
+		calls = nil
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		if rec.Code != tc.status {
+			t.Errorf("%s %s: status %d, want %d", tc.method, tc.path, rec.Code, tc.status)
+		}
+		if got := strings.Join(calls, ","); got != tc.calls {
+			t.Errorf("%s %s passed %q, want %q", tc.method, tc.path, got, tc.calls)
+		}
+	}
+}
@@ -1,21 +0,0 @@
-package router
-
-import "strings"
-
-func matchPath(pattern, path string) bool {
-	patternParts := strings.Split(pattern, "/")
-	pathParts := strings.Split(path, "/")
-
-	if len(patternParts) != len(pathParts) {
-		return false
-	}
-	for i := 0; i < len(patternParts); i++ {
-		if strings.HasPrefix(patternParts[i], "{") && strings.HasSuffix(patternParts[i], "}") {
-			continue
-		}
-		if patternParts[i] != pathParts[i] {
-			return false
-		}
-	}
-	return true
-}
@@ -0,0 +1,55 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const ParamKey contextKey = "pathParams"
+
+// constraints are the types a path param can be declared with, as in {id:uuid}. A segment
+// that does not parse does not match, so the handler never sees it.
+var constraints = map[string]func(string) bool{
+	"uuid": func(s string) bool {
+		_, err := uuid.Parse(s)
+		return err == nil && len(s) == 36
+	},
+	"int": func(s string) bool {
+		_, err := strconv.ParseInt(s, 10, 64)
+		return err == nil
+	},
+}
+
+// Params are the path params of the matched route, by name.
+type Params map[string]string
+
+// ParamsOf returns the path params of r; they are empty outside a matched route.
+func ParamsOf(r *http.Request) Params {
+	if m, ok := r.Context().Value(ParamKey).(Params); ok {
+		return m
+	}
+	return Params{}
+}
+
+func (p Params) Get(name string) string {
+	return p[name]
+}
+
+// UUID parses the param name; {name:uuid} routes only match valid ones.
+func (p Params) UUID(name string) (uuid.UUID, error) {
+	return uuid.Parse(p[name])
+}
+
+// Int parses the param name; {name:int} routes only match valid ones.
+func (p Params) Int(name string) (int64, error) {
+	return strconv.ParseInt(p[name], 10, 64)
+}
+
+// GetParam retrieves param from context
+func GetParam(r *http.Request, key string) string {
+	return ParamsOf(r).Get(key)
+}
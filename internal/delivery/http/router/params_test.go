@@ -0,0 +1,111 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParamConstraints(t *testing.T) {
+	r := NewRouter()
+	r.Handle(http.MethodGet, "/payments/{id:uuid}", echo("payment"))
+	r.Handle(http.MethodGet, "/pages/{n:int}", echo("page"))
+	r.Handle(http.MethodGet, "/pages/{slug}", echo("slug"))
+	r.Handle(http.MethodGet, "/disputes/{id:uuid}/evidence/{evidence_id:uuid}", echo("evidence"))
+
+	for _, tc := range []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{path: "/payments/" + testID, status: 200, body: "payment " + testID},
+		{path: "/payments/not-a-uuid", status: 404},
+		{path: "/payments/" + testID[:35], status: 404},
+		{path: "/payments/{" + testID + "}", status: 404},
+		{path: "/payments/" + testID + "-1", status: 404},
+		{path: "/pages/42", status: 200, body: "page "},
+		{path: "/pages/-7", status: 200, body: "page "},
+		{path: "/pages/4.2", status: 200, body: "slug "},
+		{path: "/pages/about", status: 200, body: "slug "},
+		{path: "/disputes/" + testID + "/evidence/" + testID, status: 200, body: "evidence " + testID},
+		{path: "/disputes/" + testID + "/evidence/1", status: 404},
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != tc.status {
+			t.Errorf("GET %s: status %d, want %d", tc.path, rec.Code, tc.status)
+		}
+		if tc.body != "" && rec.Body.String() != tc.body {
+			t.Errorf("GET %s: body %q, want %q", tc.path, rec.Body, tc.body)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	var got Params
+	r := NewRouter()
+	r.Handle(http.MethodGet, "/items/{id:uuid}/versions/{n:int}", func(w http.ResponseWriter, req *http.Request) {
+		got = ParamsOf(req)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/"+testID+"/versions/12", nil))
+
+	id, err := got.UUID("id")
+	if err != nil || id != uuid.MustParse(testID) {
+		t.Errorf("UUID(id) = %v, %v; want %s", id, err, testID)
+	}
+	n, err := got.Int("n")
+	if err != nil || n != 12 {
+		t.Errorf("Int(n) = %d, %v; want 12", n, err)
+	}
+	if v := got.Get("n"); v != "12" {
+		t.Errorf("Get(n) = %q, want 12", v)
+	}
+	if _, err := got.UUID("n"); err == nil {
+		t.Error("UUID(n) succeeded for a non-UUID param")
+	}
+	if _, err := got.Int("missing"); err == nil {
+		t.Error("Int(missing) succeeded for a missing param")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if p := ParamsOf(req); len(p) != 0 {
+		t.Errorf("ParamsOf outside a route = %v, want empty", p)
+	}
+	if v := GetParam(req, "id"); v != "" {
+		t.Errorf("GetParam outside a route = %q, want empty", v)
+	}
+}
+
+func TestHandleRejectsBadPatterns(t *testing.T) {
+	for _, pattern := range []string{
+		"payments",
+		"/payments/{}",
+		"/payments/{id:decimal}",
+		"/payments/pay-{id}",
+		"/payments/{id}.json",
+		"/payments/{a}{b}",
+		"/payments/{id",
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Handle(%q) did not panic", pattern)
+				}
+			}()
+			NewRouter().Handle(http.MethodGet, pattern, echo("bad"))
+		})
+	}
+
+	t.Run("duplicate", func(t *testing.T) {
+		r := NewRouter()
+		r.Handle(http.MethodGet, "/payments/{id}", echo("first"))
+		defer func() {
+			if recover() == nil {
+				t.Error("registering a route twice did not panic")
+			}
+		}()
+		r.Handle(http.MethodGet, "/payments/{id}", echo("second"))
+	})
+}
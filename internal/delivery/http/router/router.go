@@ -2,21 +2,15 @@ package router
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
-	"regexp"
+	"sort"
 	"strings"
-)
-
-type contextKey string
-
-const ParamKey contextKey = "pathParams"
 
-type Route struct {
-	Method      string
-	Pattern     *regexp.Regexp
-	ParamNames  []string
-	HandlerFunc http.HandlerFunc
-}
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/middleware"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+)
 
 type prefixRoute struct {
 	Method  string
@@ -24,32 +18,41 @@ type prefixRoute struct {
 	Handler http.Handler
 }
 
+// Router matches request paths exactly against a radix tree of the registered patterns.
+// A {name} segment matches any one non-empty segment, {name:uuid} and {name:int} only
+// segments of that type. A path registered under other methods is answered with 405 and
+// an Allow header, and OPTIONS is answered with the allowed methods unless a route
+// handles it. With RedirectTrailingSlash, a path that only matches with or without its
+// trailing slash is redirected there: 301 for GET and HEAD, 308 for other methods so the
+// method and body are kept.
 type Router struct {
-	routes       []Route
+	root         *node
 	prefixRoutes []prefixRoute
+	// unmatched wraps the responses the router answers itself; see Group.HandleUnmatched
+	unmatched middleware.Middleware
+
+	RedirectTrailingSlash bool
 }
 
 func NewRouter() *Router {
-	return &Router{}
+	return &Router{root: &node{}}
 }
 
+// Handle registers handler for method and path. It panics on a malformed pattern, an
+// unknown param constraint or a route registered twice, since routes are set up once at
+// startup.
 func (r *Router) Handle(method, path string, handler http.HandlerFunc) {
-	paramNames := []string{}
-	regexPattern := regexp.MustCompile(`\{(\w+)\}`)
-	replaced := regexPattern.ReplaceAllStringFunc(path, func(m string) string {
-		name := m[1 : len(m)-1]
-		paramNames = append(paramNames, name)
-		return `([^/]+)`
-	})
-
-	finalRegex := regexp.MustCompile("^" + replaced + "(/.*)?$")
-
-	r.routes = append(r.routes, Route{
-		Method:      method,
-		Pattern:     finalRegex,
-		ParamNames:  paramNames,
-		HandlerFunc: handler,
-	})
+	if err := validatePattern(path); err != nil {
+		panic("route " + method + " " + path + ": " + err.Error())
+	}
+	n := r.root.insert(path)
+	if n.handlers == nil {
+		n.handlers = make(map[string]http.HandlerFunc)
+	}
+	if _, ok := n.handlers[method]; ok {
+		panic("route " + method + " " + path + " is already registered")
+	}
+	n.handlers[method] = handler
 }
 
 func (r *Router) HandlePrefix(method, prefix string, handler http.Handler) {
@@ -61,35 +64,116 @@ func (r *Router) HandlePrefix(method, prefix string, handler http.Handler) {
 }
 
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+	var allowed []string
+
 	for _, pr := range r.prefixRoutes {
-		if pr.Method == req.Method && strings.HasPrefix(req.URL.Path, pr.Prefix) {
+		if !strings.HasPrefix(path, pr.Prefix) {
+			continue
+		}
+		if pr.Method == req.Method {
 			pr.Handler.ServeHTTP(w, req)
 			return
 		}
+		allowed = appendMethod(allowed, pr.Method)
 	}
-	for _, route := range r.routes {
-		if route.Method != req.Method {
-			continue
+	params := Params{}
+	if n := r.root.find(path, params); n != nil {
+		if handler, ok := n.handlers[req.Method]; ok {
+			ctx := context.WithValue(req.Context(), ParamKey, params)
+			handler(w, req.WithContext(ctx))
+			return
+		}
+		for method := range n.handlers {
+			allowed = appendMethod(allowed, method)
+		}
+	}
+
+	answer := func(w http.ResponseWriter, req *http.Request) {
+		r.answerUnmatched(w, req, allowed)
+	}
+	if r.unmatched != nil {
+		answer = r.unmatched(answer)
+	}
+	answer(w, req)
+}
+
+// answerUnmatched answers a request no route handles: OPTIONS or 405 when the path is
+// registered under other methods, a trailing slash redirect, or 404.
+func (r *Router) answerUnmatched(w http.ResponseWriter, req *http.Request, allowed []string) {
+	path := req.URL.Path
+	if len(allowed) > 0 {
+		allowed = appendMethod(allowed, http.MethodOptions)
+		sort.Strings(allowed)
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		if req.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		response.Failed(response.Negotiate(w, req), http.StatusMethodNotAllowed, "route", "matchRoute", "Method Not Allowed")
+		return
+	}
+
+	if r.RedirectTrailingSlash && path != "/" {
+		alt := path + "/"
+		if strings.HasSuffix(path, "/") {
+			alt = strings.TrimSuffix(path, "/")
 		}
-		matches := route.Pattern.FindStringSubmatch(req.URL.Path)
-		if matches != nil {
-			params := make(map[string]string)
-			for i, name := range route.ParamNames {
-				params[name] = matches[i+1]
+		if r.matches(alt) {
+			code := http.StatusPermanentRedirect
+			if req.Method == http.MethodGet || req.Method == http.MethodHead {
+				code = http.StatusMovedPermanently
 			}
-			ctx := context.WithValue(req.Context(), ParamKey, params)
-			route.HandlerFunc(w, req.WithContext(ctx))
+			target := *req.URL
+			target.Path = alt
+			http.Redirect(w, req, target.String(), code)
 			return
 		}
 	}
-	http.NotFound(w, req)
+
+	response.Failed(response.Negotiate(w, req), http.StatusNotFound, "route", "matchRoute", "Route not Found")
+}
+
+// matches reports whether any route, under any method, matches path. Only registered
+// paths are redirected to, so a redirect never leaves the API.
+func (r *Router) matches(path string) bool {
+	for _, pr := range r.prefixRoutes {
+		if strings.HasPrefix(path, pr.Prefix) {
+			return true
+		}
+	}
+	return r.root.find(path, Params{}) != nil
+}
+
+func appendMethod(methods []string, method string) []string {
+	for _, m := range methods {
+		if m == method {
+			return methods
+		}
+	}
+	return append(methods, method)
 }
 
-// GetParam retrieves param from context
-func GetParam(r *http.Request, key string) string {
-	params := r.Context().Value(ParamKey)
-	if m, ok := params.(map[string]string); ok {
-		return m[key]
+// validatePattern checks that each param of pattern is a whole segment with a known
+// constraint.
+func validatePattern(pattern string) error {
+	if !strings.HasPrefix(pattern, "/") {
+		return errors.New("pattern must start with /")
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if !strings.ContainsAny(segment, "{}") {
+			continue
+		}
+		if len(segment) < 3 || segment[0] != '{' || strings.IndexByte(segment, '}') != len(segment)-1 || strings.Count(segment, "{") != 1 {
+			return fmt.Errorf("param %q must be a whole segment", segment)
+		}
+		name, constraint, typed := strings.Cut(segment[1:len(segment)-1], ":")
+		if name == "" {
+			return fmt.Errorf("param %q has no name", segment)
+		}
+		if _, ok := constraints[constraint]; typed && !ok {
+			return fmt.Errorf("param %q has unknown constraint %q", segment, constraint)
+		}
 	}
-	return ""
+	return nil
 }
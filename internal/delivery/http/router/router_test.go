@@ -0,0 +1,113 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+)
+
+const testID = "0d9f5e3c-7b1a-4c55-9a8e-3f2b1c0d4e5f"
+
+// echo answers with the name of the route and the params it matched.
+func echo(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name + " " + ParamsOf(r).Get("id")))
+	}
+}
+
+func newTestRouter(redirect bool) *Router {
+	r := NewRouter()
+	r.RedirectTrailingSlash = redirect
+	r.Handle(http.MethodGet, "/api/v1/payments", echo("list"))
+	r.Handle(http.MethodPost, "/api/v1/payments", echo("create"))
+	r.Handle(http.MethodGet, "/api/v1/payments/summary", echo("summary"))
+	r.Handle(http.MethodGet, "/api/v1/payments/{id}", echo("get"))
+	r.Handle(http.MethodPatch, "/api/v1/payments/{id}", echo("patch"))
+	r.Handle(http.MethodDelete, "/api/v1/payments/{id}", echo("delete"))
+	r.Handle(http.MethodPost, "/api/v1/payments/{id}/capture", echo("capture"))
+	r.Handle(http.MethodOptions, "/api/v1/webhooks", echo("webhook options"))
+	r.HandlePrefix(http.MethodGet, "/swagger/", echo("swagger"))
+	return r
+}
+
+func TestRouterServeHTTP(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		redirect bool
+		method   string
+		path     string
+		accept   string
+		status   int
+		body     string
+		allow    string
+		location string
+		problem  bool
+	}{
+		// Exact matching
+		{name: "static route", method: "GET", path: "/api/v1/payments", status: 200, body: "list "},
+		{name: "param route", method: "GET", path: "/api/v1/payments/" + testID, status: 200, body: "get " + testID},
+		{name: "static segment wins over param", method: "GET", path: "/api/v1/payments/summary", status: 200, body: "summary "},
+		{name: "nested route", method: "POST", path: "/api/v1/payments/" + testID + "/capture", status: 200, body: "capture " + testID},
+		{name: "prefix route", method: "GET", path: "/swagger/index.html", status: 200, body: "swagger "},
+		{name: "extra segment is not matched", method: "GET", path: "/api/v1/payments/" + testID + "/anything", status: 404},
+		{name: "unknown path", method: "GET", path: "/api/v1/unknown", status: 404},
+		{name: "partial path", method: "GET", path: "/api/v1", status: 404},
+		{name: "empty param", method: "GET", path: "/api/v1/payments//capture", status: 404},
+		{name: "not found as problem", method: "GET", path: "/api/v1/unknown", accept: response.ProblemContentType, status: 404, problem: true},
+
+		// Method not allowed
+		{name: "wrong method on param route", method: "PUT", path: "/api/v1/payments/" + testID, status: 405, allow: "DELETE, GET, OPTIONS, PATCH"},
+		{name: "wrong method on static route", method: "DELETE", path: "/api/v1/payments", status: 405, allow: "GET, OPTIONS, POST"},
+		{name: "wrong method on static sibling", method: "PATCH", path: "/api/v1/payments/summary", status: 405, allow: "GET, OPTIONS"},
+		{name: "wrong method on prefix route", method: "POST", path: "/swagger/index.html", status: 405, allow: "GET, OPTIONS"},
+		{name: "method not allowed as problem", method: "PUT", path: "/api/v1/payments/" + testID, accept: response.ProblemContentType, status: 405, allow: "DELETE, GET, OPTIONS, PATCH", problem: true},
+
+		// OPTIONS
+		{name: "options on static route", method: "OPTIONS", path: "/api/v1/payments", status: 204, allow: "GET, OPTIONS, POST"},
+		{name: "options on param route", method: "OPTIONS", path: "/api/v1/payments/" + testID + "/capture", status: 204, allow: "OPTIONS, POST"},
+		{name: "options handled by route", method: "OPTIONS", path: "/api/v1/webhooks", status: 200, body: "webhook options "},
+		{name: "options on unknown path", method: "OPTIONS", path: "/api/v1/unknown", status: 404},
+
+		// Trailing slash redirects
+		{name: "trailing slash without redirect", method: "GET", path: "/api/v1/payments/", status: 404},
+		{name: "trailing slash on GET", redirect: true, method: "GET", path: "/api/v1/payments/", status: 301, location: "/api/v1/payments"},
+		{name: "trailing slash keeps query", redirect: true, method: "GET", path: "/api/v1/payments/" + testID + "/?expand=fees", status: 301, location: "/api/v1/payments/" + testID + "?expand=fees"},
+		{name: "trailing slash on POST", redirect: true, method: "POST", path: "/api/v1/payments/", status: 308, location: "/api/v1/payments"},
+		{name: "missing slash on prefix route", redirect: true, method: "GET", path: "/swagger", status: 301, location: "/swagger/"},
+		{name: "trailing slash on unknown path", redirect: true, method: "GET", path: "/api/v1/unknown/", status: 404},
+		{name: "no redirect off the API", redirect: true, method: "GET", path: "//evil.example/", status: 404},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+			newTestRouter(tc.redirect).ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Errorf("status %d, want %d", rec.Code, tc.status)
+			}
+			if tc.body != "" && rec.Body.String() != tc.body {
+				t.Errorf("body %q, want %q", rec.Body, tc.body)
+			}
+			if got := rec.Header().Get("Allow"); got != tc.allow {
+				t.Errorf("Allow %q, want %q", got, tc.allow)
+			}
+			if got := rec.Header().Get("Location"); got != tc.location {
+				t.Errorf("Location %q, want %q", got, tc.location)
+			}
+			if tc.status == 404 || tc.status == 405 {
+				want := "application/json"
+				if tc.problem {
+					want = response.ProblemContentType
+				}
+				if got := rec.Header().Get("Content-Type"); got != want {
+					t.Errorf("Content-Type %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+)
+
+// node is a node of the radix tree routes are matched against. Static nodes hold a run of
+// path text shared by every route below them; param nodes hold one {name} or
+// {name:constraint} segment. Static children are tried before params, and params in the
+// order they were registered, so /payments/summary wins over /payments/{id}.
+type node struct {
+	path   string
+	static []*node
+
+	params     []*node
+	param      string
+	constraint string
+	accept     func(string) bool
+
+	handlers map[string]http.HandlerFunc
+}
+
+// insert adds the rest of a pattern below n and returns the node it ends at.
+func (n *node) insert(pattern string) *node {
+	if pattern == "" {
+		return n
+	}
+
+	if pattern[0] == '{' {
+		end := strings.IndexByte(pattern, '}')
+		name, constraint, _ := strings.Cut(pattern[1:end], ":")
+		for _, child := range n.params {
+			if child.param == name && child.constraint == constraint {
+				return child.insert(pattern[end+1:])
+			}
+		}
+		child := &node{param: name, constraint: constraint, accept: constraints[constraint]}
+		n.params = append(n.params, child)
+		return child.insert(pattern[end+1:])
+	}
+
+	end := strings.IndexByte(pattern, '{')
+	if end < 0 {
+		end = len(pattern)
+	}
+	static := pattern[:end]
+	for _, child := range n.static {
+		l := commonPrefix(child.path, static)
+		if l == 0 {
+			continue
+		}
+		if l < len(child.path) {
+			// Split the edge: child keeps the shared text and the rest moves below it
+			rest := &node{path: child.path[l:], static: child.static, params: child.params, handlers: child.handlers}
+			child.path = child.path[:l]
+			child.static = []*node{rest}
+			child.params = nil
+			child.handlers = nil
+		}
+		return child.insert(pattern[l:])
+	}
+	child := &node{path: static}
+	n.static = append(n.static, child)
+	return child.insert(pattern[len(static):])
+}
+
+// find returns the node that path, the part of the request path after n, ends at, and
+// fills params with the segments it matched. Only nodes with handlers end a match.
+func (n *node) find(path string, params Params) *node {
+	if path == "" {
+		if n.handlers != nil {
+			return n
+		}
+		return nil
+	}
+
+	for _, child := range n.static {
+		if child.path[0] != path[0] {
+			continue
+		}
+		if strings.HasPrefix(path, child.path) {
+			if found := child.find(path[len(child.path):], params); found != nil {
+				return found
+			}
+		}
+		// Static children never share a first byte
+		break
+	}
+
+	end := strings.IndexByte(path, '/')
+	if end < 0 {
+		end = len(path)
+	}
+	value := path[:end]
+	if value == "" {
+		return nil
+	}
+	for _, child := range n.params {
+		if child.accept != nil && !child.accept(value) {
+			continue
+		}
+		if found := child.find(path[end:], params); found != nil {
+			params[child.param] = value
+			return found
+		}
+	}
+	return nil
+}
+
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
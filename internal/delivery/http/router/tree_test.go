@@ -0,0 +1,187 @@
+package router
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type benchRoute struct{ method, path string }
+
+// benchRoutes mirrors the route table SetupHandler registers.
+var benchRoutes = []benchRoute{
+	{"GET", "/healthz"},
+	{"PUT", "/api/v1/payments/status/{id:uuid}"},
+	{"POST", "/api/v1/payments/{id:uuid}/authorize"},
+	{"POST", "/api/v1/payments/{id:uuid}/capture"},
+	{"POST", "/api/v1/payments/{id:uuid}/refund"},
+	{"POST", "/api/v1/payments/{id:uuid}/void"},
+	{"POST", "/api/v1/payments/{id:uuid}/review"},
+	{"GET", "/api/v1/payments/{id:uuid}/fees"},
+	{"GET", "/api/v1/payments/{id:uuid}/qr"},
+	{"GET", "/api/v1/payments/summary"},
+	{"GET", "/api/v1/payments/{id:uuid}"},
+	{"GET", "/api/v1/payments"},
+	{"POST", "/api/v1/payments"},
+	{"PATCH", "/api/v1/payments/{id:uuid}"},
+	{"DELETE", "/api/v1/payments/{id:uuid}"},
+	{"GET", "/api/v1/customers/{id:uuid}/payments"},
+	{"GET", "/api/v1/customers/{id:uuid}"},
+	{"PUT", "/api/v1/customers/{id:uuid}"},
+	{"DELETE", "/api/v1/customers/{id:uuid}"},
+	{"GET", "/api/v1/customers"},
+	{"POST", "/api/v1/customers"},
+	{"POST", "/api/v1/provider-callbacks/{provider}"},
+	{"GET", "/api/v1/provider-callbacks"},
+	{"GET", "/api/v1/fx-rates"},
+	{"POST", "/api/v1/fx-rates"},
+	{"POST", "/api/v1/fees/quote"},
+	{"PUT", "/api/v1/fee-rules/{id:uuid}"},
+	{"DELETE", "/api/v1/fee-rules/{id:uuid}"},
+	{"GET", "/api/v1/fee-rules"},
+	{"POST", "/api/v1/fee-rules"},
+	{"GET", "/api/v1/disputes/{id:uuid}/evidence/{evidence_id:uuid}"},
+	{"POST", "/api/v1/disputes/{id:uuid}/evidence"},
+	{"PUT", "/api/v1/disputes/{id:uuid}/status"},
+	{"GET", "/api/v1/disputes/{id:uuid}"},
+	{"GET", "/api/v1/disputes"},
+	{"POST", "/api/v1/disputes"},
+	{"POST", "/api/v1/payment-links/{id:uuid}/revoke"},
+	{"GET", "/api/v1/payment-links/{id:uuid}"},
+	{"POST", "/api/v1/payment-links"},
+	{"POST", "/api/v1/virtual-accounts/{number}/credit"},
+	{"GET", "/api/v1/virtual-accounts/{number}"},
+	{"POST", "/api/v1/virtual-accounts"},
+	{"POST", "/api/v1/invoices/{id:uuid}/payments"},
+	{"POST", "/api/v1/invoices/{id:uuid}/finalize"},
+	{"POST", "/api/v1/invoices/{id:uuid}/void"},
+	{"GET", "/api/v1/invoices/{id:uuid}"},
+	{"PUT", "/api/v1/invoices/{id:uuid}"},
+	{"GET", "/api/v1/invoices"},
+	{"POST", "/api/v1/invoices"},
+	{"GET", "/api/v1/settlement-batches/{id:uuid}/payout-file"},
+	{"PUT", "/api/v1/settlement-batches/{id:uuid}/status"},
+	{"GET", "/api/v1/settlement-batches/{id:uuid}"},
+	{"GET", "/api/v1/settlement-batches"},
+	{"POST", "/api/v1/settlement-batches"},
+	{"GET", "/api/v1/admin/permissions"},
+	{"POST", "/api/v1/admin/api-keys/{id:uuid}/revoke"},
+	{"GET", "/api/v1/admin/api-keys"},
+	{"POST", "/api/v1/admin/api-keys"},
+	{"POST", "/pay/{token}/confirm"},
+	{"GET", "/pay/{token}"},
+}
+
+var benchRequests = []struct{ name, method, path string }{
+	{"static", "GET", "/api/v1/payments"},
+	{"param", "GET", "/api/v1/payments/" + testID},
+	{"nested param", "POST", "/api/v1/payments/" + testID + "/capture"},
+	{"two params", "GET", "/api/v1/disputes/" + testID + "/evidence/" + testID},
+	{"late route", "POST", "/api/v1/admin/api-keys/" + testID + "/revoke"},
+	{"not found", "GET", "/api/v1/unknown/" + testID},
+}
+
+var constraint = regexp.MustCompile(`:[a-z]+\}`)
+
+// scanPath is the matcher the router used before the tree: each request was split and
+// compared segment by segment against every route in registration order.
+func scanPath(pattern, path string) (map[string]string, bool) {
+	patternParts := strings.Split(pattern, "/")
+	pathParts := strings.Split(path, "/")
+
+	if len(patternParts) != len(pathParts) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i := 0; i < len(patternParts); i++ {
+		if strings.HasPrefix(patternParts[i], "{") && strings.HasSuffix(patternParts[i], "}") {
+			if pathParts[i] == "" {
+				return nil, false
+			}
+			params[patternParts[i][1:len(patternParts[i])-1]] = pathParts[i]
+			continue
+		}
+		if patternParts[i] != pathParts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// newScanRoutes returns the bench routes without constraints, which the scan did not know.
+func newScanRoutes() []benchRoute {
+	routes := make([]benchRoute, 0, len(benchRoutes))
+	for _, r := range benchRoutes {
+		routes = append(routes, benchRoute{method: r.method, path: constraint.ReplaceAllString(r.path, "}")})
+	}
+	return routes
+}
+
+func scanMatch(routes []benchRoute, method, path string) (Params, bool) {
+	for _, r := range routes {
+		params, ok := scanPath(r.path, path)
+		if ok && r.method == method {
+			return params, true
+		}
+	}
+	return nil, false
+}
+
+func newBenchTree() *node {
+	root := &node{}
+	for _, r := range benchRoutes {
+		n := root.insert(r.path)
+		if n.handlers == nil {
+			n.handlers = make(map[string]http.HandlerFunc)
+		}
+		n.handlers[r.method] = func(http.ResponseWriter, *http.Request) {}
+	}
+	return root
+}
+
+func treeMatch(root *node, method, path string) (Params, bool) {
+	params := Params{}
+	n := root.find(path, params)
+	if n == nil {
+		return nil, false
+	}
+	_, ok := n.handlers[method]
+	return params, ok
+}
+
+// The benchmark only means something if both matchers agree on every request.
+func TestTreeMatchesLikeScan(t *testing.T) {
+	root, routes := newBenchTree(), newScanRoutes()
+	for _, req := range benchRequests {
+		want, wantOK := scanMatch(routes, req.method, req.path)
+		got, gotOK := treeMatch(root, req.method, req.path)
+		if gotOK != wantOK || len(got) != len(want) {
+			t.Errorf("%s %s: tree matched %v %v, scan %v %v", req.method, req.path, gotOK, got, wantOK, want)
+			continue
+		}
+		for k, v := range want {
+			if got[k] != v {
+				t.Errorf("%s %s: param %s is %q, want %q", req.method, req.path, k, got[k], v)
+			}
+		}
+	}
+}
+
+func BenchmarkMatch(b *testing.B) {
+	root, routes := newBenchTree(), newScanRoutes()
+	for _, req := range benchRequests {
+		b.Run(req.name+"/tree", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				treeMatch(root, req.method, req.path)
+			}
+		})
+		b.Run(req.name+"/scan", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				scanMatch(routes, req.method, req.path)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package settlement
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// GenerateSettlementBatches godoc
+// @Summary      Generate settlement batches
+// @Description  Batches captured, unsettled payments per currency, net of fees and refunds. Refunds on payments already paid out are deducted as adjustments. Runs at the last configured cutoff unless cutoff_at is given
+// @Tags         settlements
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.GenerateSettlementRequest  false  "Cutoff to batch up to"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/settlement-batches [post]
+func (h *SettlementHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Generate settlement batches request")
+	var req request.GenerateSettlementRequest
+	if err := validation.DecodeOptional(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "settlementBatches", "generateSettlementBatches", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "settlementBatches", "generateSettlementBatches", err)
+		return
+	}
+
+	batches, err := h.SettlementUC.Generate(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "settlementBatches", "generateSettlementBatches", "Generate Settlement Batches", err)
+		return
+	}
+	h.Logger.Info().Int("count", len(batches)).Msg("✅ Successfully generated settlement batches")
+	response.Success(w, 201, "settlementBatches", "generateSettlementBatches", "Success Generate Settlement Batches", batches)
+}
@@ -0,0 +1,33 @@
+package settlement
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetAllSettlementBatches godoc
+// @Summary      Get list of settlement batches
+// @Description  List settlement batches, newest first
+// @Tags         settlements
+// @Produce      json
+// @Param        status    query    string  false  "Filter by batch status"
+// @Param        currency  query    string  false  "Filter by currency"
+// @Param        page      query    int     false  "Page number"
+// @Param        per_page  query    int     false  "Limit per page"
+// @Security     BearerAuth
+// @Success      200     {object}  response.APIResponse
+// @Failure      500     {object}  response.APIResponse
+// @Router       /api/v1/settlement-batches [get]
+func (h *SettlementHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetAll settlement batches request")
+	params := request.ParseSettlementBatchQueryParams(r)
+	batches, err := h.SettlementUC.GetAll(r.Context(), params)
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to fetch settlement batches, general")
+		response.FailedWithMeta(w, 500, "settlementBatches", "getAllSettlementBatches", "Error Get All Settlement Batches", nil)
+		return
+	}
+	h.Logger.Info().Int("count", len(batches)).Msg("✅ Successfully fetched settlement batches")
+	response.SuccessWithMeta(w, 200, "settlementBatches", "getAllSettlementBatches", "Success Get All Settlement Batches", &params, batches)
+}
@@ -0,0 +1,33 @@
+package settlement
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetSettlementBatchByID godoc
+// @Summary      Get settlement batch by ID
+// @Description  Get a settlement batch with the payments it settles
+// @Tags         settlements
+// @Produce      json
+// @Param        id   path      string  true  "UUID of the settlement batch"
+// @Security     BearerAuth
+// @Success      200  {object}  response.APIResponse
+// @Failure      404  {object}  response.APIResponse
+// @Failure      422  {object}  response.APIResponse
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/settlement-batches/{id} [get]
+func (h *SettlementHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetByID settlement batch request")
+	id, ok := h.parseID(w, r, "getSettlementBatchByID", "Get Settlement Batch by ID")
+	if !ok {
+		return
+	}
+	batch, err := h.SettlementUC.GetByID(r.Context(), id)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "settlementBatches", "getSettlementBatchByID", "Get Settlement Batch by ID", err)
+		return
+	}
+	h.Logger.Info().Str("data", batch.ID.String()).Msg("✅ Successfully get settlement batch by id")
+	response.Success(w, 200, "settlementBatches", "getSettlementBatchByID", "Success Get Settlement Batch by ID", batch)
+}
@@ -0,0 +1,56 @@
+package settlement
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"net/http"
+)
+
+// GetSettlementPayoutFile godoc
+// @Summary      Download payout file of a settlement batch
+// @Description  Render the payout instruction of a batch as an ISO 20022 pain.001 credit transfer or as a CSV in the configured layout
+// @Tags         settlements
+// @Produce      xml
+// @Produce      text/csv
+// @Param        id      path      string  true   "UUID of the settlement batch"
+// @Param        format  query     string  false  "pain001 (default) or csv"
+// @Security     BearerAuth
+// @Success      200  {string}  string  "Payout file"
+// @Failure      404  {object}  response.APIResponse  "Settlement batch not found"
+// @Failure      422  {object}  response.APIResponse  "Invalid UUID or format"
+// @Failure      500  {object}  response.APIResponse
+// @Router       /api/v1/settlement-batches/{id}/payout-file [get]
+func (h *SettlementHandler) GetPayoutFile(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetPayoutFile settlement batch request")
+	id, ok := h.parseID(w, r, "getSettlementPayoutFile", "Get Settlement Payout File")
+	if !ok {
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = entity.PayoutFormatPain001
+	}
+	if format != entity.PayoutFormatPain001 && format != entity.PayoutFormatCSV {
+		h.Logger.Error().Str("format", format).Msg("❌ Failed to get payout file, unsupported format")
+		response.Failed(w, 422, "settlementBatches", "getSettlementPayoutFile", "Format must be pain001 or csv, Get Settlement Payout File")
+		return
+	}
+
+	file, err := h.SettlementUC.PayoutFile(r.Context(), id, format)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "settlementBatches", "getSettlementPayoutFile", "Get Settlement Payout File", err)
+		return
+	}
+
+	contentType, extension := "application/xml; charset=utf-8", "xml"
+	if format == entity.PayoutFormatCSV {
+		contentType, extension = "text/csv; charset=utf-8", "csv"
+	}
+	// Payout files carry bank account numbers
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="settlement-`+id.String()+`.`+extension+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(file)
+	h.Logger.Info().Str("batch_id", id.String()).Str("format", format).Msg("✅ Successfully rendered settlement payout file")
+}
@@ -0,0 +1,30 @@
+package settlement
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"net/http"
+)
+
+type SettlementHandler struct {
+	SettlementUC usecase.SettlementUseCase
+	Logger       zerolog.Logger
+}
+
+func NewSettlementHandler(settlementUC usecase.SettlementUseCase, logger zerolog.Logger) *SettlementHandler {
+	return &SettlementHandler{SettlementUC: settlementUC, Logger: logger}
+}
+
+// parseID reads and validates the batch UUID path parameter.
+func (h *SettlementHandler) parseID(w http.ResponseWriter, r *http.Request, state, action string) (uuid.UUID, bool) {
+	id, err := router.ParamsOf(r).UUID("id")
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to " + action + ", invalid UUID parameter")
+		response.Failed(w, 422, "settlementBatches", state, "Invalid UUID, "+action)
+		return uuid.Nil, false
+	}
+	return id, true
+}
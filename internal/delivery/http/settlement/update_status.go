@@ -0,0 +1,51 @@
+package settlement
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// UpdateSettlementBatchStatus godoc
+// @Summary      Update settlement batch status
+// @Description  Moves a batch GENERATED → SENT once its payout file is sent to the bank, and SENT → CONFIRMED once the bank confirms the transfer
+// @Tags         settlements
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                                 true  "UUID of the settlement batch"
+// @Param        request  body      request.UpdateSettlementStatusRequest  true  "Target status"
+// @Success      200      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/settlement-batches/{id}/status [put]
+func (h *SettlementHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Update settlement batch status request")
+	id, ok := h.parseID(w, r, "updateSettlementBatchStatus", "Update Settlement Batch Status")
+	if !ok {
+		return
+	}
+	var req request.UpdateSettlementStatusRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "settlementBatches", "updateSettlementBatchStatus", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "settlementBatches", "updateSettlementBatchStatus", err)
+		return
+	}
+
+	batch, err := h.SettlementUC.UpdateStatus(r.Context(), id, &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "settlementBatches", "updateSettlementBatchStatus", "Update Settlement Batch Status", err)
+		return
+	}
+	h.Logger.Info().Str("data", batch.ID.String()).Str("status", batch.Status).Msg("✅ Successfully updated settlement batch status")
+	response.Success(w, 200, "settlementBatches", "updateSettlementBatchStatus", "Success Update Settlement Batch Status", batch)
+}
@@ -0,0 +1,54 @@
+package virtualaccount
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// CreditVirtualAccount godoc
+// @Summary      Simulate a transfer into a virtual account
+// @Description  Matches an incoming bank transfer to its payment. Exact and, by policy, over- or accumulated underpayments mark the payment PAID; late, closed or out-of-policy transfers are recorded as REJECTED. Replaying a transfer reference returns the recorded credit.
+// @Tags         virtual-accounts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        number   path      string                                true  "Virtual account number"
+// @Param        request  body      request.CreditVirtualAccountRequest  true  "Incoming transfer"
+// @Success      200      {object}  response.APIResponse  "Transfer already recorded"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/virtual-accounts/{number}/credit [post]
+func (h *VirtualAccountHandler) Credit(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Credit virtual account request")
+	var req request.CreditVirtualAccountRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "virtualAccounts", "creditVirtualAccount", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "virtualAccounts", "creditVirtualAccount", err)
+		return
+	}
+
+	credit, applied, err := h.VirtualAccountUC.Credit(r.Context(), router.GetParam(r, "number"), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "virtualAccounts", "creditVirtualAccount", "Credit Virtual Account", err)
+		return
+	}
+	if !applied {
+		h.Logger.Info().Str("data", credit.ID.String()).Msg("✅ Transfer already recorded")
+		response.Success(w, 200, "virtualAccounts", "creditVirtualAccount", "Transfer Already Recorded", credit)
+		return
+	}
+	h.Logger.Info().Str("data", credit.ID.String()).Str("outcome", credit.Outcome).Msg("✅ Successfully credited virtual account")
+	response.Success(w, 201, "virtualAccounts", "creditVirtualAccount", "Success Credit Virtual Account", credit)
+}
@@ -0,0 +1,28 @@
+package virtualaccount
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/http/router"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"net/http"
+)
+
+// GetVirtualAccountByNumber godoc
+// @Summary      Get virtual account by number
+// @Description  Retrieve a virtual account with every transfer credited to it
+// @Tags         virtual-accounts
+// @Security     BearerAuth
+// @Param        number  path      string  true  "Virtual account number"
+// @Success      200     {object}  response.APIResponse
+// @Failure      404     {object}  response.APIResponse  "Virtual account not found"
+// @Failure      500     {object}  response.APIResponse  "Internal server error"
+// @Router       /api/v1/virtual-accounts/{number} [get]
+func (h *VirtualAccountHandler) GetByNumber(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming GetByNumber virtual account request")
+	va, err := h.VirtualAccountUC.GetByNumber(r.Context(), router.GetParam(r, "number"))
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "virtualAccounts", "getVirtualAccountByNumber", "Get Virtual Account by Number", err)
+		return
+	}
+	h.Logger.Info().Str("data", va.ID.String()).Msg("✅ Successfully get virtual account by number")
+	response.Success(w, 200, "virtualAccounts", "getVirtualAccountByNumber", "Success Get Virtual Account by Number", va)
+}
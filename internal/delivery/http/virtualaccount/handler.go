@@ -0,0 +1,15 @@
+package virtualaccount
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/usecase"
+	"github.com/rs/zerolog"
+)
+
+type VirtualAccountHandler struct {
+	VirtualAccountUC usecase.VirtualAccountUseCase
+	Logger           zerolog.Logger
+}
+
+func NewVirtualAccountHandler(virtualAccountUC usecase.VirtualAccountUseCase, logger zerolog.Logger) *VirtualAccountHandler {
+	return &VirtualAccountHandler{VirtualAccountUC: virtualAccountUC, Logger: logger}
+}
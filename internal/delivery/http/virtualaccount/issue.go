@@ -0,0 +1,46 @@
+package virtualaccount
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/delivery/response"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"net/http"
+)
+
+// IssueVirtualAccount godoc
+// @Summary      Issue a virtual account
+// @Description  Assigns a unique virtual account number at the given bank to a pending bank transfer payment
+// @Tags         virtual-accounts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      request.IssueVirtualAccountRequest  true  "Payment and bank"
+// @Success      201      {object}  response.APIResponse
+// @Failure      400      {object}  response.APIResponse
+// @Failure      404      {object}  response.APIResponse
+// @Failure      409      {object}  response.APIResponse
+// @Failure      422      {object}  response.APIResponse
+// @Failure      500      {object}  response.APIResponse
+// @Router       /api/v1/virtual-accounts [post]
+func (h *VirtualAccountHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	h.Logger.Info().Msg("📥 Incoming Issue virtual account request")
+	var req request.IssueVirtualAccountRequest
+	if err := validation.Decode(w, r, &req); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Failed to decode request body")
+		response.Invalid(w, "virtualAccounts", "issueVirtualAccount", err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.Logger.Error().Err(err).Msg("❌ Validation error")
+		response.Invalid(w, "virtualAccounts", "issueVirtualAccount", err)
+		return
+	}
+
+	va, err := h.VirtualAccountUC.Issue(r.Context(), &req)
+	if err != nil {
+		response.ErrorLogged(w, h.Logger, "virtualAccounts", "issueVirtualAccount", "Issue Virtual Account", err)
+		return
+	}
+	h.Logger.Info().Str("data", va.ID.String()).Msg("✅ Successfully issued virtual account")
+	response.Success(w, 201, "virtualAccounts", "issueVirtualAccount", "Success Issue Virtual Account", va)
+}
@@ -0,0 +1,83 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+)
+
+// IssueAPIKeyRequest issues a key for the merchant of the caller.
+type IssueAPIKeyRequest struct {
+	Name      string     `json:"name" example:"Checkout backend" validate:"required,max=100"`
+	Type      string     `json:"type" example:"BEARER" validate:"enum=BEARER|HMAC"`
+	Scopes    []string   `json:"scopes" example:"payments:read,payments:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *IssueAPIKeyRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Type = strings.ToUpper(strings.TrimSpace(r.Type))
+	if r.Type == "" {
+		r.Type = entity.APIKeyTypeBearer
+	}
+
+	errs := validation.Struct(r)
+	seen := make(map[string]bool, len(r.Scopes))
+	scopes := make([]string, 0, len(r.Scopes))
+	for i, scope := range r.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !entity.IsGrantableScope(scope) {
+			field := fmt.Sprintf("scopes[%d]", i)
+			if entity.IsValidScope(scope) {
+				errs.Add(field, validation.CodeNotAllowed, "scope "+scope+" cannot be granted to merchant keys")
+			} else {
+				errs.Add(field, validation.CodeNotAllowed, "scope "+scope+" is not known")
+			}
+			continue
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	r.Scopes = scopes
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		errs.Add("expires_at", validation.CodeInvalid, "expires_at must be in the future")
+	}
+	return errs.Err()
+}
+
+type APIKeyListQueryParams struct {
+	Revoked *bool `json:"revoked"`
+	Page    int   `json:"page"`
+	PerPage int   `json:"per_page"`
+}
+
+func ParseAPIKeyQueryParams(r *http.Request) APIKeyListQueryParams {
+	q := r.URL.Query()
+
+	var revoked *bool
+	if v, err := strconv.ParseBool(q.Get("revoked")); err == nil {
+		revoked = &v
+	}
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	return APIKeyListQueryParams{
+		Revoked: revoked,
+		Page:    page,
+		PerPage: perPage,
+	}
+}
@@ -0,0 +1,49 @@
+package request
+
+import (
+	"net/mail"
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+)
+
+type CustomerRequest struct {
+	Name              string  `json:"name" validate:"required,max=200"`
+	Email             string  `json:"email" validate:"max=254"`
+	Phone             string  `json:"phone" validate:"pattern=phone"`
+	ExternalReference *string `json:"external_reference" validate:"max=128"`
+}
+
+func (r *CustomerRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Email = strings.TrimSpace(r.Email)
+	r.Phone = strings.ReplaceAll(strings.TrimSpace(r.Phone), " ", "")
+	if r.ExternalReference != nil {
+		ref := strings.TrimSpace(*r.ExternalReference)
+		if ref == "" {
+			r.ExternalReference = nil
+		} else {
+			r.ExternalReference = &ref
+		}
+	}
+
+	errs := validation.Struct(r)
+	if r.Email == "" && r.Phone == "" {
+		errs.Add("email", validation.CodeRequired, "email or phone is required")
+	}
+	if r.Email != "" {
+		addr, err := mail.ParseAddress(r.Email)
+		if err != nil || addr.Address != r.Email {
+			errs.Add("email", validation.CodeFormat, "email is not a valid address")
+		}
+		r.Email = strings.ToLower(r.Email)
+	}
+	return errs.Err()
+}
+
+type CustomerListQueryParams struct {
+	Email             string `json:"email"`
+	ExternalReference string `json:"external_reference"`
+	Page              int    `json:"page"`
+	PerPage           int    `json:"per_page"`
+}
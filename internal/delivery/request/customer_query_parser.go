@@ -0,0 +1,27 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func ParseCustomerQueryParams(r *http.Request) CustomerListQueryParams {
+	q := r.URL.Query()
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	return CustomerListQueryParams{
+		Email:             strings.ToLower(q.Get("email")),
+		ExternalReference: q.Get("external_reference"),
+		Page:              page,
+		PerPage:           perPage,
+	}
+}
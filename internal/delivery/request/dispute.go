@@ -0,0 +1,152 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+// MaxDisputeEvidenceSize bounds a single evidence attachment.
+const MaxDisputeEvidenceSize = 5 << 20
+
+type OpenDisputeRequest struct {
+	PaymentID      uuid.UUID           `json:"payment_id" validate:"required"`
+	ReasonCode     string              `json:"reason_code" example:"PRODUCT_NOT_RECEIVED" validate:"required,enum=dispute_reason"`
+	Reason         string              `json:"reason" example:"Customer says the parcel never arrived" validate:"max=1000"`
+	Amount         valueobject.Decimal `json:"amount" swaggertype:"string" validate:"positive,scale=2"`
+	ProviderCaseID string              `json:"provider_case_id" validate:"max=128"`
+	EvidenceDueAt  *time.Time          `json:"evidence_due_at"`
+}
+
+func (r *OpenDisputeRequest) Validate() error {
+	r.ReasonCode = strings.ToUpper(strings.TrimSpace(r.ReasonCode))
+	r.Reason = strings.TrimSpace(r.Reason)
+	r.ProviderCaseID = strings.TrimSpace(r.ProviderCaseID)
+
+	errs := validation.Struct(r)
+	if r.EvidenceDueAt != nil && !r.EvidenceDueAt.After(time.Now()) {
+		errs.Add("evidence_due_at", validation.CodeInvalid, "evidence_due_at must be in the future")
+	}
+	return errs.Err()
+}
+
+type UpdateDisputeStatusRequest struct {
+	Status        string     `json:"status" example:"EVIDENCE_REQUIRED" validate:"required,enum=EVIDENCE_REQUIRED|SUBMITTED|WON|LOST"`
+	EvidenceDueAt *time.Time `json:"evidence_due_at"`
+}
+
+func (r *UpdateDisputeStatusRequest) Validate() error {
+	r.Status = strings.ToUpper(strings.TrimSpace(r.Status))
+
+	errs := validation.Struct(r)
+	if r.EvidenceDueAt != nil {
+		if r.Status != entity.DisputeStatusEvidenceRequired {
+			errs.Add("evidence_due_at", validation.CodeNotAllowed, "evidence_due_at can only be set with EVIDENCE_REQUIRED")
+		} else if !r.EvidenceDueAt.After(time.Now()) {
+			errs.Add("evidence_due_at", validation.CodeInvalid, "evidence_due_at must be in the future")
+		}
+	}
+	return errs.Err()
+}
+
+// AddDisputeEvidenceRequest carries one attachment; Content is base64 in JSON. Evidence
+// without a file, such as a written statement, only needs a description.
+type AddDisputeEvidenceRequest struct {
+	Type        string `json:"type" example:"SHIPPING_PROOF" validate:"required,enum=dispute_evidence_type"`
+	Description string `json:"description" example:"Courier delivery confirmation" validate:"max=1000"`
+	FileName    string `json:"file_name" example:"delivery.pdf" validate:"max=255"`
+	ContentType string `json:"content_type" example:"application/pdf" validate:"max=255"`
+	Content     []byte `json:"content" swaggertype:"string" format:"base64"`
+}
+
+func (r *AddDisputeEvidenceRequest) Validate() error {
+	r.Type = strings.ToUpper(strings.TrimSpace(r.Type))
+	r.Description = strings.TrimSpace(r.Description)
+	r.FileName = strings.TrimSpace(r.FileName)
+	r.ContentType = strings.TrimSpace(r.ContentType)
+
+	errs := validation.Struct(r)
+	if len(r.Content) == 0 {
+		if r.Description == "" {
+			errs.Add("content", validation.CodeRequired, "content or description is required")
+		}
+		if r.FileName != "" || r.ContentType != "" {
+			errs.Add("content", validation.CodeRequired, "file_name and content_type require content")
+		}
+		return errs.Err()
+	}
+	if len(r.Content) > MaxDisputeEvidenceSize {
+		errs.Add("content", validation.CodeTooLong, fmt.Sprintf("content must be at most %d bytes", MaxDisputeEvidenceSize))
+	}
+	if r.FileName == "" {
+		errs.Add("file_name", validation.CodeRequired, "file_name is required with content")
+	} else if strings.ContainsAny(r.FileName, "/\\\"\r\n") {
+		errs.Add("file_name", validation.CodeFormat, "file_name must be a plain file name")
+	}
+	if r.ContentType == "" {
+		r.ContentType = http.DetectContentType(r.Content)
+	}
+	return errs.Err()
+}
+
+func (r *AddDisputeEvidenceRequest) ToEntity(disputeID uuid.UUID) entity.DisputeEvidence {
+	return entity.DisputeEvidence{
+		DisputeID:   disputeID,
+		Type:        r.Type,
+		Description: r.Description,
+		FileName:    r.FileName,
+		ContentType: r.ContentType,
+		Size:        int64(len(r.Content)),
+		Content:     r.Content,
+	}
+}
+
+type DisputeListQueryParams struct {
+	PaymentID       *uuid.UUID `json:"payment_id"`
+	Status          string     `json:"status"`
+	DeadlineFlagged *bool      `json:"deadline_flagged"`
+	Page            int        `json:"page"`
+	PerPage         int        `json:"per_page"`
+}
+
+func ParseDisputeQueryParams(r *http.Request) DisputeListQueryParams {
+	q := r.URL.Query()
+
+	var paymentID *uuid.UUID
+	if v := q.Get("payment_id"); v != "" {
+		// An unparsable ID must narrow the result, never silently drop the filter
+		id, err := uuid.Parse(v)
+		if err != nil {
+			id = uuid.Nil
+		}
+		paymentID = &id
+	}
+	var deadlineFlagged *bool
+	if v, err := strconv.ParseBool(q.Get("deadline_flagged")); err == nil {
+		deadlineFlagged = &v
+	}
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	return DisputeListQueryParams{
+		PaymentID:       paymentID,
+		Status:          strings.ToUpper(q.Get("status")),
+		DeadlineFlagged: deadlineFlagged,
+		Page:            page,
+		PerPage:         perPage,
+	}
+}
@@ -0,0 +1,180 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+type FeeRuleRequest struct {
+	MerchantID  *uuid.UUID          `json:"merchant_id"`
+	Name        string              `json:"name" example:"Card IDR standard" validate:"required,max=100"`
+	Method      string              `json:"method" example:"CARD" validate:"enum=payment_method"`
+	Currency    string              `json:"currency" example:"IDR" validate:"pattern=currency"`
+	MinAmount   valueobject.Decimal `json:"min_amount" swaggertype:"string" validate:"nonnegative"`
+	MaxAmount   valueobject.Decimal `json:"max_amount" swaggertype:"string" validate:"nonnegative"`
+	Type        string              `json:"type" example:"STANDARD" validate:"required,enum=STANDARD|TIERED"`
+	FixedAmount valueobject.Decimal `json:"fixed_amount" swaggertype:"string" example:"2000" validate:"nonnegative"`
+	Percentage  valueobject.Decimal `json:"percentage" swaggertype:"string" example:"2.9"`
+	Tiers       []entity.FeeTier    `json:"tiers"`
+	MinFee      valueobject.Decimal `json:"min_fee" swaggertype:"string" validate:"nonnegative"`
+	MaxFee      valueobject.Decimal `json:"max_fee" swaggertype:"string" validate:"nonnegative"`
+	Priority    *int                `json:"priority" example:"100"`
+	Active      *bool               `json:"active" example:"true"`
+}
+
+var hundredPercent = valueobject.DecimalFromInt(100)
+
+func (r *FeeRuleRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Method = strings.ToUpper(strings.TrimSpace(r.Method))
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	r.Type = strings.ToUpper(strings.TrimSpace(r.Type))
+
+	errs := validation.Struct(r)
+	if !r.MinAmount.IsNull() && !r.MaxAmount.IsNull() && r.MinAmount.Cmp(r.MaxAmount) >= 0 {
+		errs.Add("min_amount", validation.CodeInvalid, "min_amount must be less than max_amount")
+	}
+	if !r.MinFee.IsNull() && !r.MaxFee.IsNull() && r.MinFee.Cmp(r.MaxFee) > 0 {
+		errs.Add("min_fee", validation.CodeInvalid, "min_fee must not exceed max_fee")
+	}
+
+	switch r.Type {
+	case entity.FeeRuleTypeStandard:
+		if len(r.Tiers) > 0 {
+			errs.Add("tiers", validation.CodeNotAllowed, "tiers are only allowed for TIERED rules")
+		}
+		if !r.Percentage.IsNull() && !validPercentage(r.Percentage) {
+			errs.Add("percentage", validation.CodeInvalid, "percentage must be between 0 and 100")
+		}
+	case entity.FeeRuleTypeTiered:
+		if !r.Percentage.IsNull() {
+			errs.Add("percentage", validation.CodeNotAllowed, "percentage is not allowed for TIERED rules, use tiers")
+		}
+		validateFeeTiers(r.Tiers, &errs)
+	}
+	return errs.Err()
+}
+
+// validateFeeTiers requires ascending bounds with an unbounded last tier, so every
+// amount is covered exactly once.
+func validateFeeTiers(tiers []entity.FeeTier, errs *validation.Errors) {
+	if len(tiers) == 0 {
+		errs.Add("tiers", validation.CodeRequired, "tiers are required for TIERED rules")
+		return
+	}
+	lower := valueobject.DecimalFromInt(0)
+	for i, tier := range tiers {
+		field := fmt.Sprintf("tiers[%d]", i)
+		if tier.Percentage.IsNull() || !validPercentage(tier.Percentage) {
+			errs.Add(field+".percentage", validation.CodeInvalid, field+".percentage must be between 0 and 100")
+		}
+		last := i == len(tiers)-1
+		if tier.UpTo.IsNull() != last {
+			errs.Add(field+".up_to", validation.CodeInvalid, "only the last tier must have no up_to")
+			continue
+		}
+		if !last {
+			if tier.UpTo.Cmp(lower) <= 0 {
+				errs.Add(field+".up_to", validation.CodeInvalid, "tier up_to must be ascending and positive")
+			}
+			lower = tier.UpTo
+		}
+	}
+}
+
+func validPercentage(p valueobject.Decimal) bool {
+	return p.Sign() >= 0 && p.Cmp(hundredPercent) <= 0
+}
+
+// ToEntity maps the request onto a rule; unset priority and active default to 100 and true.
+func (r *FeeRuleRequest) ToEntity() entity.FeeRule {
+	rule := entity.FeeRule{
+		MerchantID:  r.MerchantID,
+		Name:        r.Name,
+		Method:      r.Method,
+		Currency:    r.Currency,
+		MinAmount:   r.MinAmount,
+		MaxAmount:   r.MaxAmount,
+		Type:        r.Type,
+		FixedAmount: r.FixedAmount,
+		Percentage:  r.Percentage,
+		Tiers:       r.Tiers,
+		MinFee:      r.MinFee,
+		MaxFee:      r.MaxFee,
+		Priority:    100,
+		Active:      true,
+	}
+	if r.Priority != nil {
+		rule.Priority = *r.Priority
+	}
+	if r.Active != nil {
+		rule.Active = *r.Active
+	}
+	return rule
+}
+
+type FeeQuoteRequest struct {
+	Amount   valueobject.Decimal `json:"amount" swaggertype:"string" example:"150000" validate:"required,positive"`
+	Currency string              `json:"currency" example:"IDR" validate:"pattern=currency"`
+	Method   string              `json:"method" example:"CARD" validate:"enum=payment_method"`
+}
+
+func (r *FeeQuoteRequest) Validate() error {
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	r.Method = strings.ToUpper(strings.TrimSpace(r.Method))
+	if r.Currency == "" {
+		r.Currency = entity.DefaultPaymentCurrency
+	}
+	if r.Method == "" {
+		r.Method = entity.PaymentMethodCard
+	}
+	return validation.Struct(r).Err()
+}
+
+type FeeRuleListQueryParams struct {
+	MerchantID *uuid.UUID `json:"merchant_id"`
+	Active     *bool      `json:"active"`
+	Page       int        `json:"page"`
+	PerPage    int        `json:"per_page"`
+}
+
+func ParseFeeRuleQueryParams(r *http.Request) FeeRuleListQueryParams {
+	q := r.URL.Query()
+
+	var merchantID *uuid.UUID
+	if v := q.Get("merchant_id"); v != "" {
+		// An unparsable ID must narrow the result, never silently drop the filter
+		id, err := uuid.Parse(v)
+		if err != nil {
+			id = uuid.Nil
+		}
+		merchantID = &id
+	}
+	var active *bool
+	if v, err := strconv.ParseBool(q.Get("active")); err == nil {
+		active = &v
+	}
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	return FeeRuleListQueryParams{
+		MerchantID: merchantID,
+		Active:     active,
+		Page:       page,
+		PerPage:    perPage,
+	}
+}
@@ -0,0 +1,69 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+type FXRateItem struct {
+	BaseCurrency  string              `json:"base_currency" example:"USD" validate:"required,pattern=currency"`
+	QuoteCurrency string              `json:"quote_currency" example:"IDR" validate:"required,pattern=currency"`
+	Rate          valueobject.Decimal `json:"rate" swaggertype:"string" example:"15600.25" validate:"required,positive"`
+	EffectiveAt   time.Time           `json:"effective_at" example:"2025-08-10T00:00:00Z" validate:"required"`
+	Source        string              `json:"source" example:"bank-indonesia" validate:"max=64"`
+}
+
+// LoadFXRatesRequest is bounded to 1000 rates so one load cannot hold a long transaction.
+type LoadFXRatesRequest struct {
+	Rates []FXRateItem `json:"rates" validate:"required,max=1000"`
+}
+
+func (r *LoadFXRatesRequest) Validate() error {
+	for i := range r.Rates {
+		item := &r.Rates[i]
+		item.BaseCurrency = strings.ToUpper(strings.TrimSpace(item.BaseCurrency))
+		item.QuoteCurrency = strings.ToUpper(strings.TrimSpace(item.QuoteCurrency))
+	}
+
+	errs := validation.Struct(r)
+	for i, item := range r.Rates {
+		if item.BaseCurrency != "" && item.BaseCurrency == item.QuoteCurrency {
+			field := fmt.Sprintf("rates[%d].quote_currency", i)
+			errs.Add(field, validation.CodeInvalid, field+" must differ from base_currency")
+		}
+	}
+	return errs.Err()
+}
+
+type FXRateListQueryParams struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	Page          int    `json:"page"`
+	PerPage       int    `json:"per_page"`
+}
+
+func ParseFXRateQueryParams(r *http.Request) FXRateListQueryParams {
+	q := r.URL.Query()
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	return FXRateListQueryParams{
+		BaseCurrency:  strings.ToUpper(q.Get("base_currency")),
+		QuoteCurrency: strings.ToUpper(q.Get("quote_currency")),
+		Page:          page,
+		PerPage:       perPage,
+	}
+}
@@ -0,0 +1,146 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+type InvoiceItemRequest struct {
+	Description string              `json:"description" example:"Consulting, August" validate:"required,max=255"`
+	Quantity    valueobject.Decimal `json:"quantity" swaggertype:"string" example:"2" validate:"required,positive,scale=4"`
+	UnitPrice   valueobject.Decimal `json:"unit_price" swaggertype:"string" example:"500000" validate:"required,nonnegative,scale=2"`
+	Discount    valueobject.Decimal `json:"discount" swaggertype:"string" example:"0" validate:"nonnegative,scale=2"`
+	TaxRate     valueobject.Decimal `json:"tax_rate" swaggertype:"string" example:"11" validate:"scale=2"`
+}
+
+// InvoiceRequest is bounded to 100 lines per invoice.
+type InvoiceRequest struct {
+	CustomerID *uuid.UUID           `json:"customer_id"`
+	Currency   string               `json:"currency" example:"IDR" validate:"pattern=currency"`
+	DueDate    string               `json:"due_date" example:"2025-09-30" validate:"required"`
+	Notes      string               `json:"notes" validate:"max=1000"`
+	Items      []InvoiceItemRequest `json:"items" validate:"required,max=100"`
+
+	dueDate time.Time
+}
+
+func (r *InvoiceRequest) Validate() error {
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	r.DueDate = strings.TrimSpace(r.DueDate)
+	r.Notes = strings.TrimSpace(r.Notes)
+	if r.Currency == "" {
+		r.Currency = entity.DefaultPaymentCurrency
+	}
+	for i := range r.Items {
+		r.Items[i].Description = strings.TrimSpace(r.Items[i].Description)
+	}
+
+	errs := validation.Struct(r)
+	if r.DueDate != "" {
+		dueDate, err := time.Parse(time.DateOnly, r.DueDate)
+		y, m, d := time.Now().UTC().Date()
+		switch {
+		case err != nil:
+			errs.Add("due_date", validation.CodeFormat, "due_date must be a date in YYYY-MM-DD format")
+		case dueDate.Before(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)):
+			errs.Add("due_date", validation.CodeInvalid, "due_date must not be in the past")
+		default:
+			r.dueDate = dueDate
+		}
+	}
+	for i, item := range r.Items {
+		if !item.TaxRate.IsNull() && !validPercentage(item.TaxRate) {
+			field := fmt.Sprintf("items[%d].tax_rate", i)
+			errs.Add(field, validation.CodeInvalid, field+" must be between 0 and 100")
+		}
+	}
+	return errs.Err()
+}
+
+// ToEntity maps the request onto an invoice; lines without a tax rate get defaultTaxRate.
+func (r *InvoiceRequest) ToEntity(defaultTaxRate valueobject.Decimal) entity.Invoice {
+	items := make([]entity.InvoiceItem, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = entity.InvoiceItem{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Discount:    item.Discount,
+			TaxRate:     item.TaxRate,
+		}
+		if item.TaxRate.IsNull() {
+			items[i].TaxRate = defaultTaxRate
+		}
+	}
+	return entity.Invoice{
+		CustomerID: r.CustomerID,
+		Currency:   r.Currency,
+		Notes:      r.Notes,
+		DueDate:    r.dueDate,
+		Items:      items,
+	}
+}
+
+// CreateInvoicePaymentRequest opens a payment against an invoice; without an amount it
+// collects the whole outstanding amount.
+type CreateInvoicePaymentRequest struct {
+	Amount   valueobject.Decimal  `json:"amount" swaggertype:"string" example:"555000" validate:"positive,scale=2"`
+	Method   string               `json:"method" example:"BANK_TRANSFER" validate:"enum=payment_method"`
+	Metadata valueobject.Metadata `json:"metadata" swaggertype:"object,string"`
+}
+
+func (r *CreateInvoicePaymentRequest) Validate() error {
+	r.Method = strings.ToUpper(strings.TrimSpace(r.Method))
+	return validation.Struct(r).Err()
+}
+
+type InvoiceListQueryParams struct {
+	CustomerID *uuid.UUID `json:"customer_id"`
+	Status     string     `json:"status"`
+	Overdue    *bool      `json:"overdue"`
+	Page       int        `json:"page"`
+	PerPage    int        `json:"per_page"`
+}
+
+func ParseInvoiceQueryParams(r *http.Request) InvoiceListQueryParams {
+	q := r.URL.Query()
+
+	var customerID *uuid.UUID
+	if v := q.Get("customer_id"); v != "" {
+		// An unparsable ID must narrow the result, never silently drop the filter
+		id, err := uuid.Parse(v)
+		if err != nil {
+			id = uuid.Nil
+		}
+		customerID = &id
+	}
+	var overdue *bool
+	if v, err := strconv.ParseBool(q.Get("overdue")); err == nil {
+		overdue = &v
+	}
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	return InvoiceListQueryParams{
+		CustomerID: customerID,
+		Status:     strings.ToUpper(q.Get("status")),
+		Overdue:    overdue,
+		Page:       page,
+		PerPage:    perPage,
+	}
+}
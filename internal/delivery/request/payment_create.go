@@ -0,0 +1,48 @@
+package request
+
+import (
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+// CreatePaymentRequest holds the fields a client sets on a new payment; status, provider
+// and settlement fields are owned by the API. Currency and method default to IDR and CARD.
+type CreatePaymentRequest struct {
+	CustomerID  *uuid.UUID            `json:"customer_id"`
+	Tag         string                `json:"tag" example:"order-1024" validate:"required,max=64"`
+	Description string                `json:"description" example:"Order #1024" validate:"max=500"`
+	Amount      valueobject.BigFloat  `json:"amount" swaggertype:"number" example:"150000" validate:"required,positive,scale=2"`
+	Currency    string                `json:"currency" example:"IDR" validate:"pattern=currency"`
+	Method      string                `json:"method" example:"CARD" validate:"enum=payment_method"`
+	Metadata    valueobject.Metadata  `json:"metadata" swaggertype:"object,string"`
+	Splits      []entity.PaymentSplit `json:"splits"`
+}
+
+func (r *CreatePaymentRequest) Validate() error {
+	r.Tag = strings.TrimSpace(r.Tag)
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	r.Method = strings.ToUpper(strings.TrimSpace(r.Method))
+
+	errs := validation.Struct(r)
+	if err := r.Metadata.Validate(); err != nil {
+		errs.Add("metadata", validation.CodeInvalid, err.Error())
+	}
+	return errs.Err()
+}
+
+func (r *CreatePaymentRequest) ToEntity() entity.Payment {
+	return entity.Payment{
+		CustomerID:  r.CustomerID,
+		Tag:         r.Tag,
+		Description: r.Description,
+		Amount:      r.Amount,
+		Currency:    r.Currency,
+		Method:      r.Method,
+		Metadata:    r.Metadata,
+		Splits:      r.Splits,
+	}
+}
@@ -0,0 +1,34 @@
+package request
+
+import (
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+type AuthorizePaymentRequest struct {
+	CardNumber string `json:"card_number" validate:"min=12,max=19,pattern=digits"`
+}
+
+func (r *AuthorizePaymentRequest) Validate() error {
+	return validation.Struct(r).Err()
+}
+
+type RefundPaymentRequest struct {
+	// Amount is optional; when omitted the remaining refundable amount is refunded.
+	Amount valueobject.BigFloat `json:"amount" validate:"positive,scale=2"`
+}
+
+func (r *RefundPaymentRequest) Validate() error {
+	return validation.Struct(r).Err()
+}
+
+type ReviewPaymentRequest struct {
+	Decision string `json:"decision" example:"APPROVE" validate:"required,enum=APPROVE|REJECT"`
+}
+
+func (r *ReviewPaymentRequest) Validate() error {
+	r.Decision = strings.ToUpper(strings.TrimSpace(r.Decision))
+	return validation.Struct(r).Err()
+}
@@ -0,0 +1,53 @@
+package request
+
+import (
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+// MaxPaymentLinkLifetime bounds how long a payment link may stay valid.
+const MaxPaymentLinkLifetime = 30 * 24 * time.Hour
+
+type CreatePaymentLinkRequest struct {
+	Amount      valueobject.BigFloat `json:"amount" swaggertype:"string" example:"150000" validate:"required,positive"`
+	Currency    string               `json:"currency" example:"IDR" validate:"pattern=currency"`
+	Method      string               `json:"method" example:"CARD" validate:"enum=payment_method"`
+	Description string               `json:"description" example:"Invoice #1024" validate:"max=500"`
+	Tag         string               `json:"tag" validate:"max=64"`
+	CustomerID  *uuid.UUID           `json:"customer_id"`
+	Metadata    valueobject.Metadata `json:"metadata" swaggertype:"object,string"`
+	ExpiresAt   *time.Time           `json:"expires_at"`
+}
+
+func (r *CreatePaymentLinkRequest) Validate() error {
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	r.Method = strings.ToUpper(strings.TrimSpace(r.Method))
+
+	errs := validation.Struct(r)
+	if r.ExpiresAt != nil {
+		now := time.Now()
+		if !r.ExpiresAt.After(now) {
+			errs.Add("expires_at", validation.CodeInvalid, "expires_at must be in the future")
+		} else if r.ExpiresAt.After(now.Add(MaxPaymentLinkLifetime)) {
+			errs.Add("expires_at", validation.CodeInvalid, "expires_at must be within 30 days")
+		}
+	}
+	return errs.Err()
+}
+
+func (r *CreatePaymentLinkRequest) ToPayment() entity.Payment {
+	return entity.Payment{
+		CustomerID:  r.CustomerID,
+		Tag:         r.Tag,
+		Description: r.Description,
+		Amount:      r.Amount,
+		Currency:    r.Currency,
+		Method:      r.Method,
+		Metadata:    r.Metadata,
+	}
+}
@@ -0,0 +1,61 @@
+package request
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// PatchPaymentRequest partially updates a payment. Omitted fields are left untouched;
+// metadata keys are merged and a null value removes the key.
+type PatchPaymentRequest struct {
+	Description *string            `json:"description" validate:"max=500"`
+	Metadata    map[string]*string `json:"metadata"`
+}
+
+func (r *PatchPaymentRequest) Validate() error {
+	errs := validation.Struct(r)
+	if r.Description == nil && r.Metadata == nil {
+		errs.Add("description", validation.CodeRequired, "description or metadata is required")
+	}
+	if r.Description != nil && strings.TrimSpace(*r.Description) == "" {
+		errs.Add("description", validation.CodeRequired, "description must not be empty")
+	}
+	keys := make([]string, 0, len(r.Metadata))
+	for k := range r.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := r.Metadata[k]
+		field := "metadata." + k
+		if err := valueobject.ValidateMetadataKey(k); err != nil {
+			errs.Add(field, validation.CodeFormat, err.Error())
+			continue
+		}
+		if v != nil && utf8.RuneCountInString(*v) > valueobject.MaxMetadataValueLength {
+			errs.Add(field, validation.CodeTooLong, fmt.Sprintf("%s must have at most %d characters", field, valueobject.MaxMetadataValueLength))
+		}
+	}
+	return errs.Err()
+}
+
+// ApplyMetadata merges the requested metadata changes into current.
+func (r *PatchPaymentRequest) ApplyMetadata(current valueobject.Metadata) valueobject.Metadata {
+	merged := valueobject.Metadata{}
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range r.Metadata {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = *v
+	}
+	return merged
+}
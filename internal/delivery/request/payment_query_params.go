@@ -1,5 +1,7 @@
 package request
 
+import "github.com/google/uuid"
+
 type QueryFilter struct {
 	Field string   `json:"field"`
 	Value []string `json:"value"`
@@ -12,12 +14,14 @@ type QueryRange struct {
 }
 
 type PaymentListQueryParams struct {
-	SearchField string        `json:"search_field"`
-	SearchValue string        `json:"search_value"`
-	Filter      []QueryFilter `json:"filter"`
-	Range       []QueryRange  `json:"range"`
-	SortField   string        `json:"sort_field"`
-	SortDir     string        `json:"sort_dir"`
-	Page        int           `json:"page"`
-	PerPage     int           `json:"per_page"`
+	CustomerID  *uuid.UUID        `json:"customer_id,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	SearchField string            `json:"search_field"`
+	SearchValue string            `json:"search_value"`
+	Filter      []QueryFilter     `json:"filter"`
+	Range       []QueryRange      `json:"range"`
+	SortField   string            `json:"sort_field"`
+	SortDir     string            `json:"sort_dir"`
+	Page        int               `json:"page"`
+	PerPage     int               `json:"per_page"`
 }
@@ -1,16 +1,96 @@
 package request
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/google/uuid"
 )
 
-func ParsePaymentQueryParams(r *http.Request) PaymentListQueryParams {
+// Field names in the query are mapped to payment columns through these allow-lists; the
+// repository interpolates the mapped names into SQL, so nothing a client sends reaches
+// the query text.
+var (
+	paymentSearchColumns = map[string]string{
+		"tag":                "tag",
+		"description":        "description",
+		"provider_reference": "provider_reference",
+	}
+	paymentFilterColumns = map[string]string{
+		"id":                  "id",
+		"customer_id":         "customer_id",
+		"invoice_id":          "invoice_id",
+		"tag":                 "tag",
+		"currency":            "currency",
+		"method":              "method",
+		"status":              "status",
+		"provider":            "provider",
+		"provider_reference":  "provider_reference",
+		"settlement_currency": "settlement_currency",
+	}
+	paymentRangeColumns = map[string]string{
+		"amount":            "amount",
+		"refunded_amount":   "refunded_amount",
+		"settlement_amount": "settlement_amount",
+		"created_at":        "created_at",
+		"updated_at":        "updated_at",
+	}
+	paymentSortColumns = map[string]string{
+		"amount":     "amount",
+		"currency":   "currency",
+		"method":     "method",
+		"status":     "status",
+		"tag":        "tag",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+)
+
+// ParsePaymentQueryParams parses the payment list query. A search, filter, range or sort
+// field outside its allow-list, or a sort direction other than ASC or DESC, is rejected
+// with a validation error naming the parameter.
+func ParsePaymentQueryParams(r *http.Request) (PaymentListQueryParams, error) {
 	q := r.URL.Query()
+	var errs validation.Errors
+	column := func(columns map[string]string, param, field string) string {
+		name, ok := columns[field]
+		if !ok {
+			errs.Add(param, validation.CodeNotAllowed, param+" must be one of "+allowed(columns))
+		}
+		return name
+	}
+
+	// Customer
+	var customerID *uuid.UUID
+	if v := q.Get("customer_id"); v != "" {
+		// An unparsable ID must narrow the result, never silently drop the filter
+		id, err := uuid.Parse(v)
+		if err != nil {
+			id = uuid.Nil
+		}
+		customerID = &id
+	}
+
+	// Metadata, e.g. metadata.order_id=123
+	var metadata map[string]string
+	for key, values := range q {
+		if name := strings.TrimPrefix(key, "metadata."); name != key && name != "" && len(values) > 0 {
+			if metadata == nil {
+				metadata = make(map[string]string)
+			}
+			metadata[name] = values[0]
+		}
+	}
 
 	// Search
-	searchField := q.Get("search_field")
+	var searchField string
+	if v := q.Get("search_field"); v != "" {
+		searchField = column(paymentSearchColumns, "search_field", v)
+	}
 	searchValue := q.Get("search_value")
 
 	// Filters
@@ -20,7 +100,7 @@ func ParsePaymentQueryParams(r *http.Request) PaymentListQueryParams {
 	for i := 0; i < len(filterFields) && i < len(filterValues); i++ {
 		values := strings.Split(filterValues[i], ",")
 		filters = append(filters, QueryFilter{
-			Field: filterFields[i],
+			Field: column(paymentFilterColumns, fmt.Sprintf("filter_field[%d]", i), filterFields[i]),
 			Value: values,
 		})
 	}
@@ -32,7 +112,7 @@ func ParsePaymentQueryParams(r *http.Request) PaymentListQueryParams {
 	tos := q["to"]
 
 	for i := 0; i < len(rangeFields); i++ {
-		rng := QueryRange{Field: rangeFields[i]}
+		rng := QueryRange{Field: column(paymentRangeColumns, fmt.Sprintf("range_field[%d]", i), rangeFields[i])}
 		if i < len(froms) {
 			rng.From = &froms[i]
 		}
@@ -43,8 +123,14 @@ func ParsePaymentQueryParams(r *http.Request) PaymentListQueryParams {
 	}
 
 	// Sort
-	sortField := q.Get("sort_field")
+	var sortField string
+	if v := q.Get("sort_field"); v != "" {
+		sortField = column(paymentSortColumns, "sort_field", v)
+	}
 	sortDir := strings.ToUpper(q.Get("sort_direction"))
+	if sortDir != "" && sortDir != "ASC" && sortDir != "DESC" {
+		errs.Add("sort_direction", validation.CodeNotAllowed, "sort_direction must be ASC or DESC")
+	}
 
 	// Pagination
 	page, _ := strconv.Atoi(q.Get("page"))
@@ -56,7 +142,9 @@ func ParsePaymentQueryParams(r *http.Request) PaymentListQueryParams {
 		per_page = 10
 	}
 
-	return PaymentListQueryParams{
+	params := PaymentListQueryParams{
+		CustomerID:  customerID,
+		Metadata:    metadata,
 		SearchField: searchField,
 		SearchValue: searchValue,
 		Filter:      filters,
@@ -66,4 +154,15 @@ func ParsePaymentQueryParams(r *http.Request) PaymentListQueryParams {
 		Page:        page,
 		PerPage:     per_page,
 	}
+	return params, errs.Err()
+}
+
+// allowed lists the field names of columns for error messages.
+func allowed(columns map[string]string) string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
 }
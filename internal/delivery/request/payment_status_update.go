@@ -1,16 +1,11 @@
 package request
 
-import (
-	"errors"
-)
+import "github.com/adf-code/beta-payment-api/internal/delivery/validation"
 
 type UpdatePaymentRequest struct {
-	Status string `json:"status"`
+	Status string `json:"status" validate:"required,enum=payment_status"`
 }
 
 func (r *UpdatePaymentRequest) Validate() error {
-	if r.Status == "" {
-		return errors.New("status is required")
-	}
-	return nil
+	return validation.Struct(r).Err()
 }
@@ -0,0 +1,64 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentSummaryQueryParams struct {
+	CustomerID        *uuid.UUID `json:"customer_id"`
+	From              *time.Time `json:"from"`
+	To                *time.Time `json:"to"`
+	ReportingCurrency string     `json:"reporting_currency"`
+	AsOf              time.Time  `json:"as_of"`
+}
+
+// ParsePaymentSummaryQueryParams parses the summary filters. Times accept RFC 3339 or a
+// plain date; rates for the reporting currency are taken as of as_of, defaulting to now.
+func ParsePaymentSummaryQueryParams(r *http.Request) (PaymentSummaryQueryParams, error) {
+	q := r.URL.Query()
+	params := PaymentSummaryQueryParams{
+		ReportingCurrency: strings.ToUpper(q.Get("reporting_currency")),
+		AsOf:              time.Now(),
+	}
+
+	if v := q.Get("customer_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return params, fmt.Errorf("invalid customer_id")
+		}
+		params.CustomerID = &id
+	}
+	if params.ReportingCurrency != "" && !currencyCodePattern.MatchString(params.ReportingCurrency) {
+		return params, fmt.Errorf("reporting_currency must be an ISO 4217 code")
+	}
+
+	for name, dst := range map[string]**time.Time{"from": &params.From, "to": &params.To} {
+		if v := q.Get(name); v != "" {
+			t, err := parseQueryTime(v)
+			if err != nil {
+				return params, fmt.Errorf("invalid %s", name)
+			}
+			*dst = &t
+		}
+	}
+	if v := q.Get("as_of"); v != "" {
+		t, err := parseQueryTime(v)
+		if err != nil {
+			return params, fmt.Errorf("invalid as_of")
+		}
+		params.AsOf = t
+	}
+	return params, nil
+}
+
+func parseQueryTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
@@ -0,0 +1,40 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+)
+
+type ProviderCallbackListQueryParams struct {
+	Provider       string `json:"provider"`
+	Outcome        string `json:"outcome"`
+	SignatureValid *bool  `json:"signature_valid"`
+	Page           int    `json:"page"`
+	PerPage        int    `json:"per_page"`
+}
+
+func ParseProviderCallbackQueryParams(r *http.Request) ProviderCallbackListQueryParams {
+	q := r.URL.Query()
+
+	var signatureValid *bool
+	if v, err := strconv.ParseBool(q.Get("signature_valid")); err == nil {
+		signatureValid = &v
+	}
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	return ProviderCallbackListQueryParams{
+		Provider:       q.Get("provider"),
+		Outcome:        q.Get("outcome"),
+		SignatureValid: signatureValid,
+		Page:           page,
+		PerPage:        perPage,
+	}
+}
@@ -0,0 +1,22 @@
+package request
+
+import (
+	"regexp"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Patterns and enums the validate tags of request bodies refer to.
+func init() {
+	validation.RegisterPattern("currency", "an ISO 4217 code", currencyCodePattern)
+	// E.164 style numbers, e.g. +6281234567890
+	validation.RegisterPattern("phone", "in international format, e.g. +6281234567890", regexp.MustCompile(`^\+?[1-9][0-9]{6,14}$`))
+	validation.RegisterPattern("digits", "digits only", regexp.MustCompile(`^[0-9]+$`))
+	validation.RegisterEnum("payment_method", "payment method", entity.IsValidPaymentMethod)
+	validation.RegisterEnum("payment_status", "payment status", entity.IsValidPaymentStatus)
+	validation.RegisterEnum("dispute_reason", "dispute reason", entity.IsValidDisputeReasonCode)
+	validation.RegisterEnum("dispute_evidence_type", "evidence type", entity.IsValidDisputeEvidenceType)
+}
@@ -0,0 +1,60 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+)
+
+// GenerateSettlementRequest batches payments captured before CutoffAt, which defaults to
+// the most recent configured cutoff.
+type GenerateSettlementRequest struct {
+	CutoffAt *time.Time `json:"cutoff_at"`
+}
+
+func (r *GenerateSettlementRequest) Validate() error {
+	var errs validation.Errors
+	if r.CutoffAt != nil && r.CutoffAt.After(time.Now()) {
+		errs.Add("cutoff_at", validation.CodeInvalid, "cutoff_at must not be in the future")
+	}
+	return errs.Err()
+}
+
+type UpdateSettlementStatusRequest struct {
+	Status string `json:"status" example:"SENT" validate:"required,enum=SENT|CONFIRMED"`
+}
+
+func (r *UpdateSettlementStatusRequest) Validate() error {
+	r.Status = strings.ToUpper(strings.TrimSpace(r.Status))
+	return validation.Struct(r).Err()
+}
+
+type SettlementBatchListQueryParams struct {
+	Status   string `json:"status"`
+	Currency string `json:"currency"`
+	Page     int    `json:"page"`
+	PerPage  int    `json:"per_page"`
+}
+
+func ParseSettlementBatchQueryParams(r *http.Request) SettlementBatchListQueryParams {
+	q := r.URL.Query()
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	return SettlementBatchListQueryParams{
+		Status:   strings.ToUpper(q.Get("status")),
+		Currency: strings.ToUpper(q.Get("currency")),
+		Page:     page,
+		PerPage:  perPage,
+	}
+}
@@ -0,0 +1,30 @@
+package request
+
+import (
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+type IssueVirtualAccountRequest struct {
+	PaymentID uuid.UUID `json:"payment_id" validate:"required"`
+	Bank      string    `json:"bank" example:"BCA" validate:"required"`
+}
+
+func (r *IssueVirtualAccountRequest) Validate() error {
+	r.Bank = strings.ToUpper(strings.TrimSpace(r.Bank))
+	return validation.Struct(r).Err()
+}
+
+// CreditVirtualAccountRequest simulates the notification a bank sends for an incoming transfer.
+type CreditVirtualAccountRequest struct {
+	Amount            valueobject.Decimal `json:"amount" swaggertype:"string" example:"150000" validate:"required,positive,scale=2"`
+	TransferReference string              `json:"transfer_reference" example:"TRF-20250815-0001" validate:"required,max=64"`
+}
+
+func (r *CreditVirtualAccountRequest) Validate() error {
+	r.TransferReference = strings.TrimSpace(r.TransferReference)
+	return validation.Struct(r).Err()
+}
@@ -2,15 +2,19 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
 )
 
 type APIResponse struct {
-	Status  string      `json:"status"`         // "success" or "failed"
-	Entity  string      `json:"entity"`         // e.g. "payments"
-	State   string      `json:"state"`          // e.g. "getAllPayments"
-	Message string      `json:"message"`        // e.g. "Success Get All Payments"
-	Data    interface{} `json:"data,omitempty"` // actual payload
+	Status  string                  `json:"status"`           // "success" or "failed"
+	Entity  string                  `json:"entity"`           // e.g. "payments"
+	State   string                  `json:"state"`            // e.g. "getAllPayments"
+	Message string                  `json:"message"`          // e.g. "Success Get All Payments"
+	Data    interface{}             `json:"data,omitempty"`   // actual payload
+	Errors  []validation.FieldError `json:"errors,omitempty"` // rejected fields of the request
 }
 
 func Success(w http.ResponseWriter, code int, entity string, state string, message string, data interface{}) {
@@ -21,7 +25,12 @@ func Failed(w http.ResponseWriter, code int, entity string, state string, messag
 	JSON(w, code, entity, state, message, nil, false)
 }
 
+// JSON writes the envelope; failures are written as Problem Details instead when the
+// client negotiated them.
 func JSON(w http.ResponseWriter, code int, entity, state, message string, data interface{}, success bool) {
+	if !success && writeProblem(w, Problem{Status: code, Detail: message}) {
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 
@@ -38,3 +47,35 @@ func JSON(w http.ResponseWriter, code int, entity, state, message string, data i
 		Data:    toSafeData(data),
 	})
 }
+
+// Invalid answers a request rejected by validation.Decode or a Validate method, listing
+// every rejected field: 400 for a body that is not the expected JSON, 413 for one over
+// the size cap and 422 for values that break the rules.
+func Invalid(w http.ResponseWriter, entity, state string, err error) {
+	code, title, message := http.StatusUnprocessableEntity, "Validation Error", "Validation Error, "+err.Error()
+	var decodeErr *validation.DecodeError
+	if errors.As(err, &decodeErr) {
+		code, title, message = decodeErr.Status, "Invalid Request Body", "Invalid Request Body"
+	}
+	fields := validation.As(err)
+	if writeProblem(w, Problem{
+		Type:   problemTypePrefix + "invalid_request",
+		Title:  title,
+		Status: code,
+		Detail: fields.Error(),
+		Code:   "invalid_request",
+		Errors: fields,
+	}) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(APIResponse{
+		Status:  "failed",
+		Entity:  entity,
+		State:   state,
+		Message: message,
+		Errors:  fields,
+	})
+}
@@ -23,6 +23,9 @@ func FailedWithMeta(w http.ResponseWriter, code int, entity string, state string
 }
 
 func JSONWithMeta(w http.ResponseWriter, code int, entity, state, message string, meta interface{}, data interface{}, success bool) {
+	if !success && writeProblem(w, Problem{Status: code, Detail: message}) {
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 
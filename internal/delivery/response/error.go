@@ -0,0 +1,94 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+	"github.com/rs/zerolog"
+)
+
+var kindStatus = map[apperror.Kind]int{
+	apperror.KindNotFound:     http.StatusNotFound,
+	apperror.KindConflict:     http.StatusConflict,
+	apperror.KindBadRequest:   http.StatusBadRequest,
+	apperror.KindValidation:   http.StatusUnprocessableEntity,
+	apperror.KindRejected:     http.StatusUnprocessableEntity,
+	apperror.KindUnauthorized: http.StatusUnauthorized,
+	apperror.KindForbidden:    http.StatusForbidden,
+	apperror.KindRateLimited:  http.StatusTooManyRequests,
+	apperror.KindDeclined:     http.StatusPaymentRequired,
+	apperror.KindUpstream:     http.StatusBadGateway,
+	apperror.KindTimeout:      http.StatusGatewayTimeout,
+}
+
+var kindTitle = map[apperror.Kind]string{
+	apperror.KindNotFound:     "Not Found",
+	apperror.KindConflict:     "Conflict",
+	apperror.KindBadRequest:   "Bad Request",
+	apperror.KindValidation:   "Validation Error",
+	apperror.KindRejected:     "Request Rejected",
+	apperror.KindUnauthorized: "Unauthorized",
+	apperror.KindForbidden:    "Forbidden",
+	apperror.KindRateLimited:  "Too Many Requests",
+	apperror.KindDeclined:     "Payment Declined",
+	apperror.KindUpstream:     "Provider Error",
+	apperror.KindTimeout:      "Provider Timeout",
+}
+
+// StatusOf is the status code err is answered with.
+func StatusOf(err error) int {
+	if status, ok := kindStatus[apperror.KindOf(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorLogged logs err at the level of its kind, as an error only when it is internal, and
+// answers with the response Error maps it to.
+func ErrorLogged(w http.ResponseWriter, logger zerolog.Logger, entity, state, action string, err error) {
+	if apperror.KindOf(err) == apperror.KindInternal {
+		logger.Error().Err(err).Msg("❌ Failed to " + action + ", general")
+	} else {
+		logger.Warn().Err(err).Msg("⚠️ Failed to " + action)
+	}
+	Error(w, entity, state, action, err)
+}
+
+// Error answers a failed request with the status of err's kind, as the envelope or as
+// Problem Details when the client negotiated them. action names the operation, as in
+// "Delete Customer", for errors that carry no message of their own: rows that were not
+// found and internal errors, whose details are never exposed.
+func Error(w http.ResponseWriter, entity, state, action string, err error) {
+	var (
+		validationErrs validation.Errors
+		decodeErr      *validation.DecodeError
+	)
+	if errors.As(err, &validationErrs) || errors.As(err, &decodeErr) {
+		Invalid(w, entity, state, err)
+		return
+	}
+
+	kind := apperror.KindOf(err)
+	status := StatusOf(err)
+	title, ok := kindTitle[kind]
+	if !ok {
+		Failed(w, status, entity, state, "Error "+action)
+		return
+	}
+
+	code := apperror.CodeOf(err)
+	if code == "" {
+		// A row the repository did not find; its error text is not meant for clients
+		if !writeProblem(w, Problem{Title: title, Status: status}) {
+			JSON(w, status, entity, state, title+", "+action, nil, false)
+		}
+		return
+	}
+
+	p := Problem{Type: problemTypePrefix + code, Title: title, Status: status, Detail: err.Error(), Code: code}
+	if !writeProblem(w, p) {
+		JSON(w, status, entity, state, title+", "+err.Error(), nil, false)
+	}
+}
@@ -0,0 +1,112 @@
+package response
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/validation"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+
+	// problemTypePrefix names problem types after the code of the domain error; problems
+	// without a code use about:blank, whose title is the status phrase.
+	problemTypePrefix = "urn:beta-payment:problem:"
+)
+
+// Problem is an RFC 9457 Problem Details body. Code and Errors are extension members:
+// the code of the domain error and the rejected fields of an invalid request.
+type Problem struct {
+	Type     string                  `json:"type" example:"urn:beta-payment:problem:customer_has_open_payments"`
+	Title    string                  `json:"title" example:"Conflict"`
+	Status   int                     `json:"status" example:"409"`
+	Detail   string                  `json:"detail,omitempty" example:"customer has payments in progress"`
+	Instance string                  `json:"instance,omitempty" example:"/api/v1/customers/0d9f5e3c-7b1a-4c55-9a8e-3f2b1c0d4e5f"`
+	Code     string                  `json:"code,omitempty" example:"customer_has_open_payments"`
+	Errors   []validation.FieldError `json:"errors,omitempty"`
+}
+
+// problemWriter marks a response whose client negotiated Problem Details.
+type problemWriter struct {
+	http.ResponseWriter
+	instance string
+}
+
+func (w *problemWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Negotiate returns w marked for Problem Details when the Accept header of r prefers
+// application/problem+json over application/json; error responses written to it are
+// then problems instead of the envelope, which stays the default.
+func Negotiate(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	if !prefersProblem(r.Header.Values("Accept")) {
+		return w
+	}
+	return &problemWriter{ResponseWriter: w, instance: r.URL.Path}
+}
+
+// prefersProblem reads the media ranges of Accept; a problem+json range must be
+// acceptable and weighted at least as high as the best JSON range.
+func prefersProblem(accept []string) bool {
+	problem, plain := -1.0, 0.0
+	for _, header := range accept {
+		for _, part := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			switch mediaType {
+			case ProblemContentType:
+				problem = max(problem, q)
+			case "application/json":
+				plain = max(plain, q)
+			}
+		}
+	}
+	return problem > 0 && problem >= plain
+}
+
+// negotiated finds the problemWriter behind w and any writers wrapping it.
+func negotiated(w http.ResponseWriter) (*problemWriter, bool) {
+	for {
+		switch v := w.(type) {
+		case *problemWriter:
+			return v, true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// writeProblem writes p if the client negotiated Problem Details and reports whether it did.
+func writeProblem(w http.ResponseWriter, p Problem) bool {
+	pw, ok := negotiated(w)
+	if !ok {
+		return false
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	p.Instance = pw.instance
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+	return true
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodySize caps request bodies decoded with Decode.
+const DefaultMaxBodySize = 1 << 20
+
+// DecodeError is a body that is not the JSON a handler expects. Status is 413 for bodies
+// over the size cap and 400 otherwise.
+type DecodeError struct {
+	Status int
+	Errors Errors
+}
+
+func (e *DecodeError) Error() string {
+	return e.Errors.Error()
+}
+
+// Decode strictly decodes the JSON body of r into dst: the body must be a single JSON
+// value of at most DefaultMaxBodySize bytes, and fields dst does not declare are rejected
+// rather than silently dropped.
+func Decode(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	return DecodeLimit(w, r, dst, DefaultMaxBodySize)
+}
+
+// DecodeOptional is Decode for endpoints whose body may be left out; an empty body leaves
+// dst untouched.
+func DecodeOptional(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	err := DecodeLimit(w, r, dst, DefaultMaxBodySize)
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) && decodeErr.Errors[0].Code == CodeEmptyBody {
+		return nil
+	}
+	return err
+}
+
+// DecodeLimit is Decode with a body size cap of limit bytes.
+func DecodeLimit(w http.ResponseWriter, r *http.Request, dst interface{}, limit int64) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return decodeError(err)
+		}
+		return &DecodeError{Status: http.StatusBadRequest, Errors: Errors{{
+			Code: CodeTrailingData, Message: "request body must contain a single JSON value",
+		}}}
+	}
+	return nil
+}
+
+func decodeError(err error) *DecodeError {
+	fe := FieldError{Code: CodeMalformed, Message: "request body is not valid JSON"}
+	status := http.StatusBadRequest
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		tooLarge  *http.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		status = http.StatusRequestEntityTooLarge
+		fe = FieldError{Code: CodeTooLarge, Message: fmt.Sprintf("request body must be at most %d bytes", tooLarge.Limit)}
+	case errors.Is(err, io.EOF):
+		fe = FieldError{Code: CodeEmptyBody, Message: "request body is required"}
+	case errors.As(err, &syntaxErr):
+		fe.Message = fmt.Sprintf("request body is not valid JSON at offset %d", syntaxErr.Offset)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		fe.Message = "request body ends unexpectedly"
+	case errors.As(err, &typeErr):
+		fe = FieldError{Field: typeErr.Field, Code: CodeInvalidType, Message: fmt.Sprintf("%s must not be a JSON %s", typeErr.Field, typeErr.Value)}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		fe = FieldError{Field: field, Code: CodeUnknownField, Message: fmt.Sprintf("%s is not a known field", field)}
+	default:
+		// Values rejected by their own UnmarshalJSON, such as a malformed decimal
+		fe = FieldError{Code: CodeInvalidValue, Message: err.Error()}
+	}
+	return &DecodeError{Status: status, Errors: Errors{fe}}
+}
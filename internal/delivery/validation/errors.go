@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+// Codes identify why a field was rejected; clients branch on them, messages are for humans.
+const (
+	CodeRequired    = "required"
+	CodeTooShort    = "too_short"
+	CodeTooLong     = "too_long"
+	CodeFormat      = "invalid_format"
+	CodeNotPositive = "not_positive"
+	CodeNegative    = "negative"
+	CodeTooPrecise  = "too_many_decimals"
+	CodeNotAllowed  = "not_allowed"
+	CodeInvalid     = "invalid"
+
+	CodeEmptyBody    = "empty_body"
+	CodeMalformed    = "malformed_json"
+	CodeUnknownField = "unknown_field"
+	CodeInvalidType  = "invalid_type"
+	CodeInvalidValue = "invalid_value"
+	CodeTrailingData = "trailing_data"
+	CodeTooLarge     = "too_large"
+)
+
+// FieldError describes one rejected field. Field is the JSON path, such as
+// "items[0].quantity", and empty for problems with the body as a whole.
+type FieldError struct {
+	Field   string `json:"field" example:"amount"`
+	Code    string `json:"code" example:"not_positive"`
+	Message string `json:"message" example:"amount must be greater than zero"`
+}
+
+// Errors collects every rejected field of a request, so clients can fix them in one go.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add records a rejected field.
+func (e *Errors) Add(field, code, message string) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err returns the collected errors, or nil when there are none. Validate methods return
+// it instead of e itself so an empty list is not a non-nil error.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// As extracts the field errors of err. Errors that were not produced by this package
+// become a single error without a field.
+func As(err error) Errors {
+	var errs Errors
+	if errors.As(err, &errs) {
+		return errs
+	}
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		return decodeErr.Errors
+	}
+	return Errors{{Code: CodeInvalid, Message: err.Error()}}
+}
@@ -0,0 +1,369 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// Rules are declared in a validate struct tag, separated by commas:
+//
+//	required       the field must be set: non-empty string, slice or map, non-nil
+//	               pointer, non-null decimal, non-nil UUID
+//	min=N, max=N   length in characters for strings, in entries for slices and maps
+//	pattern=NAME   a string matching a pattern registered with RegisterPattern
+//	enum=A|B|C     a string that is one of the listed values
+//	enum=NAME      a string accepted by an enum registered with RegisterEnum
+//	positive       a number greater than zero
+//	nonnegative    a number of zero or more
+//	scale=N        a decimal with at most N decimal places
+//
+// Rules other than required skip empty values, so optional fields only need to be valid
+// when they are given. The first rule a field breaks is reported. Nested structs and
+// slices of structs are validated too, with paths such as "items[0].quantity".
+
+type pattern struct {
+	re          *regexp.Regexp
+	description string
+}
+
+type enum struct {
+	valid       func(string) bool
+	description string
+}
+
+var (
+	patterns = map[string]pattern{}
+	enums    = map[string]enum{}
+)
+
+// RegisterPattern makes re available as pattern=name; description completes the message
+// "<field> must be <description>". Registration belongs in package initialisation, before
+// any validation runs.
+func RegisterPattern(name, description string, re *regexp.Regexp) {
+	patterns[name] = pattern{re: re, description: description}
+}
+
+// RegisterEnum makes valid available as enum=name; description completes the message
+// "<field> is not a known <description>". Registration belongs in package initialisation.
+func RegisterEnum(name, description string, valid func(string) bool) {
+	enums[name] = enum{valid: valid, description: description}
+}
+
+// Struct checks the validate tags of v, a struct or pointer to one.
+func Struct(v interface{}) Errors {
+	var errs Errors
+	validateValue(reflect.ValueOf(v), "", &errs)
+	return errs
+}
+
+type check func(v reflect.Value, field string) *FieldError
+
+type fieldRules struct {
+	index  int
+	name   string
+	checks []check
+	dive   bool
+}
+
+// typeRules caches the parsed rules of each struct type.
+var typeRules sync.Map
+
+func rulesOf(t reflect.Type) []fieldRules {
+	return buildRules(t, map[reflect.Type]bool{})
+}
+
+// buildRules parses the rules of t. visiting holds the types being parsed further up, so
+// a recursive type does not dive into itself.
+func buildRules(t reflect.Type, visiting map[reflect.Type]bool) []fieldRules {
+	if cached, ok := typeRules.Load(t); ok {
+		return cached.([]fieldRules)
+	}
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+
+	var fields []fieldRules
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n, _, _ := strings.Cut(tag, ","); n != "" {
+				name = n
+			}
+		}
+		fr := fieldRules{index: i, name: name, dive: hasRules(f.Type, visiting)}
+		if tag := f.Tag.Get("validate"); tag != "" {
+			for _, spec := range strings.Split(tag, ",") {
+				fr.checks = append(fr.checks, parseRule(t, f.Name, strings.TrimSpace(spec)))
+			}
+		}
+		if len(fr.checks) > 0 || fr.dive {
+			fields = append(fields, fr)
+		}
+	}
+	typeRules.Store(t, fields)
+	return fields
+}
+
+// hasRules reports whether values of t contain structs with validate tags.
+func hasRules(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && len(buildRules(t, visiting)) > 0
+}
+
+func validateValue(v reflect.Value, path string, errs *Errors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, fr := range rulesOf(v.Type()) {
+			fv := v.Field(fr.index)
+			field := fr.name
+			if path != "" {
+				field = path + "." + fr.name
+			}
+			valid := true
+			for _, c := range fr.checks {
+				if fe := c(fv, field); fe != nil {
+					*errs = append(*errs, *fe)
+					valid = false
+					break
+				}
+			}
+			if valid && fr.dive {
+				validateValue(fv, field, errs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// parseRule turns one rule of a validate tag into a check. Malformed tags are programming
+// errors and panic on the first validation of the type.
+func parseRule(t reflect.Type, fieldName, spec string) check {
+	name, arg, _ := strings.Cut(spec, "=")
+	invalid := func(reason string) {
+		panic(fmt.Sprintf("validation: %s.%s: rule %q %s", t.Name(), fieldName, spec, reason))
+	}
+	number := func() int {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			invalid("needs a non-negative integer")
+		}
+		return n
+	}
+
+	switch name {
+	case "required":
+		return func(v reflect.Value, field string) *FieldError {
+			if isEmpty(v) {
+				return &FieldError{Field: field, Code: CodeRequired, Message: field + " is required"}
+			}
+			return nil
+		}
+	case "min":
+		min := number()
+		return lengthCheck(func(n int, field, unit string) *FieldError {
+			if n < min {
+				return &FieldError{Field: field, Code: CodeTooShort, Message: fmt.Sprintf("%s must have at least %d %s", field, min, unit)}
+			}
+			return nil
+		})
+	case "max":
+		max := number()
+		return lengthCheck(func(n int, field, unit string) *FieldError {
+			if n > max {
+				return &FieldError{Field: field, Code: CodeTooLong, Message: fmt.Sprintf("%s must have at most %d %s", field, max, unit)}
+			}
+			return nil
+		})
+	case "pattern":
+		p, ok := patterns[arg]
+		if !ok {
+			invalid("names no registered pattern")
+		}
+		return stringCheck(func(s, field string) *FieldError {
+			if !p.re.MatchString(s) {
+				return &FieldError{Field: field, Code: CodeFormat, Message: field + " must be " + p.description}
+			}
+			return nil
+		})
+	case "enum":
+		if strings.Contains(arg, "|") {
+			values := strings.Split(arg, "|")
+			return stringCheck(func(s, field string) *FieldError {
+				for _, v := range values {
+					if s == v {
+						return nil
+					}
+				}
+				return &FieldError{Field: field, Code: CodeNotAllowed, Message: field + " must be one of " + strings.Join(values, ", ")}
+			})
+		}
+		e, ok := enums[arg]
+		if !ok {
+			invalid("names no registered enum")
+		}
+		return stringCheck(func(s, field string) *FieldError {
+			if !e.valid(s) {
+				return &FieldError{Field: field, Code: CodeNotAllowed, Message: field + " is not a known " + e.description}
+			}
+			return nil
+		})
+	case "positive":
+		return signCheck(func(sign int, field string) *FieldError {
+			if sign <= 0 {
+				return &FieldError{Field: field, Code: CodeNotPositive, Message: field + " must be greater than zero"}
+			}
+			return nil
+		})
+	case "nonnegative":
+		return signCheck(func(sign int, field string) *FieldError {
+			if sign < 0 {
+				return &FieldError{Field: field, Code: CodeNegative, Message: field + " must not be negative"}
+			}
+			return nil
+		})
+	case "scale":
+		scale := number()
+		return func(v reflect.Value, field string) *FieldError {
+			d, ok := decimalOf(v)
+			if !ok || d.IsNull() {
+				return nil
+			}
+			if d.Round(scale, valueobject.RoundDown).Cmp(d) != 0 {
+				return &FieldError{Field: field, Code: CodeTooPrecise, Message: fmt.Sprintf("%s must have at most %d decimal places", field, scale)}
+			}
+			return nil
+		}
+	}
+	invalid("is not a known rule")
+	return nil
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+func lengthCheck(fn func(n int, field, unit string) *FieldError) check {
+	return func(v reflect.Value, field string) *FieldError {
+		v, ok := indirect(v)
+		if !ok {
+			return nil
+		}
+		switch v.Kind() {
+		case reflect.String:
+			if v.Len() == 0 {
+				return nil
+			}
+			return fn(utf8.RuneCountInString(v.String()), field, "characters")
+		case reflect.Slice, reflect.Map, reflect.Array:
+			if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+				return fn(v.Len(), field, "bytes")
+			}
+			return fn(v.Len(), field, "entries")
+		}
+		return nil
+	}
+}
+
+func stringCheck(fn func(s, field string) *FieldError) check {
+	return func(v reflect.Value, field string) *FieldError {
+		v, ok := indirect(v)
+		if !ok || v.Kind() != reflect.String || v.Len() == 0 {
+			return nil
+		}
+		return fn(v.String(), field)
+	}
+}
+
+func signCheck(fn func(sign int, field string) *FieldError) check {
+	return func(v reflect.Value, field string) *FieldError {
+		if d, ok := decimalOf(v); ok {
+			if d.IsNull() {
+				return nil
+			}
+			return fn(d.Sign(), field)
+		}
+		v, ok := indirect(v)
+		if !ok {
+			return nil
+		}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return fn(sign(float64(v.Int())), field)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return fn(sign(float64(v.Uint())), field)
+		case reflect.Float32, reflect.Float64:
+			return fn(sign(v.Float()), field)
+		}
+		return nil
+	}
+}
+
+func sign(f float64) int {
+	switch {
+	case f > 0:
+		return 1
+	case f < 0:
+		return -1
+	}
+	return 0
+}
+
+// decimalOf reads the money types; ok is false for values of any other type.
+func decimalOf(v reflect.Value) (valueobject.Decimal, bool) {
+	v, ok := indirect(v)
+	if !ok || !v.CanInterface() {
+		return valueobject.Decimal{}, false
+	}
+	switch d := v.Interface().(type) {
+	case valueobject.Decimal:
+		return d, true
+	case valueobject.BigFloat:
+		if d.Float == nil {
+			return valueobject.Decimal{}, true
+		}
+		return valueobject.DecimalFromBigFloat(d), true
+	}
+	return valueobject.Decimal{}, false
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// APIKeyTypeBearer keys are sent as "Authorization: Bearer <key>".
+	APIKeyTypeBearer = "BEARER"
+	// APIKeyTypeHMAC keys sign requests instead; the key prefix is the keyId.
+	APIKeyTypeHMAC = "HMAC"
+)
+
+var (
+	ErrInvalidAPIKey    = apperror.Forbidden("invalid_api_key", "invalid api key")
+	ErrAPIKeyRevoked    = apperror.Conflict("api_key_revoked", "api key was already revoked")
+	ErrMerchantNotFound = apperror.Validation("merchant_not_found", "merchant not found")
+	ErrHMACKeysDisabled = apperror.Rejected("hmac_keys_disabled", "hmac signing is not configured")
+
+	// ErrInvalidSignature rejects a signed request that has to be signed again: its
+	// signature does not match, its timestamp is outside the allowed skew or its nonce
+	// was already used.
+	ErrInvalidSignature = apperror.Unauthorized("invalid_signature", "request signature is invalid, expired or replayed")
+)
+
+// APIKey authenticates server-to-server callers as its merchant. Only a salted hash of the
+// secret is stored; the plaintext key is returned once, when the key is issued.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	MerchantID uuid.UUID  `json:"merchant_id"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Salt       []byte     `json:"-"`
+	Hash       []byte     `json:"-"`
+	CreatedAt  *time.Time `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// IsActive reports whether the key can authenticate at now.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// IssuedAPIKey is an issued key together with its plaintext, which is not stored anywhere.
+// For HMAC keys Key is the signing secret and Prefix the keyId.
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"bpk_3f9c1a2b7d4e_Q2hhbmdlIG1lIHRvIGEgcmVhbCBrZXk"`
+}
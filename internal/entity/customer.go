@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrCustomerNotFound        = apperror.Validation("customer_not_found", "customer not found")
+	ErrCustomerConflict        = apperror.Conflict("customer_reference_conflict", "customer external reference already exists")
+	ErrCustomerHasOpenPayments = apperror.Conflict("customer_has_open_payments", "customer has payments in progress")
+)
+
+type Customer struct {
+	ID                uuid.UUID  `json:"id"`
+	MerchantID        uuid.UUID  `json:"merchant_id"`
+	Name              string     `json:"name"`
+	Email             string     `json:"email"`
+	Phone             string     `json:"phone"`
+	ExternalReference *string    `json:"external_reference"`
+	CreatedAt         *time.Time `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at"`
+}
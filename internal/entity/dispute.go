@@ -0,0 +1,153 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	DisputeStatusOpened           = "OPENED"
+	DisputeStatusEvidenceRequired = "EVIDENCE_REQUIRED"
+	DisputeStatusSubmitted        = "SUBMITTED"
+	DisputeStatusWon              = "WON"
+	DisputeStatusLost             = "LOST"
+)
+
+const (
+	DisputeReasonFraudulent           = "FRAUDULENT"
+	DisputeReasonDuplicate            = "DUPLICATE"
+	DisputeReasonProductNotReceived   = "PRODUCT_NOT_RECEIVED"
+	DisputeReasonProductUnacceptable  = "PRODUCT_UNACCEPTABLE"
+	DisputeReasonSubscriptionCanceled = "SUBSCRIPTION_CANCELED"
+	DisputeReasonCreditNotProcessed   = "CREDIT_NOT_PROCESSED"
+	DisputeReasonGeneral              = "GENERAL"
+)
+
+const (
+	DisputeEvidenceReceipt               = "RECEIPT"
+	DisputeEvidenceShippingProof         = "SHIPPING_PROOF"
+	DisputeEvidenceCustomerCommunication = "CUSTOMER_COMMUNICATION"
+	DisputeEvidenceRefundPolicy          = "REFUND_POLICY"
+	DisputeEvidenceOther                 = "OTHER"
+)
+
+var (
+	ErrInvalidDisputeTransition = apperror.Conflict("invalid_dispute_transition", "invalid dispute status transition")
+	ErrDisputeAmountExceeded    = apperror.Rejected("dispute_amount_exceeded", "dispute amount exceeds disputable amount")
+	ErrDisputeEvidenceRequired  = apperror.Rejected("dispute_evidence_required", "dispute has no evidence to submit")
+	ErrDisputeEvidenceClosed    = apperror.Conflict("dispute_evidence_closed", "dispute no longer accepts evidence")
+	ErrDisputeDeadlinePassed    = apperror.Conflict("dispute_deadline_passed", "dispute evidence deadline has passed")
+)
+
+// Dispute is a cardholder's challenge of a payment. While it is open the payment is
+// DISPUTED; PaymentStatusBefore is restored when the dispute is won.
+type Dispute struct {
+	ID                  uuid.UUID           `json:"id"`
+	MerchantID          uuid.UUID           `json:"merchant_id"`
+	PaymentID           uuid.UUID           `json:"payment_id"`
+	Status              string              `json:"status"`
+	ReasonCode          string              `json:"reason_code"`
+	Reason              string              `json:"reason"`
+	Amount              valueobject.Decimal `json:"amount" swaggertype:"string"`
+	Currency            string              `json:"currency"`
+	PaymentStatusBefore string              `json:"payment_status_before"`
+	ProviderCaseID      string              `json:"provider_case_id"`
+	EvidenceDueAt       time.Time           `json:"evidence_due_at"`
+	DeadlineFlaggedAt   *time.Time          `json:"deadline_flagged_at"`
+	SubmittedAt         *time.Time          `json:"submitted_at"`
+	ResolvedAt          *time.Time          `json:"resolved_at"`
+	CreatedAt           *time.Time          `json:"created_at"`
+	UpdatedAt           *time.Time          `json:"updated_at"`
+	Evidence            []DisputeEvidence   `json:"evidence,omitempty"`
+	Reversal            *PaymentReversal    `json:"reversal,omitempty"`
+}
+
+// DisputeEvidence is a document supporting the merchant's side of a dispute. Content is
+// only loaded when the attachment itself is downloaded.
+type DisputeEvidence struct {
+	ID          uuid.UUID  `json:"id"`
+	DisputeID   uuid.UUID  `json:"dispute_id"`
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+	FileName    string     `json:"file_name"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	Content     []byte     `json:"-"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+// PaymentReversal records funds taken back from the merchant after a payment was captured,
+// such as a lost dispute.
+type PaymentReversal struct {
+	ID        uuid.UUID           `json:"id"`
+	PaymentID uuid.UUID           `json:"payment_id"`
+	DisputeID *uuid.UUID          `json:"dispute_id"`
+	Amount    valueobject.Decimal `json:"amount" swaggertype:"string"`
+	Currency  string              `json:"currency"`
+	Reason    string              `json:"reason"`
+	CreatedAt *time.Time          `json:"created_at"`
+}
+
+// disputeTransitions lists, for every dispute status, the statuses it may move to next.
+// WON and LOST are terminal.
+var disputeTransitions = map[string][]string{
+	DisputeStatusOpened: {
+		DisputeStatusEvidenceRequired,
+		DisputeStatusWon,
+		DisputeStatusLost,
+	},
+	DisputeStatusEvidenceRequired: {
+		DisputeStatusSubmitted,
+		DisputeStatusWon,
+		DisputeStatusLost,
+	},
+	DisputeStatusSubmitted: {
+		DisputeStatusWon,
+		DisputeStatusLost,
+	},
+}
+
+// CanTransitionDispute reports whether a dispute in status from may move to status to.
+func CanTransitionDispute(from, to string) bool {
+	for _, s := range disputeTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsEvidence reports whether evidence may still be attached in status.
+func (d *Dispute) AcceptsEvidence() bool {
+	return d.Status == DisputeStatusOpened || d.Status == DisputeStatusEvidenceRequired
+}
+
+func IsValidDisputeStatus(status string) bool {
+	switch status {
+	case DisputeStatusOpened, DisputeStatusEvidenceRequired, DisputeStatusSubmitted, DisputeStatusWon, DisputeStatusLost:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidDisputeReasonCode(code string) bool {
+	switch code {
+	case DisputeReasonFraudulent, DisputeReasonDuplicate, DisputeReasonProductNotReceived, DisputeReasonProductUnacceptable,
+		DisputeReasonSubscriptionCanceled, DisputeReasonCreditNotProcessed, DisputeReasonGeneral:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidDisputeEvidenceType(t string) bool {
+	switch t {
+	case DisputeEvidenceReceipt, DisputeEvidenceShippingProof, DisputeEvidenceCustomerCommunication, DisputeEvidenceRefundPolicy, DisputeEvidenceOther:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// FeeRuleTypeStandard charges a fixed amount plus a flat percentage.
+	FeeRuleTypeStandard = "STANDARD"
+	// FeeRuleTypeTiered charges a fixed amount plus graduated percentages per amount tier.
+	FeeRuleTypeTiered = "TIERED"
+)
+
+const (
+	FeeLineFixed      = "FIXED"
+	FeeLinePercentage = "PERCENTAGE"
+	FeeLineTier       = "TIER"
+	FeeLineMinimum    = "MINIMUM"
+	FeeLineMaximum    = "MAXIMUM"
+)
+
+// FeeTier charges Percentage on the part of the amount up to UpTo; a null UpTo is unbounded.
+type FeeTier struct {
+	UpTo       valueobject.Decimal `json:"up_to" swaggertype:"string" example:"1000000"`
+	Percentage valueobject.Decimal `json:"percentage" swaggertype:"string" example:"2.5"`
+}
+
+// FeeRule prices payments of a method, currency and amount band. Empty Method and Currency
+// match any value, the band is [MinAmount, MaxAmount) with null bounds left open, and a
+// nil MerchantID applies to every merchant without a rule of their own.
+type FeeRule struct {
+	ID          uuid.UUID           `json:"id"`
+	MerchantID  *uuid.UUID          `json:"merchant_id"`
+	Name        string              `json:"name"`
+	Method      string              `json:"method"`
+	Currency    string              `json:"currency"`
+	MinAmount   valueobject.Decimal `json:"min_amount" swaggertype:"string"`
+	MaxAmount   valueobject.Decimal `json:"max_amount" swaggertype:"string"`
+	Type        string              `json:"type"`
+	FixedAmount valueobject.Decimal `json:"fixed_amount" swaggertype:"string"`
+	Percentage  valueobject.Decimal `json:"percentage" swaggertype:"string"`
+	Tiers       []FeeTier           `json:"tiers"`
+	MinFee      valueobject.Decimal `json:"min_fee" swaggertype:"string"`
+	MaxFee      valueobject.Decimal `json:"max_fee" swaggertype:"string"`
+	Priority    int                 `json:"priority"`
+	Active      bool                `json:"active"`
+	CreatedAt   *time.Time          `json:"created_at"`
+	UpdatedAt   *time.Time          `json:"updated_at"`
+}
+
+// PaymentFee is one line item of the fee charged for a payment.
+type PaymentFee struct {
+	ID          uuid.UUID           `json:"id"`
+	PaymentID   uuid.UUID           `json:"payment_id"`
+	FeeRuleID   *uuid.UUID          `json:"fee_rule_id"`
+	Type        string              `json:"type"`
+	Description string              `json:"description"`
+	Amount      valueobject.Decimal `json:"amount" swaggertype:"string"`
+	Currency    string              `json:"currency"`
+	CreatedAt   *time.Time          `json:"created_at"`
+}
+
+// FeeQuote is the fee computed for an amount; Lines always sum to Total.
+type FeeQuote struct {
+	FeeRuleID *uuid.UUID          `json:"fee_rule_id"`
+	Amount    valueobject.Decimal `json:"amount" swaggertype:"string"`
+	Currency  string              `json:"currency"`
+	Method    string              `json:"method"`
+	Lines     []PaymentFee        `json:"lines"`
+	Total     valueobject.Decimal `json:"total" swaggertype:"string"`
+}
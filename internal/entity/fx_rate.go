@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrFXRateNotFound = apperror.Rejected("fx_rate_not_found", "no effective fx rate")
+
+// FXRate is the price of one unit of BaseCurrency in QuoteCurrency from EffectiveAt
+// until a later rate for the same pair takes effect.
+type FXRate struct {
+	ID            uuid.UUID           `json:"id"`
+	BaseCurrency  string              `json:"base_currency"`
+	QuoteCurrency string              `json:"quote_currency"`
+	Rate          valueobject.Decimal `json:"rate" swaggertype:"string" example:"15600.25"`
+	EffectiveAt   time.Time           `json:"effective_at"`
+	Source        string              `json:"source"`
+	CreatedAt     *time.Time          `json:"created_at"`
+}
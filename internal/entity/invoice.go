@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+const (
+	InvoiceStatusDraft         = "DRAFT"
+	InvoiceStatusOpen          = "OPEN"
+	InvoiceStatusPartiallyPaid = "PARTIALLY_PAID"
+	InvoiceStatusPaid          = "PAID"
+	InvoiceStatusVoid          = "VOID"
+)
+
+var (
+	ErrInvoiceNotEditable       = apperror.Conflict("invoice_not_editable", "only draft invoices can be edited or finalized")
+	ErrInvoiceNotPayable        = apperror.Conflict("invoice_not_payable", "only open or partially paid invoices accept payments")
+	ErrInvoiceNotVoidable       = apperror.Conflict("invoice_not_voidable", "invoices with payments received or in progress cannot be voided")
+	ErrInvoicePaymentExceeded   = apperror.Rejected("invoice_payment_exceeded", "payment amount exceeds the invoice's outstanding amount")
+	ErrInvalidInvoiceItemAmount = apperror.Validation("invalid_invoice_item_amount", "invoice item discount exceeds its subtotal")
+)
+
+// Invoice bills a customer for line items and is settled by one or more payments. Once
+// finalized its status follows the linked payments: amount_paid counts captured money net of
+// refunds, and the status moves between OPEN, PARTIALLY_PAID and PAID accordingly.
+type Invoice struct {
+	ID            uuid.UUID           `json:"id"`
+	MerchantID    uuid.UUID           `json:"merchant_id"`
+	CustomerID    *uuid.UUID          `json:"customer_id"`
+	Number        string              `json:"number"`
+	Currency      string              `json:"currency"`
+	Status        string              `json:"status"`
+	Notes         string              `json:"notes"`
+	Subtotal      valueobject.Decimal `json:"subtotal" swaggertype:"string"`
+	DiscountTotal valueobject.Decimal `json:"discount_total" swaggertype:"string"`
+	TaxTotal      valueobject.Decimal `json:"tax_total" swaggertype:"string"`
+	Total         valueobject.Decimal `json:"total" swaggertype:"string"`
+	AmountPaid    valueobject.Decimal `json:"amount_paid" swaggertype:"string"`
+	DueDate       time.Time           `json:"due_date"`
+	Overdue       bool                `json:"overdue"`
+	IssuedAt      *time.Time          `json:"issued_at"`
+	PaidAt        *time.Time          `json:"paid_at"`
+	VoidedAt      *time.Time          `json:"voided_at"`
+	CreatedAt     *time.Time          `json:"created_at"`
+	UpdatedAt     *time.Time          `json:"updated_at"`
+	Items         []InvoiceItem       `json:"items,omitempty"`
+	Payments      []Payment           `json:"payments,omitempty"`
+}
+
+// InvoiceItem is one invoice line. Discount is an amount taken off quantity x unit price
+// before tax; TaxRate is a percentage, e.g. 11 for Indonesian PPN.
+type InvoiceItem struct {
+	ID          uuid.UUID           `json:"id"`
+	Description string              `json:"description"`
+	Quantity    valueobject.Decimal `json:"quantity" swaggertype:"string"`
+	UnitPrice   valueobject.Decimal `json:"unit_price" swaggertype:"string"`
+	Discount    valueobject.Decimal `json:"discount" swaggertype:"string"`
+	TaxRate     valueobject.Decimal `json:"tax_rate" swaggertype:"string"`
+	Subtotal    valueobject.Decimal `json:"subtotal" swaggertype:"string"`
+	TaxAmount   valueobject.Decimal `json:"tax_amount" swaggertype:"string"`
+	Total       valueobject.Decimal `json:"total" swaggertype:"string"`
+}
+
+// Outstanding is what remains to be paid.
+func (i *Invoice) Outstanding() valueobject.Decimal {
+	return i.Total.Sub(i.AmountPaid)
+}
+
+// IsOverdue reports whether a finalized, unpaid invoice is past its due date at now.
+func (i *Invoice) IsOverdue(now time.Time) bool {
+	if i.Status != InvoiceStatusOpen && i.Status != InvoiceStatusPartiallyPaid {
+		return false
+	}
+	y, m, d := i.DueDate.Date()
+	return now.After(time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC))
+}
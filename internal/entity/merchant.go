@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/google/uuid"
+)
+
+var ErrQRISProfileMissing = apperror.Conflict("qris_profile_missing", "merchant has no QRIS profile")
+
+// MerchantQRISProfile is the merchant identity printed into the QRIS payloads of its
+// payments. NMID is the national merchant ID registered with QRIS and Criteria its
+// business size (UMI, UKE, UME, UBE, URE); AcquirerGUID, PAN and ID describe the acquirer
+// account and are optional.
+type MerchantQRISProfile struct {
+	MerchantID   uuid.UUID `json:"merchant_id"`
+	Name         string    `json:"name"`
+	City         string    `json:"city"`
+	PostalCode   string    `json:"postal_code"`
+	CategoryCode string    `json:"category_code"`
+	NMID         string    `json:"nmid"`
+	Criteria     string    `json:"criteria"`
+	AcquirerGUID string    `json:"acquirer_guid"`
+	PAN          string    `json:"pan"`
+	ID           string    `json:"id"`
+}
@@ -7,11 +7,25 @@ import (
 )
 
 type Payment struct {
-	ID          uuid.UUID            `json:"id"`
-	Tag         string               `json:"tag"`
-	Description string               `json:"description"`
-	Amount      valueobject.BigFloat `json:"amount"`
-	Status      string               `json:"status"`
-	CreatedAt   *time.Time           `json:"created_at"`
-	UpdatedAt   *time.Time           `json:"updated_at"`
+	ID                 uuid.UUID            `json:"id"`
+	MerchantID         uuid.UUID            `json:"merchant_id"`
+	CustomerID         *uuid.UUID           `json:"customer_id"`
+	InvoiceID          *uuid.UUID           `json:"invoice_id"`
+	Tag                string               `json:"tag"`
+	Description        string               `json:"description"`
+	Amount             valueobject.BigFloat `json:"amount"`
+	Currency           string               `json:"currency"`
+	Method             string               `json:"method"`
+	Status             string               `json:"status"`
+	Provider           string               `json:"provider"`
+	ProviderReference  string               `json:"provider_reference"`
+	RefundedAmount     valueobject.BigFloat `json:"refunded_amount"`
+	SettlementCurrency string               `json:"settlement_currency"`
+	FXRate             valueobject.Decimal  `json:"fx_rate" swaggertype:"string"`
+	SettlementAmount   valueobject.Decimal  `json:"settlement_amount" swaggertype:"string"`
+	Metadata           valueobject.Metadata `json:"metadata" swaggertype:"object,string"`
+	ClientIP           string               `json:"-"`
+	Splits             []PaymentSplit       `json:"splits,omitempty"`
+	CreatedAt          *time.Time           `json:"created_at"`
+	UpdatedAt          *time.Time           `json:"updated_at"`
 }
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	PaymentLinkStatusActive  = "ACTIVE"
+	PaymentLinkStatusUsed    = "USED"
+	PaymentLinkStatusRevoked = "REVOKED"
+)
+
+var (
+	ErrInvalidPaymentLinkToken = apperror.NotFound("invalid_payment_link_token", "invalid payment link token")
+	ErrPaymentLinkExpired      = apperror.Conflict("payment_link_expired", "payment link has expired")
+	ErrPaymentLinkUnavailable  = apperror.Conflict("payment_link_unavailable", "payment link was already used or revoked")
+)
+
+// PaymentLink lets a customer pay a PENDING payment through a hosted checkout page. The
+// URL carries a signed token that expires with the link and works for one payment attempt.
+type PaymentLink struct {
+	ID         uuid.UUID  `json:"id"`
+	MerchantID uuid.UUID  `json:"merchant_id"`
+	PaymentID  uuid.UUID  `json:"payment_id"`
+	Status     string     `json:"status"`
+	URL        string     `json:"url,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+	Payment    *Payment   `json:"payment,omitempty"`
+}
+
+// IsUsable reports whether the link can still start a payment at now.
+func (l *PaymentLink) IsUsable(now time.Time) bool {
+	return l.Status == PaymentLinkStatusActive && now.Before(l.ExpiresAt)
+}
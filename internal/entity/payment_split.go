@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrInvalidSplits = apperror.Validation("invalid_splits", "invalid payment splits")
+
+// PaymentSplit is the share of a payment owed to one recipient. On create either Amount
+// or Percentage (of the payment amount) is given; Amount is always the allocated share
+// once stored.
+type PaymentSplit struct {
+	ID             uuid.UUID           `json:"id"`
+	PaymentID      uuid.UUID           `json:"payment_id"`
+	RecipientID    string              `json:"recipient_id"`
+	Percentage     valueobject.Decimal `json:"percentage" swaggertype:"string"`
+	Amount         valueobject.Decimal `json:"amount" swaggertype:"string"`
+	RefundedAmount valueobject.Decimal `json:"refunded_amount" swaggertype:"string"`
+	CreatedAt      *time.Time          `json:"created_at"`
+}
@@ -0,0 +1,120 @@
+package entity
+
+import "github.com/adf-code/beta-payment-api/internal/apperror"
+
+const (
+	PaymentStatusPending           = "PENDING"
+	PaymentStatusAuthorized        = "AUTHORIZED"
+	PaymentStatusPaid              = "PAID"
+	PaymentStatusPartiallyRefunded = "PARTIALLY_REFUNDED"
+	PaymentStatusRefunded          = "REFUNDED"
+	PaymentStatusFailed            = "FAILED"
+	PaymentStatusCancelled         = "CANCELLED"
+	PaymentStatusExpired           = "EXPIRED"
+	PaymentStatusDisputed          = "DISPUTED"
+	PaymentStatusChargedBack       = "CHARGED_BACK"
+	PaymentStatusReview            = "REVIEW"
+)
+
+const (
+	PaymentMethodCard         = "CARD"
+	PaymentMethodBankTransfer = "BANK_TRANSFER"
+	PaymentMethodQRIS         = "QRIS"
+	PaymentMethodEWallet      = "EWALLET"
+)
+
+const DefaultPaymentCurrency = "IDR"
+
+var (
+	ErrInvalidStatusTransition = apperror.Conflict("invalid_status_transition", "invalid payment status transition")
+	ErrRefundAmountExceeded    = apperror.Rejected("refund_amount_exceeded", "refund amount exceeds refundable amount")
+	ErrQRISUnavailable         = apperror.Conflict("qris_unavailable", "qr payload is only available for pending QRIS payments")
+)
+
+// paymentTransitions lists, for every status, the statuses a payment may move to next.
+// Statuses without an entry are terminal.
+var paymentTransitions = map[string][]string{
+	// Held by the risk rules until a reviewer approves or rejects it
+	PaymentStatusReview: {
+		PaymentStatusPending,
+		PaymentStatusCancelled,
+	},
+	PaymentStatusPending: {
+		PaymentStatusAuthorized,
+		PaymentStatusPaid,
+		PaymentStatusFailed,
+		PaymentStatusCancelled,
+		PaymentStatusExpired,
+	},
+	PaymentStatusAuthorized: {
+		PaymentStatusPaid,
+		PaymentStatusFailed,
+		PaymentStatusCancelled,
+		PaymentStatusExpired,
+	},
+	PaymentStatusPaid: {
+		PaymentStatusPartiallyRefunded,
+		PaymentStatusRefunded,
+		PaymentStatusDisputed,
+	},
+	PaymentStatusPartiallyRefunded: {
+		PaymentStatusPartiallyRefunded,
+		PaymentStatusRefunded,
+		PaymentStatusDisputed,
+	},
+	// A won dispute returns the payment to the status it had when the dispute opened
+	PaymentStatusDisputed: {
+		PaymentStatusPaid,
+		PaymentStatusPartiallyRefunded,
+		PaymentStatusChargedBack,
+	},
+}
+
+// IsValidPaymentStatus reports whether status is part of the payment status machine.
+func IsValidPaymentStatus(status string) bool {
+	if _, ok := paymentTransitions[status]; ok {
+		return true
+	}
+	for _, next := range paymentTransitions {
+		for _, s := range next {
+			if s == status {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanTransitionPayment reports whether a payment in status from may move to status to.
+func CanTransitionPayment(from, to string) bool {
+	for _, s := range paymentTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentStatusesLeadingTo returns every status from which a payment may move to status to.
+func PaymentStatusesLeadingTo(to string) []string {
+	var from []string
+	for status, next := range paymentTransitions {
+		for _, s := range next {
+			if s == to {
+				from = append(from, status)
+				break
+			}
+		}
+	}
+	return from
+}
+
+// IsValidPaymentMethod reports whether method is one of the supported payment methods.
+func IsValidPaymentMethod(method string) bool {
+	switch method {
+	case PaymentMethodCard, PaymentMethodBankTransfer, PaymentMethodQRIS, PaymentMethodEWallet:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "github.com/adf-code/beta-payment-api/internal/valueobject"
+
+// PaymentSummaryGroup aggregates payments of one currency and status. Reporting totals
+// are only set when a reporting currency was requested.
+type PaymentSummaryGroup struct {
+	Currency               string              `json:"currency"`
+	Status                 string              `json:"status"`
+	Count                  int64               `json:"count"`
+	Total                  valueobject.Decimal `json:"total" swaggertype:"string"`
+	RefundedTotal          valueobject.Decimal `json:"refunded_total" swaggertype:"string"`
+	ReportingTotal         valueobject.Decimal `json:"reporting_total" swaggertype:"string"`
+	ReportingRefundedTotal valueobject.Decimal `json:"reporting_refunded_total" swaggertype:"string"`
+}
+
+// PaymentSummaryRecipient totals the split shares of one recipient in one currency.
+type PaymentSummaryRecipient struct {
+	RecipientID            string              `json:"recipient_id"`
+	Currency               string              `json:"currency"`
+	Count                  int64               `json:"count"`
+	Total                  valueobject.Decimal `json:"total" swaggertype:"string"`
+	RefundedTotal          valueobject.Decimal `json:"refunded_total" swaggertype:"string"`
+	ReportingTotal         valueobject.Decimal `json:"reporting_total" swaggertype:"string"`
+	ReportingRefundedTotal valueobject.Decimal `json:"reporting_refunded_total" swaggertype:"string"`
+}
+
+type PaymentSummary struct {
+	ReportingCurrency      string                         `json:"reporting_currency,omitempty"`
+	Rates                  map[string]valueobject.Decimal `json:"rates,omitempty" swaggertype:"object,string"`
+	ReportingTotal         valueobject.Decimal            `json:"reporting_total" swaggertype:"string"`
+	ReportingRefundedTotal valueobject.Decimal            `json:"reporting_refunded_total" swaggertype:"string"`
+	Groups                 []PaymentSummaryGroup          `json:"groups"`
+	Recipients             []PaymentSummaryRecipient      `json:"recipients"`
+}
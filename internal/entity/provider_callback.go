@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	CallbackOutcomeReceived  = "RECEIVED"
+	CallbackOutcomeApplied   = "APPLIED"
+	CallbackOutcomeUnchanged = "UNCHANGED"
+	CallbackOutcomeStale     = "STALE"
+	CallbackOutcomeUnmatched = "UNMATCHED"
+	CallbackOutcomeDuplicate = "DUPLICATE"
+	CallbackOutcomeRejected  = "REJECTED"
+	CallbackOutcomeInvalid   = "INVALID"
+)
+
+type ProviderCallback struct {
+	ID             uuid.UUID       `json:"id"`
+	MerchantID     *uuid.UUID      `json:"merchant_id"`
+	Provider       string          `json:"provider"`
+	EventID        *string         `json:"event_id"`
+	Reference      *string         `json:"reference"`
+	PaymentID      *uuid.UUID      `json:"payment_id"`
+	Status         *string         `json:"status"`
+	SignatureValid bool            `json:"signature_valid"`
+	Outcome        string          `json:"outcome"`
+	Error          *string         `json:"error"`
+	Headers        json.RawMessage `json:"headers" swaggertype:"object"`
+	Payload        string          `json:"payload"`
+	ReceivedAt     *time.Time      `json:"received_at"`
+	ProcessedAt    *time.Time      `json:"processed_at"`
+}
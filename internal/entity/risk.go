@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	RiskActionAllow  = "ALLOW"
+	RiskActionReview = "REVIEW"
+	RiskActionDeny   = "DENY"
+)
+
+const (
+	RiskReviewApprove = "APPROVE"
+	RiskReviewReject  = "REJECT"
+)
+
+// RiskReasonScore is the reason code of a decision escalated by its total score rather
+// than by a single rule.
+const RiskReasonScore = "RISK_SCORE"
+
+var ErrRiskDenied = apperror.Rejected("risk_denied", "payment denied by risk rules")
+
+// RiskDeniedError carries the decision that denied a payment; it matches ErrRiskDenied.
+type RiskDeniedError struct {
+	Decision RiskDecision
+}
+
+func (e *RiskDeniedError) Error() string {
+	return ErrRiskDenied.Error() + ": " + e.Decision.ReasonCode
+}
+
+func (e *RiskDeniedError) Unwrap() error {
+	return ErrRiskDenied
+}
+
+// RiskSeverity orders risk actions from ALLOW to DENY.
+func RiskSeverity(action string) int {
+	switch action {
+	case RiskActionDeny:
+		return 2
+	case RiskActionReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// RiskReason is one rule that matched a payment.
+type RiskReason struct {
+	Rule   string `json:"rule"`
+	Code   string `json:"code"`
+	Action string `json:"action"`
+	Score  int    `json:"score"`
+}
+
+// RiskDecision is the outcome of the risk rules for a payment being created. Denied
+// payments are never stored, so PaymentID is only set for allowed and reviewed ones.
+type RiskDecision struct {
+	ID         uuid.UUID           `json:"id"`
+	PaymentID  *uuid.UUID          `json:"payment_id"`
+	Action     string              `json:"action"`
+	Score      int                 `json:"score"`
+	ReasonCode string              `json:"reason_code"`
+	Reasons    []RiskReason        `json:"reasons"`
+	Tag        string              `json:"tag"`
+	CustomerID *uuid.UUID          `json:"customer_id"`
+	ClientIP   string              `json:"client_ip"`
+	Amount     valueobject.Decimal `json:"amount" swaggertype:"string"`
+	Currency   string              `json:"currency"`
+	CreatedAt  *time.Time          `json:"created_at"`
+}
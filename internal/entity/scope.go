@@ -0,0 +1,88 @@
+package entity
+
+import "strings"
+
+// Scopes grant access to routes. API keys list them explicitly and JWTs carry them in the
+// scope or scp claim; admin does not imply any other scope.
+const (
+	// ScopeOperator opens the platform routes that change data every merchant shares, such
+	// as fx rates and fee rules. It is never granted to merchant keys: only the api_key
+	// command issues it, with -operator, and tokens carrying it are not trusted with it.
+	ScopeOperator              = "operator"
+	ScopeAdmin                 = "admin"
+	ScopePaymentsRead          = "payments:read"
+	ScopePaymentsWrite         = "payments:write"
+	ScopePaymentsDelete        = "payments:delete"
+	ScopeCustomersRead         = "customers:read"
+	ScopeCustomersWrite        = "customers:write"
+	ScopeCustomersDelete       = "customers:delete"
+	ScopeProviderCallbacksRead = "provider_callbacks:read"
+	ScopeFXRatesRead           = "fx_rates:read"
+	ScopeFeesRead              = "fees:read"
+	ScopeDisputesRead          = "disputes:read"
+	ScopeDisputesWrite         = "disputes:write"
+	ScopePaymentLinksRead      = "payment_links:read"
+	ScopePaymentLinksWrite     = "payment_links:write"
+	ScopeVirtualAccountsRead   = "virtual_accounts:read"
+	ScopeVirtualAccountsWrite  = "virtual_accounts:write"
+	ScopeInvoicesRead          = "invoices:read"
+	ScopeInvoicesWrite         = "invoices:write"
+	ScopeSettlementsRead       = "settlements:read"
+	ScopeSettlementsWrite      = "settlements:write"
+)
+
+var validScopes = map[string]bool{
+	ScopeOperator:              true,
+	ScopeAdmin:                 true,
+	ScopePaymentsRead:          true,
+	ScopePaymentsWrite:         true,
+	ScopePaymentsDelete:        true,
+	ScopeCustomersRead:         true,
+	ScopeCustomersWrite:        true,
+	ScopeCustomersDelete:       true,
+	ScopeProviderCallbacksRead: true,
+	ScopeFXRatesRead:           true,
+	ScopeFeesRead:              true,
+	ScopeDisputesRead:          true,
+	ScopeDisputesWrite:         true,
+	ScopePaymentLinksRead:      true,
+	ScopePaymentLinksWrite:     true,
+	ScopeVirtualAccountsRead:   true,
+	ScopeVirtualAccountsWrite:  true,
+	ScopeInvoicesRead:          true,
+	ScopeInvoicesWrite:         true,
+	ScopeSettlementsRead:       true,
+	ScopeSettlementsWrite:      true,
+}
+
+func IsValidScope(scope string) bool {
+	return validScopes[scope]
+}
+
+// IsGrantableScope reports whether scope can be granted to a merchant's API key.
+func IsGrantableScope(scope string) bool {
+	return validScopes[scope] && scope != ScopeOperator
+}
+
+// ScopeAction is the action part of a scope, such as "write" for "payments:write", or
+// the scope itself for scopes without a resource.
+func ScopeAction(scope string) string {
+	if i := strings.LastIndex(scope, ":"); i >= 0 {
+		return scope[i+1:]
+	}
+	return scope
+}
+
+// RoutePermission is the scope a route requires; public routes require none.
+type RoutePermission struct {
+	Method string `json:"method" example:"GET"`
+	Path   string `json:"path" example:"/api/v1/payments/{id}"`
+	Scope  string `json:"scope,omitempty" example:"payments:read"`
+	Public bool   `json:"public"`
+}
+
+// PermissionMatrix lists every route with its scope, and the routes each scope opens.
+type PermissionMatrix struct {
+	Routes []RoutePermission   `json:"routes"`
+	Scopes map[string][]string `json:"scopes"`
+}
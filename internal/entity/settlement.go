@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	SettlementBatchStatusGenerated = "GENERATED"
+	SettlementBatchStatusSent      = "SENT"
+	SettlementBatchStatusConfirmed = "CONFIRMED"
+)
+
+const (
+	// SettlementItemPayment pays out a captured payment, net of its fees and refunds.
+	SettlementItemPayment = "PAYMENT"
+	// SettlementItemRefundAdjustment deducts a refund made after the payment was paid out.
+	SettlementItemRefundAdjustment = "REFUND_ADJUSTMENT"
+	// SettlementItemChargebackAdjustment deducts a chargeback made after the payment was paid
+	// out; the amount charged back is carried as the refund amount.
+	SettlementItemChargebackAdjustment = "CHARGEBACK_ADJUSTMENT"
+)
+
+const (
+	PayoutFormatPain001 = "pain001"
+	PayoutFormatCSV     = "csv"
+)
+
+var (
+	ErrInvalidSettlementTransition = apperror.Conflict("invalid_settlement_transition", "invalid settlement batch status transition")
+	ErrPayoutAccountMissing        = apperror.Conflict("payout_account_missing", "merchant has no payout account")
+)
+
+// settlementTransitions lists, for every batch status, the statuses it may move to next.
+var settlementTransitions = map[string][]string{
+	SettlementBatchStatusGenerated: {SettlementBatchStatusSent},
+	SettlementBatchStatusSent:      {SettlementBatchStatusConfirmed},
+}
+
+// CanTransitionSettlement reports whether a batch in status from may move to status to.
+func CanTransitionSettlement(from, to string) bool {
+	for _, s := range settlementTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PayoutAccount is the bank account a merchant's settlements are paid into.
+type PayoutAccount struct {
+	Name     string `json:"name"`
+	Number   string `json:"number"`
+	BankCode string `json:"bank_code"`
+}
+
+// SettlementBatch pays out one merchant's captured payments in one currency. Its amounts
+// and payout account are fixed when it is generated; only the status moves afterwards.
+type SettlementBatch struct {
+	ID            uuid.UUID             `json:"id"`
+	MerchantID    uuid.UUID             `json:"merchant_id"`
+	Currency      string                `json:"currency"`
+	Status        string                `json:"status"`
+	CutoffAt      time.Time             `json:"cutoff_at"`
+	ItemCount     int                   `json:"item_count"`
+	GrossAmount   valueobject.Decimal   `json:"gross_amount" swaggertype:"string"`
+	RefundAmount  valueobject.Decimal   `json:"refund_amount" swaggertype:"string"`
+	FeeAmount     valueobject.Decimal   `json:"fee_amount" swaggertype:"string"`
+	NetAmount     valueobject.Decimal   `json:"net_amount" swaggertype:"string"`
+	PayoutAccount PayoutAccount         `json:"payout_account"`
+	SentAt        *time.Time            `json:"sent_at"`
+	ConfirmedAt   *time.Time            `json:"confirmed_at"`
+	CreatedAt     *time.Time            `json:"created_at"`
+	UpdatedAt     *time.Time            `json:"updated_at"`
+	Items         []SettlementBatchItem `json:"items,omitempty"`
+}
+
+// SettlementBatchItem is one payment's contribution to a batch; NetAmount is GrossAmount
+// less RefundAmount and FeeAmount and may be negative for refund and chargeback adjustments.
+type SettlementBatchItem struct {
+	ID           uuid.UUID           `json:"id"`
+	BatchID      uuid.UUID           `json:"batch_id"`
+	PaymentID    uuid.UUID           `json:"payment_id"`
+	Type         string              `json:"type"`
+	GrossAmount  valueobject.Decimal `json:"gross_amount" swaggertype:"string"`
+	RefundAmount valueobject.Decimal `json:"refund_amount" swaggertype:"string"`
+	FeeAmount    valueobject.Decimal `json:"fee_amount" swaggertype:"string"`
+	NetAmount    valueobject.Decimal `json:"net_amount" swaggertype:"string"`
+	CreatedAt    *time.Time          `json:"created_at"`
+}
+
+// SettlementCandidate is a payment due to be settled: either captured and not yet paid out,
+// or paid out and refunded or charged back since. Fees is the total fee charged on capture;
+// Reversed is the total of its chargebacks no batch has deducted yet.
+type SettlementCandidate struct {
+	PaymentID       uuid.UUID
+	Currency        string
+	Amount          valueobject.Decimal
+	RefundedAmount  valueobject.Decimal
+	SettledRefunded valueobject.Decimal
+	Fees            valueobject.Decimal
+	Reversed        valueobject.Decimal
+	Settled         bool
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+const (
+	VirtualAccountStatusActive  = "ACTIVE"
+	VirtualAccountStatusPaid    = "PAID"
+	VirtualAccountStatusExpired = "EXPIRED"
+	VirtualAccountStatusClosed  = "CLOSED"
+)
+
+// Outcomes of a transfer into a virtual account. Rejected transfers are recorded with a
+// reason and are due back to the sender; they never change the amount paid.
+const (
+	VirtualAccountCreditAccepted = "ACCEPTED"
+	VirtualAccountCreditOverpaid = "OVERPAID"
+	VirtualAccountCreditPartial  = "PARTIAL"
+	VirtualAccountCreditRejected = "REJECTED"
+)
+
+const (
+	VirtualAccountRejectUnderpaid = "UNDERPAID"
+	VirtualAccountRejectOverpaid  = "OVERPAID"
+	VirtualAccountRejectLate      = "LATE"
+	VirtualAccountRejectClosed    = "CLOSED"
+)
+
+var (
+	ErrUnknownVirtualAccountBank = apperror.Validation("unknown_virtual_account_bank", "no virtual account prefix configured for bank")
+	ErrVirtualAccountUnavailable = apperror.Conflict("virtual_account_unavailable", "virtual accounts are only issued for pending bank transfer payments")
+	ErrVirtualAccountConflict    = apperror.Conflict("virtual_account_conflict", "payment already has an active virtual account")
+	ErrInvalidVirtualAccount     = apperror.Validation("invalid_virtual_account", "invalid virtual account number")
+	ErrVirtualAccountChanged     = apperror.Conflict("virtual_account_changed", "virtual account changed concurrently")
+)
+
+// VirtualAccount is a single-use bank account number a customer transfers a payment to.
+type VirtualAccount struct {
+	ID             uuid.UUID              `json:"id"`
+	MerchantID     uuid.UUID              `json:"merchant_id"`
+	PaymentID      uuid.UUID              `json:"payment_id"`
+	Bank           string                 `json:"bank"`
+	Number         string                 `json:"number"`
+	ExpectedAmount valueobject.Decimal    `json:"expected_amount" swaggertype:"string"`
+	PaidAmount     valueobject.Decimal    `json:"paid_amount" swaggertype:"string"`
+	Currency       string                 `json:"currency"`
+	Status         string                 `json:"status"`
+	ExpiresAt      time.Time              `json:"expires_at"`
+	PaidAt         *time.Time             `json:"paid_at"`
+	CreatedAt      *time.Time             `json:"created_at"`
+	UpdatedAt      *time.Time             `json:"updated_at"`
+	Credits        []VirtualAccountCredit `json:"credits,omitempty"`
+}
+
+// VirtualAccountCredit is one incoming transfer, identified by the bank's transfer reference.
+type VirtualAccountCredit struct {
+	ID                uuid.UUID           `json:"id"`
+	VirtualAccountID  uuid.UUID           `json:"virtual_account_id"`
+	TransferReference string              `json:"transfer_reference"`
+	Amount            valueobject.Decimal `json:"amount" swaggertype:"string"`
+	Outcome           string              `json:"outcome"`
+	RejectReason      string              `json:"reject_reason,omitempty"`
+	CreatedAt         *time.Time          `json:"created_at"`
+}
@@ -0,0 +1,90 @@
+package fees
+
+import (
+	"fmt"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// AmountScale is the number of fractional digits fee lines are rounded to.
+const AmountScale = 2
+
+var hundred = valueobject.DecimalFromInt(100)
+
+// Matches reports whether the rule prices a payment with the given attributes.
+func Matches(rule entity.FeeRule, method, currency string, amount valueobject.Decimal) bool {
+	if !rule.Active {
+		return false
+	}
+	if rule.Method != "" && rule.Method != method {
+		return false
+	}
+	if rule.Currency != "" && rule.Currency != currency {
+		return false
+	}
+	if !rule.MinAmount.IsNull() && amount.Cmp(rule.MinAmount) < 0 {
+		return false
+	}
+	if !rule.MaxAmount.IsNull() && amount.Cmp(rule.MaxAmount) >= 0 {
+		return false
+	}
+	return true
+}
+
+// Calculate prices amount with rule. Every line is rounded on its own and the caps are
+// applied as adjustment lines, so the lines always add up to the total exactly.
+func Calculate(rule entity.FeeRule, amount valueobject.Decimal, currency string, rounding valueobject.RoundingMode) ([]entity.PaymentFee, valueobject.Decimal) {
+	ruleID := rule.ID
+	var lines []entity.PaymentFee
+	total := valueobject.DecimalFromInt(0)
+	add := func(lineType, description string, value valueobject.Decimal) {
+		value = value.Round(AmountScale, rounding)
+		if value.Sign() == 0 {
+			return
+		}
+		lines = append(lines, entity.PaymentFee{
+			FeeRuleID:   &ruleID,
+			Type:        lineType,
+			Description: description,
+			Amount:      value,
+			Currency:    currency,
+		})
+		total = total.Add(value)
+	}
+
+	if !rule.FixedAmount.IsNull() {
+		add(entity.FeeLineFixed, "Fixed fee", rule.FixedAmount)
+	}
+
+	switch rule.Type {
+	case entity.FeeRuleTypeStandard:
+		if !rule.Percentage.IsNull() {
+			add(entity.FeeLinePercentage, fmt.Sprintf("%s%% of %s", rule.Percentage, amount.StringFixed(AmountScale, rounding)),
+				amount.Mul(rule.Percentage).Quo(hundred))
+		}
+	case entity.FeeRuleTypeTiered:
+		lower := valueobject.DecimalFromInt(0)
+		for _, tier := range rule.Tiers {
+			upper := amount
+			if !tier.UpTo.IsNull() && tier.UpTo.Cmp(amount) < 0 {
+				upper = tier.UpTo
+			}
+			portion := upper.Sub(lower)
+			if portion.Sign() <= 0 {
+				break
+			}
+			add(entity.FeeLineTier, fmt.Sprintf("%s%% of %s-%s", tier.Percentage, lower.StringFixed(AmountScale, rounding), upper.StringFixed(AmountScale, rounding)),
+				portion.Mul(tier.Percentage).Quo(hundred))
+			lower = upper
+		}
+	}
+
+	if !rule.MinFee.IsNull() && total.Cmp(rule.MinFee) < 0 {
+		add(entity.FeeLineMinimum, "Minimum fee adjustment", rule.MinFee.Sub(total))
+	}
+	if !rule.MaxFee.IsNull() && total.Cmp(rule.MaxFee) > 0 {
+		add(entity.FeeLineMaximum, "Maximum fee adjustment", rule.MaxFee.Sub(total))
+	}
+	return lines, total
+}
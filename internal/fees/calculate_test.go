@@ -0,0 +1,150 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+func dec(t *testing.T, s string) valueobject.Decimal {
+	t.Helper()
+	if s == "" {
+		return valueobject.Decimal{}
+	}
+	d, err := valueobject.ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return d
+}
+
+type line struct {
+	typ    string
+	amount string
+}
+
+func TestCalculateClampsToMinAndMaxFee(t *testing.T) {
+	tiers := func(t *testing.T) []entity.FeeTier {
+		return []entity.FeeTier{
+			{UpTo: dec(t, "1000"), Percentage: dec(t, "3")},
+			{UpTo: dec(t, "5000"), Percentage: dec(t, "2")},
+			{Percentage: dec(t, "1")},
+		}
+	}
+	tests := []struct {
+		name      string
+		ruleType  string
+		fixed     string
+		pct       string
+		minFee    string
+		maxFee    string
+		amount    string
+		wantLines []line
+		wantTotal string
+	}{
+		{
+			name: "within the caps", ruleType: entity.FeeRuleTypeStandard, fixed: "2000", pct: "2.9", minFee: "1000", maxFee: "50000", amount: "10000",
+			wantLines: []line{{entity.FeeLineFixed, "2000"}, {entity.FeeLinePercentage, "290"}}, wantTotal: "2290",
+		},
+		{
+			name: "raised to the minimum", ruleType: entity.FeeRuleTypeStandard, pct: "1", minFee: "10", amount: "500",
+			wantLines: []line{{entity.FeeLinePercentage, "5"}, {entity.FeeLineMinimum, "5"}}, wantTotal: "10",
+		},
+		{
+			name: "cut to the maximum", ruleType: entity.FeeRuleTypeStandard, fixed: "2000", pct: "2.9", maxFee: "25000", amount: "1000000",
+			wantLines: []line{{entity.FeeLineFixed, "2000"}, {entity.FeeLinePercentage, "29000"}, {entity.FeeLineMaximum, "-6000"}}, wantTotal: "25000",
+		},
+		{
+			name: "exactly the minimum is not adjusted", ruleType: entity.FeeRuleTypeStandard, pct: "1", minFee: "10", maxFee: "20", amount: "1000",
+			wantLines: []line{{entity.FeeLinePercentage, "10"}}, wantTotal: "10",
+		},
+		{
+			name: "exactly the maximum is not adjusted", ruleType: entity.FeeRuleTypeStandard, pct: "1", minFee: "10", maxFee: "20", amount: "2000",
+			wantLines: []line{{entity.FeeLinePercentage, "20"}}, wantTotal: "20",
+		},
+		{
+			// 1.5% of 33.33 = 0.49995 rounds to 0.50 before the minimum is compared
+			name: "minimum applies to the rounded lines", ruleType: entity.FeeRuleTypeStandard, pct: "1.5", minFee: "0.75", amount: "33.33",
+			wantLines: []line{{entity.FeeLinePercentage, "0.5"}, {entity.FeeLineMinimum, "0.25"}}, wantTotal: "0.75",
+		},
+		{
+			name: "no fee at all is raised to the minimum", ruleType: entity.FeeRuleTypeStandard, minFee: "1.5", amount: "100",
+			wantLines: []line{{entity.FeeLineMinimum, "1.5"}}, wantTotal: "1.5",
+		},
+		{
+			// 3% of 1000 + 2% of 4000 + 1% of 3000 = 30 + 80 + 30
+			name: "tiered cut to the maximum", ruleType: entity.FeeRuleTypeTiered, maxFee: "120", amount: "8000",
+			wantLines: []line{{entity.FeeLineTier, "30"}, {entity.FeeLineTier, "80"}, {entity.FeeLineTier, "30"}, {entity.FeeLineMaximum, "-20"}}, wantTotal: "120",
+		},
+		{
+			name: "tiered raised to the minimum", ruleType: entity.FeeRuleTypeTiered, fixed: "1", minFee: "5", amount: "100",
+			wantLines: []line{{entity.FeeLineFixed, "1"}, {entity.FeeLineTier, "3"}, {entity.FeeLineMinimum, "1"}}, wantTotal: "5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := entity.FeeRule{
+				Type:        tt.ruleType,
+				FixedAmount: dec(t, tt.fixed),
+				Percentage:  dec(t, tt.pct),
+				MinFee:      dec(t, tt.minFee),
+				MaxFee:      dec(t, tt.maxFee),
+			}
+			if tt.ruleType == entity.FeeRuleTypeTiered {
+				rule.Tiers = tiers(t)
+			}
+			lines, total := Calculate(rule, dec(t, tt.amount), "IDR", valueobject.RoundHalfEven)
+
+			if total.Cmp(dec(t, tt.wantTotal)) != 0 {
+				t.Errorf("total %s, want %s", total, tt.wantTotal)
+			}
+			if len(lines) != len(tt.wantLines) {
+				t.Fatalf("got %d lines %+v, want %d", len(lines), lines, len(tt.wantLines))
+			}
+			sum := valueobject.DecimalFromInt(0)
+			for i, l := range lines {
+				if l.Type != tt.wantLines[i].typ || l.Amount.Cmp(dec(t, tt.wantLines[i].amount)) != 0 {
+					t.Errorf("line %d = %s %s, want %s %s", i, l.Type, l.Amount, tt.wantLines[i].typ, tt.wantLines[i].amount)
+				}
+				if l.Currency != "IDR" {
+					t.Errorf("line %d currency %q, want IDR", i, l.Currency)
+				}
+				sum = sum.Add(l.Amount)
+			}
+			if sum.Cmp(total) != 0 {
+				t.Errorf("lines add up to %s, total is %s", sum, total)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	rule := entity.FeeRule{Active: true, Method: "CARD", Currency: "IDR", MinAmount: dec(t, "1000"), MaxAmount: dec(t, "5000")}
+	tests := []struct {
+		name     string
+		method   string
+		currency string
+		amount   string
+		want     bool
+	}{
+		{name: "inside the range", method: "CARD", currency: "IDR", amount: "2500", want: true},
+		{name: "min amount is inclusive", method: "CARD", currency: "IDR", amount: "1000", want: true},
+		{name: "max amount is exclusive", method: "CARD", currency: "IDR", amount: "5000", want: false},
+		{name: "below the range", method: "CARD", currency: "IDR", amount: "999.99", want: false},
+		{name: "other method", method: "VA", currency: "IDR", amount: "2500", want: false},
+		{name: "other currency", method: "CARD", currency: "USD", amount: "2500", want: false},
+	}
+	for _, tt := range tests {
+		if got := Matches(rule, tt.method, tt.currency, dec(t, tt.amount)); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if Matches(entity.FeeRule{}, "CARD", "IDR", dec(t, "2500")) {
+		t.Error("an inactive rule matches")
+	}
+	if !Matches(entity.FeeRule{Active: true}, "CARD", "IDR", dec(t, "2500")) {
+		t.Error("a rule without constraints does not match")
+	}
+}
@@ -0,0 +1,118 @@
+package fees
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+// RuleSource loads the active fee rules.
+type RuleSource interface {
+	FetchActive(ctx context.Context) ([]entity.FeeRule, error)
+}
+
+// Engine selects and applies fee rules. Rules are cached in memory and reloaded after
+// ttl, or immediately after Invalidate when rules change in this process.
+type Engine struct {
+	source   RuleSource
+	ttl      time.Duration
+	rounding valueobject.RoundingMode
+
+	mu       sync.RWMutex
+	rules    []entity.FeeRule
+	loadedAt time.Time
+}
+
+func NewEngine(source RuleSource, ttl time.Duration, rounding valueobject.RoundingMode) *Engine {
+	return &Engine{source: source, ttl: ttl, rounding: rounding}
+}
+
+// Invalidate drops the cached rules so the next quote reloads them.
+func (e *Engine) Invalidate() {
+	e.mu.Lock()
+	e.loadedAt = time.Time{}
+	e.mu.Unlock()
+}
+
+// Quote prices a payment for a merchant. Without a matching rule the fee is zero.
+func (e *Engine) Quote(ctx context.Context, merchantID uuid.UUID, method, currency string, amount valueobject.Decimal) (*entity.FeeQuote, error) {
+	rules, err := e.activeRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &entity.FeeQuote{
+		Amount:   amount,
+		Currency: currency,
+		Method:   method,
+		Lines:    []entity.PaymentFee{},
+		Total:    valueobject.DecimalFromInt(0),
+	}
+	rule, ok := selectRule(rules, merchantID, method, currency, amount)
+	if !ok {
+		return quote, nil
+	}
+	quote.FeeRuleID = &rule.ID
+	lines, total := Calculate(rule, amount, currency, e.rounding)
+	if lines != nil {
+		quote.Lines = lines
+	}
+	quote.Total = total
+	return quote, nil
+}
+
+func (e *Engine) activeRules(ctx context.Context) ([]entity.FeeRule, error) {
+	e.mu.RLock()
+	if !e.loadedAt.IsZero() && time.Since(e.loadedAt) < e.ttl {
+		rules := e.rules
+		e.mu.RUnlock()
+		return rules, nil
+	}
+	e.mu.RUnlock()
+
+	rules, err := e.source.FetchActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.rules = rules
+	e.loadedAt = time.Now()
+	e.mu.Unlock()
+	return rules, nil
+}
+
+// selectRule prefers the merchant's own rules over global ones, then the lowest priority
+// value; ties go to the rule created first so selection is deterministic.
+func selectRule(rules []entity.FeeRule, merchantID uuid.UUID, method, currency string, amount valueobject.Decimal) (entity.FeeRule, bool) {
+	var candidates []entity.FeeRule
+	for _, rule := range rules {
+		if rule.MerchantID != nil && *rule.MerchantID != merchantID {
+			continue
+		}
+		if Matches(rule, method, currency, amount) {
+			candidates = append(candidates, rule)
+		}
+	}
+	if len(candidates) == 0 {
+		return entity.FeeRule{}, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.MerchantID != nil) != (b.MerchantID != nil) {
+			return a.MerchantID != nil
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.CreatedAt != nil && b.CreatedAt != nil && !a.CreatedAt.Equal(*b.CreatedAt) {
+			return a.CreatedAt.Before(*b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+	return candidates[0], true
+}
@@ -0,0 +1,243 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// prefix marks encrypted values; anything without it is a plaintext value written before
+// encryption was enabled.
+const prefix = "enc:v"
+
+var (
+	ErrUnknownKeyVersion = errors.New("fieldcrypt: value is encrypted with an unknown key version")
+	ErrMalformedValue    = errors.New("fieldcrypt: encrypted value is malformed")
+)
+
+// Encryptor seals field values with envelope encryption: every value gets a fresh
+// AES-256-GCM data key, which is wrapped by the active master key and stored next to the
+// ciphertext as "enc:v<version>:<base64>". The field name is bound as additional data,
+// so a ciphertext copied into another column fails to decrypt.
+//
+// A nil Encryptor stores values in plaintext, which keeps encryption optional.
+type Encryptor struct {
+	masters map[uint32]cipher.AEAD
+	active  uint32
+	index   []byte
+}
+
+// NewEncryptor encrypts with the active master key version, or with the highest version
+// when active is 0. The keyring must hold a blind index key.
+func NewEncryptor(ring *Keyring, active uint32) (*Encryptor, error) {
+	versions := ring.Versions()
+	if len(versions) == 0 {
+		return nil, errors.New("fieldcrypt: no master key")
+	}
+	if ring.index == nil {
+		return nil, errors.New("fieldcrypt: no blind index key")
+	}
+	if active == 0 {
+		active = versions[len(versions)-1]
+	}
+	if _, ok := ring.masters[active]; !ok {
+		return nil, fmt.Errorf("fieldcrypt: active key version %d is not in the keyring", active)
+	}
+
+	e := &Encryptor{masters: make(map[uint32]cipher.AEAD, len(versions)), active: active, index: ring.index}
+	for v, key := range ring.masters {
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		e.masters[v] = aead
+	}
+	return e, nil
+}
+
+// Load builds the encryptor from configuration: keys and the contents of file in the
+// format of Keyring.Parse, an optional separate index key and the active version. It
+// returns nil without error when no key is configured.
+func Load(keys, file, indexKey, active string) (*Encryptor, error) {
+	ring := NewKeyring()
+	if err := ring.Parse(keys); err != nil {
+		return nil, err
+	}
+	if file != "" {
+		if err := ring.ParseFile(file); err != nil {
+			return nil, err
+		}
+	}
+	if indexKey != "" {
+		if err := ring.Parse("index:" + indexKey); err != nil {
+			return nil, err
+		}
+	}
+	if ring.Empty() {
+		return nil, nil
+	}
+
+	var version uint64
+	if active != "" {
+		v, err := strconv.ParseUint(active, 10, 32)
+		if err != nil || v == 0 {
+			return nil, fmt.Errorf("fieldcrypt: active key version %q must be a positive integer", active)
+		}
+		version = v
+	}
+	return NewEncryptor(ring, uint32(version))
+}
+
+// ActiveVersion is the master key version new values are encrypted with.
+func (e *Encryptor) ActiveVersion() uint32 {
+	if e == nil {
+		return 0
+	}
+	return e.active
+}
+
+// Encrypt seals plaintext for field. Empty values stay empty so optional fields keep
+// their meaning.
+func (e *Encryptor) Encrypt(field, plaintext string) (string, error) {
+	if e == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	master := e.masters[e.active]
+	ad := additionalData(e.active, field)
+
+	// wrap nonce | wrapped data key | data nonce | ciphertext
+	out := make([]byte, 0, 2*master.NonceSize()+keySize+2*master.Overhead()+len(plaintext))
+	out, err = appendNonce(out, master.NonceSize())
+	if err != nil {
+		return "", err
+	}
+	out = master.Seal(out, out[:master.NonceSize()], dataKey, ad)
+	start := len(out)
+	out, err = appendNonce(out, data.NonceSize())
+	if err != nil {
+		return "", err
+	}
+	out = data.Seal(out, out[start:], []byte(plaintext), ad)
+	return prefix + strconv.FormatUint(uint64(e.active), 10) + ":" + base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+// Decrypt opens a value of field. Plaintext values are returned unchanged, so rows written
+// before encryption was enabled stay readable until they are re-encrypted.
+func (e *Encryptor) Decrypt(field, value string) (string, error) {
+	version, sealed, ok := split(value)
+	if !ok {
+		return value, nil
+	}
+	if e == nil {
+		return "", ErrUnknownKeyVersion
+	}
+	master, ok := e.masters[version]
+	if !ok {
+		return "", ErrUnknownKeyVersion
+	}
+	ad := additionalData(version, field)
+
+	wrappedLen := master.NonceSize() + keySize + master.Overhead()
+	if len(sealed) < wrappedLen {
+		return "", ErrMalformedValue
+	}
+	dataKey, err := master.Open(nil, sealed[:master.NonceSize()], sealed[master.NonceSize():wrappedLen], ad)
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	rest := sealed[wrappedLen:]
+	if len(rest) < data.NonceSize() {
+		return "", ErrMalformedValue
+	}
+	plaintext, err := data.Open(nil, rest[:data.NonceSize()], rest[data.NonceSize():], ad)
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+	return string(plaintext), nil
+}
+
+// Current reports whether value is stored the way Encrypt would store it now: encrypted
+// under the active key, or plaintext while encryption is disabled. Values that are not
+// current are what re-encryption migrates.
+func (e *Encryptor) Current(value string) bool {
+	version, _, ok := split(value)
+	if e == nil {
+		return !ok
+	}
+	return value == "" || (ok && version == e.active)
+}
+
+// BlindIndex is a keyed hash of value for equality search on an encrypted field. It is
+// deterministic, so callers normalise value first, and independent of the master key
+// version, so rotation leaves existing indexes valid.
+func (e *Encryptor) BlindIndex(field, value string) string {
+	if e == nil || value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, e.index)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// split separates an encrypted value into its key version and sealed bytes. ok is false
+// for plaintext, including legacy text that merely starts like an encrypted value.
+func split(value string) (version uint32, sealed []byte, ok bool) {
+	rest, found := strings.CutPrefix(value, prefix)
+	if !found {
+		return 0, nil, false
+	}
+	v, encoded, found := strings.Cut(rest, ":")
+	if !found {
+		return 0, nil, false
+	}
+	parsed, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		return 0, nil, false
+	}
+	sealed, err = base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, nil, false
+	}
+	return uint32(parsed), sealed, true
+}
+
+func additionalData(version uint32, field string) []byte {
+	return []byte(strconv.FormatUint(uint64(version), 10) + ":" + field)
+}
+
+func appendNonce(b []byte, size int) ([]byte, error) {
+	nonce := make([]byte, size)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return append(b, nonce...), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
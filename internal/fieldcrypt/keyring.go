@@ -0,0 +1,102 @@
+package fieldcrypt
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// keySize is the length of master, data and blind index keys: AES-256 and HMAC-SHA256.
+const keySize = 32
+
+// Keyring holds the versioned master keys and the blind index key. Master keys are only
+// ever added: rows encrypted under a retired version stay readable until re-encrypted.
+type Keyring struct {
+	masters map[uint32][]byte
+	index   []byte
+}
+
+func NewKeyring() *Keyring {
+	return &Keyring{masters: make(map[uint32][]byte)}
+}
+
+// Parse adds the keys in text. Entries are "<version>:<base64 key>" for master keys and
+// "index:<base64 key>" for the blind index key, separated by commas or new lines; blank
+// lines and lines starting with # are ignored.
+func (k *Keyring) Parse(text string) error {
+	entries := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' })
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		name, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return fmt.Errorf("fieldcrypt: key entry must be <version>:<base64 key>")
+		}
+		key, err := decodeKey(strings.TrimSpace(encoded))
+		if err != nil {
+			return fmt.Errorf("fieldcrypt: key %s: %w", name, err)
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "index" {
+			if k.index != nil {
+				return errors.New("fieldcrypt: index key is set twice")
+			}
+			k.index = key
+			continue
+		}
+		version, err := strconv.ParseUint(name, 10, 32)
+		if err != nil || version == 0 {
+			return fmt.Errorf("fieldcrypt: key version %q must be a positive integer", name)
+		}
+		if _, exists := k.masters[uint32(version)]; exists {
+			return fmt.Errorf("fieldcrypt: key version %d is set twice", version)
+		}
+		k.masters[uint32(version)] = key
+	}
+	return nil
+}
+
+// ParseFile adds the keys of a file in the format of Parse.
+func (k *Keyring) ParseFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return k.Parse(string(data))
+}
+
+// Empty reports whether no key was added at all, i.e. encryption is not configured.
+func (k *Keyring) Empty() bool {
+	return len(k.masters) == 0 && k.index == nil
+}
+
+// Versions returns the master key versions in ascending order.
+func (k *Keyring) Versions() []uint32 {
+	versions := make([]uint32, 0, len(k.masters))
+	for v := range k.masters {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		key, err = base64.RawURLEncoding.DecodeString(encoded)
+	}
+	if err != nil {
+		return nil, errors.New("is not valid base64")
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("must be %d bytes, got %d", keySize, len(key))
+	}
+	return key, nil
+}
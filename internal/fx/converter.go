@@ -0,0 +1,107 @@
+package fx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+const (
+	// AmountScale is the number of fractional digits converted amounts are rounded to.
+	AmountScale = 2
+	// RateScale matches the precision fx_rates and payments.fx_rate are stored with.
+	RateScale = 12
+)
+
+// RateFetcher looks up the rate effective for a currency pair at a point in time.
+type RateFetcher interface {
+	FetchEffective(ctx context.Context, base, quote string, at time.Time) (*entity.FXRate, error)
+}
+
+// Conversion is the result of converting an amount, with the rate that was applied.
+type Conversion struct {
+	From   string
+	To     string
+	Rate   valueobject.Decimal
+	Amount valueobject.Decimal
+}
+
+// Converter converts amounts between currencies using exact decimal arithmetic. Pairs
+// without a loaded rate are crossed through the base currency.
+type Converter struct {
+	rates    RateFetcher
+	base     string
+	rounding valueobject.RoundingMode
+}
+
+func NewConverter(rates RateFetcher, base string, rounding valueobject.RoundingMode) *Converter {
+	return &Converter{rates: rates, base: base, rounding: rounding}
+}
+
+// Rate returns the rate from one currency to another at a point in time. When only the
+// opposite pair is loaded its inverse is used; when neither is, the rate is crossed
+// through the base currency, from -> base -> to. Derived rates are rounded to RateScale
+// so that the rate recorded on a payment reproduces the converted amount.
+func (c *Converter) Rate(ctx context.Context, from, to string, at time.Time) (valueobject.Decimal, error) {
+	if from == to {
+		return valueobject.DecimalFromInt(1), nil
+	}
+
+	rate, err := c.pairRate(ctx, from, to, at)
+	if errors.Is(err, sql.ErrNoRows) && c.base != "" && from != c.base && to != c.base {
+		var toBase, fromBase valueobject.Decimal
+		if toBase, err = c.pairRate(ctx, from, c.base, at); err == nil {
+			if fromBase, err = c.pairRate(ctx, c.base, to, at); err == nil {
+				rate = toBase.Mul(fromBase)
+			}
+		}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return valueobject.Decimal{}, fmt.Errorf("%w: %s/%s at %s", entity.ErrFXRateNotFound, from, to, at.UTC().Format(time.RFC3339))
+	}
+	if err != nil {
+		return valueobject.Decimal{}, err
+	}
+	return rate.Round(RateScale, c.rounding), nil
+}
+
+// pairRate is the exact rate of a loaded pair or the inverse of the opposite pair, or
+// sql.ErrNoRows when neither is loaded.
+func (c *Converter) pairRate(ctx context.Context, from, to string, at time.Time) (valueobject.Decimal, error) {
+	direct, err := c.rates.FetchEffective(ctx, from, to, at)
+	if err == nil {
+		return direct.Rate, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return valueobject.Decimal{}, err
+	}
+	inverse, err := c.rates.FetchEffective(ctx, to, from, at)
+	if err != nil {
+		return valueobject.Decimal{}, err
+	}
+	return valueobject.DecimalFromInt(1).Quo(inverse.Rate), nil
+}
+
+// Convert converts amount and rounds the result to AmountScale with the configured mode.
+func (c *Converter) Convert(ctx context.Context, amount valueobject.Decimal, from, to string, at time.Time) (*Conversion, error) {
+	rate, err := c.Rate(ctx, from, to, at)
+	if err != nil {
+		return nil, err
+	}
+	return &Conversion{
+		From:   from,
+		To:     to,
+		Rate:   rate,
+		Amount: c.Apply(amount, rate),
+	}, nil
+}
+
+// Apply converts amount with a rate obtained from Rate.
+func (c *Converter) Apply(amount, rate valueobject.Decimal) valueobject.Decimal {
+	return amount.Mul(rate).Round(AmountScale, c.rounding)
+}
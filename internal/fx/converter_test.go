@@ -0,0 +1,126 @@
+package fx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// stubRates serves the loaded pairs, keyed "BASE/QUOTE", whatever the time.
+type stubRates map[string]string
+
+func (s stubRates) FetchEffective(_ context.Context, base, quote string, _ time.Time) (*entity.FXRate, error) {
+	rate, ok := s[base+"/"+quote]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if rate == "error" {
+		return nil, errors.New("connection reset")
+	}
+	d, err := valueobject.ParseDecimal(rate)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.FXRate{BaseCurrency: base, QuoteCurrency: quote, Rate: d}, nil
+}
+
+func dec(t *testing.T, s string) valueobject.Decimal {
+	t.Helper()
+	d, err := valueobject.ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return d
+}
+
+func TestConvert(t *testing.T) {
+	rates := stubRates{
+		"USD/IDR": "15600.25",
+		"SGD/IDR": "12000",
+		"EUR/IDR": "17000",
+		"IDR/JPY": "0.0095",
+		"AUD/IDR": "error",
+	}
+	c := NewConverter(rates, "IDR", valueobject.RoundHalfEven)
+
+	tests := []struct {
+		name       string
+		amount     string
+		from, to   string
+		wantRate   string
+		wantAmount string
+	}{
+		{name: "same currency", amount: "10.005", from: "IDR", to: "IDR", wantRate: "1", wantAmount: "10"},
+		{name: "loaded pair", amount: "10.005", from: "USD", to: "IDR", wantRate: "15600.25", wantAmount: "156080.5"},
+		// 1/15600.25 = 0.0000641015368343...
+		{name: "inverse of a loaded pair", amount: "156002.5", from: "IDR", to: "USD", wantRate: "0.000064101537", wantAmount: "10"},
+		// SGD -> IDR loaded, IDR -> EUR only as EUR/IDR: 12000/17000 = 0.70588235294117...
+		{name: "cross through the base with an inverse leg", amount: "100", from: "SGD", to: "EUR", wantRate: "0.705882352941", wantAmount: "70.59"},
+		// USD -> IDR and IDR -> JPY both loaded: 15600.25 * 0.0095
+		{name: "cross through the base with direct legs", amount: "2", from: "USD", to: "JPY", wantRate: "148.202375", wantAmount: "296.4"},
+		// EUR/IDR and IDR/SGD as the inverse of SGD/IDR: 17000/12000 = 1.41666...
+		{name: "cross the other way", amount: "3", from: "EUR", to: "SGD", wantRate: "1.416666666667", wantAmount: "4.25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Convert(context.Background(), dec(t, tt.amount), tt.from, tt.to, time.Now())
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if got.Rate.Cmp(dec(t, tt.wantRate)) != 0 {
+				t.Errorf("rate %s, want %s", got.Rate, tt.wantRate)
+			}
+			if got.Amount.Cmp(dec(t, tt.wantAmount)) != 0 {
+				t.Errorf("amount %s, want %s", got.Amount, tt.wantAmount)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name     string
+		from, to string
+	}{
+		{name: "a leg is missing", from: "SGD", to: "CHF"},
+		{name: "no pair to or from the base", from: "CHF", to: "IDR"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.Rate(context.Background(), tt.from, tt.to, time.Now()); !errors.Is(err, entity.ErrFXRateNotFound) {
+				t.Errorf("Rate: got %v, want ErrFXRateNotFound", err)
+			}
+		})
+	}
+
+	t.Run("lookup failure is not a missing rate", func(t *testing.T) {
+		_, err := c.Rate(context.Background(), "AUD", "EUR", time.Now())
+		if err == nil || errors.Is(err, entity.ErrFXRateNotFound) {
+			t.Errorf("Rate: got %v, want the lookup error", err)
+		}
+	})
+}
+
+func TestConvertWithoutBaseDoesNotCross(t *testing.T) {
+	c := NewConverter(stubRates{"SGD/IDR": "12000", "EUR/IDR": "17000"}, "", valueobject.RoundHalfEven)
+	if _, err := c.Rate(context.Background(), "SGD", "EUR", time.Now()); !errors.Is(err, entity.ErrFXRateNotFound) {
+		t.Errorf("Rate: got %v, want ErrFXRateNotFound", err)
+	}
+}
+
+func TestApplyRoundsWithTheConfiguredMode(t *testing.T) {
+	// 0.125 is halfway between two minor units
+	for mode, want := range map[valueobject.RoundingMode]string{
+		valueobject.RoundHalfUp:   "0.13",
+		valueobject.RoundHalfEven: "0.12",
+		valueobject.RoundDown:     "0.12",
+		valueobject.RoundUp:       "0.13",
+	} {
+		c := NewConverter(stubRates{}, "IDR", mode)
+		if got := c.Apply(dec(t, "0.25"), dec(t, "0.5")); got.Cmp(dec(t, want)) != 0 {
+			t.Errorf("%s: Apply = %s, want %s", mode, got, want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package invoice
+
+import (
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// AmountScale is the number of decimals invoice amounts are kept in.
+const AmountScale = 2
+
+var hundred = valueobject.DecimalFromInt(100)
+
+// Totals are the invoice level sums of its lines.
+type Totals struct {
+	Subtotal      valueobject.Decimal
+	DiscountTotal valueobject.Decimal
+	TaxTotal      valueobject.Decimal
+	Total         valueobject.Decimal
+}
+
+// Compute fills in the subtotal, tax and total of every item and returns the invoice
+// totals. Tax is rounded half up per line, as it is printed per line on the invoice; the
+// invoice tax is the sum of the rounded line taxes so the lines always add up.
+func Compute(items []entity.InvoiceItem) (Totals, error) {
+	zero := valueobject.DecimalFromInt(0)
+	totals := Totals{Subtotal: zero, DiscountTotal: zero, TaxTotal: zero, Total: zero}
+	for i := range items {
+		item := &items[i]
+		if item.Discount.IsNull() {
+			item.Discount = zero
+		}
+		if item.TaxRate.IsNull() {
+			item.TaxRate = zero
+		}
+
+		item.Subtotal = item.Quantity.Mul(item.UnitPrice).Round(AmountScale, valueobject.RoundHalfUp)
+		if item.Discount.Cmp(item.Subtotal) > 0 {
+			return Totals{}, entity.ErrInvalidInvoiceItemAmount
+		}
+		taxable := item.Subtotal.Sub(item.Discount)
+		item.TaxAmount = taxable.Mul(item.TaxRate).Quo(hundred).Round(AmountScale, valueobject.RoundHalfUp)
+		item.Total = taxable.Add(item.TaxAmount)
+
+		totals.Subtotal = totals.Subtotal.Add(item.Subtotal)
+		totals.DiscountTotal = totals.DiscountTotal.Add(item.Discount)
+		totals.TaxTotal = totals.TaxTotal.Add(item.TaxAmount)
+		totals.Total = totals.Total.Add(item.Total)
+	}
+	return totals, nil
+}
@@ -0,0 +1,28 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// RunPeriodically calls fn every interval until ctx is cancelled. A failed run is logged
+// and retried on the next tick; runs never overlap.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, logger zerolog.Logger, fn func(ctx context.Context) error) {
+	logger.Info().Str("job", name).Msgf("⏱️ Job scheduled every %s", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info().Str("job", name).Msg("🛑 Job stopped")
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				logger.Error().Err(err).Str("job", name).Msg("❌ Job run failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package jwt
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+const (
+	minSecretSize = 32
+	minRSABits    = 2048
+)
+
+// Key is a verification key. Its algorithm follows from the key type, so a token can
+// never pick a weaker algorithm than the key was meant for.
+type Key struct {
+	ID        string
+	Algorithm string
+	secret    []byte
+	rsa       *rsa.PublicKey
+	ecdsa     *ecdsa.PublicKey
+}
+
+// KeySet is the keys tokens may be signed with.
+type KeySet struct {
+	keys []Key
+}
+
+// lookup selects the key named by kid. Without a kid only a set of exactly one key
+// matches, so tokens cannot probe a key set by leaving the kid out.
+func (ks *KeySet) lookup(kid, alg string) (Key, error) {
+	for _, k := range ks.keys {
+		if (kid == "" && len(ks.keys) == 1) || (kid != "" && k.ID == kid) {
+			if k.Algorithm != alg {
+				return Key{}, fmt.Errorf("%w: algorithm %s does not match the key", ErrInvalidToken, alg)
+			}
+			return k, nil
+		}
+	}
+	return Key{}, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+// SecretKeySet returns a set with one HS256 secret.
+func SecretKeySet(secret []byte) (*KeySet, error) {
+	if len(secret) < minSecretSize {
+		return nil, fmt.Errorf("jwt: HS256 secret must be at least %d bytes", minSecretSize)
+	}
+	return &KeySet{keys: []Key{{Algorithm: AlgHS256, secret: secret}}}, nil
+}
+
+// ParsePEM reads one RSA or P-256 public key, as a PUBLIC KEY, RSA PUBLIC KEY or
+// CERTIFICATE block.
+func ParsePEM(data []byte) (*KeySet, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwt: no PEM block found")
+	}
+	if next, _ := pem.Decode(rest); next != nil {
+		return nil, errors.New("jwt: PEM file must hold exactly one key, use a JWKS file for several")
+	}
+
+	var (
+		pub interface{}
+		err error
+	)
+	switch block.Type {
+	case "PUBLIC KEY":
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			pub = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("jwt: unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: %w", err)
+	}
+	key, err := publicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{keys: []Key{key}}, nil
+}
+
+func publicKey(pub interface{}) (Key, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return Key{}, fmt.Errorf("jwt: RSA keys must have at least %d bits", minRSABits)
+		}
+		return Key{Algorithm: AlgRS256, rsa: pub}, nil
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return Key{}, errors.New("jwt: only P-256 EC keys are supported")
+		}
+		return Key{Algorithm: AlgES256, ecdsa: pub}, nil
+	default:
+		return Key{}, fmt.Errorf("jwt: unsupported public key type %T", pub)
+	}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// ParseJWKS reads a JSON Web Key Set. Every signing key needs a distinct kid; keys
+// marked for encryption are skipped.
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("jwt: invalid JWKS: %w", err)
+	}
+
+	ks := &KeySet{}
+	seen := make(map[string]bool, len(doc.Keys))
+	for i, raw := range doc.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := raw.key()
+		if err != nil {
+			return nil, fmt.Errorf("jwt: JWKS key %d: %w", i, err)
+		}
+		if raw.Alg != "" && raw.Alg != key.Algorithm {
+			return nil, fmt.Errorf("jwt: JWKS key %d: alg %s does not match kty %s", i, raw.Alg, raw.Kty)
+		}
+		if raw.Kid == "" || seen[raw.Kid] {
+			return nil, fmt.Errorf("jwt: JWKS key %d: kid must be set and unique", i)
+		}
+		seen[raw.Kid] = true
+		key.ID = raw.Kid
+		ks.keys = append(ks.keys, key)
+	}
+	if len(ks.keys) == 0 {
+		return nil, errors.New("jwt: JWKS has no signing keys")
+	}
+	return ks, nil
+}
+
+func (k jwk) key() (Key, error) {
+	switch k.Kty {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return Key{}, errors.New("invalid k")
+		}
+		ks, err := SecretKeySet(secret)
+		if err != nil {
+			return Key{}, err
+		}
+		return ks.keys[0], nil
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return Key{}, errors.New("invalid n")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return Key{}, errors.New("invalid e")
+		}
+		exponent := int(new(big.Int).SetBytes(e).Int64())
+		if exponent < 3 || exponent%2 == 0 {
+			return Key{}, errors.New("invalid e")
+		}
+		return publicKey(&rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent})
+	case "EC":
+		if k.Crv != "P-256" {
+			return Key{}, errors.New("only crv P-256 is supported")
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return Key{}, errors.New("invalid x or y")
+		}
+		// crypto/ecdh rejects points that are not on the curve
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return Key{}, errors.New("point is not on the curve")
+		}
+		return publicKey(&ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)})
+	default:
+		return Key{}, fmt.Errorf("unsupported kty %q", k.Kty)
+	}
+}
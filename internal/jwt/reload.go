@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"os"
+	"time"
+)
+
+// FileLoader loads a PEM or JWKS key file into a verifier and reloads it whenever the
+// file changes, so keys can be rotated without a restart.
+type FileLoader struct {
+	path     string
+	parse    func([]byte) (*KeySet, error)
+	verifier *Verifier
+	modTime  time.Time
+	size     int64
+}
+
+// NewPEMFileLoader watches a PEM public key file.
+func NewPEMFileLoader(path string, verifier *Verifier) *FileLoader {
+	return &FileLoader{path: path, parse: ParsePEM, verifier: verifier}
+}
+
+// NewJWKSFileLoader watches a JWKS file.
+func NewJWKSFileLoader(path string, verifier *Verifier) *FileLoader {
+	return &FileLoader{path: path, parse: ParseJWKS, verifier: verifier}
+}
+
+// Reload loads the file if it changed since it was last read and reports whether it did.
+// A file that fails to parse leaves the current keys in place and is not retried until
+// it changes again.
+func (l *FileLoader) Reload() (bool, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(l.modTime) && info.Size() == l.size {
+		return false, nil
+	}
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return false, err
+	}
+	l.modTime, l.size = info.ModTime(), info.Size()
+	keys, err := l.parse(data)
+	if err != nil {
+		return false, err
+	}
+	l.verifier.Load(keys)
+	return true, nil
+}
@@ -0,0 +1,245 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken wraps every reason a token is rejected.
+var ErrInvalidToken = apperror.Forbidden("invalid_token", "jwt: invalid token")
+
+const (
+	// maxTokenSize bounds the work spent on a token before its signature is checked.
+	maxTokenSize = 8 << 10
+	// maxNumericDate is the end of year 9999, beyond which time arithmetic overflows.
+	maxNumericDate = 253402300799
+)
+
+// Claims are the verified claims of a token. Scopes come from the space separated "scope"
+// claim or the "scp" array, and MerchantID from the "merchant_id" claim.
+type Claims struct {
+	Issuer     string
+	Subject    string
+	Audience   []string
+	ExpiresAt  time.Time
+	NotBefore  *time.Time
+	IssuedAt   *time.Time
+	Scopes     []string
+	MerchantID uuid.UUID
+}
+
+// Verifier checks compact JWS tokens against a key set, which can be replaced while
+// tokens are being verified.
+type Verifier struct {
+	keys     atomic.Pointer[KeySet]
+	issuer   string
+	audience string
+	skew     time.Duration
+}
+
+// NewVerifier accepts tokens issued by issuer for audience, tolerating skew between the
+// issuer's clock and ours.
+func NewVerifier(keys *KeySet, issuer, audience string, skew time.Duration) *Verifier {
+	v := &Verifier{issuer: issuer, audience: audience, skew: skew}
+	v.keys.Store(keys)
+	return v
+}
+
+// Load replaces the key set.
+func (v *Verifier) Load(keys *KeySet) {
+	v.keys.Store(keys)
+}
+
+type header struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid"`
+	Crit []string `json:"crit"`
+}
+
+type rawClaims struct {
+	Iss        string          `json:"iss"`
+	Sub        string          `json:"sub"`
+	Aud        json.RawMessage `json:"aud"`
+	Exp        *json.Number    `json:"exp"`
+	Nbf        *json.Number    `json:"nbf"`
+	Iat        *json.Number    `json:"iat"`
+	Scope      string          `json:"scope"`
+	Scp        json.RawMessage `json:"scp"`
+	MerchantID string          `json:"merchant_id"`
+}
+
+// Verify checks the signature and the registered claims of token at now. A token must
+// carry exp, sub and merchant_id; iss and aud must match the configured values.
+func (v *Verifier) Verify(token string, now time.Time) (*Claims, error) {
+	if len(token) > maxTokenSize {
+		return nil, fmt.Errorf("%w: token too large", ErrInvalidToken)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a compact JWS", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if len(h.Crit) > 0 {
+		return nil, fmt.Errorf("%w: unsupported critical headers", ErrInvalidToken)
+	}
+	keys := v.keys.Load()
+	if keys == nil {
+		return nil, fmt.Errorf("%w: no keys loaded", ErrInvalidToken)
+	}
+	key, err := keys.lookup(h.Kid, h.Alg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	if !key.verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var raw rawClaims
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	return v.validate(raw, now)
+}
+
+func (v *Verifier) validate(raw rawClaims, now time.Time) (*Claims, error) {
+	claims := &Claims{Issuer: raw.Iss, Subject: raw.Sub}
+
+	exp, err := numericDate(raw.Exp)
+	if err != nil || exp == nil {
+		return nil, fmt.Errorf("%w: exp is required", ErrInvalidToken)
+	}
+	if !now.Before(exp.Add(v.skew)) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	claims.ExpiresAt = *exp
+	if claims.NotBefore, err = numericDate(raw.Nbf); err != nil {
+		return nil, fmt.Errorf("%w: nbf", ErrInvalidToken)
+	}
+	if claims.NotBefore != nil && now.Add(v.skew).Before(*claims.NotBefore) {
+		return nil, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if claims.IssuedAt, err = numericDate(raw.Iat); err != nil {
+		return nil, fmt.Errorf("%w: iat", ErrInvalidToken)
+	}
+
+	if raw.Iss != v.issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, raw.Iss)
+	}
+	if claims.Audience, err = stringOrList(raw.Aud); err != nil {
+		return nil, fmt.Errorf("%w: aud", ErrInvalidToken)
+	}
+	if !contains(claims.Audience, v.audience) {
+		return nil, fmt.Errorf("%w: token is not meant for this audience", ErrInvalidToken)
+	}
+	if raw.Sub == "" {
+		return nil, fmt.Errorf("%w: sub is required", ErrInvalidToken)
+	}
+	if claims.MerchantID, err = uuid.Parse(raw.MerchantID); err != nil || claims.MerchantID == uuid.Nil {
+		return nil, fmt.Errorf("%w: merchant_id is required", ErrInvalidToken)
+	}
+
+	claims.Scopes = strings.Fields(raw.Scope)
+	scp, err := stringOrList(raw.Scp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: scp", ErrInvalidToken)
+	}
+	for _, s := range scp {
+		if !contains(claims.Scopes, s) {
+			claims.Scopes = append(claims.Scopes, s)
+		}
+	}
+	return claims, nil
+}
+
+func (k Key) verify(signingInput, sig []byte) bool {
+	digest := sha256.Sum256(signingInput)
+	switch k.Algorithm {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(signingInput)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case AlgRS256:
+		return rsa.VerifyPKCS1v15(k.rsa, crypto.SHA256, digest[:], sig) == nil
+	case AlgES256:
+		// JWS carries R and S as two fixed size big-endian integers, not ASN.1
+		if len(sig) != 64 {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(k.ecdsa, digest[:], r, s)
+	default:
+		return false
+	}
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func numericDate(n *json.Number) (*time.Time, error) {
+	if n == nil {
+		return nil, nil
+	}
+	f, err := n.Float64()
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || f < 0 || f > maxNumericDate {
+		return nil, errors.New("invalid numeric date")
+	}
+	sec, frac := math.Modf(f)
+	t := time.Unix(int64(sec), int64(frac*1e9))
+	return &t, nil
+}
+
+// stringOrList reads claims that may be a single string or an array of strings.
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	testIssuer   = "https://auth.example.com"
+	testAudience = "beta-payment-api"
+	testSkew     = 30 * time.Second
+)
+
+func segment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signRS256 signs header and claims with key the way an issuer would.
+func signRS256(t *testing.T, key *rsa.PrivateKey, header, claims map[string]interface{}) string {
+	t.Helper()
+	input := segment(t, header) + "." + segment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// signHS256 signs header and claims with secret, whatever the header claims.
+func signHS256(t *testing.T, secret []byte, header, claims map[string]interface{}) string {
+	t.Helper()
+	input := segment(t, header) + "." + segment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// rsaKeySet publishes the public half of key under kid as a JWKS.
+func rsaKeySet(t *testing.T, key *rsa.PrivateKey, kid string) *KeySet {
+	t.Helper()
+	doc := map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": AlgRS256,
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal JWKS: %v", err)
+	}
+	ks, err := ParseJWKS(data)
+	if err != nil {
+		t.Fatalf("ParseJWKS: %v", err)
+	}
+	return ks
+}
+
+func TestVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	now := time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+	merchantID := uuid.New()
+	claims := func(edit func(c map[string]interface{})) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":         testIssuer,
+			"sub":         "user-1",
+			"aud":         testAudience,
+			"exp":         now.Add(time.Hour).Unix(),
+			"iat":         now.Unix(),
+			"scope":       "payments:read payments:write",
+			"merchant_id": merchantID.String(),
+		}
+		if edit != nil {
+			edit(c)
+		}
+		return c
+	}
+	rs256 := map[string]interface{}{"alg": AlgRS256, "typ": "JWT", "kid": "rsa-1"}
+
+	tests := []struct {
+		name   string
+		token  func() string
+		ok     bool
+		reason string
+	}{
+		{
+			name:  "valid",
+			token: func() string { return signRS256(t, key, rs256, claims(nil)) },
+			ok:    true,
+		},
+		{
+			name:   "alg none",
+			reason: "does not match the key",
+			token: func() string {
+				return segment(t, map[string]string{"alg": "none", "kid": "rsa-1"}) + "." + segment(t, claims(nil)) + "."
+			},
+		},
+		{
+			name:   "alg none without kid",
+			reason: "does not match the key",
+			token: func() string {
+				return segment(t, map[string]string{"alg": "none"}) + "." + segment(t, claims(nil)) + "."
+			},
+		},
+		{
+			name:   "HS256 signed with the RSA public key",
+			reason: "does not match the key",
+			token: func() string {
+				return signHS256(t, publicPEM, map[string]interface{}{"alg": AlgHS256, "kid": "rsa-1"}, claims(nil))
+			},
+		},
+		{
+			name:   "unknown kid",
+			reason: "unknown key",
+			token: func() string {
+				return signRS256(t, key, map[string]interface{}{"alg": AlgRS256, "kid": "rsa-2"}, claims(nil))
+			},
+		},
+		{
+			name: "no kid against a set of one",
+			token: func() string {
+				return signRS256(t, key, map[string]interface{}{"alg": AlgRS256}, claims(nil))
+			},
+			ok: true,
+		},
+		{
+			name:   "tampered claims",
+			reason: "bad signature",
+			token: func() string {
+				parts := strings.Split(signRS256(t, key, rs256, claims(nil)), ".")
+				parts[1] = segment(t, claims(func(c map[string]interface{}) { c["merchant_id"] = uuid.NewString() }))
+				return strings.Join(parts, ".")
+			},
+		},
+		{
+			name: "expired within skew",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { c["exp"] = now.Add(-testSkew / 2).Unix() }))
+			},
+			ok: true,
+		},
+		{
+			name:   "expired beyond skew",
+			reason: "expired",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { c["exp"] = now.Add(-testSkew).Unix() }))
+			},
+		},
+		{
+			name:   "missing exp",
+			reason: "exp is required",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { delete(c, "exp") }))
+			},
+		},
+		{
+			name: "not before within skew",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { c["nbf"] = now.Add(testSkew / 2).Unix() }))
+			},
+			ok: true,
+		},
+		{
+			name:   "not before beyond skew",
+			reason: "not valid yet",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { c["nbf"] = now.Add(2 * testSkew).Unix() }))
+			},
+		},
+		{
+			name:   "issuer mismatch",
+			reason: "unexpected issuer",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }))
+			},
+		},
+		{
+			name:   "audience mismatch",
+			reason: "audience",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { c["aud"] = "another-api" }))
+			},
+		},
+		{
+			name: "audience list holding ours",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { c["aud"] = []string{"another-api", testAudience} }))
+			},
+			ok: true,
+		},
+		{
+			name:   "missing merchant_id",
+			reason: "merchant_id is required",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { delete(c, "merchant_id") }))
+			},
+		},
+		{
+			name:   "nil merchant_id",
+			reason: "merchant_id is required",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { c["merchant_id"] = uuid.Nil.String() }))
+			},
+		},
+		{
+			name:   "missing sub",
+			reason: "sub is required",
+			token: func() string {
+				return signRS256(t, key, rs256, claims(func(c map[string]interface{}) { delete(c, "sub") }))
+			},
+		},
+	}
+
+	v := NewVerifier(rsaKeySet(t, key, "rsa-1"), testIssuer, testAudience, testSkew)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Verify(tt.token(), now)
+			if !tt.ok {
+				if !errors.Is(err, ErrInvalidToken) || !strings.Contains(err.Error(), tt.reason) {
+					t.Fatalf("Verify: got %v, want ErrInvalidToken for %s", err, tt.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if got.MerchantID != merchantID || got.Subject != "user-1" {
+				t.Errorf("claims: merchant %s sub %q", got.MerchantID, got.Subject)
+			}
+		})
+	}
+}
+
+// A PEM key set has a single RS256 key: an HS256 token keyed with that PEM, the classic
+// algorithm confusion, is refused even without a kid.
+func TestVerifyRejectsAlgorithmConfusionWithPEMKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	ks, err := ParsePEM(publicPEM)
+	if err != nil {
+		t.Fatalf("ParsePEM: %v", err)
+	}
+	now := time.Now()
+	token := signHS256(t, publicPEM, map[string]interface{}{"alg": AlgHS256}, map[string]interface{}{
+		"iss": testIssuer, "sub": "user-1", "aud": testAudience, "exp": now.Add(time.Hour).Unix(), "merchant_id": uuid.NewString(),
+	})
+	if _, err := NewVerifier(ks, testIssuer, testAudience, testSkew).Verify(token, now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify: got %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,65 @@
+package paymentlink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+// Signer issues and verifies payment link tokens. A token is the link ID and expiry,
+// base64url encoded, followed by an HMAC-SHA256 over them. The signature only proves the
+// link was issued here; whether it was used or revoked is read from the database.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{secret: secret}
+}
+
+const payloadSize = 16 + 8
+
+func (s *Signer) Sign(linkID uuid.UUID, expiresAt time.Time) string {
+	payload := make([]byte, payloadSize)
+	copy(payload, linkID[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(expiresAt.Unix()))
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.mac(payload))
+}
+
+// Verify checks the signature and expiry of token and returns the link ID it names.
+func (s *Signer) Verify(token string, now time.Time) (uuid.UUID, error) {
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, entity.ErrInvalidPaymentLinkToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != payloadSize {
+		return uuid.Nil, entity.ErrInvalidPaymentLinkToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil || !hmac.Equal(sig, s.mac(payload)) {
+		return uuid.Nil, entity.ErrInvalidPaymentLinkToken
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)
+	if !now.Before(expiresAt) {
+		return uuid.Nil, entity.ErrPaymentLinkExpired
+	}
+	linkID, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.Nil, entity.ErrInvalidPaymentLinkToken
+	}
+	return linkID, nil
+}
+
+func (s *Signer) mac(payload []byte) []byte {
+	m := hmac.New(sha256.New, s.secret)
+	m.Write(payload)
+	return m.Sum(nil)
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const principalKey contextKey = "principal"
+
+const (
+	MethodAPIKey = "API_KEY"
+	MethodJWT    = "JWT"
+	MethodHMAC   = "HMAC"
+)
+
+// Principal is the authenticated caller of a request. Subject is the API key ID or the
+// token's sub claim; Issuer is only set for tokens.
+type Principal struct {
+	Method     string
+	Subject    string
+	Name       string
+	Issuer     string
+	MerchantID uuid.UUID
+	Scopes     []string
+}
+
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WithPrincipal returns a context carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+// FromContext returns the authenticated caller, if the request has one.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey).(Principal)
+	return p, ok
+}
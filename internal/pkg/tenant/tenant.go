@@ -0,0 +1,30 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const merchantKey contextKey = "merchantID"
+
+// ErrMissingTenant is returned when tenant scoped data is accessed without a merchant in
+// the context. Repositories fail closed with it instead of falling back to unscoped queries.
+var ErrMissingTenant = errors.New("tenant: no merchant in context")
+
+// WithMerchantID returns a context scoped to the given merchant.
+func WithMerchantID(ctx context.Context, merchantID uuid.UUID) context.Context {
+	return context.WithValue(ctx, merchantKey, merchantID)
+}
+
+// MerchantID returns the merchant the context is scoped to.
+func MerchantID(ctx context.Context) (uuid.UUID, error) {
+	id, ok := ctx.Value(merchantKey).(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		return uuid.Nil, ErrMissingTenant
+	}
+	return id, nil
+}
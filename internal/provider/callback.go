@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+)
+
+var (
+	ErrInvalidSignature = apperror.Unauthorized("invalid_callback_signature", "provider: invalid callback signature")
+	ErrInvalidCallback  = apperror.BadRequest("invalid_callback", "provider: invalid callback payload")
+	ErrNoCallbacks      = apperror.NotFound("provider_without_callbacks", "provider: provider does not send callbacks")
+)
+
+// CallbackEvent is a provider notification normalized to our vocabulary.
+type CallbackEvent struct {
+	EventID    string
+	Reference  string
+	Status     Status
+	OccurredAt time.Time
+}
+
+// CallbackHandler is implemented by gateways that report results asynchronously.
+// VerifyCallback must be called, and succeed, before ParseCallback is trusted.
+type CallbackHandler interface {
+	VerifyCallback(header http.Header, body []byte) error
+	ParseCallback(body []byte) (*CallbackEvent, error)
+}
+
+// CallbackHandler returns the callback handler of a registered gateway.
+func (r *Router) CallbackHandler(name string) (CallbackHandler, error) {
+	g, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	h, ok := g.(CallbackHandler)
+	if !ok {
+		return nil, ErrNoCallbacks
+	}
+	return h, nil
+}
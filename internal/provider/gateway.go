@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+// Status is the state of a transaction as reported by a payment provider.
+type Status string
+
+const (
+	StatusPending    Status = "PENDING"
+	StatusAuthorized Status = "AUTHORIZED"
+	StatusCaptured   Status = "CAPTURED"
+	StatusRefunded   Status = "REFUNDED"
+	StatusVoided     Status = "VOIDED"
+	StatusDeclined   Status = "DECLINED"
+	StatusFailed     Status = "FAILED"
+)
+
+var (
+	ErrDeclined         = apperror.Declined("provider_declined", "provider: payment declined")
+	ErrTimeout          = apperror.Timeout("provider_timeout", "provider: request timed out")
+	ErrUnavailable      = apperror.Upstream("provider_unavailable", "provider: service unavailable")
+	ErrNotFound         = apperror.Upstream("provider_transaction_not_found", "provider: transaction not found")
+	ErrInvalidOperation = apperror.Upstream("provider_invalid_operation", "provider: operation not allowed in current transaction state")
+	ErrUnknownProvider  = apperror.NotFound("unknown_provider", "provider: unknown provider")
+	ErrNoRoute          = apperror.Rejected("no_provider_route", "provider: no provider route matches payment")
+)
+
+// Gateway is the contract every payment provider integration implements.
+// Declines are reported through Result.Status; errors are reserved for
+// transport and provider failures where the outcome is unknown.
+type Gateway interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Result, error)
+	Capture(ctx context.Context, req CaptureRequest) (*Result, error)
+	Refund(ctx context.Context, req RefundRequest) (*Result, error)
+	Void(ctx context.Context, req VoidRequest) (*Result, error)
+	GetStatus(ctx context.Context, reference string) (*Result, error)
+}
+
+type AuthorizeRequest struct {
+	PaymentID  uuid.UUID
+	Amount     valueobject.BigFloat
+	Currency   string
+	Method     string
+	Tag        string
+	CardNumber string
+}
+
+type CaptureRequest struct {
+	Reference string
+	Amount    valueobject.BigFloat
+}
+
+type RefundRequest struct {
+	Reference string
+	Amount    valueobject.BigFloat
+}
+
+type VoidRequest struct {
+	Reference string
+}
+
+type Result struct {
+	Reference string `json:"reference"`
+	Status    Status `json:"status"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
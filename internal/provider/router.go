@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule routes payments to a provider. Empty match fields act as wildcards.
+type Rule struct {
+	Currency string
+	Method   string
+	Tag      string
+	Provider string
+}
+
+// RouteRequest carries the payment attributes a Rule is matched against.
+type RouteRequest struct {
+	Currency string
+	Method   string
+	Tag      string
+}
+
+func (r Rule) matches(req RouteRequest) bool {
+	if r.Currency != "" && !strings.EqualFold(r.Currency, req.Currency) {
+		return false
+	}
+	if r.Method != "" && !strings.EqualFold(r.Method, req.Method) {
+		return false
+	}
+	if r.Tag != "" && r.Tag != req.Tag {
+		return false
+	}
+	return true
+}
+
+// Router selects the gateway for a payment. Rules are evaluated in order and
+// the first match wins; when nothing matches the default provider is used.
+type Router struct {
+	gateways        map[string]Gateway
+	rules           []Rule
+	defaultProvider string
+}
+
+func NewRouter(defaultProvider string, rules []Rule, gateways ...Gateway) (*Router, error) {
+	r := &Router{
+		gateways:        make(map[string]Gateway, len(gateways)),
+		rules:           rules,
+		defaultProvider: defaultProvider,
+	}
+	for _, g := range gateways {
+		r.gateways[g.Name()] = g
+	}
+	if defaultProvider != "" {
+		if _, ok := r.gateways[defaultProvider]; !ok {
+			return nil, fmt.Errorf("%w: default provider %q", ErrUnknownProvider, defaultProvider)
+		}
+	}
+	for _, rule := range rules {
+		if _, ok := r.gateways[rule.Provider]; !ok {
+			return nil, fmt.Errorf("%w: route target %q", ErrUnknownProvider, rule.Provider)
+		}
+	}
+	return r, nil
+}
+
+// Route returns the gateway that should process a payment with the given attributes.
+func (r *Router) Route(req RouteRequest) (Gateway, error) {
+	for _, rule := range r.rules {
+		if rule.matches(req) {
+			return r.gateways[rule.Provider], nil
+		}
+	}
+	if r.defaultProvider == "" {
+		return nil, ErrNoRoute
+	}
+	return r.gateways[r.defaultProvider], nil
+}
+
+// Get returns a registered gateway by name, e.g. the provider stored on a payment.
+func (r *Router) Get(name string) (Gateway, error) {
+	g, ok := r.gateways[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+	return g, nil
+}
+
+// ParseRules parses a routing spec such as
+// "currency=USD:stripe;method=QRIS,currency=IDR:simulator;tag=vip:simulator".
+// Rules are separated by ';', each rule is "<conditions>:<provider>" and
+// conditions are comma separated key=value pairs (currency, method, tag).
+func ParseRules(spec string) ([]Rule, error) {
+	var rules []Rule
+	for _, raw := range strings.Split(spec, ";") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		idx := strings.LastIndex(raw, ":")
+		if idx <= 0 || idx == len(raw)-1 {
+			return nil, fmt.Errorf("invalid provider route %q", raw)
+		}
+		rule := Rule{Provider: strings.TrimSpace(raw[idx+1:])}
+		for _, cond := range strings.Split(raw[:idx], ",") {
+			kv := strings.SplitN(strings.TrimSpace(cond), "=", 2)
+			if len(kv) != 2 || kv[1] == "" {
+				return nil, fmt.Errorf("invalid provider route condition %q", cond)
+			}
+			switch strings.ToLower(kv[0]) {
+			case "currency":
+				rule.Currency = kv[1]
+			case "method":
+				rule.Method = kv[1]
+			case "tag":
+				rule.Tag = kv[1]
+			default:
+				return nil, fmt.Errorf("unknown provider route condition %q", kv[0])
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
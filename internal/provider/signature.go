@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// SignatureVerifier checks that a callback body was signed by the provider.
+type SignatureVerifier interface {
+	Verify(header http.Header, body []byte) error
+}
+
+// HMACVerifier expects the hex encoded HMAC-SHA256 of the raw body in Header,
+// optionally prefixed with "sha256=".
+type HMACVerifier struct {
+	Header string
+	Secret []byte
+}
+
+func (v HMACVerifier) Verify(header http.Header, body []byte) error {
+	if len(v.Secret) == 0 {
+		return fmt.Errorf("%w: no secret configured", ErrInvalidSignature)
+	}
+	sig := strings.TrimPrefix(strings.TrimSpace(header.Get(v.Header)), "sha256=")
+	got, err := hex.DecodeString(sig)
+	if err != nil || len(got) == 0 {
+		return fmt.Errorf("%w: missing or malformed %s header", ErrInvalidSignature, v.Header)
+	}
+	mac := hmac.New(sha256.New, v.Secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// RSAVerifier expects the base64 encoded RSA PKCS#1 v1.5 SHA-256 signature of the raw body in Header.
+type RSAVerifier struct {
+	Header    string
+	PublicKey *rsa.PublicKey
+}
+
+func (v RSAVerifier) Verify(header http.Header, body []byte) error {
+	if v.PublicKey == nil {
+		return fmt.Errorf("%w: no public key configured", ErrInvalidSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(header.Get(v.Header)))
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("%w: missing or malformed %s header", ErrInvalidSignature, v.Header)
+	}
+	digest := sha256.Sum256(body)
+	if err := rsa.VerifyPKCS1v15(v.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// LoadRSAPublicKey reads a PEM encoded PKIX or PKCS#1 RSA public key.
+func LoadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in %s", path)
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an RSA public key", path)
+	}
+	return key, nil
+}
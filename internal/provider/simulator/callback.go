@@ -0,0 +1,52 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/provider"
+)
+
+// SignatureHeader carries the simulator callback signature.
+const SignatureHeader = "X-Simulator-Signature"
+
+type callbackPayload struct {
+	EventID    string    `json:"event_id"`
+	Reference  string    `json:"reference"`
+	Status     string    `json:"status"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (g *Gateway) VerifyCallback(header http.Header, body []byte) error {
+	if g.cfg.CallbackVerifier == nil {
+		return fmt.Errorf("%w: callback verification is not configured", provider.ErrInvalidSignature)
+	}
+	return g.cfg.CallbackVerifier.Verify(header, body)
+}
+
+func (g *Gateway) ParseCallback(body []byte) (*provider.CallbackEvent, error) {
+	var p callbackPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", provider.ErrInvalidCallback, err)
+	}
+	if p.EventID == "" || p.Reference == "" {
+		return nil, fmt.Errorf("%w: event_id and reference are required", provider.ErrInvalidCallback)
+	}
+
+	status := provider.Status(p.Status)
+	switch status {
+	case provider.StatusPending, provider.StatusAuthorized, provider.StatusCaptured,
+		provider.StatusRefunded, provider.StatusVoided, provider.StatusDeclined, provider.StatusFailed:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", provider.ErrInvalidCallback, p.Status)
+	}
+
+	return &provider.CallbackEvent{
+		EventID:    p.EventID,
+		Reference:  p.Reference,
+		Status:     status,
+		OccurredAt: p.OccurredAt,
+	}, nil
+}
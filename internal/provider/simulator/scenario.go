@@ -0,0 +1,73 @@
+package simulator
+
+import (
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/provider"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+type outcome int
+
+const (
+	outcomeApprove outcome = iota
+	outcomeDecline
+	outcomePending
+	outcomeTimeout
+	outcomeUnavailable
+	outcomeSlowApprove
+)
+
+type scenario struct {
+	outcome outcome
+	code    string
+	message string
+}
+
+var approved = scenario{outcome: outcomeApprove, code: "00", message: "Approved"}
+
+// Test card numbers take precedence over magic amounts.
+var cardScenarios = map[string]scenario{
+	"4242424242424242": approved,
+	"4000000000000002": {outcome: outcomeDecline, code: "card_declined", message: "Card declined"},
+	"4000000000009995": {outcome: outcomeDecline, code: "insufficient_funds", message: "Insufficient funds"},
+	"4000000000000069": {outcome: outcomeDecline, code: "expired_card", message: "Card expired"},
+	"4000000000003220": {outcome: outcomePending, code: "authentication_required", message: "Awaiting cardholder authentication"},
+	"4000000000000077": {outcome: outcomeTimeout, code: "timeout", message: "Issuer did not respond"},
+	"4000000000000119": {outcome: outcomeUnavailable, code: "processing_error", message: "Processing error"},
+}
+
+// Magic amounts are selected by the minor unit (cents) of the amount.
+var amountScenarios = map[string]scenario{
+	"01": {outcome: outcomeDecline, code: "insufficient_funds", message: "Insufficient funds"},
+	"02": {outcome: outcomeDecline, code: "do_not_honor", message: "Do not honor"},
+	"03": {outcome: outcomeTimeout, code: "timeout", message: "Issuer did not respond"},
+	"04": {outcome: outcomeUnavailable, code: "processing_error", message: "Processing error"},
+	"05": {outcome: outcomePending, code: "pending", message: "Awaiting asynchronous confirmation"},
+	"06": {outcome: outcomeSlowApprove, code: "00", message: "Approved"},
+}
+
+func resolveScenario(cardNumber string, amount valueobject.BigFloat) scenario {
+	if s, ok := cardScenarios[strings.ReplaceAll(cardNumber, " ", "")]; ok {
+		return s
+	}
+	if amount.Float == nil {
+		return approved
+	}
+	text := amount.Text('f', 2)
+	if s, ok := amountScenarios[text[len(text)-2:]]; ok {
+		return s
+	}
+	return approved
+}
+
+func (s scenario) status() provider.Status {
+	switch s.outcome {
+	case outcomeDecline:
+		return provider.StatusDeclined
+	case outcomePending:
+		return provider.StatusPending
+	default:
+		return provider.StatusAuthorized
+	}
+}
@@ -0,0 +1,208 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/provider"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+const Name = "simulator"
+
+type Config struct {
+	// Latency is added to every call.
+	Latency time.Duration
+	// SlowLatency is added on top of Latency for the slow-approval scenario.
+	SlowLatency time.Duration
+	// TimeoutLatency is how long a timeout scenario blocks before failing.
+	TimeoutLatency time.Duration
+	// CallbackVerifier authenticates inbound callbacks. Callbacks are rejected when nil.
+	CallbackVerifier provider.SignatureVerifier
+}
+
+type transaction struct {
+	status     provider.Status
+	authorized *big.Float
+	captured   *big.Float
+	refunded   *big.Float
+}
+
+// Gateway is a deterministic, in-memory payment provider. Outcomes are driven
+// by test card numbers and magic amounts so that flows can be exercised offline.
+type Gateway struct {
+	cfg          Config
+	mu           sync.Mutex
+	transactions map[string]*transaction
+}
+
+func New(cfg Config) *Gateway {
+	if cfg.SlowLatency == 0 {
+		cfg.SlowLatency = 2 * time.Second
+	}
+	if cfg.TimeoutLatency == 0 {
+		cfg.TimeoutLatency = 5 * time.Second
+	}
+	return &Gateway{
+		cfg:          cfg,
+		transactions: make(map[string]*transaction),
+	}
+}
+
+func (g *Gateway) Name() string {
+	return Name
+}
+
+func (g *Gateway) Authorize(ctx context.Context, req provider.AuthorizeRequest) (*provider.Result, error) {
+	sc := resolveScenario(req.CardNumber, req.Amount)
+
+	delay := g.cfg.Latency
+	switch sc.outcome {
+	case outcomeSlowApprove:
+		delay += g.cfg.SlowLatency
+	case outcomeTimeout:
+		delay += g.cfg.TimeoutLatency
+	}
+	if err := sleep(ctx, delay); err != nil {
+		return nil, err
+	}
+
+	switch sc.outcome {
+	case outcomeTimeout:
+		return nil, fmt.Errorf("%w: %s", provider.ErrTimeout, sc.message)
+	case outcomeUnavailable:
+		return nil, fmt.Errorf("%w: %s", provider.ErrUnavailable, sc.message)
+	}
+
+	ref := reference(req)
+	g.mu.Lock()
+	g.transactions[ref] = &transaction{
+		status:     sc.status(),
+		authorized: copyAmount(req.Amount),
+		captured:   new(big.Float),
+		refunded:   new(big.Float),
+	}
+	g.mu.Unlock()
+
+	return &provider.Result{Reference: ref, Status: sc.status(), Code: sc.code, Message: sc.message}, nil
+}
+
+func (g *Gateway) Capture(ctx context.Context, req provider.CaptureRequest) (*provider.Result, error) {
+	if err := sleep(ctx, g.cfg.Latency); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	txn, ok := g.transactions[req.Reference]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	if txn.status != provider.StatusAuthorized {
+		return nil, fmt.Errorf("%w: cannot capture %s transaction", provider.ErrInvalidOperation, txn.status)
+	}
+	amount := txn.authorized
+	if req.Amount.Float != nil {
+		if req.Amount.Cmp(txn.authorized) > 0 {
+			return &provider.Result{Reference: req.Reference, Status: provider.StatusDeclined, Code: "amount_too_large", Message: "Capture exceeds authorized amount"}, nil
+		}
+		amount = copyAmount(req.Amount)
+	}
+	txn.captured = amount
+	txn.status = provider.StatusCaptured
+
+	return &provider.Result{Reference: req.Reference, Status: provider.StatusCaptured, Code: "00", Message: "Captured"}, nil
+}
+
+func (g *Gateway) Refund(ctx context.Context, req provider.RefundRequest) (*provider.Result, error) {
+	if err := sleep(ctx, g.cfg.Latency); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	txn, ok := g.transactions[req.Reference]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	if txn.status != provider.StatusCaptured && txn.status != provider.StatusRefunded {
+		return nil, fmt.Errorf("%w: cannot refund %s transaction", provider.ErrInvalidOperation, txn.status)
+	}
+	remaining := new(big.Float).Sub(txn.captured, txn.refunded)
+	if req.Amount.Float == nil || req.Amount.Sign() <= 0 || req.Amount.Cmp(remaining) > 0 {
+		return &provider.Result{Reference: req.Reference, Status: provider.StatusDeclined, Code: "invalid_amount", Message: "Refund amount is not refundable"}, nil
+	}
+	txn.refunded = new(big.Float).Add(txn.refunded, req.Amount.Float)
+	if txn.refunded.Cmp(txn.captured) == 0 {
+		txn.status = provider.StatusRefunded
+	}
+
+	return &provider.Result{Reference: req.Reference, Status: provider.StatusRefunded, Code: "00", Message: "Refunded"}, nil
+}
+
+func (g *Gateway) Void(ctx context.Context, req provider.VoidRequest) (*provider.Result, error) {
+	if err := sleep(ctx, g.cfg.Latency); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	txn, ok := g.transactions[req.Reference]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	if txn.status != provider.StatusAuthorized && txn.status != provider.StatusPending {
+		return nil, fmt.Errorf("%w: cannot void %s transaction", provider.ErrInvalidOperation, txn.status)
+	}
+	txn.status = provider.StatusVoided
+
+	return &provider.Result{Reference: req.Reference, Status: provider.StatusVoided, Code: "00", Message: "Voided"}, nil
+}
+
+func (g *Gateway) GetStatus(ctx context.Context, ref string) (*provider.Result, error) {
+	if err := sleep(ctx, g.cfg.Latency); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	txn, ok := g.transactions[ref]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	return &provider.Result{Reference: ref, Status: txn.status, Code: "00", Message: string(txn.status)}, nil
+}
+
+// reference is derived from the payment ID so repeated runs produce the same references.
+func reference(req provider.AuthorizeRequest) string {
+	return "sim_" + strings.ReplaceAll(req.PaymentID.String(), "-", "")
+}
+
+func copyAmount(amount valueobject.BigFloat) *big.Float {
+	if amount.Float == nil {
+		return new(big.Float)
+	}
+	return new(big.Float).Copy(amount.Float)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", provider.ErrTimeout, ctx.Err())
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,307 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// decodeSymbol reads c back the way a scanner would: it checks the finder patterns, reads
+// and verifies the format and version information, removes the mask, de-interleaves the
+// blocks, checks every block's Reed-Solomon syndromes and returns the byte mode segment.
+func decodeSymbol(t *testing.T, c *Code) ([]byte, Level) {
+	t.Helper()
+	if c.Size != c.Version*4+17 {
+		t.Fatalf("version %d symbol is %d modules wide", c.Version, c.Size)
+	}
+	for _, corner := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(abs(dx-3), abs(dy-3))
+				if c.Dark(corner[0]+dx, corner[1]+dy) != (ring != 2) {
+					t.Fatalf("finder pattern at %v is broken at %d,%d", corner, dx, dy)
+				}
+			}
+		}
+	}
+
+	// Format information, both copies, BCH(15,5) checked
+	var first, second int
+	for i := 0; i <= 5; i++ {
+		first |= b2i(c.Dark(8, i)) << i
+	}
+	first |= b2i(c.Dark(8, 7))<<6 | b2i(c.Dark(8, 8))<<7 | b2i(c.Dark(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		first |= b2i(c.Dark(14-i, 8)) << i
+	}
+	for i := 0; i < 8; i++ {
+		second |= b2i(c.Dark(c.Size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= b2i(c.Dark(8, c.Size-15+i)) << i
+	}
+	if first != second {
+		t.Fatalf("format copies differ: %015b and %015b", first, second)
+	}
+	if !c.Dark(8, c.Size-8) {
+		t.Fatal("dark module is light")
+	}
+	format := first ^ 0x5412
+	if bchRemainder(format, 0x537, 10) != 0 {
+		t.Fatalf("format information %015b fails its BCH check", format)
+	}
+	mask := format >> 10 & 7
+	level := Level(-1)
+	for l, bits := range formatBits {
+		if bits == format>>13 {
+			level = Level(l)
+		}
+	}
+
+	if c.Version >= 7 {
+		var a, b int
+		for i := 0; i < 18; i++ {
+			a |= b2i(c.Dark(c.Size-11+i%3, i/3)) << i
+			b |= b2i(c.Dark(i/3, c.Size-11+i%3)) << i
+		}
+		if a != b || a>>12 != c.Version || bchRemainder(a, 0x1F25, 12) != 0 {
+			t.Fatalf("version information %018b / %018b does not encode version %d", a, b, c.Version)
+		}
+	}
+
+	// Codewords in the two column zigzag, unmasked
+	var bits bitBuffer
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if isFunctionModule(c.Version, x, y) {
+					continue
+				}
+				bits.append(b2i(c.Dark(x, y) != masked(mask, x, y)), 1)
+			}
+		}
+	}
+	raw := rawDataModules(c.Version) / 8
+	codewords := bits.bytes[:raw]
+
+	// De-interleave: data codewords of every block first, then their ECC codewords
+	numBlocks := eccBlocks[level][c.Version]
+	eccLen := eccCodewordsPerBlock[level][c.Version]
+	numShort := numBlocks - raw%numBlocks
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= raw/numBlocks-eccLen; i++ {
+		for j := range blocks {
+			if i < raw/numBlocks-eccLen || j >= numShort {
+				blocks[j] = append(blocks[j], codewords[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for j := range blocks {
+		data = append(data, blocks[j]...)
+	}
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+	for j, block := range blocks {
+		for i, root := 0, byte(1); i < eccLen; i, root = i+1, gfMultiply(root, 2) {
+			var syndrome byte
+			for _, cw := range block {
+				syndrome = gfMultiply(syndrome, root) ^ cw
+			}
+			if syndrome != 0 {
+				t.Fatalf("block %d has a non-zero syndrome %d", j, i)
+			}
+		}
+	}
+
+	// Byte mode segment
+	r := bitReader{data: data}
+	if mode := r.read(4); mode != 0x4 {
+		t.Fatalf("mode indicator %04b, want byte mode", mode)
+	}
+	n := r.read(charCountBits(c.Version))
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = byte(r.read(8))
+	}
+	return out, level
+}
+
+// isFunctionModule reports whether x, y belongs to a finder, separator, timing, alignment,
+// format or version pattern, following ISO/IEC 18004 section 6.3.
+func isFunctionModule(version, x, y int) bool {
+	size := version*4 + 17
+	switch {
+	case x == 6 || y == 6:
+		return true
+	case x <= 8 && y <= 8, x >= size-8 && y <= 8, x <= 8 && y >= size-8:
+		return true
+	case version >= 7 && ((x >= size-11 && y <= 5) || (y >= size-11 && x <= 5)):
+		return true
+	}
+	positions := alignmentPositions(version)
+	last := len(positions) - 1
+	for i, ax := range positions {
+		for j, ay := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			if abs(x-ax) <= 2 && abs(y-ay) <= 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// masked reports whether mask pattern mask inverts the module at x, y (ISO/IEC 18004 table 10).
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return y*x%2+y*x%3 == 0
+	case 6:
+		return (y*x%2+y*x%3)%2 == 0
+	default:
+		return ((y+x)%2+y*x%3)%2 == 0
+	}
+}
+
+func bchRemainder(value, generator, degree int) int {
+	for i := bitLength(value) - 1; i >= degree; i-- {
+		if value>>i&1 != 0 {
+			value ^= generator << (i - degree)
+		}
+	}
+	return value
+}
+
+func bitLength(v int) int {
+	n := 0
+	for ; v > 0; v >>= 1 {
+		n++
+	}
+	return n
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		v = v<<1 | int(r.data[r.pos/8]>>(7-r.pos%8)&1)
+		r.pos++
+	}
+	return v
+}
+
+func TestEncodeDecodes(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		data    string
+		level   Level
+		version int
+	}{
+		{name: "short", data: "HELLO", level: LevelL, version: 1},
+		{name: "version 1 full", data: strings.Repeat("a", 17), level: LevelL, version: 1},
+		{name: "version 2", data: strings.Repeat("a", 18), level: LevelL, version: 2},
+		{name: "qris dynamic", data: qrisDynamic, level: LevelM},
+		{name: "emvco sample", data: emvcoSample, level: LevelQ},
+		{name: "qris static high", data: qrisStatic, level: LevelH},
+		{name: "version information", data: strings.Repeat("0123456789", 20), level: LevelM},
+		{name: "uneven blocks", data: strings.Repeat("Z", 500), level: LevelQ},
+		{name: "sixteen bit length", data: strings.Repeat("x", 1200), level: LevelL},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := Encode([]byte(tc.data), tc.level)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if tc.version != 0 && c.Version != tc.version {
+				t.Errorf("version %d, want %d", c.Version, tc.version)
+			}
+			got, level := decodeSymbol(t, c)
+			if level != tc.level {
+				t.Errorf("level %d, want %d", level, tc.level)
+			}
+			if string(got) != tc.data {
+				t.Errorf("decoded %q, want %q", got, tc.data)
+			}
+		})
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	// 2953 bytes is the byte mode capacity of version 40 at level L
+	if _, err := Encode(bytes.Repeat([]byte("x"), 2953), LevelL); err != nil {
+		t.Errorf("Encode at capacity: %v", err)
+	}
+	if _, err := Encode(bytes.Repeat([]byte("x"), 2954), LevelL); !errors.Is(err, ErrDataTooLong) {
+		t.Errorf("Encode over capacity: got %v, want ErrDataTooLong", err)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	c, err := Encode([]byte(qrisDynamic), LevelM)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	const scale = 3
+	b, err := c.PNG(scale)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if width := (c.Size + 2*QuietZone) * scale; img.Bounds().Dx() != width || img.Bounds().Dy() != width {
+		t.Fatalf("image is %v, want %dx%d", img.Bounds(), width, width)
+	}
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			mx, my := x/scale-QuietZone, y/scale-QuietZone
+			want := mx >= 0 && my >= 0 && mx < c.Size && my < c.Size && c.Dark(mx, my)
+			r, _, _, _ := img.At(x, y).RGBA()
+			if dark := r == 0; dark != want {
+				t.Fatalf("pixel %d,%d dark = %v, want %v", x, y, dark, want)
+			}
+		}
+	}
+}
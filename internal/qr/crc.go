@@ -0,0 +1,18 @@
+package qr
+
+// crc16CCITT is the CRC-16/CCITT-FALSE checksum EMVCo uses for tag 63: polynomial 0x1021,
+// initial value 0xFFFF, no reflection and no final XOR.
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
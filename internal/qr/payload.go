@@ -0,0 +1,136 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EMVCo Merchant-Presented Mode root tags.
+const (
+	TagPayloadFormat       = "00"
+	TagInitiationMethod    = "01"
+	TagMerchantCategory    = "52"
+	TagCurrency            = "53"
+	TagAmount              = "54"
+	TagTipIndicator        = "55"
+	TagCountryCode         = "58"
+	TagMerchantName        = "59"
+	TagMerchantCity        = "60"
+	TagPostalCode          = "61"
+	TagAdditionalData      = "62"
+	TagCRC                 = "63"
+	TagMerchantInformation = "64"
+)
+
+// Additional data (tag 62) sub-tags.
+const (
+	TagBillNumber     = "01"
+	TagStoreLabel     = "03"
+	TagReferenceLabel = "05"
+	TagTerminalLabel  = "07"
+)
+
+// Merchant account information (tags 26-51) sub-tags as used by QRIS.
+const (
+	TagAccountGUID     = "00"
+	TagAccountPAN      = "01"
+	TagAccountID       = "02"
+	TagAccountCriteria = "03"
+)
+
+const (
+	PayloadFormatVersion = "01"
+	InitiationStatic     = "11"
+	InitiationDynamic    = "12"
+
+	// TagQRISAccount carries the national QRIS merchant ID (NMID) under the QRIS domain.
+	TagQRISAccount = "51"
+	QRISDomain     = "ID.CO.QRIS.WWW"
+)
+
+// Payload is an ordered list of top-level data objects. Order is preserved so a parsed payload
+// re-encodes to the same string; the CRC is always recomputed when encoding.
+type Payload struct {
+	Fields []Field
+}
+
+func isTemplateTag(tag string) bool {
+	return (tag >= "26" && tag <= "51") || tag == TagAdditionalData || tag == TagMerchantInformation || tag >= "80"
+}
+
+// Parse decodes an EMVCo payload, checking that it starts with the payload format indicator,
+// ends with a CRC and that the CRC matches.
+func Parse(s string) (*Payload, error) {
+	if len(s) < 8 || !strings.HasPrefix(s, TagPayloadFormat+"02") {
+		return nil, fmt.Errorf("%w: missing payload format indicator", ErrInvalidPayload)
+	}
+	crcStart := len(s) - 8
+	if s[crcStart:crcStart+4] != TagCRC+"04" {
+		return nil, fmt.Errorf("%w: missing crc", ErrInvalidPayload)
+	}
+	if want := fmt.Sprintf("%04X", crc16CCITT([]byte(s[:len(s)-4]))); !strings.EqualFold(s[len(s)-4:], want) {
+		return nil, fmt.Errorf("%w: crc mismatch, expected %s", ErrInvalidPayload, want)
+	}
+
+	fields, err := parseFields(s[:crcStart], isTemplateTag)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.Tag == TagCRC {
+			return nil, fmt.Errorf("%w: crc must be the last data object", ErrInvalidPayload)
+		}
+	}
+	return &Payload{Fields: fields}, nil
+}
+
+// Encode serialises the payload and appends the CRC data object.
+func (p *Payload) Encode() (string, error) {
+	if len(p.Fields) == 0 || p.Fields[0].Tag != TagPayloadFormat {
+		return "", fmt.Errorf("%w: payload format indicator must come first", ErrInvalidPayload)
+	}
+	fields := make([]Field, 0, len(p.Fields))
+	for _, f := range p.Fields {
+		if f.Tag != TagCRC {
+			fields = append(fields, f)
+		}
+	}
+
+	var b strings.Builder
+	if err := encodeFields(&b, fields); err != nil {
+		return "", err
+	}
+	b.WriteString(TagCRC + "04")
+	return b.String() + fmt.Sprintf("%04X", crc16CCITT([]byte(b.String()))), nil
+}
+
+// Value returns the value of a top-level data object.
+func (p *Payload) Value(tag string) string {
+	f, _ := findField(p.Fields, tag)
+	return f.Value
+}
+
+// SubValue returns the value of a data object nested in a template.
+func (p *Payload) SubValue(tag, subTag string) string {
+	f, _ := findField(p.Fields, tag)
+	sub, _ := findField(f.Children, subTag)
+	return sub.Value
+}
+
+// Dynamic reports whether the payload is for a single transaction.
+func (p *Payload) Dynamic() bool {
+	return p.Value(TagInitiationMethod) == InitiationDynamic
+}
+
+// MerchantAccount returns the merchant account template registered under guid, if any.
+func (p *Payload) MerchantAccount(guid string) (Field, bool) {
+	for _, f := range p.Fields {
+		if f.Tag < "26" || f.Tag > "51" {
+			continue
+		}
+		if sub, ok := findField(f.Children, TagAccountGUID); ok && strings.EqualFold(sub.Value, guid) {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
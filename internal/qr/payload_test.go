@@ -0,0 +1,230 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// emvcoSample is the merchant-presented payload from the EMVCo QR Code Specification for
+// Payment Systems, Merchant-Presented Mode, Appendix A. Its lengths count characters: the
+// merchant information template (tag 64) holds Chinese text.
+const emvcoSample = "00020101021229300012D156000000000510A93FO3230Q31280012D15600000001030812345678" +
+	"520441115802CN5914BEST TRANSPORT6007BEIJING64200002ZH0104最佳运输0202北京540523.7253031565502016233030412340603***0708A60086670902ME91320016A0112233449988770708123456786304A13A"
+
+// qrisDynamic and qrisStatic are QRIS payloads for the merchant in testMerchant, with and
+// without a transaction amount.
+const (
+	qrisDynamic = "00020101021226570011ID.DANA.WWW011893600915302259148102090225914810303UMI" +
+		"51440014ID.CO.QRIS.WWW0215ID10200176114730303UMI520458125303360540815000.005802ID" +
+		"5922Warung Sayur Bu Sugeng6010Kab. Demak610559567623605250T17G7INGUVFBBBQWSSCNR97J0703A016304729E"
+	qrisStatic = "00020101021151440014ID.CO.QRIS.WWW0215ID10200176114730303UMI5204581253033605802ID" +
+		"5922Warung Sayur Bu Sugeng6010Kab. Demak6304BA01"
+)
+
+const testPaymentID = "0d9f5e3c-7b1a-4c55-9a8e-3f2b1c0d4e5f"
+
+var testMerchant = Merchant{
+	Name:         "Warung Sayur Bu Sugeng",
+	City:         "Kab. Demak",
+	PostalCode:   "59567",
+	CategoryCode: "5812",
+	AcquirerGUID: "ID.DANA.WWW",
+	PAN:          "936009153022591481",
+	ID:           "022591481",
+	NMID:         "ID1020017611473",
+	Criteria:     "UMI",
+}
+
+func TestCRC16CCITT(t *testing.T) {
+	// The standard check value of CRC-16/CCITT-FALSE
+	if got := crc16CCITT([]byte("123456789")); got != 0x29B1 {
+		t.Errorf("crc16CCITT(123456789) = %04X, want 29B1", got)
+	}
+	for _, sample := range []string{emvcoSample, qrisDynamic, qrisStatic} {
+		want := sample[len(sample)-4:]
+		if got := fmt.Sprintf("%04X", crc16CCITT([]byte(sample[:len(sample)-4]))); got != want {
+			t.Errorf("crc16CCITT of %.20s... = %s, want %s", sample, got, want)
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		payload string
+		values  map[string]string
+		sub     map[[2]string]string
+		dynamic bool
+	}{
+		{
+			name:    "emvco sample",
+			payload: emvcoSample,
+			values: map[string]string{
+				TagMerchantName: "BEST TRANSPORT", TagMerchantCity: "BEIJING", TagCountryCode: "CN",
+				TagMerchantCategory: "4111", TagCurrency: "156", TagAmount: "23.72", TagTipIndicator: "01",
+			},
+			sub: map[[2]string]string{
+				{TagMerchantInformation, "00"}: "ZH",
+				{TagMerchantInformation, "01"}: "最佳运输",
+				{TagMerchantInformation, "02"}: "北京",
+				{TagAdditionalData, "03"}:      "1234",
+				{TagAdditionalData, "07"}:      "A6008667",
+				{"29", TagAccountGUID}:         "D15600000000",
+			},
+			dynamic: true,
+		},
+		{
+			name:    "qris dynamic",
+			payload: qrisDynamic,
+			values: map[string]string{
+				TagMerchantName: "Warung Sayur Bu Sugeng", TagAmount: "15000.00", TagCurrency: "360", TagPostalCode: "59567",
+			},
+			sub: map[[2]string]string{
+				{TagQRISAccount, TagAccountGUID}:      QRISDomain,
+				{TagQRISAccount, TagAccountID}:        "ID1020017611473",
+				{TagQRISAccount, TagAccountCriteria}:  "UMI",
+				{"26", TagAccountPAN}:                 "936009153022591481",
+				{TagAdditionalData, TagTerminalLabel}: "A01",
+			},
+			dynamic: true,
+		},
+		{
+			name:    "qris static",
+			payload: qrisStatic,
+			values:  map[string]string{TagMerchantCity: "Kab. Demak", TagAmount: ""},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := Parse(tc.payload)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			for tag, want := range tc.values {
+				if got := p.Value(tag); got != want {
+					t.Errorf("tag %s = %q, want %q", tag, got, want)
+				}
+			}
+			for tags, want := range tc.sub {
+				if got := p.SubValue(tags[0], tags[1]); got != want {
+					t.Errorf("tag %s %s = %q, want %q", tags[0], tags[1], got, want)
+				}
+			}
+			if p.Dynamic() != tc.dynamic {
+				t.Errorf("Dynamic() = %v, want %v", p.Dynamic(), tc.dynamic)
+			}
+
+			encoded, err := p.Encode()
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if encoded != tc.payload {
+				t.Errorf("re-encoded payload differs\n got %s\nwant %s", encoded, tc.payload)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		payload string
+	}{
+		{name: "empty", payload: ""},
+		{name: "no format indicator", payload: emvcoSample[6:]},
+		{name: "no crc", payload: emvcoSample[:len(emvcoSample)-8]},
+		{name: "wrong crc", payload: emvcoSample[:len(emvcoSample)-4] + "A13B"},
+		{name: "altered value", payload: strings.Replace(emvcoSample, "23.72", "93.72", 1)},
+		{name: "length past the end", payload: withCRC("0002015910BEST")},
+		{name: "non-digit tag", payload: withCRC("000201X1020A")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Parse(tc.payload); !errors.Is(err, ErrInvalidPayload) {
+				t.Errorf("Parse: got %v, want ErrInvalidPayload", err)
+			}
+		})
+	}
+}
+
+// withCRC completes s with a valid CRC so only the structure before it is under test.
+func withCRC(s string) string {
+	s += TagCRC + "04"
+	return s + fmt.Sprintf("%04X", crc16CCITT([]byte(s)))
+}
+
+func TestNewQRIS(t *testing.T) {
+	id := uuid.MustParse(testPaymentID)
+	for _, tc := range []struct {
+		name string
+		tx   Transaction
+		want string
+	}{
+		{
+			name: "dynamic",
+			tx:   Transaction{Amount: "15000.00", Currency: "360", ReferenceLabel: EncodeReference(id), TerminalLabel: "A01"},
+			want: qrisDynamic,
+		},
+		{name: "static", tx: Transaction{Currency: "360"}, want: qrisStatic},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := testMerchant
+			if tc.tx.Amount == "" {
+				m.AcquirerGUID, m.PostalCode = "", ""
+			}
+			p, err := NewQRIS(m, tc.tx)
+			if err != nil {
+				t.Fatalf("NewQRIS: %v", err)
+			}
+			got, err := p.Encode()
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("payload\n got %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name string
+		m    func(*Merchant)
+		tx   Transaction
+	}{
+		{name: "no name", m: func(m *Merchant) { m.Name = "" }, tx: Transaction{Currency: "360"}},
+		{name: "long name", m: func(m *Merchant) { m.Name = strings.Repeat("W", 26) }, tx: Transaction{Currency: "360"}},
+		{name: "long city", m: func(m *Merchant) { m.City = "Kabupaten Demak Jawa" }, tx: Transaction{Currency: "360"}},
+		{name: "bad category", m: func(m *Merchant) { m.CategoryCode = "58A2" }, tx: Transaction{Currency: "360"}},
+		{name: "alphabetic currency", m: func(*Merchant) {}, tx: Transaction{Currency: "IDR"}},
+		{name: "long amount", m: func(*Merchant) {}, tx: Transaction{Currency: "360", Amount: "10000000000.00"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := testMerchant
+			tc.m(&m)
+			if _, err := NewQRIS(m, tc.tx); !errors.Is(err, ErrInvalidPayload) {
+				t.Errorf("NewQRIS: got %v, want ErrInvalidPayload", err)
+			}
+		})
+	}
+}
+
+func TestReference(t *testing.T) {
+	for _, s := range []string{testPaymentID, "00000000-0000-0000-0000-000000000000", "ffffffff-ffff-ffff-ffff-ffffffffffff"} {
+		id := uuid.MustParse(s)
+		ref := EncodeReference(id)
+		if len(ref) != referenceLength {
+			t.Errorf("EncodeReference(%s) = %q, want %d characters", s, ref, referenceLength)
+		}
+		got, err := DecodeReference(ref)
+		if err != nil || got != id {
+			t.Errorf("DecodeReference(%q) = %v, %v; want %s", ref, got, err, s)
+		}
+	}
+	for _, ref := range []string{"", "0T17G7INGUVFBBBQWSSCNR97", "0T17G7INGUVFBBBQWSSCNR97!", "ZZZZZZZZZZZZZZZZZZZZZZZZZ"} {
+		if _, err := DecodeReference(ref); !errors.Is(err, ErrInvalidPayload) {
+			t.Errorf("DecodeReference(%q): got %v, want ErrInvalidPayload", ref, err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// QuietZone is the light border, in modules, scanners need around a symbol.
+const QuietZone = 4
+
+// PNG renders the symbol with each module scale pixels wide, surrounded by the quiet zone.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+	width := (c.Size + 2*QuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, width, width), color.Palette{color.White, color.Black})
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Dark(x, y) {
+				continue
+			}
+			px, py := (x+QuietZone)*scale, (y+QuietZone)*scale
+			for dy := 0; dy < scale; dy++ {
+				row := img.Pix[(py+dy)*img.Stride+px : (py+dy)*img.Stride+px+scale]
+				for i := range row {
+					row[i] = 1
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,138 @@
+package qr
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Merchant holds the merchant identity printed into every QRIS payload.
+type Merchant struct {
+	Name         string
+	City         string
+	PostalCode   string
+	CountryCode  string
+	CategoryCode string
+	// AcquirerGUID, PAN and ID describe the acquirer account (tag 26); it is omitted when AcquirerGUID is empty
+	AcquirerGUID string
+	PAN          string
+	ID           string
+	// NMID is the national merchant ID registered with QRIS and Criteria its business size (UMI, UKE, UME, UBE, URE)
+	NMID     string
+	Criteria string
+}
+
+// Transaction holds the per-payment fields of a dynamic payload. An empty Amount produces a
+// static payload where the payer enters the amount.
+type Transaction struct {
+	Amount         string
+	Currency       string
+	BillNumber     string
+	ReferenceLabel string
+	TerminalLabel  string
+}
+
+// EMVCo field limits that are tighter than the generic 99 characters.
+const (
+	maxNameLength   = 25
+	maxCityLength   = 15
+	maxAmountLength = 13
+	maxLabelLength  = 25
+)
+
+// NewQRIS builds a QRIS merchant-presented payload.
+func NewQRIS(m Merchant, t Transaction) (*Payload, error) {
+	if m.Name == "" || m.City == "" {
+		return nil, fmt.Errorf("%w: merchant name and city are required", ErrInvalidPayload)
+	}
+	if len(m.CategoryCode) != 4 || !isDigits(m.CategoryCode) {
+		return nil, fmt.Errorf("%w: merchant category code must be 4 digits", ErrInvalidPayload)
+	}
+	if len(t.Currency) != 3 || !isDigits(t.Currency) {
+		return nil, fmt.Errorf("%w: currency must be an ISO 4217 numeric code", ErrInvalidPayload)
+	}
+	for name, v := range map[string]struct {
+		value string
+		max   int
+	}{
+		"merchant name":   {m.Name, maxNameLength},
+		"merchant city":   {m.City, maxCityLength},
+		"amount":          {t.Amount, maxAmountLength},
+		"bill number":     {t.BillNumber, maxLabelLength},
+		"reference label": {t.ReferenceLabel, maxLabelLength},
+		"terminal label":  {t.TerminalLabel, maxLabelLength},
+	} {
+		if utf8.RuneCountInString(v.value) > v.max {
+			return nil, fmt.Errorf("%w: %s exceeds %d characters", ErrInvalidPayload, name, v.max)
+		}
+	}
+
+	method := InitiationStatic
+	if t.Amount != "" {
+		method = InitiationDynamic
+	}
+	country := m.CountryCode
+	if country == "" {
+		country = "ID"
+	}
+
+	p := &Payload{Fields: []Field{
+		{Tag: TagPayloadFormat, Value: PayloadFormatVersion},
+		{Tag: TagInitiationMethod, Value: method},
+	}}
+	if m.AcquirerGUID != "" {
+		p.Fields = append(p.Fields, Field{Tag: "26", Children: nonEmpty(
+			Field{Tag: TagAccountGUID, Value: m.AcquirerGUID},
+			Field{Tag: TagAccountPAN, Value: m.PAN},
+			Field{Tag: TagAccountID, Value: m.ID},
+			Field{Tag: TagAccountCriteria, Value: m.Criteria},
+		)})
+	}
+	if m.NMID != "" {
+		p.Fields = append(p.Fields, Field{Tag: TagQRISAccount, Children: nonEmpty(
+			Field{Tag: TagAccountGUID, Value: QRISDomain},
+			Field{Tag: TagAccountID, Value: m.NMID},
+			Field{Tag: TagAccountCriteria, Value: m.Criteria},
+		)})
+	}
+	p.Fields = append(p.Fields, nonEmpty(
+		Field{Tag: TagMerchantCategory, Value: m.CategoryCode},
+		Field{Tag: TagCurrency, Value: t.Currency},
+		Field{Tag: TagAmount, Value: t.Amount},
+		Field{Tag: TagCountryCode, Value: country},
+		Field{Tag: TagMerchantName, Value: m.Name},
+		Field{Tag: TagMerchantCity, Value: m.City},
+		Field{Tag: TagPostalCode, Value: m.PostalCode},
+	)...)
+	if additional := nonEmpty(
+		Field{Tag: TagBillNumber, Value: t.BillNumber},
+		Field{Tag: TagReferenceLabel, Value: t.ReferenceLabel},
+		Field{Tag: TagTerminalLabel, Value: t.TerminalLabel},
+	); len(additional) > 0 {
+		p.Fields = append(p.Fields, Field{Tag: TagAdditionalData, Children: additional})
+	}
+	return p, nil
+}
+
+func nonEmpty(fields ...Field) []Field {
+	out := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Value != "" || f.Children != nil {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// numericCurrencies maps the ISO 4217 alphabetic codes this API accepts most often to the numeric
+// codes EMVCo tag 53 requires.
+var numericCurrencies = map[string]string{
+	"IDR": "360", "USD": "840", "EUR": "978", "SGD": "702", "MYR": "458",
+	"THB": "764", "PHP": "608", "VND": "704", "JPY": "392", "CNY": "156",
+	"AUD": "036", "GBP": "826", "HKD": "344", "KRW": "410", "INR": "356",
+}
+
+// NumericCurrency returns the ISO 4217 numeric code for an alphabetic currency code.
+func NumericCurrency(alpha string) (string, bool) {
+	code, ok := numericCurrencies[alpha]
+	return code, ok
+}
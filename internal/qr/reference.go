@@ -0,0 +1,30 @@
+package qr
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// referenceLength is the width of a base36 encoded 128-bit ID; it fits the 25 character
+// reference label (tag 62 05) where a hyphenated UUID would not.
+const referenceLength = 25
+
+// EncodeReference renders id as a fixed width upper-case base36 string.
+func EncodeReference(id uuid.UUID) string {
+	s := strings.ToUpper(new(big.Int).SetBytes(id[:]).Text(36))
+	return strings.Repeat("0", referenceLength-len(s)) + s
+}
+
+// DecodeReference is the inverse of EncodeReference.
+func DecodeReference(s string) (uuid.UUID, error) {
+	n, ok := new(big.Int).SetString(strings.ToLower(s), 36)
+	if len(s) != referenceLength || !ok || n.BitLen() > 128 {
+		return uuid.Nil, fmt.Errorf("%w: malformed reference %q", ErrInvalidPayload, s)
+	}
+	var id uuid.UUID
+	n.FillBytes(id[:])
+	return id, nil
+}
@@ -0,0 +1,105 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidPayload = errors.New("invalid emvco payload")
+
+// Field is one EMVCo data object: a two digit tag, a two digit length and a value. Template
+// fields (merchant accounts, additional data) hold their nested objects in Children.
+type Field struct {
+	Tag      string
+	Value    string
+	Children []Field
+}
+
+// maxValueLength is the largest length the two digit length field can express.
+const maxValueLength = 99
+
+func encodeFields(b *strings.Builder, fields []Field) error {
+	for _, f := range fields {
+		value := f.Value
+		if f.Children != nil {
+			var nested strings.Builder
+			if err := encodeFields(&nested, f.Children); err != nil {
+				return fmt.Errorf("tag %s: %w", f.Tag, err)
+			}
+			value = nested.String()
+		}
+		if len(f.Tag) != 2 || !isDigits(f.Tag) {
+			return fmt.Errorf("%w: tag %q must be two digits", ErrInvalidPayload, f.Tag)
+		}
+		// Lengths count characters, not bytes, so names in non-Latin scripts fit the same limit
+		n := utf8.RuneCountInString(value)
+		if n == 0 || n > maxValueLength {
+			return fmt.Errorf("%w: tag %s must be 1 to %d characters", ErrInvalidPayload, f.Tag, maxValueLength)
+		}
+		fmt.Fprintf(b, "%s%02d%s", f.Tag, n, value)
+	}
+	return nil
+}
+
+// parseFields splits s into data objects; tags that are templates are parsed recursively.
+func parseFields(s string, isTemplate func(tag string) bool) ([]Field, error) {
+	var fields []Field
+	for len(s) > 0 {
+		if len(s) < 4 || !isDigits(s[:4]) {
+			return nil, fmt.Errorf("%w: truncated data object %q", ErrInvalidPayload, s)
+		}
+		tag := s[:2]
+		n, _ := strconv.Atoi(s[2:4])
+		rest := s[4:]
+		end := runeOffset(rest, n)
+		if n == 0 || end < 0 {
+			return nil, fmt.Errorf("%w: tag %s length %d exceeds payload", ErrInvalidPayload, tag, n)
+		}
+
+		f := Field{Tag: tag, Value: rest[:end]}
+		if isTemplate != nil && isTemplate(tag) {
+			children, err := parseFields(f.Value, nil)
+			if err != nil {
+				return nil, fmt.Errorf("tag %s: %w", tag, err)
+			}
+			f.Children = children
+		}
+		fields = append(fields, f)
+		s = rest[end:]
+	}
+	return fields, nil
+}
+
+// runeOffset returns the byte offset after n characters of s, or -1 if s is shorter.
+func runeOffset(s string, n int) int {
+	offset := 0
+	for i := 0; i < n; i++ {
+		if offset >= len(s) {
+			return -1
+		}
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func findField(fields []Field, tag string) (Field, bool) {
+	for _, f := range fields {
+		if f.Tag == tag {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
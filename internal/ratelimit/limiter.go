@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Window, in bursts of up to Requests.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseLimit reads a limit such as "600/1m". An empty spec is no limit.
+func ParseLimit(spec string) (*Limit, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	count, window, ok := strings.Cut(spec, "/")
+	if !ok {
+		return nil, fmt.Errorf("rate limit %q must look like 600/1m", spec)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || requests <= 0 {
+		return nil, fmt.Errorf("rate limit %q must allow a positive number of requests", spec)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || d <= 0 {
+		return nil, fmt.Errorf("rate limit %q has an invalid window", spec)
+	}
+	return &Limit{Requests: requests, Window: d}, nil
+}
+
+// ParseClassLimits reads per route class limits such as "write=300/1m,delete=60/1m".
+func ParseClassLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, raw := range strings.Split(spec, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		class, limitSpec, ok := strings.Cut(raw, "=")
+		if !ok || strings.TrimSpace(class) == "" {
+			return nil, fmt.Errorf("route class limit %q must look like write=300/1m", raw)
+		}
+		limit, err := ParseLimit(limitSpec)
+		if err != nil {
+			return nil, err
+		}
+		if limit == nil {
+			return nil, fmt.Errorf("route class limit %q has no limit", raw)
+		}
+		limits[strings.TrimSpace(class)] = *limit
+	}
+	return limits, nil
+}
+
+// rate is the refill speed in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+// Result is the state of a bucket after a request took from it.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// ResultOf describes a bucket of limit holding tokens after a take, for stores kept
+// outside this package.
+func ResultOf(limit Limit, allowed bool, tokens float64) Result {
+	return limit.result(allowed, tokens)
+}
+
+// result describes a bucket holding tokens after a take.
+func (l Limit) result(allowed bool, tokens float64) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     l.Requests,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(l.Requests) - tokens) / l.rate()),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / l.rate())
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Max(s, 0) * float64(time.Second))
+}
+
+// Store keeps token buckets. A bucket starts full, refills continuously at the limit's
+// rate and every request takes one token when there is one.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Refund puts back a token taken for a request that another bucket then denied.
+	Refund(ctx context.Context, key string, limit Limit) error
+	// Sweep drops buckets untouched for idle; they would have refilled completely.
+	Sweep(ctx context.Context, idle time.Duration) error
+}
+
+// Limiter applies the configured limits. Nil limits are not enforced.
+type Limiter struct {
+	Store   Store
+	PerKey  *Limit
+	PerIP   *Limit
+	Classes map[string]Limit
+}
+
+// MaxWindow is the longest configured window, after which idle buckets can be swept.
+func (l *Limiter) MaxWindow() time.Duration {
+	var longest time.Duration
+	for _, limit := range []*Limit{l.PerKey, l.PerIP} {
+		if limit != nil && limit.Window > longest {
+			longest = limit.Window
+		}
+	}
+	for _, limit := range l.Classes {
+		if limit.Window > longest {
+			longest = limit.Window
+		}
+	}
+	return longest
+}
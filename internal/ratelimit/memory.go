@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps buckets in process memory; with several replicas every replica
+// enforces the limits on its own.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updatedAt: now}
+		s.buckets[key] = b
+	}
+	elapsed := math.Max(now.Sub(b.updatedAt).Seconds(), 0)
+	b.tokens = math.Min(float64(limit.Requests), b.tokens+elapsed*limit.rate())
+	b.updatedAt = now
+	if b.tokens < 1 {
+		return limit.result(false, b.tokens), nil
+	}
+	b.tokens--
+	return limit.result(true, b.tokens), nil
+}
+
+func (s *MemoryStore) Refund(_ context.Context, key string, limit Limit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.buckets[key]; ok {
+		b.tokens = math.Min(float64(limit.Requests), b.tokens+1)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Sweep(_ context.Context, idle time.Duration) error {
+	cutoff := s.now().Add(-idle)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, b := range s.buckets {
+		if b.updatedAt.Before(cutoff) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type apiKeyRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+// APIKeyRepository stores API keys. Keys are resolved before the caller's merchant is
+// known, so FetchByPrefix and ModifyLastUsed are not tenant scoped; listing, issuing and
+// revoking keys only reach the keys of the merchant of ctx.
+type APIKeyRepository interface {
+	FetchByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+	FetchWithQueryParams(ctx context.Context, params request.APIKeyListQueryParams) ([]entity.APIKey, error)
+	Store(ctx context.Context, key *entity.APIKey) error
+	ModifyRevoked(ctx context.Context, id uuid.UUID) (*entity.APIKey, error)
+	ModifyLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+func NewAPIKeyRepo(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepo{DB: db, scope: tenantScope{db: db}}
+}
+
+const apiKeyColumns = "id, merchant_id, name, type, prefix, scopes, salt, key_hash, created_at, last_used_at, expires_at, revoked_at"
+
+func scanAPIKey(row rowScanner, k *entity.APIKey) error {
+	return row.Scan(&k.ID, &k.MerchantID, &k.Name, &k.Type, &k.Prefix, pq.Array(&k.Scopes), &k.Salt, &k.Hash, &k.CreatedAt, &k.LastUsedAt, &k.ExpiresAt, &k.RevokedAt)
+}
+
+// foreignKeyViolation is the Postgres error code for foreign key violations.
+const foreignKeyViolation = "23503"
+
+func (r *apiKeyRepo) FetchByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	var k entity.APIKey
+	row := r.DB.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = $1", prefix)
+	if err := scanAPIKey(row, &k); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *apiKeyRepo) FetchWithQueryParams(ctx context.Context, params request.APIKeyListQueryParams) ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE merchant_id = $1"
+		args := []interface{}{merchantID}
+		argIndex := 2
+
+		if params.Revoked != nil {
+			if *params.Revoked {
+				query += " AND revoked_at IS NOT NULL"
+			} else {
+				query += " AND revoked_at IS NULL"
+			}
+		}
+
+		query += " ORDER BY created_at DESC"
+		if params.Page > 0 && params.PerPage > 0 {
+			offset := (params.Page - 1) * params.PerPage
+			query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+			args = append(args, params.PerPage, offset)
+		}
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var k entity.APIKey
+			if err := scanAPIKey(rows, &k); err != nil {
+				return err
+			}
+			keys = append(keys, k)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Store inserts the key for the merchant of ctx; k.MerchantID is ignored.
+func (r *apiKeyRepo) Store(ctx context.Context, k *entity.APIKey) error {
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO api_keys (merchant_id, name, type, prefix, scopes, salt, key_hash, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING `+apiKeyColumns,
+			merchantID, k.Name, k.Type, k.Prefix, pq.Array(k.Scopes), k.Salt, k.Hash, k.ExpiresAt,
+		)
+		return scanAPIKey(row, k)
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return entity.ErrMerchantNotFound
+	}
+	return err
+}
+
+// ModifyRevoked revokes a key of the merchant of ctx. It returns sql.ErrNoRows for an
+// unknown key, or one of another merchant, and entity.ErrAPIKeyRevoked when the key was
+// revoked before.
+func (r *apiKeyRepo) ModifyRevoked(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
+	var k entity.APIKey
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE api_keys SET revoked_at = NOW()
+			WHERE id = $1 AND merchant_id = $2 AND revoked_at IS NULL
+			RETURNING `+apiKeyColumns, id, merchantID)
+		err := scanAPIKey(row, &k)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		var exists bool
+		if err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM api_keys WHERE id = $1 AND merchant_id = $2)", id, merchantID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return entity.ErrAPIKeyRevoked
+		}
+		return sql.ErrNoRows
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *apiKeyRepo) ModifyLastUsed(ctx context.Context, id uuid.UUID) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE api_keys SET last_used_at = NOW() WHERE id = $1", id)
+	return err
+}
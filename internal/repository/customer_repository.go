@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/fieldcrypt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type customerRepo struct {
+	DB     *sql.DB
+	scope  tenantScope
+	fields *fieldcrypt.Encryptor
+}
+
+type CustomerRepository interface {
+	FetchWithQueryParams(ctx context.Context, params request.CustomerListQueryParams) ([]entity.Customer, error)
+	FetchByID(ctx context.Context, id uuid.UUID) (*entity.Customer, error)
+	LockByID(ctx context.Context, tx *sql.Tx, id uuid.UUID) error
+	Store(ctx context.Context, customer *entity.Customer) error
+	ModifyByID(ctx context.Context, id uuid.UUID, req *request.CustomerRequest) (*entity.Customer, error)
+	Remove(ctx context.Context, id uuid.UUID) error
+	ReEncrypt(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error)
+}
+
+// NewCustomerRepo encrypts name, email and phone with fields, or stores them in plaintext
+// when fields is nil. Email lookups go through the blind index in email_bidx.
+func NewCustomerRepo(db *sql.DB, rowLevelSecurity bool, fields *fieldcrypt.Encryptor) CustomerRepository {
+	return &customerRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}, fields: fields}
+}
+
+const customerColumns = "id, merchant_id, name, email, phone, external_reference, created_at, updated_at"
+
+// Field names of the encrypted customer columns.
+const (
+	customerNameField  = "customers.name"
+	customerEmailField = "customers.email"
+	customerPhoneField = "customers.phone"
+)
+
+func scanCustomer(row rowScanner, c *entity.Customer) error {
+	return row.Scan(&c.ID, &c.MerchantID, &c.Name, &c.Email, &c.Phone, &c.ExternalReference, &c.CreatedAt, &c.UpdatedAt)
+}
+
+// scan reads a customer row and decrypts its encrypted fields.
+func (r *customerRepo) scan(row rowScanner, c *entity.Customer) error {
+	if err := scanCustomer(row, c); err != nil {
+		return err
+	}
+	return r.open(c)
+}
+
+// open decrypts the encrypted fields of c in place.
+func (r *customerRepo) open(c *entity.Customer) error {
+	var err error
+	if c.Name, err = r.fields.Decrypt(customerNameField, c.Name); err != nil {
+		return err
+	}
+	if c.Email, err = r.fields.Decrypt(customerEmailField, c.Email); err != nil {
+		return err
+	}
+	c.Phone, err = r.fields.Decrypt(customerPhoneField, c.Phone)
+	return err
+}
+
+// sealedCustomer holds the stored form of the encrypted customer columns.
+type sealedCustomer struct {
+	name, email, phone, emailIndex string
+}
+
+func (r *customerRepo) seal(name, email, phone string) (sealedCustomer, error) {
+	var s sealedCustomer
+	var err error
+	if s.name, err = r.fields.Encrypt(customerNameField, name); err != nil {
+		return s, err
+	}
+	if s.email, err = r.fields.Encrypt(customerEmailField, email); err != nil {
+		return s, err
+	}
+	if s.phone, err = r.fields.Encrypt(customerPhoneField, phone); err != nil {
+		return s, err
+	}
+	s.emailIndex = r.fields.BlindIndex(customerEmailField, email)
+	return s, nil
+}
+
+// uniqueViolation is the Postgres error code for unique constraint violations.
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+func (r *customerRepo) FetchWithQueryParams(ctx context.Context, params request.CustomerListQueryParams) ([]entity.Customer, error) {
+	var customers []entity.Customer
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := "SELECT " + customerColumns + " FROM customers WHERE deleted_at IS NULL AND merchant_id = $1"
+		args := []interface{}{merchantID}
+		argIndex := 2
+
+		// Email; rows stored before encryption have no blind index yet and match in plaintext
+		if index := r.fields.BlindIndex(customerEmailField, params.Email); index != "" {
+			query += fmt.Sprintf(" AND (email_bidx = $%d OR email = $%d)", argIndex, argIndex+1)
+			args = append(args, index, params.Email)
+			argIndex += 2
+		} else if params.Email != "" {
+			query += fmt.Sprintf(" AND email = $%d", argIndex)
+			args = append(args, params.Email)
+			argIndex++
+		}
+		if params.ExternalReference != "" {
+			query += fmt.Sprintf(" AND external_reference = $%d", argIndex)
+			args = append(args, params.ExternalReference)
+			argIndex++
+		}
+
+		query += " ORDER BY created_at DESC"
+		if params.Page > 0 && params.PerPage > 0 {
+			offset := (params.Page - 1) * params.PerPage
+			query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+			args = append(args, params.PerPage, offset)
+		}
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c entity.Customer
+			if err := r.scan(rows, &c); err != nil {
+				return err
+			}
+			customers = append(customers, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+func (r *customerRepo) FetchByID(ctx context.Context, id uuid.UUID) (*entity.Customer, error) {
+	var c entity.Customer
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+customerColumns+" FROM customers WHERE id = $1 AND merchant_id = $2 AND deleted_at IS NULL", id, merchantID)
+		return r.scan(row, &c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// LockByID locks an active customer of the current merchant for the rest of tx so it
+// cannot be deleted while a payment referencing it is being stored.
+func (r *customerRepo) LockByID(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		var locked uuid.UUID
+		err := q.QueryRowContext(ctx, "SELECT id FROM customers WHERE id = $1 AND merchant_id = $2 AND deleted_at IS NULL FOR SHARE", id, merchantID).Scan(&locked)
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrCustomerNotFound
+		}
+		return err
+	})
+}
+
+// Store inserts the customer for the merchant of ctx; customer.MerchantID is ignored.
+func (r *customerRepo) Store(ctx context.Context, customer *entity.Customer) error {
+	sealed, err := r.seal(customer.Name, customer.Email, customer.Phone)
+	if err != nil {
+		return err
+	}
+	err = r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx,
+			"INSERT INTO customers (merchant_id, name, email, phone, email_bidx, external_reference) VALUES ($1, $2, $3, $4, $5, $6) RETURNING "+customerColumns,
+			merchantID, sealed.name, sealed.email, sealed.phone, sealed.emailIndex, customer.ExternalReference,
+		)
+		return r.scan(row, customer)
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.ErrCustomerConflict
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *customerRepo) ModifyByID(ctx context.Context, id uuid.UUID, req *request.CustomerRequest) (*entity.Customer, error) {
+	query := `
+		UPDATE customers
+		SET name = $1, email = $2, phone = $3, email_bidx = $4, external_reference = $5, updated_at = NOW()
+		WHERE id = $6 AND merchant_id = $7 AND deleted_at IS NULL
+		RETURNING ` + customerColumns
+
+	sealed, err := r.seal(req.Name, req.Email, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	var updated entity.Customer
+	err = r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, query, sealed.name, sealed.email, sealed.phone, sealed.emailIndex, req.ExternalReference, id, merchantID)
+		return r.scan(row, &updated)
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, entity.ErrCustomerConflict
+		}
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Remove soft-deletes a customer. Customers with payments still in progress cannot be removed;
+// settled payments keep pointing at the deleted customer so history stays intact.
+func (r *customerRepo) Remove(ctx context.Context, id uuid.UUID) error {
+	var affected int64
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		res, err := q.ExecContext(ctx, `
+			UPDATE customers
+			SET deleted_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND merchant_id = $2 AND deleted_at IS NULL
+			  AND NOT EXISTS (
+			    SELECT 1 FROM payments
+			    WHERE customer_id = $1 AND merchant_id = $2 AND deleted_at IS NULL AND status = ANY($3)
+			  )`,
+			id, merchantID, pq.Array([]string{entity.PaymentStatusPending, entity.PaymentStatusAuthorized, entity.PaymentStatusDisputed}),
+		)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	// Nothing updated: tell a missing customer apart from one with payments in progress
+	if _, err := r.FetchByID(ctx, id); err != nil {
+		return err
+	}
+	return entity.ErrCustomerHasOpenPayments
+}
+
+// ReEncrypt rewrites up to limit customers of the merchant of ctx, with ids after after,
+// whose name, email or phone is not stored under the active encryption key or whose email
+// blind index is missing. Deleted customers are included. It returns the last id it looked
+// at, uuid.Nil once none are left, and how many customers it rewrote.
+func (r *customerRepo) ReEncrypt(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	defer tx.Rollback()
+
+	last, updated := uuid.Nil, 0
+	err = r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx, "SELECT id, name, email, phone, email_bidx FROM customers WHERE merchant_id = $1 AND id > $2 ORDER BY id LIMIT $3 FOR UPDATE", merchantID, after, limit)
+		if err != nil {
+			return err
+		}
+		type stored struct {
+			id                             uuid.UUID
+			name, email, phone, emailIndex string
+		}
+		var batch []stored
+		for rows.Next() {
+			var s stored
+			if err := rows.Scan(&s.id, &s.name, &s.email, &s.phone, &s.emailIndex); err != nil {
+				rows.Close()
+				return err
+			}
+			batch = append(batch, s)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, s := range batch {
+			last = s.id
+			c := entity.Customer{Name: s.name, Email: s.email, Phone: s.phone}
+			if err := r.open(&c); err != nil {
+				return fmt.Errorf("customer %s: %w", s.id, err)
+			}
+			if r.fields.Current(s.name) && r.fields.Current(s.email) && r.fields.Current(s.phone) &&
+				s.emailIndex == r.fields.BlindIndex(customerEmailField, c.Email) {
+				continue
+			}
+
+			sealed, err := r.seal(c.Name, c.Email, c.Phone)
+			if err != nil {
+				return err
+			}
+			if _, err := q.ExecContext(ctx, "UPDATE customers SET name = $1, email = $2, phone = $3, email_bidx = $4 WHERE id = $5 AND merchant_id = $6",
+				sealed.name, sealed.email, sealed.phone, sealed.emailIndex, s.id, merchantID); err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, 0, err
+	}
+	return last, updated, nil
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type disputeRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type DisputeRepository interface {
+	FetchWithQueryParams(ctx context.Context, params request.DisputeListQueryParams) ([]entity.Dispute, error)
+	FetchByID(ctx context.Context, id uuid.UUID) (*entity.Dispute, error)
+	FetchByIDForUpdate(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*entity.Dispute, error)
+	Store(ctx context.Context, tx *sql.Tx, dispute *entity.Dispute) error
+	ModifyState(ctx context.Context, tx *sql.Tx, dispute *entity.Dispute) error
+	ModifyDeadlineFlagged(ctx context.Context, dueBefore time.Time) ([]entity.Dispute, error)
+	FetchEvidence(ctx context.Context, disputeID uuid.UUID) ([]entity.DisputeEvidence, error)
+	FetchEvidenceByID(ctx context.Context, disputeID, evidenceID uuid.UUID) (*entity.DisputeEvidence, error)
+	CountEvidence(ctx context.Context, tx *sql.Tx, disputeID uuid.UUID) (int, error)
+	StoreEvidence(ctx context.Context, tx *sql.Tx, evidence *entity.DisputeEvidence) error
+}
+
+func NewDisputeRepo(db *sql.DB, rowLevelSecurity bool) DisputeRepository {
+	return &disputeRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const disputeColumns = "id, merchant_id, payment_id, status, reason_code, reason, amount, currency, payment_status_before, provider_case_id, evidence_due_at, deadline_flagged_at, submitted_at, resolved_at, created_at, updated_at"
+
+func scanDispute(row rowScanner, d *entity.Dispute) error {
+	return row.Scan(
+		&d.ID,
+		&d.MerchantID,
+		&d.PaymentID,
+		&d.Status,
+		&d.ReasonCode,
+		&d.Reason,
+		&d.Amount,
+		&d.Currency,
+		&d.PaymentStatusBefore,
+		&d.ProviderCaseID,
+		&d.EvidenceDueAt,
+		&d.DeadlineFlaggedAt,
+		&d.SubmittedAt,
+		&d.ResolvedAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+}
+
+// disputeEvidenceColumns leaves out content, which is only read for downloads.
+const disputeEvidenceColumns = "id, dispute_id, type, description, file_name, content_type, size, created_at"
+
+func scanDisputeEvidence(row rowScanner, e *entity.DisputeEvidence) error {
+	return row.Scan(&e.ID, &e.DisputeID, &e.Type, &e.Description, &e.FileName, &e.ContentType, &e.Size, &e.CreatedAt)
+}
+
+func queryDisputes(ctx context.Context, q querier, query string, args ...interface{}) ([]entity.Dispute, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disputes []entity.Dispute
+	for rows.Next() {
+		var d entity.Dispute
+		if err := scanDispute(rows, &d); err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, d)
+	}
+	return disputes, rows.Err()
+}
+
+func (r *disputeRepo) FetchWithQueryParams(ctx context.Context, params request.DisputeListQueryParams) ([]entity.Dispute, error) {
+	var disputes []entity.Dispute
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := "SELECT " + disputeColumns + " FROM disputes WHERE merchant_id = $1"
+		args := []interface{}{merchantID}
+		argIndex := 2
+
+		if params.PaymentID != nil {
+			query += fmt.Sprintf(" AND payment_id = $%d", argIndex)
+			args = append(args, *params.PaymentID)
+			argIndex++
+		}
+		if params.Status != "" {
+			query += fmt.Sprintf(" AND status = $%d", argIndex)
+			args = append(args, params.Status)
+			argIndex++
+		}
+		if params.DeadlineFlagged != nil {
+			if *params.DeadlineFlagged {
+				query += " AND deadline_flagged_at IS NOT NULL"
+			} else {
+				query += " AND deadline_flagged_at IS NULL"
+			}
+		}
+
+		query += " ORDER BY evidence_due_at, created_at"
+		if params.Page > 0 && params.PerPage > 0 {
+			offset := (params.Page - 1) * params.PerPage
+			query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+			args = append(args, params.PerPage, offset)
+		}
+
+		var err error
+		disputes, err = queryDisputes(ctx, q, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
+
+func (r *disputeRepo) FetchByID(ctx context.Context, id uuid.UUID) (*entity.Dispute, error) {
+	var d entity.Dispute
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+disputeColumns+" FROM disputes WHERE id = $1 AND merchant_id = $2", id, merchantID)
+		return scanDispute(row, &d)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *disputeRepo) FetchByIDForUpdate(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*entity.Dispute, error) {
+	var d entity.Dispute
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+disputeColumns+" FROM disputes WHERE id = $1 AND merchant_id = $2 FOR UPDATE", id, merchantID)
+		return scanDispute(row, &d)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *disputeRepo) Store(ctx context.Context, tx *sql.Tx, d *entity.Dispute) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO disputes (merchant_id, payment_id, status, reason_code, reason, amount, currency, payment_status_before, provider_case_id, evidence_due_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING `+disputeColumns,
+			merchantID, d.PaymentID, d.Status, d.ReasonCode, d.Reason, d.Amount, d.Currency, d.PaymentStatusBefore, d.ProviderCaseID, d.EvidenceDueAt.UTC(),
+		)
+		return scanDispute(row, d)
+	})
+}
+
+func (r *disputeRepo) ModifyState(ctx context.Context, tx *sql.Tx, d *entity.Dispute) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE disputes
+			SET status = $1, evidence_due_at = $2, deadline_flagged_at = $3, submitted_at = $4, resolved_at = $5, updated_at = NOW()
+			WHERE id = $6 AND merchant_id = $7
+			RETURNING `+disputeColumns,
+			d.Status, d.EvidenceDueAt.UTC(), d.DeadlineFlaggedAt, d.SubmittedAt, d.ResolvedAt, d.ID, merchantID,
+		)
+		return scanDispute(row, d)
+	})
+}
+
+// ModifyDeadlineFlagged flags the merchant's disputes still waiting for evidence that are
+// due before dueBefore and returns them. Each dispute is flagged once per deadline.
+func (r *disputeRepo) ModifyDeadlineFlagged(ctx context.Context, dueBefore time.Time) ([]entity.Dispute, error) {
+	var disputes []entity.Dispute
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		var err error
+		disputes, err = queryDisputes(ctx, q, `
+			UPDATE disputes
+			SET deadline_flagged_at = NOW(), updated_at = NOW()
+			WHERE merchant_id = $1 AND deadline_flagged_at IS NULL AND status IN ($2, $3) AND evidence_due_at <= $4
+			RETURNING `+disputeColumns,
+			merchantID, entity.DisputeStatusOpened, entity.DisputeStatusEvidenceRequired, dueBefore.UTC(),
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
+
+func (r *disputeRepo) FetchEvidence(ctx context.Context, disputeID uuid.UUID) ([]entity.DisputeEvidence, error) {
+	evidence := []entity.DisputeEvidence{}
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx,
+			"SELECT "+disputeEvidenceColumns+" FROM dispute_evidence WHERE dispute_id = $1 AND merchant_id = $2 ORDER BY created_at, id",
+			disputeID, merchantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var e entity.DisputeEvidence
+			if err := scanDisputeEvidence(rows, &e); err != nil {
+				return err
+			}
+			evidence = append(evidence, e)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+func (r *disputeRepo) FetchEvidenceByID(ctx context.Context, disputeID, evidenceID uuid.UUID) (*entity.DisputeEvidence, error) {
+	var e entity.DisputeEvidence
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		return q.QueryRowContext(ctx,
+			"SELECT "+disputeEvidenceColumns+", content FROM dispute_evidence WHERE id = $1 AND dispute_id = $2 AND merchant_id = $3",
+			evidenceID, disputeID, merchantID,
+		).Scan(&e.ID, &e.DisputeID, &e.Type, &e.Description, &e.FileName, &e.ContentType, &e.Size, &e.CreatedAt, &e.Content)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *disputeRepo) CountEvidence(ctx context.Context, tx *sql.Tx, disputeID uuid.UUID) (int, error) {
+	var count int
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		return q.QueryRowContext(ctx, "SELECT COUNT(*) FROM dispute_evidence WHERE dispute_id = $1 AND merchant_id = $2", disputeID, merchantID).Scan(&count)
+	})
+	return count, err
+}
+
+func (r *disputeRepo) StoreEvidence(ctx context.Context, tx *sql.Tx, e *entity.DisputeEvidence) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO dispute_evidence (merchant_id, dispute_id, type, description, file_name, content_type, size, content)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING `+disputeEvidenceColumns,
+			merchantID, e.DisputeID, e.Type, e.Description, e.FileName, e.ContentType, e.Size, e.Content,
+		)
+		return scanDisputeEvidence(row, e)
+	})
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type feeRuleRepo struct {
+	DB *sql.DB
+}
+
+// FeeRuleRepository stores the platform's fee rules; they are not tenant scoped, so only operators reach them.
+type FeeRuleRepository interface {
+	FetchActive(ctx context.Context) ([]entity.FeeRule, error)
+	FetchWithQueryParams(ctx context.Context, params request.FeeRuleListQueryParams) ([]entity.FeeRule, error)
+	Store(ctx context.Context, rule *entity.FeeRule) error
+	ModifyByID(ctx context.Context, rule *entity.FeeRule) error
+	Remove(ctx context.Context, id uuid.UUID) error
+}
+
+func NewFeeRuleRepo(db *sql.DB) FeeRuleRepository {
+	return &feeRuleRepo{DB: db}
+}
+
+const feeRuleColumns = "id, merchant_id, name, method, currency, min_amount, max_amount, type, fixed_amount, percentage, tiers, min_fee, max_fee, priority, active, created_at, updated_at"
+
+func scanFeeRule(row rowScanner, rule *entity.FeeRule) error {
+	var tiers []byte
+	err := row.Scan(
+		&rule.ID,
+		&rule.MerchantID,
+		&rule.Name,
+		&rule.Method,
+		&rule.Currency,
+		&rule.MinAmount,
+		&rule.MaxAmount,
+		&rule.Type,
+		&rule.FixedAmount,
+		&rule.Percentage,
+		&tiers,
+		&rule.MinFee,
+		&rule.MaxFee,
+		&rule.Priority,
+		&rule.Active,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	rule.Tiers = nil
+	return json.Unmarshal(tiers, &rule.Tiers)
+}
+
+func marshalFeeTiers(tiers []entity.FeeTier) (string, error) {
+	if tiers == nil {
+		tiers = []entity.FeeTier{}
+	}
+	raw, err := json.Marshal(tiers)
+	return string(raw), err
+}
+
+func (r *feeRuleRepo) queryFeeRules(ctx context.Context, query string, args ...interface{}) ([]entity.FeeRule, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []entity.FeeRule
+	for rows.Next() {
+		var rule entity.FeeRule
+		if err := scanFeeRule(rows, &rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *feeRuleRepo) FetchActive(ctx context.Context) ([]entity.FeeRule, error) {
+	return r.queryFeeRules(ctx, "SELECT "+feeRuleColumns+" FROM fee_rules WHERE active ORDER BY priority, created_at")
+}
+
+func (r *feeRuleRepo) FetchWithQueryParams(ctx context.Context, params request.FeeRuleListQueryParams) ([]entity.FeeRule, error) {
+	query := "SELECT " + feeRuleColumns + " FROM fee_rules WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.MerchantID != nil {
+		query += fmt.Sprintf(" AND merchant_id = $%d", argIndex)
+		args = append(args, *params.MerchantID)
+		argIndex++
+	}
+	if params.Active != nil {
+		query += fmt.Sprintf(" AND active = $%d", argIndex)
+		args = append(args, *params.Active)
+		argIndex++
+	}
+
+	query += " ORDER BY priority, created_at"
+	if params.Page > 0 && params.PerPage > 0 {
+		offset := (params.Page - 1) * params.PerPage
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, params.PerPage, offset)
+	}
+	return r.queryFeeRules(ctx, query, args...)
+}
+
+func (r *feeRuleRepo) Store(ctx context.Context, rule *entity.FeeRule) error {
+	tiers, err := marshalFeeTiers(rule.Tiers)
+	if err != nil {
+		return err
+	}
+	row := r.DB.QueryRowContext(ctx, `
+		INSERT INTO fee_rules (merchant_id, name, method, currency, min_amount, max_amount, type, fixed_amount, percentage, tiers, min_fee, max_fee, priority, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING `+feeRuleColumns,
+		rule.MerchantID, rule.Name, rule.Method, rule.Currency, rule.MinAmount, rule.MaxAmount, rule.Type,
+		rule.FixedAmount, rule.Percentage, tiers, rule.MinFee, rule.MaxFee, rule.Priority, rule.Active,
+	)
+	return scanFeeRule(row, rule)
+}
+
+func (r *feeRuleRepo) ModifyByID(ctx context.Context, rule *entity.FeeRule) error {
+	tiers, err := marshalFeeTiers(rule.Tiers)
+	if err != nil {
+		return err
+	}
+	row := r.DB.QueryRowContext(ctx, `
+		UPDATE fee_rules
+		SET merchant_id = $1, name = $2, method = $3, currency = $4, min_amount = $5, max_amount = $6, type = $7,
+		    fixed_amount = $8, percentage = $9, tiers = $10, min_fee = $11, max_fee = $12, priority = $13, active = $14,
+		    updated_at = NOW()
+		WHERE id = $15
+		RETURNING `+feeRuleColumns,
+		rule.MerchantID, rule.Name, rule.Method, rule.Currency, rule.MinAmount, rule.MaxAmount, rule.Type,
+		rule.FixedAmount, rule.Percentage, tiers, rule.MinFee, rule.MaxFee, rule.Priority, rule.Active, rule.ID,
+	)
+	return scanFeeRule(row, rule)
+}
+
+// Remove deletes a rule; fee lines already charged keep their amounts and lose the reference.
+func (r *feeRuleRepo) Remove(ctx context.Context, id uuid.UUID) error {
+	res, err := r.DB.ExecContext(ctx, "DELETE FROM fee_rules WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
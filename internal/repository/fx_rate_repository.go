@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+)
+
+type fxRateRepo struct {
+	DB *sql.DB
+}
+
+// FXRateRepository stores exchange rates. Rates are shared by all merchants.
+type FXRateRepository interface {
+	FetchWithQueryParams(ctx context.Context, params request.FXRateListQueryParams) ([]entity.FXRate, error)
+	FetchEffective(ctx context.Context, base, quote string, at time.Time) (*entity.FXRate, error)
+	Store(ctx context.Context, rates []entity.FXRate) error
+}
+
+func NewFXRateRepo(db *sql.DB) FXRateRepository {
+	return &fxRateRepo{DB: db}
+}
+
+const fxRateColumns = "id, base_currency, quote_currency, rate, effective_at, source, created_at"
+
+func scanFXRate(row rowScanner, rate *entity.FXRate) error {
+	return row.Scan(&rate.ID, &rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate, &rate.EffectiveAt, &rate.Source, &rate.CreatedAt)
+}
+
+func (r *fxRateRepo) FetchWithQueryParams(ctx context.Context, params request.FXRateListQueryParams) ([]entity.FXRate, error) {
+	query := "SELECT " + fxRateColumns + " FROM fx_rates WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.BaseCurrency != "" {
+		query += fmt.Sprintf(" AND base_currency = $%d", argIndex)
+		args = append(args, params.BaseCurrency)
+		argIndex++
+	}
+	if params.QuoteCurrency != "" {
+		query += fmt.Sprintf(" AND quote_currency = $%d", argIndex)
+		args = append(args, params.QuoteCurrency)
+		argIndex++
+	}
+
+	query += " ORDER BY effective_at DESC, base_currency, quote_currency"
+	if params.Page > 0 && params.PerPage > 0 {
+		offset := (params.Page - 1) * params.PerPage
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, params.PerPage, offset)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []entity.FXRate
+	for rows.Next() {
+		var rate entity.FXRate
+		if err := scanFXRate(rows, &rate); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// FetchEffective returns the latest rate for the pair that took effect at or before at.
+func (r *fxRateRepo) FetchEffective(ctx context.Context, base, quote string, at time.Time) (*entity.FXRate, error) {
+	var rate entity.FXRate
+	row := r.DB.QueryRowContext(ctx,
+		"SELECT "+fxRateColumns+" FROM fx_rates WHERE base_currency = $1 AND quote_currency = $2 AND effective_at <= $3 ORDER BY effective_at DESC LIMIT 1",
+		base, quote, at.UTC(),
+	)
+	if err := scanFXRate(row, &rate); err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// Store upserts the rates in one transaction; loading the same file twice is a no-op.
+func (r *fxRateRepo) Store(ctx context.Context, rates []entity.FXRate) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO fx_rates (base_currency, quote_currency, rate, effective_at, source)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (base_currency, quote_currency, effective_at)
+		DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source
+		RETURNING `+fxRateColumns)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i := range rates {
+		rate := &rates[i]
+		row := stmt.QueryRowContext(ctx, rate.BaseCurrency, rate.QuoteCurrency, rate.Rate, rate.EffectiveAt.UTC(), rate.Source)
+		if err := scanFXRate(row, rate); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type invoiceRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type InvoiceRepository interface {
+	NextNumberSequence(ctx context.Context) (int64, error)
+	FetchWithQueryParams(ctx context.Context, params request.InvoiceListQueryParams) ([]entity.Invoice, error)
+	FetchByID(ctx context.Context, id uuid.UUID) (*entity.Invoice, error)
+	FetchByIDForUpdate(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*entity.Invoice, error)
+	FetchItems(ctx context.Context, invoiceID uuid.UUID) ([]entity.InvoiceItem, error)
+	Store(ctx context.Context, tx *sql.Tx, invoice *entity.Invoice) error
+	ModifyDraft(ctx context.Context, tx *sql.Tx, invoice *entity.Invoice) error
+	ModifyFinalized(ctx context.Context, id uuid.UUID) (*entity.Invoice, error)
+	ModifyVoided(ctx context.Context, id uuid.UUID) (*entity.Invoice, error)
+}
+
+func NewInvoiceRepo(db *sql.DB, rowLevelSecurity bool) InvoiceRepository {
+	return &invoiceRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const invoiceColumns = "id, merchant_id, customer_id, number, currency, status, notes, subtotal, discount_total, tax_total, total, amount_paid, due_date, issued_at, paid_at, voided_at, created_at, updated_at"
+
+func scanInvoice(row rowScanner, i *entity.Invoice) error {
+	return row.Scan(
+		&i.ID,
+		&i.MerchantID,
+		&i.CustomerID,
+		&i.Number,
+		&i.Currency,
+		&i.Status,
+		&i.Notes,
+		&i.Subtotal,
+		&i.DiscountTotal,
+		&i.TaxTotal,
+		&i.Total,
+		&i.AmountPaid,
+		&i.DueDate,
+		&i.IssuedAt,
+		&i.PaidAt,
+		&i.VoidedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+}
+
+const invoiceItemColumns = "id, description, quantity, unit_price, discount, tax_rate, subtotal, tax_amount, total"
+
+func scanInvoiceItem(row rowScanner, i *entity.InvoiceItem) error {
+	return row.Scan(&i.ID, &i.Description, &i.Quantity, &i.UnitPrice, &i.Discount, &i.TaxRate, &i.Subtotal, &i.TaxAmount, &i.Total)
+}
+
+// overdueCondition matches finalized invoices with money outstanding after their due date.
+const overdueCondition = "status IN ('OPEN', 'PARTIALLY_PAID') AND due_date < CURRENT_DATE"
+
+// NextNumberSequence draws the next value for invoice numbers.
+func (r *invoiceRepo) NextNumberSequence(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.DB.QueryRowContext(ctx, "SELECT nextval('invoice_number_seq')").Scan(&seq)
+	return seq, err
+}
+
+func (r *invoiceRepo) FetchWithQueryParams(ctx context.Context, params request.InvoiceListQueryParams) ([]entity.Invoice, error) {
+	var invoices []entity.Invoice
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := "SELECT " + invoiceColumns + " FROM invoices WHERE merchant_id = $1"
+		args := []interface{}{merchantID}
+		argIndex := 2
+
+		if params.CustomerID != nil {
+			query += fmt.Sprintf(" AND customer_id = $%d", argIndex)
+			args = append(args, *params.CustomerID)
+			argIndex++
+		}
+		if params.Status != "" {
+			query += fmt.Sprintf(" AND status = $%d", argIndex)
+			args = append(args, params.Status)
+			argIndex++
+		}
+		if params.Overdue != nil {
+			if *params.Overdue {
+				query += " AND " + overdueCondition
+			} else {
+				query += " AND NOT (" + overdueCondition + ")"
+			}
+		}
+
+		query += " ORDER BY created_at DESC"
+		if params.Page > 0 && params.PerPage > 0 {
+			offset := (params.Page - 1) * params.PerPage
+			query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+			args = append(args, params.PerPage, offset)
+		}
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var i entity.Invoice
+			if err := scanInvoice(rows, &i); err != nil {
+				return err
+			}
+			invoices = append(invoices, i)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *invoiceRepo) FetchByID(ctx context.Context, id uuid.UUID) (*entity.Invoice, error) {
+	var i entity.Invoice
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+invoiceColumns+" FROM invoices WHERE id = $1 AND merchant_id = $2", id, merchantID)
+		return scanInvoice(row, &i)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *invoiceRepo) FetchByIDForUpdate(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*entity.Invoice, error) {
+	var i entity.Invoice
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+invoiceColumns+" FROM invoices WHERE id = $1 AND merchant_id = $2 FOR UPDATE", id, merchantID)
+		return scanInvoice(row, &i)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *invoiceRepo) FetchItems(ctx context.Context, invoiceID uuid.UUID) ([]entity.InvoiceItem, error) {
+	var items []entity.InvoiceItem
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx, "SELECT "+invoiceItemColumns+" FROM invoice_items WHERE invoice_id = $1 AND merchant_id = $2 ORDER BY position", invoiceID, merchantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var i entity.InvoiceItem
+			if err := scanInvoiceItem(rows, &i); err != nil {
+				return err
+			}
+			items = append(items, i)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Store inserts a DRAFT invoice with its items.
+func (r *invoiceRepo) Store(ctx context.Context, tx *sql.Tx, inv *entity.Invoice) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO invoices (merchant_id, customer_id, number, currency, status, notes, subtotal, discount_total, tax_total, total, due_date)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING `+invoiceColumns,
+			merchantID, inv.CustomerID, inv.Number, inv.Currency, entity.InvoiceStatusDraft, inv.Notes,
+			inv.Subtotal, inv.DiscountTotal, inv.TaxTotal, inv.Total, inv.DueDate,
+		)
+		if err := scanInvoice(row, inv); err != nil {
+			return err
+		}
+		return storeInvoiceItems(ctx, q, merchantID, inv.ID, inv.Items)
+	})
+}
+
+// ModifyDraft replaces the details and items of a DRAFT invoice. It returns sql.ErrNoRows
+// when the invoice is missing or no longer a draft.
+func (r *invoiceRepo) ModifyDraft(ctx context.Context, tx *sql.Tx, inv *entity.Invoice) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE invoices
+			SET customer_id = $1, currency = $2, notes = $3, subtotal = $4, discount_total = $5, tax_total = $6, total = $7, due_date = $8, updated_at = NOW()
+			WHERE id = $9 AND merchant_id = $10 AND status = $11
+			RETURNING `+invoiceColumns,
+			inv.CustomerID, inv.Currency, inv.Notes, inv.Subtotal, inv.DiscountTotal, inv.TaxTotal, inv.Total, inv.DueDate,
+			inv.ID, merchantID, entity.InvoiceStatusDraft,
+		)
+		if err := scanInvoice(row, inv); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, "DELETE FROM invoice_items WHERE invoice_id = $1 AND merchant_id = $2", inv.ID, merchantID); err != nil {
+			return err
+		}
+		return storeInvoiceItems(ctx, q, merchantID, inv.ID, inv.Items)
+	})
+}
+
+func storeInvoiceItems(ctx context.Context, q querier, merchantID, invoiceID uuid.UUID, items []entity.InvoiceItem) error {
+	for i := range items {
+		item := &items[i]
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO invoice_items (merchant_id, invoice_id, position, description, quantity, unit_price, discount, tax_rate, subtotal, tax_amount, total)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING `+invoiceItemColumns,
+			merchantID, invoiceID, i, item.Description, item.Quantity, item.UnitPrice, item.Discount, item.TaxRate, item.Subtotal, item.TaxAmount, item.Total,
+		)
+		if err := scanInvoiceItem(row, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ModifyFinalized issues a DRAFT invoice, making it OPEN for payments. It returns
+// sql.ErrNoRows when the invoice is missing or no longer a draft.
+func (r *invoiceRepo) ModifyFinalized(ctx context.Context, id uuid.UUID) (*entity.Invoice, error) {
+	var i entity.Invoice
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE invoices
+			SET status = $1, issued_at = NOW(), updated_at = NOW()
+			WHERE id = $2 AND merchant_id = $3 AND status = $4
+			RETURNING `+invoiceColumns,
+			entity.InvoiceStatusOpen, id, merchantID, entity.InvoiceStatusDraft,
+		)
+		return scanInvoice(row, &i)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// ModifyVoided voids a DRAFT or OPEN invoice that has no payment in progress or holding
+// money. It returns sql.ErrNoRows when the invoice is missing or cannot be voided.
+func (r *invoiceRepo) ModifyVoided(ctx context.Context, id uuid.UUID) (*entity.Invoice, error) {
+	var i entity.Invoice
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE invoices
+			SET status = $1, voided_at = NOW(), updated_at = NOW()
+			WHERE id = $2 AND merchant_id = $3 AND status = ANY($4)
+			  AND NOT EXISTS (
+			    SELECT 1 FROM payments
+			    WHERE invoice_id = $2 AND merchant_id = $3 AND deleted_at IS NULL AND status = ANY($5)
+			  )
+			RETURNING `+invoiceColumns,
+			entity.InvoiceStatusVoid, id, merchantID,
+			pq.Array([]string{entity.InvoiceStatusDraft, entity.InvoiceStatusOpen}),
+			pq.Array([]string{
+				entity.PaymentStatusPending, entity.PaymentStatusAuthorized, entity.PaymentStatusPaid,
+				entity.PaymentStatusPartiallyRefunded, entity.PaymentStatusDisputed,
+			}),
+		)
+		return scanInvoice(row, &i)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
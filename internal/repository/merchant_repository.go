@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type merchantRepo struct {
+	DB *sql.DB
+}
+
+// MerchantRepository lists merchants for background jobs, which have no request tenant
+// and run their tenant scoped work once per merchant, and reads merchant profiles.
+type MerchantRepository interface {
+	FetchActiveIDs(ctx context.Context) ([]uuid.UUID, error)
+	FetchIDs(ctx context.Context) ([]uuid.UUID, error)
+	FetchQRISProfile(ctx context.Context, merchantID uuid.UUID) (*entity.MerchantQRISProfile, error)
+}
+
+func NewMerchantRepo(db *sql.DB) MerchantRepository {
+	return &merchantRepo{DB: db}
+}
+
+func (r *merchantRepo) FetchActiveIDs(ctx context.Context) ([]uuid.UUID, error) {
+	return r.fetchIDs(ctx, "SELECT id FROM merchants WHERE status = 'ACTIVE' ORDER BY id")
+}
+
+// FetchIDs lists every merchant whatever its status, for maintenance that must reach all
+// stored rows.
+func (r *merchantRepo) FetchIDs(ctx context.Context) ([]uuid.UUID, error) {
+	return r.fetchIDs(ctx, "SELECT id FROM merchants ORDER BY id")
+}
+
+func (r *merchantRepo) fetchIDs(ctx context.Context, query string) ([]uuid.UUID, error) {
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// FetchQRISProfile returns sql.ErrNoRows when the merchant does not exist or has no profile.
+func (r *merchantRepo) FetchQRISProfile(ctx context.Context, merchantID uuid.UUID) (*entity.MerchantQRISProfile, error) {
+	query := `SELECT id, qris_merchant_name, qris_merchant_city, qris_postal_code, qris_merchant_category_code,
+		qris_nmid, qris_merchant_criteria, qris_acquirer_guid, qris_merchant_pan, qris_merchant_id
+		FROM merchants WHERE id = $1 AND qris_merchant_name IS NOT NULL`
+	var p entity.MerchantQRISProfile
+	err := r.DB.QueryRowContext(ctx, query, merchantID).Scan(
+		&p.MerchantID, &p.Name, &p.City, &p.PostalCode, &p.CategoryCode,
+		&p.NMID, &p.Criteria, &p.AcquirerGUID, &p.PAN, &p.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
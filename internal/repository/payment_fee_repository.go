@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type paymentFeeRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type PaymentFeeRepository interface {
+	FetchByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]entity.PaymentFee, error)
+	Store(ctx context.Context, tx *sql.Tx, paymentID uuid.UUID, fees []entity.PaymentFee) error
+}
+
+func NewPaymentFeeRepo(db *sql.DB, rowLevelSecurity bool) PaymentFeeRepository {
+	return &paymentFeeRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const paymentFeeColumns = "id, payment_id, fee_rule_id, type, description, amount, currency, created_at"
+
+func scanPaymentFee(row rowScanner, fee *entity.PaymentFee) error {
+	return row.Scan(&fee.ID, &fee.PaymentID, &fee.FeeRuleID, &fee.Type, &fee.Description, &fee.Amount, &fee.Currency, &fee.CreatedAt)
+}
+
+func (r *paymentFeeRepo) FetchByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]entity.PaymentFee, error) {
+	fees := []entity.PaymentFee{}
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx,
+			"SELECT "+paymentFeeColumns+" FROM payment_fees WHERE payment_id = $1 AND merchant_id = $2 ORDER BY created_at, id",
+			paymentID, merchantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var fee entity.PaymentFee
+			if err := scanPaymentFee(rows, &fee); err != nil {
+				return err
+			}
+			fees = append(fees, fee)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+func (r *paymentFeeRepo) Store(ctx context.Context, tx *sql.Tx, paymentID uuid.UUID, fees []entity.PaymentFee) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		for i := range fees {
+			fee := &fees[i]
+			row := q.QueryRowContext(ctx, `
+				INSERT INTO payment_fees (merchant_id, payment_id, fee_rule_id, type, description, amount, currency)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING `+paymentFeeColumns,
+				merchantID, paymentID, fee.FeeRuleID, fee.Type, fee.Description, fee.Amount, fee.Currency,
+			)
+			if err := scanPaymentFee(row, fee); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
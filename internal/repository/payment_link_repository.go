@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type paymentLinkRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type PaymentLinkRepository interface {
+	FetchMerchantIDByID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	FetchByID(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error)
+	Store(ctx context.Context, link *entity.PaymentLink) error
+	ModifyClaimed(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error)
+	ModifyReleased(ctx context.Context, id uuid.UUID) error
+	ModifyRevoked(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error)
+}
+
+func NewPaymentLinkRepo(db *sql.DB, rowLevelSecurity bool) PaymentLinkRepository {
+	return &paymentLinkRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const paymentLinkColumns = "id, merchant_id, payment_id, status, expires_at, used_at, revoked_at, created_at, updated_at"
+
+func scanPaymentLink(row rowScanner, l *entity.PaymentLink) error {
+	return row.Scan(&l.ID, &l.MerchantID, &l.PaymentID, &l.Status, &l.ExpiresAt, &l.UsedAt, &l.RevokedAt, &l.CreatedAt, &l.UpdatedAt)
+}
+
+// FetchMerchantIDByID resolves the owner of a link for public checkout requests, which
+// carry no merchant. Callers verify the link token before trusting the result.
+func (r *paymentLinkRepo) FetchMerchantIDByID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var merchantID uuid.NullUUID
+	if err := r.DB.QueryRowContext(ctx, "SELECT payment_link_merchant($1)", id).Scan(&merchantID); err != nil {
+		return uuid.Nil, err
+	}
+	if !merchantID.Valid {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return merchantID.UUID, nil
+}
+
+func (r *paymentLinkRepo) FetchByID(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error) {
+	var l entity.PaymentLink
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+paymentLinkColumns+" FROM payment_links WHERE id = $1 AND merchant_id = $2", id, merchantID)
+		return scanPaymentLink(row, &l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *paymentLinkRepo) Store(ctx context.Context, l *entity.PaymentLink) error {
+	return r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO payment_links (merchant_id, payment_id, status, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+paymentLinkColumns,
+			merchantID, l.PaymentID, entity.PaymentLinkStatusActive, l.ExpiresAt.UTC(),
+		)
+		return scanPaymentLink(row, l)
+	})
+}
+
+// ModifyClaimed marks an active, unexpired link as used. It returns sql.ErrNoRows when
+// the link was used, revoked or expired in the meantime, so only one attempt can claim it.
+func (r *paymentLinkRepo) ModifyClaimed(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error) {
+	var l entity.PaymentLink
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE payment_links
+			SET status = $1, used_at = NOW(), updated_at = NOW()
+			WHERE id = $2 AND merchant_id = $3 AND status = $4 AND expires_at > NOW()
+			RETURNING `+paymentLinkColumns,
+			entity.PaymentLinkStatusUsed, id, merchantID, entity.PaymentLinkStatusActive,
+		)
+		return scanPaymentLink(row, &l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// ModifyReleased reactivates a claimed link whose payment attempt did not reach the provider.
+func (r *paymentLinkRepo) ModifyReleased(ctx context.Context, id uuid.UUID) error {
+	return r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		_, err := q.ExecContext(ctx, `
+			UPDATE payment_links
+			SET status = $1, used_at = NULL, updated_at = NOW()
+			WHERE id = $2 AND merchant_id = $3 AND status = $4`,
+			entity.PaymentLinkStatusActive, id, merchantID, entity.PaymentLinkStatusUsed,
+		)
+		return err
+	})
+}
+
+func (r *paymentLinkRepo) ModifyRevoked(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error) {
+	var l entity.PaymentLink
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE payment_links
+			SET status = $1, revoked_at = NOW(), updated_at = NOW()
+			WHERE id = $2 AND merchant_id = $3 AND status = $4
+			RETURNING `+paymentLinkColumns,
+			entity.PaymentLinkStatusRevoked, id, merchantID, entity.PaymentLinkStatusActive,
+		)
+		return scanPaymentLink(row, &l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
@@ -3,125 +3,301 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/adf-code/beta-payment-api/internal/delivery/request"
 	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/fieldcrypt"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 type paymentRepo struct {
-	DB *sql.DB
+	DB     *sql.DB
+	scope  tenantScope
+	fields *fieldcrypt.Encryptor
 }
 
 type PaymentRepository interface {
 	FetchWithQueryParams(ctx context.Context, params request.PaymentListQueryParams) ([]entity.Payment, error)
 	FetchByID(ctx context.Context, id uuid.UUID) (*entity.Payment, error)
+	FetchByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]entity.Payment, error)
+	SumInProgressByInvoiceID(ctx context.Context, tx *sql.Tx, invoiceID uuid.UUID) (valueobject.Decimal, error)
+	FetchByProviderReference(ctx context.Context, provider, reference string) (*entity.Payment, error)
+	FetchMerchantIDByProviderReference(ctx context.Context, provider, reference string) (uuid.UUID, error)
+	FetchByIDForUpdate(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*entity.Payment, error)
+	ModifyDetails(ctx context.Context, tx *sql.Tx, payment *entity.Payment) error
 	ModifyByID(ctx context.Context, id uuid.UUID, req *request.UpdatePaymentRequest) (*entity.Payment, error)
 	Store(ctx context.Context, tx *sql.Tx, payment *entity.Payment) error
+	ModifyGatewayState(ctx context.Context, payment *entity.Payment, fromStatus string) error
+	ModifyGatewayStateInTx(ctx context.Context, tx *sql.Tx, payment *entity.Payment, fromStatus string) error
 	Remove(ctx context.Context, id uuid.UUID) error
+	FetchSummary(ctx context.Context, params request.PaymentSummaryQueryParams) ([]entity.PaymentSummaryGroup, error)
+	ReEncrypt(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error)
 }
 
-func NewPaymentRepo(db *sql.DB) PaymentRepository {
-	return &paymentRepo{DB: db}
+const paymentColumns = "id, merchant_id, customer_id, invoice_id, tag, description, amount, currency, method, status, provider, provider_reference, refunded_amount, settlement_currency, fx_rate, settlement_amount, metadata, created_at, updated_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPayment(row rowScanner, p *entity.Payment) error {
+	return row.Scan(
+		&p.ID,
+		&p.MerchantID,
+		&p.CustomerID,
+		&p.InvoiceID,
+		&p.Tag,
+		&p.Description,
+		&p.Amount,
+		&p.Currency,
+		&p.Method,
+		&p.Status,
+		&p.Provider,
+		&p.ProviderReference,
+		&p.RefundedAmount,
+		&p.SettlementCurrency,
+		&p.FXRate,
+		&p.SettlementAmount,
+		&p.Metadata,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+}
+
+// paymentDescriptionField names payments.description for field encryption.
+const paymentDescriptionField = "payments.description"
+
+// NewPaymentRepo returns a repository scoped to the merchant carried by each call's context.
+// With rowLevelSecurity every call also runs under the payments row level policy. The
+// description is encrypted with fields, or stored in plaintext when fields is nil.
+func NewPaymentRepo(db *sql.DB, rowLevelSecurity bool, fields *fieldcrypt.Encryptor) PaymentRepository {
+	return &paymentRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}, fields: fields}
+}
+
+// scan reads a payment row and decrypts its encrypted fields.
+func (r *paymentRepo) scan(row rowScanner, p *entity.Payment) error {
+	if err := scanPayment(row, p); err != nil {
+		return err
+	}
+	description, err := r.fields.Decrypt(paymentDescriptionField, p.Description)
+	if err != nil {
+		return err
+	}
+	p.Description = description
+	return nil
 }
 
 func (r *paymentRepo) FetchWithQueryParams(ctx context.Context, params request.PaymentListQueryParams) ([]entity.Payment, error) {
-	query := "SELECT id, tag, description, amount, status, created_at, updated_at FROM payments WHERE 1=1"
-	args := []interface{}{}
-	argIndex := 1
-
-	// Search
-	if params.SearchField != "" && params.SearchValue != "" {
-		query += fmt.Sprintf(" AND %s ILIKE $%d", params.SearchField, argIndex)
-		args = append(args, "%"+params.SearchValue+"%")
-		argIndex++
-	}
-
-	// Filters
-	for _, f := range params.Filter {
-		if len(f.Value) > 0 {
-			query += fmt.Sprintf(" AND %s = ANY($%d)", f.Field, argIndex)
-			args = append(args, pq.Array(f.Value))
+	var payments []entity.Payment
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := "SELECT " + paymentColumns + " FROM payments WHERE deleted_at IS NULL AND merchant_id = $1"
+		args := []interface{}{merchantID}
+		argIndex := 2
+
+		// Customer
+		if params.CustomerID != nil {
+			query += fmt.Sprintf(" AND customer_id = $%d", argIndex)
+			args = append(args, *params.CustomerID)
 			argIndex++
 		}
-	}
 
-	// Range
-	for _, r := range params.Range {
-		if r.From != nil {
-			query += fmt.Sprintf(" AND %s >= $%d", r.Field, argIndex)
-			args = append(args, *r.From)
+		// Metadata containment, served by the GIN index on payments.metadata
+		if len(params.Metadata) > 0 {
+			filter, err := json.Marshal(params.Metadata)
+			if err != nil {
+				return err
+			}
+			query += fmt.Sprintf(" AND metadata @> $%d::jsonb", argIndex)
+			args = append(args, string(filter))
 			argIndex++
 		}
-		if r.To != nil {
-			query += fmt.Sprintf(" AND %s <= $%d", r.Field, argIndex)
-			args = append(args, *r.To)
+
+		// Search, filter, range and sort fields are column names from the allow-lists of
+		// request.ParsePaymentQueryParams, never raw client input
+
+		// Search; an encrypted description only matches on payments stored before encryption
+		if params.SearchField != "" && params.SearchValue != "" {
+			query += fmt.Sprintf(" AND %s ILIKE $%d", params.SearchField, argIndex)
+			args = append(args, "%"+params.SearchValue+"%")
 			argIndex++
 		}
-	}
 
-	// Sort
-	if params.SortField != "" && (params.SortDir == "ASC" || params.SortDir == "DESC") {
-		query += fmt.Sprintf(" ORDER BY %s %s", params.SortField, params.SortDir)
-	}
+		// Filters
+		for _, f := range params.Filter {
+			if len(f.Value) > 0 {
+				query += fmt.Sprintf(" AND %s = ANY($%d)", f.Field, argIndex)
+				args = append(args, pq.Array(f.Value))
+				argIndex++
+			}
+		}
+
+		// Range
+		for _, r := range params.Range {
+			if r.From != nil {
+				query += fmt.Sprintf(" AND %s >= $%d", r.Field, argIndex)
+				args = append(args, *r.From)
+				argIndex++
+			}
+			if r.To != nil {
+				query += fmt.Sprintf(" AND %s <= $%d", r.Field, argIndex)
+				args = append(args, *r.To)
+				argIndex++
+			}
+		}
+
+		// Sort
+		if params.SortField != "" && (params.SortDir == "ASC" || params.SortDir == "DESC") {
+			query += fmt.Sprintf(" ORDER BY %s %s", params.SortField, params.SortDir)
+		}
 
-	// Pagination
-	if params.Page > 0 && params.PerPage > 0 {
-		offset := (params.Page - 1) * params.PerPage
-		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-		args = append(args, params.PerPage, offset)
+		// Pagination
+		if params.Page > 0 && params.PerPage > 0 {
+			offset := (params.Page - 1) * params.PerPage
+			query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+			args = append(args, params.PerPage, offset)
+		}
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var p entity.Payment
+			if err := r.scan(rows, &p); err != nil {
+				return err
+			}
+			payments = append(payments, p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
-	rows, err := r.DB.QueryContext(ctx, query, args...)
+
+	return payments, nil
+}
+
+func (r *paymentRepo) FetchByID(ctx context.Context, id uuid.UUID) (*entity.Payment, error) {
+	var p entity.Payment
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		return r.scan(q.QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE id = $1 AND merchant_id = $2 AND deleted_at is null", id, merchantID), &p)
+	})
+
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return &p, nil
+}
 
+// FetchByInvoiceID returns the payments made against an invoice, oldest first.
+func (r *paymentRepo) FetchByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]entity.Payment, error) {
 	var payments []entity.Payment
-	for rows.Next() {
-		var p entity.Payment
-		if err := rows.Scan(&p.ID, &p.Tag, &p.Description, &p.Amount, &p.Status, &p.CreatedAt, &p.UpdatedAt); err != nil {
-			return nil, err
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE invoice_id = $1 AND merchant_id = $2 AND deleted_at IS NULL ORDER BY created_at", invoiceID, merchantID)
+		if err != nil {
+			return err
 		}
-		payments = append(payments, p)
-	}
+		defer rows.Close()
 
+		for rows.Next() {
+			var p entity.Payment
+			if err := r.scan(rows, &p); err != nil {
+				return err
+			}
+			payments = append(payments, p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
 	return payments, nil
 }
 
-func (r *paymentRepo) FetchByID(ctx context.Context, id uuid.UUID) (*entity.Payment, error) {
+// SumInProgressByInvoiceID totals the payments against an invoice that are open but not yet
+// captured; callers lock the invoice first so the total cannot change under them.
+func (r *paymentRepo) SumInProgressByInvoiceID(ctx context.Context, tx *sql.Tx, invoiceID uuid.UUID) (valueobject.Decimal, error) {
+	var total valueobject.Decimal
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT COALESCE(SUM(amount), 0) FROM payments WHERE invoice_id = $1 AND merchant_id = $2 AND deleted_at IS NULL AND status IN ($3, $4)",
+			invoiceID, merchantID, entity.PaymentStatusPending, entity.PaymentStatusAuthorized)
+		return row.Scan(&total)
+	})
+	return total, err
+}
+
+func (r *paymentRepo) FetchByProviderReference(ctx context.Context, provider, reference string) (*entity.Payment, error) {
 	var p entity.Payment
-	err := r.DB.QueryRowContext(ctx, "SELECT id, tag, description, amount, status, created_at, updated_at FROM payments WHERE id = $1 AND deleted_at is null", id).
-		Scan(&p.ID, &p.Tag, &p.Description, &p.Amount, &p.Status, &p.CreatedAt, &p.UpdatedAt)
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE provider = $1 AND provider_reference = $2 AND merchant_id = $3 AND deleted_at is null", provider, reference, merchantID)
+		return r.scan(row, &p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
 
+// FetchMerchantIDByProviderReference resolves which merchant owns a provider reference.
+// It is the only unscoped lookup: provider callbacks carry no merchant, so the caller
+// resolves it here and then continues through the scoped methods.
+func (r *paymentRepo) FetchMerchantIDByProviderReference(ctx context.Context, provider, reference string) (uuid.UUID, error) {
+	var merchantID uuid.NullUUID
+	err := r.DB.QueryRowContext(ctx, "SELECT payment_merchant_by_provider_reference($1, $2)", provider, reference).Scan(&merchantID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !merchantID.Valid {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return merchantID.UUID, nil
+}
+
+func (r *paymentRepo) FetchByIDForUpdate(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*entity.Payment, error) {
+	var p entity.Payment
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE id = $1 AND merchant_id = $2 AND deleted_at is null FOR UPDATE", id, merchantID)
+		return r.scan(row, &p)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
+func (r *paymentRepo) ModifyDetails(ctx context.Context, tx *sql.Tx, payment *entity.Payment) error {
+	description, err := r.fields.Encrypt(paymentDescriptionField, payment.Description)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE payments
+		SET description = $1, metadata = $2, updated_at = NOW()
+		WHERE id = $3 AND merchant_id = $4 AND deleted_at IS NULL
+		RETURNING ` + paymentColumns
+
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, query, description, payment.Metadata, payment.ID, merchantID)
+		return r.scan(row, payment)
+	})
+}
+
 func (r *paymentRepo) ModifyByID(ctx context.Context, id uuid.UUID, req *request.UpdatePaymentRequest) (*entity.Payment, error) {
 	query := `
 		UPDATE payments
 		SET status = $1, updated_at = NOW()
-		WHERE id = $2
-		RETURNING id, tag, description, amount, status, created_at, updated_at
-	`
-
-	row := r.DB.QueryRowContext(ctx, query, req.Status, id)
+		WHERE id = $2 AND merchant_id = $3 AND deleted_at IS NULL
+		RETURNING ` + paymentColumns
 
 	var updated entity.Payment
 
-	err := row.Scan(
-		&updated.ID,
-		&updated.Tag,
-		&updated.Description,
-		&updated.Amount,
-		&updated.Status,
-		&updated.CreatedAt,
-		&updated.UpdatedAt,
-	)
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		return r.scan(q.QueryRowContext(ctx, query, req.Status, id, merchantID), &updated)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -129,15 +305,176 @@ func (r *paymentRepo) ModifyByID(ctx context.Context, id uuid.UUID, req *request
 	return &updated, nil
 }
 
+// Store inserts the payment for the merchant of ctx in payment.Status; payment.MerchantID
+// is ignored.
 func (r *paymentRepo) Store(ctx context.Context, tx *sql.Tx, payment *entity.Payment) error {
-	return tx.QueryRowContext(
-		ctx,
-		"INSERT INTO payments (tag, description, amount) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at, status",
-		payment.Tag, payment.Description, payment.Amount,
-	).Scan(&payment.ID, &payment.CreatedAt, &payment.UpdatedAt, &payment.Status)
+	description, err := r.fields.Encrypt(paymentDescriptionField, payment.Description)
+	if err != nil {
+		return err
+	}
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(
+			ctx,
+			"INSERT INTO payments (merchant_id, customer_id, invoice_id, tag, description, amount, currency, method, metadata, status, client_ip) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING "+paymentColumns,
+			merchantID, payment.CustomerID, payment.InvoiceID, payment.Tag, description, payment.Amount, payment.Currency, payment.Method, payment.Metadata, payment.Status, payment.ClientIP,
+		)
+		return r.scan(row, payment)
+	})
 }
 
+const modifyGatewayStateQuery = `
+	UPDATE payments
+	SET status = $1, provider = $2, provider_reference = $3, refunded_amount = $4,
+	    settlement_currency = $5, fx_rate = $6, settlement_amount = $7, updated_at = NOW()
+	WHERE id = $8 AND status = $9 AND merchant_id = $10 AND deleted_at IS NULL
+	RETURNING ` + paymentColumns
+
+// ModifyGatewayState persists the outcome of a provider call. The update only applies
+// while the payment is still in fromStatus, so concurrent operations cannot both win.
+func (r *paymentRepo) ModifyGatewayState(ctx context.Context, payment *entity.Payment, fromStatus string) error {
+	return r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		return r.modifyGatewayState(ctx, q, merchantID, payment, fromStatus)
+	})
+}
+
+// ModifyGatewayStateInTx is ModifyGatewayState for callers that write related rows in tx.
+func (r *paymentRepo) ModifyGatewayStateInTx(ctx context.Context, tx *sql.Tx, payment *entity.Payment, fromStatus string) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		return r.modifyGatewayState(ctx, q, merchantID, payment, fromStatus)
+	})
+}
+
+func (r *paymentRepo) modifyGatewayState(ctx context.Context, q querier, merchantID uuid.UUID, payment *entity.Payment, fromStatus string) error {
+	row := q.QueryRowContext(ctx, modifyGatewayStateQuery,
+		payment.Status, payment.Provider, payment.ProviderReference, payment.RefundedAmount,
+		payment.SettlementCurrency, payment.FXRate, payment.SettlementAmount, payment.ID, fromStatus, merchantID)
+	return r.scan(row, payment)
+}
+
+// Remove soft-deletes a payment; it disappears from reads but keeps its references intact.
 func (r *paymentRepo) Remove(ctx context.Context, id uuid.UUID) error {
-	_, err := r.DB.ExecContext(ctx, "DELETE FROM payments WHERE id = $1", id)
-	return err
+	return r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		res, err := q.ExecContext(ctx, "UPDATE payments SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND merchant_id = $2 AND deleted_at IS NULL", id, merchantID)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// FetchSummary totals the merchant's payments per currency and status.
+func (r *paymentRepo) FetchSummary(ctx context.Context, params request.PaymentSummaryQueryParams) ([]entity.PaymentSummaryGroup, error) {
+	var groups []entity.PaymentSummaryGroup
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := "SELECT currency, status, COUNT(*), SUM(amount), SUM(refunded_amount) FROM payments WHERE deleted_at IS NULL AND merchant_id = $1"
+		args := []interface{}{merchantID}
+		argIndex := 2
+
+		if params.CustomerID != nil {
+			query += fmt.Sprintf(" AND customer_id = $%d", argIndex)
+			args = append(args, *params.CustomerID)
+			argIndex++
+		}
+		if params.From != nil {
+			query += fmt.Sprintf(" AND created_at >= $%d", argIndex)
+			args = append(args, params.From.UTC())
+			argIndex++
+		}
+		if params.To != nil {
+			query += fmt.Sprintf(" AND created_at <= $%d", argIndex)
+			args = append(args, params.To.UTC())
+			argIndex++
+		}
+		query += " GROUP BY currency, status ORDER BY currency, status"
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var g entity.PaymentSummaryGroup
+			if err := rows.Scan(&g.Currency, &g.Status, &g.Count, &g.Total, &g.RefundedTotal); err != nil {
+				return err
+			}
+			groups = append(groups, g)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ReEncrypt rewrites the description of up to limit payments of the merchant of ctx, with
+// ids after after, that are not stored under the active encryption key. Deleted payments
+// are included. It returns the last id it looked at, uuid.Nil once none are left, and how
+// many payments it rewrote.
+func (r *paymentRepo) ReEncrypt(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	defer tx.Rollback()
+
+	last, updated := uuid.Nil, 0
+	err = r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx, "SELECT id, description FROM payments WHERE merchant_id = $1 AND id > $2 ORDER BY id LIMIT $3 FOR UPDATE", merchantID, after, limit)
+		if err != nil {
+			return err
+		}
+		type stored struct {
+			id          uuid.UUID
+			description string
+		}
+		var batch []stored
+		for rows.Next() {
+			var s stored
+			if err := rows.Scan(&s.id, &s.description); err != nil {
+				rows.Close()
+				return err
+			}
+			batch = append(batch, s)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, s := range batch {
+			last = s.id
+			if r.fields.Current(s.description) {
+				continue
+			}
+			plaintext, err := r.fields.Decrypt(paymentDescriptionField, s.description)
+			if err != nil {
+				return fmt.Errorf("payment %s: %w", s.id, err)
+			}
+			description, err := r.fields.Encrypt(paymentDescriptionField, plaintext)
+			if err != nil {
+				return err
+			}
+			if _, err := q.ExecContext(ctx, "UPDATE payments SET description = $1 WHERE id = $2 AND merchant_id = $3", description, s.id, merchantID); err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, 0, err
+	}
+	return last, updated, nil
 }
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type paymentReversalRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type PaymentReversalRepository interface {
+	FetchByDisputeID(ctx context.Context, disputeID uuid.UUID) (*entity.PaymentReversal, error)
+	Store(ctx context.Context, tx *sql.Tx, reversal *entity.PaymentReversal) error
+}
+
+func NewPaymentReversalRepo(db *sql.DB, rowLevelSecurity bool) PaymentReversalRepository {
+	return &paymentReversalRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const paymentReversalColumns = "id, payment_id, dispute_id, amount, currency, reason, created_at"
+
+func scanPaymentReversal(row rowScanner, rev *entity.PaymentReversal) error {
+	return row.Scan(&rev.ID, &rev.PaymentID, &rev.DisputeID, &rev.Amount, &rev.Currency, &rev.Reason, &rev.CreatedAt)
+}
+
+func (r *paymentReversalRepo) FetchByDisputeID(ctx context.Context, disputeID uuid.UUID) (*entity.PaymentReversal, error) {
+	var rev entity.PaymentReversal
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+paymentReversalColumns+" FROM payment_reversals WHERE dispute_id = $1 AND merchant_id = $2", disputeID, merchantID)
+		return scanPaymentReversal(row, &rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (r *paymentReversalRepo) Store(ctx context.Context, tx *sql.Tx, rev *entity.PaymentReversal) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO payment_reversals (merchant_id, payment_id, dispute_id, amount, currency, reason)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+paymentReversalColumns,
+			merchantID, rev.PaymentID, rev.DisputeID, rev.Amount, rev.Currency, rev.Reason,
+		)
+		return scanPaymentReversal(row, rev)
+	})
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type paymentSplitRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type PaymentSplitRepository interface {
+	FetchByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]entity.PaymentSplit, error)
+	FetchByPaymentIDForUpdate(ctx context.Context, tx *sql.Tx, paymentID uuid.UUID) ([]entity.PaymentSplit, error)
+	Store(ctx context.Context, tx *sql.Tx, paymentID uuid.UUID, splits []entity.PaymentSplit) error
+	ModifyRefundedAmounts(ctx context.Context, tx *sql.Tx, splits []entity.PaymentSplit) error
+	FetchRecipientSummary(ctx context.Context, params request.PaymentSummaryQueryParams) ([]entity.PaymentSummaryRecipient, error)
+}
+
+func NewPaymentSplitRepo(db *sql.DB, rowLevelSecurity bool) PaymentSplitRepository {
+	return &paymentSplitRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const paymentSplitColumns = "id, payment_id, recipient_id, percentage, amount, refunded_amount, created_at"
+
+func scanPaymentSplit(row rowScanner, s *entity.PaymentSplit) error {
+	return row.Scan(&s.ID, &s.PaymentID, &s.RecipientID, &s.Percentage, &s.Amount, &s.RefundedAmount, &s.CreatedAt)
+}
+
+func (r *paymentSplitRepo) FetchByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]entity.PaymentSplit, error) {
+	var splits []entity.PaymentSplit
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		var err error
+		splits, err = fetchPaymentSplits(ctx, q, merchantID, paymentID, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return splits, nil
+}
+
+// FetchByPaymentIDForUpdate locks the splits of a payment until tx ends, so concurrent
+// refunds pro-rate against each other's results.
+func (r *paymentSplitRepo) FetchByPaymentIDForUpdate(ctx context.Context, tx *sql.Tx, paymentID uuid.UUID) ([]entity.PaymentSplit, error) {
+	var splits []entity.PaymentSplit
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		var err error
+		splits, err = fetchPaymentSplits(ctx, q, merchantID, paymentID, " FOR UPDATE")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return splits, nil
+}
+
+// fetchPaymentSplits returns splits in the order they were given on create, which is the
+// order rounding ties are resolved in.
+func fetchPaymentSplits(ctx context.Context, q querier, merchantID, paymentID uuid.UUID, lock string) ([]entity.PaymentSplit, error) {
+	rows, err := q.QueryContext(ctx,
+		"SELECT "+paymentSplitColumns+" FROM payment_splits WHERE payment_id = $1 AND merchant_id = $2 ORDER BY created_at, position"+lock,
+		paymentID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	splits := []entity.PaymentSplit{}
+	for rows.Next() {
+		var s entity.PaymentSplit
+		if err := scanPaymentSplit(rows, &s); err != nil {
+			return nil, err
+		}
+		splits = append(splits, s)
+	}
+	return splits, rows.Err()
+}
+
+func (r *paymentSplitRepo) Store(ctx context.Context, tx *sql.Tx, paymentID uuid.UUID, splits []entity.PaymentSplit) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		for i := range splits {
+			s := &splits[i]
+			row := q.QueryRowContext(ctx, `
+				INSERT INTO payment_splits (merchant_id, payment_id, position, recipient_id, percentage, amount)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING `+paymentSplitColumns,
+				merchantID, paymentID, i, s.RecipientID, s.Percentage, s.Amount,
+			)
+			if err := scanPaymentSplit(row, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *paymentSplitRepo) ModifyRefundedAmounts(ctx context.Context, tx *sql.Tx, splits []entity.PaymentSplit) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		for _, s := range splits {
+			result, err := q.ExecContext(ctx,
+				"UPDATE payment_splits SET refunded_amount = $1 WHERE id = $2 AND merchant_id = $3",
+				s.RefundedAmount, s.ID, merchantID)
+			if err != nil {
+				return err
+			}
+			if n, _ := result.RowsAffected(); n == 0 {
+				return sql.ErrNoRows
+			}
+		}
+		return nil
+	})
+}
+
+// FetchRecipientSummary totals split amounts per recipient and currency. Only captured
+// payments count, since recipients are owed nothing for payments that never collected funds.
+func (r *paymentSplitRepo) FetchRecipientSummary(ctx context.Context, params request.PaymentSummaryQueryParams) ([]entity.PaymentSummaryRecipient, error) {
+	var recipients []entity.PaymentSummaryRecipient
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := `
+			SELECT s.recipient_id, p.currency, COUNT(*), SUM(s.amount), SUM(s.refunded_amount)
+			FROM payment_splits s
+			JOIN payments p ON p.id = s.payment_id AND p.merchant_id = s.merchant_id
+			WHERE p.deleted_at IS NULL AND s.merchant_id = $1 AND p.status IN ($2, $3, $4)`
+		args := []interface{}{merchantID, entity.PaymentStatusPaid, entity.PaymentStatusPartiallyRefunded, entity.PaymentStatusRefunded}
+		argIndex := 5
+
+		if params.CustomerID != nil {
+			query += fmt.Sprintf(" AND p.customer_id = $%d", argIndex)
+			args = append(args, *params.CustomerID)
+			argIndex++
+		}
+		if params.From != nil {
+			query += fmt.Sprintf(" AND p.created_at >= $%d", argIndex)
+			args = append(args, params.From.UTC())
+			argIndex++
+		}
+		if params.To != nil {
+			query += fmt.Sprintf(" AND p.created_at <= $%d", argIndex)
+			args = append(args, params.To.UTC())
+			argIndex++
+		}
+		query += " GROUP BY s.recipient_id, p.currency ORDER BY s.recipient_id, p.currency"
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var rec entity.PaymentSummaryRecipient
+			if err := rows.Scan(&rec.RecipientID, &rec.Currency, &rec.Count, &rec.Total, &rec.RefundedTotal); err != nil {
+				return err
+			}
+			recipients = append(recipients, rec)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
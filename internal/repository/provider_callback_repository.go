@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type providerCallbackRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type ProviderCallbackRepository interface {
+	FetchWithQueryParams(ctx context.Context, params request.ProviderCallbackListQueryParams) ([]entity.ProviderCallback, error)
+	Store(ctx context.Context, callback *entity.ProviderCallback) error
+	StoreVerified(ctx context.Context, callback *entity.ProviderCallback) (bool, error)
+	ModifyOutcome(ctx context.Context, callback *entity.ProviderCallback) error
+}
+
+// NewProviderCallbackRepo stores callbacks without a merchant, since they arrive before
+// they are matched to a payment; listing them is tenant scoped.
+func NewProviderCallbackRepo(db *sql.DB, rowLevelSecurity bool) ProviderCallbackRepository {
+	return &providerCallbackRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const providerCallbackColumns = "id, merchant_id, provider, event_id, reference, payment_id, status, signature_valid, outcome, error, headers, payload, received_at, processed_at"
+
+func scanProviderCallback(row rowScanner, c *entity.ProviderCallback) error {
+	var headers []byte
+	err := row.Scan(
+		&c.ID,
+		&c.MerchantID,
+		&c.Provider,
+		&c.EventID,
+		&c.Reference,
+		&c.PaymentID,
+		&c.Status,
+		&c.SignatureValid,
+		&c.Outcome,
+		&c.Error,
+		&headers,
+		&c.Payload,
+		&c.ReceivedAt,
+		&c.ProcessedAt,
+	)
+	c.Headers = headers
+	return err
+}
+
+func (r *providerCallbackRepo) FetchWithQueryParams(ctx context.Context, params request.ProviderCallbackListQueryParams) ([]entity.ProviderCallback, error) {
+	var callbacks []entity.ProviderCallback
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := "SELECT " + providerCallbackColumns + " FROM provider_callbacks WHERE merchant_id = $1"
+		args := []interface{}{merchantID}
+		argIndex := 2
+
+		if params.Provider != "" {
+			query += fmt.Sprintf(" AND provider = $%d", argIndex)
+			args = append(args, params.Provider)
+			argIndex++
+		}
+		if params.Outcome != "" {
+			query += fmt.Sprintf(" AND outcome = $%d", argIndex)
+			args = append(args, params.Outcome)
+			argIndex++
+		}
+		if params.SignatureValid != nil {
+			query += fmt.Sprintf(" AND signature_valid = $%d", argIndex)
+			args = append(args, *params.SignatureValid)
+			argIndex++
+		}
+
+		query += " ORDER BY received_at DESC"
+		if params.Page > 0 && params.PerPage > 0 {
+			offset := (params.Page - 1) * params.PerPage
+			query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+			args = append(args, params.PerPage, offset)
+		}
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c entity.ProviderCallback
+			if err := scanProviderCallback(rows, &c); err != nil {
+				return err
+			}
+			callbacks = append(callbacks, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return callbacks, nil
+}
+
+// Store records a callback as-is, used for callbacks that failed verification or parsing.
+func (r *providerCallbackRepo) Store(ctx context.Context, c *entity.ProviderCallback) error {
+	row := r.DB.QueryRowContext(ctx, `
+		INSERT INTO provider_callbacks (provider, event_id, reference, status, signature_valid, outcome, error, headers, payload, processed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		RETURNING `+providerCallbackColumns,
+		c.Provider, c.EventID, c.Reference, c.Status, c.SignatureValid, c.Outcome, c.Error, []byte(c.Headers), c.Payload,
+	)
+	return scanProviderCallback(row, c)
+}
+
+// StoreVerified records a verified callback, deduplicated by provider event ID. It returns
+// false when the event was already processed. Events left RECEIVED or UNMATCHED by an earlier
+// delivery are handed out again so that provider retries can complete them.
+func (r *providerCallbackRepo) StoreVerified(ctx context.Context, c *entity.ProviderCallback) (bool, error) {
+	row := r.DB.QueryRowContext(ctx, `
+		INSERT INTO provider_callbacks (provider, event_id, reference, status, signature_valid, outcome, headers, payload)
+		VALUES ($1, $2, $3, $4, TRUE, $5, $6, $7)
+		ON CONFLICT (provider, event_id) WHERE signature_valid
+		DO UPDATE SET received_at = NOW(), headers = EXCLUDED.headers, payload = EXCLUDED.payload
+		WHERE provider_callbacks.outcome IN ('`+entity.CallbackOutcomeReceived+`', '`+entity.CallbackOutcomeUnmatched+`')
+		RETURNING `+providerCallbackColumns,
+		c.Provider, c.EventID, c.Reference, c.Status, entity.CallbackOutcomeReceived, []byte(c.Headers), c.Payload,
+	)
+	if err := scanProviderCallback(row, c); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ModifyOutcome records the outcome of a callback and, once it is matched, the payment
+// and merchant it belongs to.
+func (r *providerCallbackRepo) ModifyOutcome(ctx context.Context, c *entity.ProviderCallback) error {
+	row := r.DB.QueryRowContext(ctx, `
+		UPDATE provider_callbacks
+		SET outcome = $1, payment_id = $2, merchant_id = $3, error = $4, processed_at = NOW()
+		WHERE id = $5
+		RETURNING `+providerCallbackColumns,
+		c.Outcome, c.PaymentID, c.MerchantID, c.Error, c.ID,
+	)
+	return scanProviderCallback(row, c)
+}
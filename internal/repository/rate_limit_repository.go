@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/ratelimit"
+)
+
+type rateLimitRepo struct {
+	DB *sql.DB
+}
+
+// NewRateLimitRepo returns a rate limit store shared by every replica. Buckets are keyed
+// by caller, not merchant data, so they are not tenant scoped.
+func NewRateLimitRepo(db *sql.DB) ratelimit.Store {
+	return &rateLimitRepo{DB: db}
+}
+
+// refilledTokens is the bucket content after refilling it for the time since its last
+// request; $2 is the bucket size and $3 the refill rate per second.
+const refilledTokens = "LEAST($2::float8, b.tokens + GREATEST(EXTRACT(EPOCH FROM (NOW() - b.updated_at))::float8, 0) * $3::float8)"
+
+// Take refills and takes from the bucket in one statement, so concurrent requests on
+// any replica serialize on the bucket row.
+func (r *rateLimitRepo) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	var (
+		tokens  float64
+		allowed bool
+	)
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+		VALUES ($1, $2::float8 - 1, TRUE, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = CASE WHEN `+refilledTokens+` >= 1 THEN `+refilledTokens+` - 1 ELSE `+refilledTokens+` END,
+			allowed = `+refilledTokens+` >= 1,
+			updated_at = NOW()
+		RETURNING tokens, allowed`,
+		key, float64(limit.Requests), float64(limit.Requests)/limit.Window.Seconds(),
+	).Scan(&tokens, &allowed)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	return ratelimit.ResultOf(limit, allowed, tokens), nil
+}
+
+func (r *rateLimitRepo) Refund(ctx context.Context, key string, limit ratelimit.Limit) error {
+	_, err := r.DB.ExecContext(ctx, `
+		UPDATE rate_limit_buckets AS b SET
+			tokens = LEAST($2::float8, `+refilledTokens+` + 1),
+			updated_at = NOW()
+		WHERE b.key = $1`,
+		key, float64(limit.Requests), float64(limit.Requests)/limit.Window.Seconds(),
+	)
+	return err
+}
+
+func (r *rateLimitRepo) Sweep(ctx context.Context, idle time.Duration) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - make_interval(secs => $1::float8)", idle.Seconds())
+	return err
+}
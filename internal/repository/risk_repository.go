@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type riskRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+// RiskRepository answers the risk rules' questions about earlier payments and records
+// their decisions.
+type RiskRepository interface {
+	CountPayments(ctx context.Context, key, value string, since time.Time) (int, error)
+	CustomerCreatedAt(ctx context.Context, customerID uuid.UUID) (time.Time, error)
+	StoreDecision(ctx context.Context, tx *sql.Tx, decision *entity.RiskDecision) error
+}
+
+func NewRiskRepo(db *sql.DB, rowLevelSecurity bool) RiskRepository {
+	return &riskRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+// riskKeyColumns maps velocity keys to the payment column they count on.
+var riskKeyColumns = map[string]string{
+	"TAG":      "tag",
+	"CUSTOMER": "customer_id::text",
+	"IP":       "client_ip",
+}
+
+// CountPayments counts the payments created since for a tag, customer or client IP.
+// Deleted payments still count: deleting them must not reset a velocity limit.
+func (r *riskRepo) CountPayments(ctx context.Context, key, value string, since time.Time) (int, error) {
+	column, ok := riskKeyColumns[key]
+	if !ok {
+		return 0, fmt.Errorf("unknown risk key %q", key)
+	}
+	var count int
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		return q.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM payments WHERE merchant_id = $1 AND "+column+" = $2 AND created_at >= $3",
+			merchantID, value, since,
+		).Scan(&count)
+	})
+	return count, err
+}
+
+func (r *riskRepo) CustomerCreatedAt(ctx context.Context, customerID uuid.UUID) (time.Time, error) {
+	var createdAt time.Time
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		return q.QueryRowContext(ctx,
+			"SELECT created_at FROM customers WHERE id = $1 AND merchant_id = $2 AND deleted_at IS NULL",
+			customerID, merchantID,
+		).Scan(&createdAt)
+	})
+	return createdAt, err
+}
+
+// StoreDecision records a decision, in tx when given so it commits with the payment.
+func (r *riskRepo) StoreDecision(ctx context.Context, tx *sql.Tx, d *entity.RiskDecision) error {
+	reasons, err := json.Marshal(d.Reasons)
+	if err != nil {
+		return err
+	}
+	store := func(q querier, merchantID uuid.UUID) error {
+		return q.QueryRowContext(ctx, `
+			INSERT INTO risk_decisions (merchant_id, payment_id, action, score, reason_code, reasons, tag, customer_id, client_ip, amount, currency)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id, created_at`,
+			merchantID, d.PaymentID, d.Action, d.Score, d.ReasonCode, string(reasons), d.Tag, d.CustomerID, d.ClientIP, d.Amount, d.Currency,
+		).Scan(&d.ID, &d.CreatedAt)
+	}
+	if tx == nil {
+		return r.scope.run(ctx, store)
+	}
+	return r.scope.runInTx(ctx, tx, store)
+}
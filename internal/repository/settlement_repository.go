@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/google/uuid"
+)
+
+type settlementRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type SettlementRepository interface {
+	FetchCandidatesForUpdate(ctx context.Context, tx *sql.Tx, cutoff time.Time) ([]entity.SettlementCandidate, error)
+	FetchPayoutAccount(ctx context.Context, tx *sql.Tx) (entity.PayoutAccount, error)
+	Store(ctx context.Context, tx *sql.Tx, batch *entity.SettlementBatch) error
+	FetchWithQueryParams(ctx context.Context, params request.SettlementBatchListQueryParams) ([]entity.SettlementBatch, error)
+	FetchByID(ctx context.Context, id uuid.UUID) (*entity.SettlementBatch, error)
+	FetchItems(ctx context.Context, batchID uuid.UUID) ([]entity.SettlementBatchItem, error)
+	ModifyStatus(ctx context.Context, id uuid.UUID, from, to string) (*entity.SettlementBatch, error)
+}
+
+func NewSettlementRepo(db *sql.DB, rowLevelSecurity bool) SettlementRepository {
+	return &settlementRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const settlementBatchColumns = "id, merchant_id, currency, status, cutoff_at, item_count, gross_amount, refund_amount, fee_amount, net_amount, payout_account_name, payout_account_number, payout_bank_code, sent_at, confirmed_at, created_at, updated_at"
+
+func scanSettlementBatch(row rowScanner, b *entity.SettlementBatch) error {
+	return row.Scan(
+		&b.ID,
+		&b.MerchantID,
+		&b.Currency,
+		&b.Status,
+		&b.CutoffAt,
+		&b.ItemCount,
+		&b.GrossAmount,
+		&b.RefundAmount,
+		&b.FeeAmount,
+		&b.NetAmount,
+		&b.PayoutAccount.Name,
+		&b.PayoutAccount.Number,
+		&b.PayoutAccount.BankCode,
+		&b.SentAt,
+		&b.ConfirmedAt,
+		&b.CreatedAt,
+		&b.UpdatedAt,
+	)
+}
+
+const settlementBatchItemColumns = "id, batch_id, payment_id, type, gross_amount, refund_amount, fee_amount, net_amount, created_at"
+
+func scanSettlementBatchItem(row rowScanner, i *entity.SettlementBatchItem) error {
+	return row.Scan(&i.ID, &i.BatchID, &i.PaymentID, &i.Type, &i.GrossAmount, &i.RefundAmount, &i.FeeAmount, &i.NetAmount, &i.CreatedAt)
+}
+
+// FetchCandidatesForUpdate locks the payments last changed before cutoff that are due to
+// be settled: captured payments not yet paid out, and paid out payments refunded or charged
+// back since. Disputed payments are held until the dispute is resolved; a dispute lost
+// before payout leaves the payment CHARGED_BACK, so it is never paid out and its reversal
+// is not deducted. A concurrent run blocks on the locks and then skips the payments this
+// one settled; resolving a dispute locks its payment too, so no reversal lands in between.
+func (r *settlementRepo) FetchCandidatesForUpdate(ctx context.Context, tx *sql.Tx, cutoff time.Time) ([]entity.SettlementCandidate, error) {
+	var candidates []entity.SettlementCandidate
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx, `
+			SELECT p.id, p.currency, p.amount, p.refunded_amount, p.settled_refunded_amount,
+			       p.settlement_batch_id IS NOT NULL,
+			       COALESCE((SELECT SUM(f.amount) FROM payment_fees f WHERE f.payment_id = p.id), 0),
+			       CASE WHEN p.settlement_batch_id IS NULL THEN 0 ELSE COALESCE(
+			           (SELECT SUM(v.amount) FROM payment_reversals v WHERE v.payment_id = p.id AND v.settlement_batch_id IS NULL), 0) END
+			FROM payments p
+			WHERE p.merchant_id = $1 AND p.deleted_at IS NULL AND p.updated_at < $2
+			  AND ((p.settlement_batch_id IS NULL AND p.status IN ($3, $4, $5))
+			    OR (p.settlement_batch_id IS NOT NULL AND (p.refunded_amount > p.settled_refunded_amount
+			        OR EXISTS (SELECT 1 FROM payment_reversals v WHERE v.payment_id = p.id AND v.settlement_batch_id IS NULL))))
+			ORDER BY p.created_at, p.id
+			FOR UPDATE OF p`,
+			merchantID, cutoff, entity.PaymentStatusPaid, entity.PaymentStatusPartiallyRefunded, entity.PaymentStatusRefunded,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c entity.SettlementCandidate
+			if err := rows.Scan(&c.PaymentID, &c.Currency, &c.Amount, &c.RefundedAmount, &c.SettledRefunded, &c.Settled, &c.Fees, &c.Reversed); err != nil {
+				return err
+			}
+			candidates = append(candidates, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+func (r *settlementRepo) FetchPayoutAccount(ctx context.Context, tx *sql.Tx) (entity.PayoutAccount, error) {
+	var account entity.PayoutAccount
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT payout_account_name, payout_account_number, payout_bank_code FROM merchants WHERE id = $1", merchantID)
+		return row.Scan(&account.Name, &account.Number, &account.BankCode)
+	})
+	return account, err
+}
+
+// Store inserts a batch with its items and marks the payments, and the reversals it
+// deducts, as settled by it.
+func (r *settlementRepo) Store(ctx context.Context, tx *sql.Tx, batch *entity.SettlementBatch) error {
+	return r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO settlement_batches (merchant_id, currency, status, cutoff_at, item_count, gross_amount, refund_amount, fee_amount, net_amount, payout_account_name, payout_account_number, payout_bank_code)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING `+settlementBatchColumns,
+			merchantID, batch.Currency, batch.Status, batch.CutoffAt, batch.ItemCount, batch.GrossAmount, batch.RefundAmount, batch.FeeAmount, batch.NetAmount,
+			batch.PayoutAccount.Name, batch.PayoutAccount.Number, batch.PayoutAccount.BankCode,
+		)
+		if err := scanSettlementBatch(row, batch); err != nil {
+			return err
+		}
+
+		for i := range batch.Items {
+			item := &batch.Items[i]
+			row := q.QueryRowContext(ctx, `
+				INSERT INTO settlement_batch_items (merchant_id, batch_id, payment_id, type, gross_amount, refund_amount, fee_amount, net_amount)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING `+settlementBatchItemColumns,
+				merchantID, batch.ID, item.PaymentID, item.Type, item.GrossAmount, item.RefundAmount, item.FeeAmount, item.NetAmount,
+			)
+			if err := scanSettlementBatchItem(row, item); err != nil {
+				return err
+			}
+
+			if item.Type == entity.SettlementItemChargebackAdjustment {
+				// The payment is locked, so these are the reversals the candidate summed
+				if _, err := q.ExecContext(ctx, `
+					UPDATE payment_reversals SET settlement_batch_id = $1
+					WHERE payment_id = $2 AND merchant_id = $3 AND settlement_batch_id IS NULL`,
+					batch.ID, item.PaymentID, merchantID,
+				); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// updated_at is left alone: it is when the payment itself last changed
+			result, err := q.ExecContext(ctx, `
+				UPDATE payments
+				SET settlement_batch_id = COALESCE(settlement_batch_id, $1),
+				    settled_refunded_amount = settled_refunded_amount + $2
+				WHERE id = $3 AND merchant_id = $4`,
+				batch.ID, item.RefundAmount, item.PaymentID, merchantID,
+			)
+			if err != nil {
+				return err
+			}
+			if n, _ := result.RowsAffected(); n == 0 {
+				return sql.ErrNoRows
+			}
+		}
+		return nil
+	})
+}
+
+func (r *settlementRepo) FetchWithQueryParams(ctx context.Context, params request.SettlementBatchListQueryParams) ([]entity.SettlementBatch, error) {
+	var batches []entity.SettlementBatch
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		query := "SELECT " + settlementBatchColumns + " FROM settlement_batches WHERE merchant_id = $1"
+		args := []interface{}{merchantID}
+		argIndex := 2
+
+		if params.Status != "" {
+			query += fmt.Sprintf(" AND status = $%d", argIndex)
+			args = append(args, params.Status)
+			argIndex++
+		}
+		if params.Currency != "" {
+			query += fmt.Sprintf(" AND currency = $%d", argIndex)
+			args = append(args, params.Currency)
+			argIndex++
+		}
+
+		query += " ORDER BY created_at DESC, id"
+		if params.Page > 0 && params.PerPage > 0 {
+			offset := (params.Page - 1) * params.PerPage
+			query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+			args = append(args, params.PerPage, offset)
+		}
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var b entity.SettlementBatch
+			if err := scanSettlementBatch(rows, &b); err != nil {
+				return err
+			}
+			batches = append(batches, b)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+func (r *settlementRepo) FetchByID(ctx context.Context, id uuid.UUID) (*entity.SettlementBatch, error) {
+	var b entity.SettlementBatch
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+settlementBatchColumns+" FROM settlement_batches WHERE id = $1 AND merchant_id = $2", id, merchantID)
+		return scanSettlementBatch(row, &b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *settlementRepo) FetchItems(ctx context.Context, batchID uuid.UUID) ([]entity.SettlementBatchItem, error) {
+	var items []entity.SettlementBatchItem
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx, "SELECT "+settlementBatchItemColumns+" FROM settlement_batch_items WHERE batch_id = $1 AND merchant_id = $2 ORDER BY created_at, id", batchID, merchantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var i entity.SettlementBatchItem
+			if err := scanSettlementBatchItem(rows, &i); err != nil {
+				return err
+			}
+			items = append(items, i)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ModifyStatus moves a batch from status from to status to, stamping when it was sent or
+// confirmed. It returns sql.ErrNoRows when the batch is no longer in status from.
+func (r *settlementRepo) ModifyStatus(ctx context.Context, id uuid.UUID, from, to string) (*entity.SettlementBatch, error) {
+	var b entity.SettlementBatch
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE settlement_batches
+			SET status = $1,
+			    sent_at = CASE WHEN $1::text = $5::text THEN NOW() ELSE sent_at END,
+			    confirmed_at = CASE WHEN $1::text = $6::text THEN NOW() ELSE confirmed_at END,
+			    updated_at = NOW()
+			WHERE id = $2 AND merchant_id = $3 AND status = $4
+			RETURNING `+settlementBatchColumns,
+			to, id, merchantID, from, entity.SettlementBatchStatusSent, entity.SettlementBatchStatusConfirmed,
+		)
+		return scanSettlementBatch(row, &b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
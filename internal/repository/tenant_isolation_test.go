@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// These tests run against the migrated database in TEST_DATABASE_URL and are skipped
+// without one. Owners, superusers and BYPASSRLS roles are exempt from row level security:
+// run them once as the table owner to check the merchant filter alone, and once as the
+// role the API connects as to check the policies.
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("ping database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// rowLevelSecurityApplies reports whether the policies bind the current role.
+func rowLevelSecurityApplies(t *testing.T, db *sql.DB) bool {
+	t.Helper()
+	var exempt bool
+	err := db.QueryRow(`
+		SELECT r.rolsuper OR r.rolbypassrls OR pg_has_role(current_user, t.tableowner, 'MEMBER')
+		FROM pg_roles r, pg_tables t
+		WHERE r.rolname = current_user AND t.schemaname = 'public' AND t.tablename = 'payments'`,
+	).Scan(&exempt)
+	if err != nil {
+		t.Fatalf("read role attributes: %v", err)
+	}
+	return !exempt
+}
+
+type tenantFixture struct {
+	ctx        context.Context
+	merchantID uuid.UUID
+	payment    entity.Payment
+	customer   entity.Customer
+}
+
+// newTenant creates a merchant with one customer and one payment, removed after the test.
+func newTenant(t *testing.T, db *sql.DB, payments PaymentRepository, customers CustomerRepository) *tenantFixture {
+	t.Helper()
+	var merchantID uuid.UUID
+	if err := db.QueryRow("INSERT INTO merchants (name) VALUES ($1) RETURNING id", "Isolation "+t.Name()).Scan(&merchantID); err != nil {
+		t.Fatalf("store merchant: %v", err)
+	}
+	f := &tenantFixture{ctx: tenant.WithMerchantID(context.Background(), merchantID), merchantID: merchantID}
+	t.Cleanup(func() {
+		scope := tenantScope{db: db, rowLevelSecurity: true}
+		err := scope.run(f.ctx, func(q querier, merchantID uuid.UUID) error {
+			if _, err := q.ExecContext(f.ctx, "DELETE FROM payments WHERE merchant_id = $1", merchantID); err != nil {
+				return err
+			}
+			_, err := q.ExecContext(f.ctx, "DELETE FROM customers WHERE merchant_id = $1", merchantID)
+			return err
+		})
+		if err == nil {
+			_, err = db.Exec("DELETE FROM merchants WHERE id = $1", merchantID)
+		}
+		if err != nil {
+			t.Errorf("remove merchant %s: %v", merchantID, err)
+		}
+	})
+
+	f.customer = entity.Customer{Name: "Isolated Customer", Email: "isolated@example.com"}
+	if err := customers.Store(f.ctx, &f.customer); err != nil {
+		t.Fatalf("store customer: %v", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback()
+	f.payment = entity.Payment{
+		CustomerID:  &f.customer.ID,
+		Tag:         "isolation",
+		Description: "isolated payment",
+		Amount:      valueobject.BigFloat{Float: big.NewFloat(10)},
+		Currency:    "IDR",
+		Method:      entity.PaymentMethodCard,
+		Status:      entity.PaymentStatusPending,
+	}
+	if err := payments.Store(f.ctx, tx, &f.payment); err != nil {
+		t.Fatalf("store payment: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	return f
+}
+
+func TestTenantIsolation(t *testing.T) {
+	db := openTestDB(t)
+	enforced := rowLevelSecurityApplies(t, db)
+
+	for _, tc := range []struct {
+		name             string
+		rowLevelSecurity bool
+	}{
+		{name: "filter", rowLevelSecurity: false},
+		{name: "filter and row level security", rowLevelSecurity: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if enforced && !tc.rowLevelSecurity {
+				t.Skip("the policies hide every row from queries without a merchant set")
+			}
+			payments := NewPaymentRepo(db, tc.rowLevelSecurity, nil)
+			customers := NewCustomerRepo(db, tc.rowLevelSecurity, nil)
+			a := newTenant(t, db, payments, customers)
+			b := newTenant(t, db, payments, customers)
+
+			// Merchant A addresses merchant B's rows by id
+			if _, err := payments.FetchByID(a.ctx, b.payment.ID); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("FetchByID payment of another merchant: got %v, want sql.ErrNoRows", err)
+			}
+			if _, err := payments.ModifyByID(a.ctx, b.payment.ID, &request.UpdatePaymentRequest{Status: entity.PaymentStatusFailed}); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("ModifyByID payment of another merchant: got %v, want sql.ErrNoRows", err)
+			}
+			if err := payments.Remove(a.ctx, b.payment.ID); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("Remove payment of another merchant: got %v, want sql.ErrNoRows", err)
+			}
+			if _, err := customers.FetchByID(a.ctx, b.customer.ID); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("FetchByID customer of another merchant: got %v, want sql.ErrNoRows", err)
+			}
+			if _, err := customers.ModifyByID(a.ctx, b.customer.ID, &request.CustomerRequest{Name: "Taken Over"}); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("ModifyByID customer of another merchant: got %v, want sql.ErrNoRows", err)
+			}
+			if err := customers.Remove(a.ctx, b.customer.ID); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("Remove customer of another merchant: got %v, want sql.ErrNoRows", err)
+			}
+
+			// Lists only hold the merchant's own rows, whatever the filters ask for
+			list, err := payments.FetchWithQueryParams(a.ctx, request.PaymentListQueryParams{
+				Filter:  []request.QueryFilter{{Field: "id", Value: []string{a.payment.ID.String(), b.payment.ID.String()}}},
+				Page:    1,
+				PerPage: 10,
+			})
+			if err != nil {
+				t.Fatalf("FetchWithQueryParams: %v", err)
+			}
+			if len(list) != 1 || list[0].ID != a.payment.ID {
+				t.Errorf("FetchWithQueryParams returned %d payments, want only merchant A's", len(list))
+			}
+
+			// Merchant B's rows are untouched
+			p, err := payments.FetchByID(b.ctx, b.payment.ID)
+			if err != nil {
+				t.Fatalf("FetchByID own payment: %v", err)
+			}
+			if p.Status != entity.PaymentStatusPending {
+				t.Errorf("payment of merchant B has status %s, want %s", p.Status, entity.PaymentStatusPending)
+			}
+			c, err := customers.FetchByID(b.ctx, b.customer.ID)
+			if err != nil {
+				t.Fatalf("FetchByID own customer: %v", err)
+			}
+			if c.Name != b.customer.Name {
+				t.Errorf("customer of merchant B is named %q, want %q", c.Name, b.customer.Name)
+			}
+
+			// A context without a merchant is refused rather than unscoped
+			if _, err := payments.FetchByID(context.Background(), b.payment.ID); !errors.Is(err, tenant.ErrMissingTenant) {
+				t.Errorf("FetchByID without merchant: got %v, want tenant.ErrMissingTenant", err)
+			}
+		})
+	}
+}
+
+// TestRowLevelSecurityWithoutFilter checks the policies on their own: queries that forget
+// the merchant predicate still only reach the rows of the merchant set on the transaction.
+func TestRowLevelSecurityWithoutFilter(t *testing.T) {
+	db := openTestDB(t)
+	if !rowLevelSecurityApplies(t, db) {
+		t.Skip("current role is exempt from row level security")
+	}
+
+	payments := NewPaymentRepo(db, true, nil)
+	customers := NewCustomerRepo(db, true, nil)
+	a := newTenant(t, db, payments, customers)
+	b := newTenant(t, db, payments, customers)
+	scope := tenantScope{db: db, rowLevelSecurity: true}
+
+	for _, tc := range []struct {
+		name  string
+		query string
+		id    uuid.UUID
+	}{
+		{name: "read payment", query: "SELECT id FROM payments WHERE id = $1", id: b.payment.ID},
+		{name: "update payment", query: "UPDATE payments SET status = 'FAILED' WHERE id = $1 RETURNING id", id: b.payment.ID},
+		{name: "delete payment", query: "DELETE FROM payments WHERE id = $1 RETURNING id", id: b.payment.ID},
+		{name: "read customer", query: "SELECT id FROM customers WHERE id = $1", id: b.customer.ID},
+		{name: "update customer", query: "UPDATE customers SET name = 'Taken Over' WHERE id = $1 RETURNING id", id: b.customer.ID},
+		{name: "delete customer", query: "DELETE FROM customers WHERE id = $1 RETURNING id", id: b.customer.ID},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := scope.run(a.ctx, func(q querier, _ uuid.UUID) error {
+				var id uuid.UUID
+				return q.QueryRowContext(a.ctx, tc.query, tc.id).Scan(&id)
+			})
+			if !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("got %v, want sql.ErrNoRows", err)
+			}
+		})
+	}
+
+	// Rows cannot be written into another merchant either
+	err := scope.run(a.ctx, func(q querier, _ uuid.UUID) error {
+		_, err := q.ExecContext(a.ctx, "INSERT INTO customers (merchant_id, name, email, phone) VALUES ($1, 'Planted', '', '')", b.merchantID)
+		return err
+	})
+	if err == nil {
+		t.Error("inserting a customer for another merchant succeeded")
+	}
+}
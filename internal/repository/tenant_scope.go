@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/google/uuid"
+)
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// tenantScope runs tenant scoped queries. Every query filters on the merchant from the
+// context; with rowLevelSecurity enabled the merchant is also set on the transaction so
+// the Postgres policies on payments and customers apply as a second line of defence.
+type tenantScope struct {
+	db               *sql.DB
+	rowLevelSecurity bool
+}
+
+// run calls fn with the merchant of ctx. It fails closed with tenant.ErrMissingTenant
+// when the context carries no merchant.
+func (s tenantScope) run(ctx context.Context, fn func(q querier, merchantID uuid.UUID) error) error {
+	merchantID, err := tenant.MerchantID(ctx)
+	if err != nil {
+		return err
+	}
+	if !s.rowLevelSecurity {
+		return fn(s.db, merchantID)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setLocalMerchant(ctx, tx, merchantID); err != nil {
+		return err
+	}
+	if err := fn(tx, merchantID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// runInTx is run for callers that own the transaction.
+func (s tenantScope) runInTx(ctx context.Context, tx *sql.Tx, fn func(q querier, merchantID uuid.UUID) error) error {
+	merchantID, err := tenant.MerchantID(ctx)
+	if err != nil {
+		return err
+	}
+	if s.rowLevelSecurity {
+		if err := setLocalMerchant(ctx, tx, merchantID); err != nil {
+			return err
+		}
+	}
+	return fn(tx, merchantID)
+}
+
+// setLocalMerchant is the parameterised form of SET LOCAL app.merchant_id; the setting
+// is discarded when the transaction ends so pooled connections never leak a tenant.
+func setLocalMerchant(ctx context.Context, tx *sql.Tx, merchantID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config('app.merchant_id', $1, true)", merchantID.String())
+	return err
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+type virtualAccountRepo struct {
+	DB    *sql.DB
+	scope tenantScope
+}
+
+type VirtualAccountRepository interface {
+	NextNumberSequence(ctx context.Context) (int64, error)
+	FetchByNumber(ctx context.Context, number string) (*entity.VirtualAccount, error)
+	FetchExpired(ctx context.Context, before time.Time) ([]entity.VirtualAccount, error)
+	Store(ctx context.Context, va *entity.VirtualAccount) error
+	ModifyCredited(ctx context.Context, tx *sql.Tx, va *entity.VirtualAccount, previousPaid valueobject.Decimal) error
+	ModifyStatus(ctx context.Context, tx *sql.Tx, va *entity.VirtualAccount) error
+	FetchCredits(ctx context.Context, virtualAccountID uuid.UUID) ([]entity.VirtualAccountCredit, error)
+	FetchCreditByReference(ctx context.Context, virtualAccountID uuid.UUID, reference string) (*entity.VirtualAccountCredit, error)
+	StoreCredit(ctx context.Context, tx *sql.Tx, credit *entity.VirtualAccountCredit) error
+}
+
+func NewVirtualAccountRepo(db *sql.DB, rowLevelSecurity bool) VirtualAccountRepository {
+	return &virtualAccountRepo{DB: db, scope: tenantScope{db: db, rowLevelSecurity: rowLevelSecurity}}
+}
+
+const virtualAccountColumns = "id, merchant_id, payment_id, bank, number, expected_amount, paid_amount, currency, status, expires_at, paid_at, created_at, updated_at"
+
+func scanVirtualAccount(row rowScanner, va *entity.VirtualAccount) error {
+	return row.Scan(&va.ID, &va.MerchantID, &va.PaymentID, &va.Bank, &va.Number, &va.ExpectedAmount, &va.PaidAmount, &va.Currency, &va.Status, &va.ExpiresAt, &va.PaidAt, &va.CreatedAt, &va.UpdatedAt)
+}
+
+const virtualAccountCreditColumns = "id, virtual_account_id, transfer_reference, amount, outcome, reject_reason, created_at"
+
+func scanVirtualAccountCredit(row rowScanner, c *entity.VirtualAccountCredit) error {
+	return row.Scan(&c.ID, &c.VirtualAccountID, &c.TransferReference, &c.Amount, &c.Outcome, &c.RejectReason, &c.CreatedAt)
+}
+
+// NextNumberSequence draws the next value of the global number sequence. Sequences are not
+// transactional, so a value is never handed out twice even when the insert later fails.
+func (r *virtualAccountRepo) NextNumberSequence(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.DB.QueryRowContext(ctx, "SELECT nextval('virtual_account_number_seq')").Scan(&seq)
+	return seq, err
+}
+
+func (r *virtualAccountRepo) FetchByNumber(ctx context.Context, number string) (*entity.VirtualAccount, error) {
+	var va entity.VirtualAccount
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+virtualAccountColumns+" FROM virtual_accounts WHERE number = $1 AND merchant_id = $2", number, merchantID)
+		return scanVirtualAccount(row, &va)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &va, nil
+}
+
+// FetchExpired returns the active accounts of the current merchant that expired before before.
+func (r *virtualAccountRepo) FetchExpired(ctx context.Context, before time.Time) ([]entity.VirtualAccount, error) {
+	var accounts []entity.VirtualAccount
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx, `
+			SELECT `+virtualAccountColumns+` FROM virtual_accounts
+			WHERE merchant_id = $1 AND status = $2 AND expires_at < $3
+			ORDER BY expires_at`,
+			merchantID, entity.VirtualAccountStatusActive, before.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var va entity.VirtualAccount
+			if err := scanVirtualAccount(rows, &va); err != nil {
+				return err
+			}
+			accounts = append(accounts, va)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *virtualAccountRepo) Store(ctx context.Context, va *entity.VirtualAccount) error {
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO virtual_accounts (merchant_id, payment_id, bank, number, expected_amount, currency, status, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING `+virtualAccountColumns,
+			merchantID, va.PaymentID, va.Bank, va.Number, va.ExpectedAmount, va.Currency, entity.VirtualAccountStatusActive, va.ExpiresAt.UTC(),
+		)
+		return scanVirtualAccount(row, va)
+	})
+	if isUniqueViolation(err) {
+		return entity.ErrVirtualAccountConflict
+	}
+	return err
+}
+
+// ModifyCredited writes the paid amount and status of an active account. It returns
+// entity.ErrVirtualAccountChanged when another transfer was applied since previousPaid was read.
+func (r *virtualAccountRepo) ModifyCredited(ctx context.Context, tx *sql.Tx, va *entity.VirtualAccount, previousPaid valueobject.Decimal) error {
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE virtual_accounts
+			SET paid_amount = $1, status = $2, paid_at = $3, updated_at = NOW()
+			WHERE id = $4 AND merchant_id = $5 AND status = $6 AND paid_amount = $7
+			RETURNING `+virtualAccountColumns,
+			va.PaidAmount, va.Status, va.PaidAt, va.ID, merchantID, entity.VirtualAccountStatusActive, previousPaid,
+		)
+		return scanVirtualAccount(row, va)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrVirtualAccountChanged
+	}
+	return err
+}
+
+// ModifyStatus closes an active account with va.Status, returning
+// entity.ErrVirtualAccountChanged when it is no longer active.
+func (r *virtualAccountRepo) ModifyStatus(ctx context.Context, tx *sql.Tx, va *entity.VirtualAccount) error {
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			UPDATE virtual_accounts
+			SET status = $1, updated_at = NOW()
+			WHERE id = $2 AND merchant_id = $3 AND status = $4
+			RETURNING `+virtualAccountColumns,
+			va.Status, va.ID, merchantID, entity.VirtualAccountStatusActive,
+		)
+		return scanVirtualAccount(row, va)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrVirtualAccountChanged
+	}
+	return err
+}
+
+func (r *virtualAccountRepo) FetchCredits(ctx context.Context, virtualAccountID uuid.UUID) ([]entity.VirtualAccountCredit, error) {
+	var credits []entity.VirtualAccountCredit
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		rows, err := q.QueryContext(ctx, "SELECT "+virtualAccountCreditColumns+" FROM virtual_account_credits WHERE virtual_account_id = $1 AND merchant_id = $2 ORDER BY created_at", virtualAccountID, merchantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c entity.VirtualAccountCredit
+			if err := scanVirtualAccountCredit(rows, &c); err != nil {
+				return err
+			}
+			credits = append(credits, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+func (r *virtualAccountRepo) FetchCreditByReference(ctx context.Context, virtualAccountID uuid.UUID, reference string) (*entity.VirtualAccountCredit, error) {
+	var c entity.VirtualAccountCredit
+	err := r.scope.run(ctx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, "SELECT "+virtualAccountCreditColumns+" FROM virtual_account_credits WHERE virtual_account_id = $1 AND transfer_reference = $2 AND merchant_id = $3", virtualAccountID, reference, merchantID)
+		return scanVirtualAccountCredit(row, &c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// StoreCredit records a transfer. A replayed transfer reference fails with
+// entity.ErrVirtualAccountChanged so the caller re-reads the recorded outcome.
+func (r *virtualAccountRepo) StoreCredit(ctx context.Context, tx *sql.Tx, c *entity.VirtualAccountCredit) error {
+	err := r.scope.runInTx(ctx, tx, func(q querier, merchantID uuid.UUID) error {
+		row := q.QueryRowContext(ctx, `
+			INSERT INTO virtual_account_credits (merchant_id, virtual_account_id, transfer_reference, amount, outcome, reject_reason)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+virtualAccountCreditColumns,
+			merchantID, c.VirtualAccountID, c.TransferReference, c.Amount, c.Outcome, c.RejectReason,
+		)
+		return scanVirtualAccountCredit(row, c)
+	})
+	if isUniqueViolation(err) {
+		return entity.ErrVirtualAccountChanged
+	}
+	return err
+}
@@ -0,0 +1,185 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+// History answers the questions rules ask about earlier activity of the merchant.
+type History interface {
+	CountPayments(ctx context.Context, key, value string, since time.Time) (int, error)
+	CustomerCreatedAt(ctx context.Context, customerID uuid.UUID) (time.Time, error)
+}
+
+// Input is the payment being scored.
+type Input struct {
+	Tag        string
+	CustomerID *uuid.UUID
+	ClientIP   string
+	Amount     valueobject.Decimal
+	Currency   string
+}
+
+// Engine evaluates payments against the current rule set, which can be replaced while
+// payments are being scored.
+type Engine struct {
+	history History
+	rules   atomic.Pointer[RuleSet]
+}
+
+// NewEngine returns an engine without rules; it allows every payment until rules are loaded.
+func NewEngine(history History) *Engine {
+	e := &Engine{history: history}
+	e.rules.Store(&RuleSet{})
+	return e
+}
+
+// Load replaces the rule set. Evaluations already running finish with the previous one.
+func (e *Engine) Load(rs *RuleSet) {
+	e.rules.Store(rs)
+}
+
+// Evaluate scores a payment. The decision takes the most severe action of the rules that
+// matched, escalated further when their total score reaches a threshold of the rule set.
+func (e *Engine) Evaluate(ctx context.Context, in Input) (entity.RiskDecision, error) {
+	rs := e.rules.Load()
+	now := time.Now()
+	decision := entity.RiskDecision{
+		Action:     entity.RiskActionAllow,
+		Reasons:    []entity.RiskReason{},
+		Tag:        in.Tag,
+		CustomerID: in.CustomerID,
+		ClientIP:   in.ClientIP,
+		Amount:     in.Amount,
+		Currency:   in.Currency,
+	}
+
+	for i := range rs.Rules {
+		rule := &rs.Rules[i]
+		matched, err := e.match(ctx, rule, in, now)
+		if err != nil {
+			return entity.RiskDecision{}, err
+		}
+		if !matched {
+			continue
+		}
+		decision.Reasons = append(decision.Reasons, entity.RiskReason{Rule: rule.Name, Code: rule.ReasonCode, Action: rule.Action, Score: rule.Score})
+		decision.Score += rule.Score
+		if entity.RiskSeverity(rule.Action) > entity.RiskSeverity(decision.Action) {
+			decision.Action = rule.Action
+			decision.ReasonCode = rule.ReasonCode
+		}
+	}
+
+	escalate := func(action string, threshold int) {
+		if threshold > 0 && decision.Score >= threshold && entity.RiskSeverity(action) > entity.RiskSeverity(decision.Action) {
+			decision.Action = action
+			decision.ReasonCode = entity.RiskReasonScore
+		}
+	}
+	escalate(entity.RiskActionDeny, rs.DenyScore)
+	escalate(entity.RiskActionReview, rs.ReviewScore)
+	return decision, nil
+}
+
+func (e *Engine) match(ctx context.Context, rule *Rule, in Input, now time.Time) (bool, error) {
+	switch rule.Type {
+	case RuleVelocity:
+		value := keyValue(rule.Key, in)
+		if value == "" {
+			return false, nil
+		}
+		count, err := e.history.CountPayments(ctx, rule.Key, value, now.Add(-rule.window))
+		if err != nil {
+			return false, err
+		}
+		return count >= rule.Limit, nil
+
+	case RuleAmount:
+		if rule.Currency != "" && rule.Currency != in.Currency {
+			return false, nil
+		}
+		if !rule.MinAmount.IsNull() && in.Amount.Cmp(rule.MinAmount) < 0 {
+			return false, nil
+		}
+		if !rule.MaxAmount.IsNull() && in.Amount.Cmp(rule.MaxAmount) >= 0 {
+			return false, nil
+		}
+		return true, nil
+
+	case RuleBlocklist:
+		value := keyValue(rule.Key, in)
+		if value == "" {
+			return false, nil
+		}
+		if rule.values[value] {
+			return true, nil
+		}
+		if rule.Key == KeyIP && len(rule.prefixes) > 0 {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return false, nil
+			}
+			for _, p := range rule.prefixes {
+				if p.Contains(addr.Unmap()) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+
+	case RuleNewCustomer:
+		if in.CustomerID == nil {
+			return false, nil
+		}
+		createdAt, err := e.history.CustomerCreatedAt(ctx, *in.CustomerID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Payment creation reports the missing customer itself
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if now.Sub(createdAt) >= rule.maxAge {
+			return false, nil
+		}
+		if rule.AmountLimit.IsNull() && rule.PaymentLimit == 0 {
+			return true, nil
+		}
+		if !rule.AmountLimit.IsNull() && in.Amount.Cmp(rule.AmountLimit) > 0 {
+			return true, nil
+		}
+		if rule.PaymentLimit > 0 {
+			count, err := e.history.CountPayments(ctx, KeyCustomer, in.CustomerID.String(), createdAt)
+			if err != nil {
+				return false, err
+			}
+			return count >= rule.PaymentLimit, nil
+		}
+		return false, nil
+	}
+	return false, nil
+}
+
+func keyValue(key string, in Input) string {
+	switch key {
+	case KeyTag:
+		return in.Tag
+	case KeyCustomer:
+		if in.CustomerID != nil {
+			return strings.ToLower(in.CustomerID.String())
+		}
+	case KeyIP:
+		return in.ClientIP
+	}
+	return ""
+}
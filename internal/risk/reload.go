@@ -0,0 +1,42 @@
+package risk
+
+import (
+	"os"
+	"time"
+)
+
+// FileLoader loads a rules file into an engine and reloads it whenever the file changes.
+type FileLoader struct {
+	path    string
+	engine  *Engine
+	modTime time.Time
+	size    int64
+}
+
+func NewFileLoader(path string, engine *Engine) *FileLoader {
+	return &FileLoader{path: path, engine: engine}
+}
+
+// Reload loads the file if it changed since it was last read and reports whether it did.
+// A file that fails to parse leaves the current rules in place and is not retried until
+// it changes again.
+func (l *FileLoader) Reload() (bool, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(l.modTime) && info.Size() == l.size {
+		return false, nil
+	}
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return false, err
+	}
+	l.modTime, l.size = info.ModTime(), info.Size()
+	rs, err := ParseRules(data)
+	if err != nil {
+		return false, err
+	}
+	l.engine.Load(rs)
+	return true, nil
+}
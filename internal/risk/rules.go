@@ -0,0 +1,172 @@
+// Package risk scores payments against configurable rules before they are created.
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+const (
+	// RuleVelocity matches when Limit payments sharing Key were created within Window.
+	RuleVelocity = "VELOCITY"
+	// RuleAmount matches amounts in [MinAmount, MaxAmount), optionally of one Currency.
+	RuleAmount = "AMOUNT"
+	// RuleBlocklist matches when the Key value is in Values; IP values may be CIDR ranges.
+	RuleBlocklist = "BLOCKLIST"
+	// RuleNewCustomer matches customers created within MaxAge that pay more than
+	// AmountLimit or already made PaymentLimit payments; without limits it matches any.
+	RuleNewCustomer = "NEW_CUSTOMER"
+)
+
+const (
+	KeyTag      = "TAG"
+	KeyCustomer = "CUSTOMER"
+	KeyIP       = "IP"
+)
+
+// Rule is one entry of the rules file. Fields apply depending on Type.
+type Rule struct {
+	Name         string              `json:"name"`
+	Type         string              `json:"type"`
+	Action       string              `json:"action"`
+	Score        int                 `json:"score"`
+	ReasonCode   string              `json:"reason_code"`
+	Key          string              `json:"key"`
+	Window       string              `json:"window"`
+	Limit        int                 `json:"limit"`
+	Currency     string              `json:"currency"`
+	MinAmount    valueobject.Decimal `json:"min_amount"`
+	MaxAmount    valueobject.Decimal `json:"max_amount"`
+	Values       []string            `json:"values"`
+	MaxAge       string              `json:"max_age"`
+	AmountLimit  valueobject.Decimal `json:"amount_limit"`
+	PaymentLimit int                 `json:"payment_limit"`
+
+	window   time.Duration
+	maxAge   time.Duration
+	values   map[string]bool
+	prefixes []netip.Prefix
+}
+
+// RuleSet is a parsed rules file. A payment is reviewed once the scores of the rules it
+// matches add up to ReviewScore and denied at DenyScore; zero disables a threshold.
+type RuleSet struct {
+	ReviewScore int    `json:"review_score"`
+	DenyScore   int    `json:"deny_score"`
+	Rules       []Rule `json:"rules"`
+}
+
+// ParseRules parses and validates a JSON rules file.
+func ParseRules(data []byte) (*RuleSet, error) {
+	var rs RuleSet
+	if err := json.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("invalid risk rules: %w", err)
+	}
+	if rs.ReviewScore < 0 || rs.DenyScore < 0 {
+		return nil, fmt.Errorf("risk score thresholds must not be negative")
+	}
+	names := map[string]bool{}
+	for i := range rs.Rules {
+		rule := &rs.Rules[i]
+		if err := rule.prepare(); err != nil {
+			return nil, fmt.Errorf("risk rule %d (%s): %w", i+1, rule.Name, err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate risk rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	return &rs, nil
+}
+
+func (r *Rule) prepare() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Type = strings.ToUpper(strings.TrimSpace(r.Type))
+	r.Action = strings.ToUpper(strings.TrimSpace(r.Action))
+	r.Key = strings.ToUpper(strings.TrimSpace(r.Key))
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if r.Action != entity.RiskActionAllow && r.Action != entity.RiskActionReview && r.Action != entity.RiskActionDeny {
+		return fmt.Errorf("action must be ALLOW, REVIEW or DENY")
+	}
+	if r.Score < 0 {
+		return fmt.Errorf("score must not be negative")
+	}
+	if r.ReasonCode == "" {
+		r.ReasonCode = strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(r.Name))
+	}
+
+	switch r.Type {
+	case RuleVelocity:
+		if err := r.validateKey(); err != nil {
+			return err
+		}
+		window, err := time.ParseDuration(r.Window)
+		if err != nil || window <= 0 {
+			return fmt.Errorf("window must be a positive duration")
+		}
+		r.window = window
+		if r.Limit <= 0 {
+			return fmt.Errorf("limit must be greater than zero")
+		}
+	case RuleAmount:
+		if r.MinAmount.IsNull() && r.MaxAmount.IsNull() {
+			return fmt.Errorf("min_amount or max_amount is required")
+		}
+		if !r.MinAmount.IsNull() && !r.MaxAmount.IsNull() && r.MinAmount.Cmp(r.MaxAmount) >= 0 {
+			return fmt.Errorf("min_amount must be less than max_amount")
+		}
+	case RuleBlocklist:
+		if err := r.validateKey(); err != nil {
+			return err
+		}
+		if len(r.Values) == 0 {
+			return fmt.Errorf("values must not be empty")
+		}
+		r.values = map[string]bool{}
+		for _, v := range r.Values {
+			v = strings.TrimSpace(v)
+			if r.Key == KeyIP && strings.Contains(v, "/") {
+				prefix, err := netip.ParsePrefix(v)
+				if err != nil {
+					return fmt.Errorf("invalid CIDR %q", v)
+				}
+				r.prefixes = append(r.prefixes, prefix.Masked())
+				continue
+			}
+			if r.Key == KeyCustomer {
+				v = strings.ToLower(v)
+			}
+			r.values[v] = true
+		}
+	case RuleNewCustomer:
+		maxAge, err := time.ParseDuration(r.MaxAge)
+		if err != nil || maxAge <= 0 {
+			return fmt.Errorf("max_age must be a positive duration")
+		}
+		r.maxAge = maxAge
+		if r.PaymentLimit < 0 || (!r.AmountLimit.IsNull() && r.AmountLimit.Sign() < 0) {
+			return fmt.Errorf("limits must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+	return nil
+}
+
+func (r *Rule) validateKey() error {
+	switch r.Key {
+	case KeyTag, KeyCustomer, KeyIP:
+		return nil
+	default:
+		return fmt.Errorf("key must be TAG, CUSTOMER or IP")
+	}
+}
@@ -0,0 +1,82 @@
+// Package settlement groups captured payments into payout batches and renders the payout
+// instruction files sent to the bank.
+package settlement
+
+import (
+	"sort"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// Build groups the candidates of one merchant into a batch per currency. A captured
+// payment contributes its amount less fees and refunds so far; a payment that was already
+// paid out contributes only the refunds and chargebacks made since, each as a negative
+// adjustment. Currencies whose net is not positive get no batch, so their payments stay
+// unsettled and carry over to a later run.
+func Build(candidates []entity.SettlementCandidate) []entity.SettlementBatch {
+	zero := valueobject.DecimalFromInt(0)
+	byCurrency := map[string]*entity.SettlementBatch{}
+	for _, c := range candidates {
+		var items []entity.SettlementBatchItem
+		if !c.Settled {
+			items = append(items, entity.SettlementBatchItem{
+				PaymentID:    c.PaymentID,
+				Type:         entity.SettlementItemPayment,
+				GrossAmount:  c.Amount,
+				RefundAmount: c.RefundedAmount,
+				FeeAmount:    c.Fees,
+			})
+		} else {
+			if refunded := c.RefundedAmount.Sub(c.SettledRefunded); refunded.Sign() > 0 {
+				items = append(items, adjustment(c, entity.SettlementItemRefundAdjustment, refunded))
+			}
+			if c.Reversed.Sign() > 0 {
+				items = append(items, adjustment(c, entity.SettlementItemChargebackAdjustment, c.Reversed))
+			}
+		}
+
+		batch, ok := byCurrency[c.Currency]
+		if !ok && len(items) > 0 {
+			batch = &entity.SettlementBatch{
+				Currency:     c.Currency,
+				Status:       entity.SettlementBatchStatusGenerated,
+				GrossAmount:  zero,
+				RefundAmount: zero,
+				FeeAmount:    zero,
+				NetAmount:    zero,
+			}
+			byCurrency[c.Currency] = batch
+		}
+		for _, item := range items {
+			item.NetAmount = item.GrossAmount.Sub(item.RefundAmount).Sub(item.FeeAmount)
+			batch.Items = append(batch.Items, item)
+			batch.ItemCount++
+			batch.GrossAmount = batch.GrossAmount.Add(item.GrossAmount)
+			batch.RefundAmount = batch.RefundAmount.Add(item.RefundAmount)
+			batch.FeeAmount = batch.FeeAmount.Add(item.FeeAmount)
+			batch.NetAmount = batch.NetAmount.Add(item.NetAmount)
+		}
+	}
+
+	batches := make([]entity.SettlementBatch, 0, len(byCurrency))
+	for _, batch := range byCurrency {
+		if batch.NetAmount.Sign() > 0 {
+			batches = append(batches, *batch)
+		}
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].Currency < batches[j].Currency })
+	return batches
+}
+
+// adjustment deducts amount from a payment that was already paid out.
+func adjustment(c entity.SettlementCandidate, itemType string, amount valueobject.Decimal) entity.SettlementBatchItem {
+	zero := valueobject.DecimalFromInt(0)
+	return entity.SettlementBatchItem{
+		PaymentID:    c.PaymentID,
+		Type:         itemType,
+		GrossAmount:  zero,
+		RefundAmount: amount,
+		FeeAmount:    zero,
+	}
+}
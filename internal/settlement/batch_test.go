@@ -0,0 +1,122 @@
+package settlement
+
+import (
+	"testing"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+func dec(t *testing.T, s string) valueobject.Decimal {
+	t.Helper()
+	d, err := valueobject.ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return d
+}
+
+// A dispute lost after payout reverses a payment that was already paid out: the next batch
+// deducts the chargeback from whatever else it pays.
+func TestBuildDeductsChargebackOfSettledPayment(t *testing.T) {
+	captured, chargedBack := uuid.New(), uuid.New()
+	batches := Build([]entity.SettlementCandidate{
+		{PaymentID: captured, Currency: "IDR", Amount: dec(t, "100000"), RefundedAmount: dec(t, "0"), SettledRefunded: dec(t, "0"), Fees: dec(t, "2000")},
+		{PaymentID: chargedBack, Currency: "IDR", Amount: dec(t, "50000"), RefundedAmount: dec(t, "0"), SettledRefunded: dec(t, "0"), Fees: dec(t, "1000"), Reversed: dec(t, "50000"), Settled: true},
+	})
+	if len(batches) != 1 {
+		t.Fatalf("got %d batches, want 1", len(batches))
+	}
+	batch := batches[0]
+	if batch.ItemCount != 2 || len(batch.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(batch.Items))
+	}
+
+	item := batch.Items[1]
+	if item.PaymentID != chargedBack || item.Type != entity.SettlementItemChargebackAdjustment {
+		t.Fatalf("second item is %s for %s, want a chargeback adjustment for %s", item.Type, item.PaymentID, chargedBack)
+	}
+	if item.NetAmount.Cmp(dec(t, "-50000")) != 0 || item.FeeAmount.Sign() != 0 || item.GrossAmount.Sign() != 0 {
+		t.Errorf("chargeback item gross %s fee %s net %s, want 0, 0, -50000", item.GrossAmount, item.FeeAmount, item.NetAmount)
+	}
+	if batch.NetAmount.Cmp(dec(t, "48000")) != 0 {
+		t.Errorf("batch net %s, want 48000", batch.NetAmount)
+	}
+	if batch.RefundAmount.Cmp(dec(t, "50000")) != 0 {
+		t.Errorf("batch refund %s, want 50000", batch.RefundAmount)
+	}
+}
+
+func TestBuildAdjustments(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate entity.SettlementCandidate
+		wantTypes []string
+		wantNet   string
+	}{
+		{
+			name:      "refund after payout",
+			candidate: entity.SettlementCandidate{Amount: dec(t, "50000"), RefundedAmount: dec(t, "20000"), SettledRefunded: dec(t, "5000"), Settled: true},
+			wantTypes: []string{entity.SettlementItemRefundAdjustment},
+			wantNet:   "-15000",
+		},
+		{
+			name:      "chargeback after payout",
+			candidate: entity.SettlementCandidate{Amount: dec(t, "50000"), RefundedAmount: dec(t, "0"), SettledRefunded: dec(t, "0"), Reversed: dec(t, "50000"), Settled: true},
+			wantTypes: []string{entity.SettlementItemChargebackAdjustment},
+			wantNet:   "-50000",
+		},
+		{
+			name:      "partial refund then chargeback of the rest",
+			candidate: entity.SettlementCandidate{Amount: dec(t, "50000"), RefundedAmount: dec(t, "10000"), SettledRefunded: dec(t, "0"), Reversed: dec(t, "40000"), Settled: true},
+			wantTypes: []string{entity.SettlementItemRefundAdjustment, entity.SettlementItemChargebackAdjustment},
+			wantNet:   "-50000",
+		},
+		{
+			name:      "chargeback after an already deducted refund",
+			candidate: entity.SettlementCandidate{Amount: dec(t, "50000"), RefundedAmount: dec(t, "10000"), SettledRefunded: dec(t, "10000"), Reversed: dec(t, "40000"), Settled: true},
+			wantTypes: []string{entity.SettlementItemChargebackAdjustment},
+			wantNet:   "-40000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A captured payment keeps the batch positive so the adjustments are paid out
+			tt.candidate.PaymentID = uuid.New()
+			tt.candidate.Currency = "IDR"
+			batches := Build([]entity.SettlementCandidate{
+				{PaymentID: uuid.New(), Currency: "IDR", Amount: dec(t, "1000000"), RefundedAmount: dec(t, "0"), SettledRefunded: dec(t, "0"), Fees: dec(t, "0")},
+				tt.candidate,
+			})
+			if len(batches) != 1 {
+				t.Fatalf("got %d batches, want 1", len(batches))
+			}
+			items := batches[0].Items[1:]
+			if len(items) != len(tt.wantTypes) {
+				t.Fatalf("got %d adjustments, want %d", len(items), len(tt.wantTypes))
+			}
+			net := valueobject.DecimalFromInt(0)
+			for i, item := range items {
+				if item.Type != tt.wantTypes[i] {
+					t.Errorf("adjustment %d is %s, want %s", i, item.Type, tt.wantTypes[i])
+				}
+				net = net.Add(item.NetAmount)
+			}
+			if net.Cmp(dec(t, tt.wantNet)) != 0 {
+				t.Errorf("adjustments net %s, want %s", net, tt.wantNet)
+			}
+		})
+	}
+}
+
+// A chargeback with nothing to deduct it from leaves the currency without a batch, so the
+// reversal stays unsettled and is deducted by a later run.
+func TestBuildCarriesOverNegativeChargeback(t *testing.T) {
+	batches := Build([]entity.SettlementCandidate{
+		{PaymentID: uuid.New(), Currency: "IDR", Amount: dec(t, "50000"), RefundedAmount: dec(t, "0"), SettledRefunded: dec(t, "0"), Reversed: dec(t, "50000"), Settled: true},
+	})
+	if len(batches) != 0 {
+		t.Fatalf("got %d batches, want none", len(batches))
+	}
+}
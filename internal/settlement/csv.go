@@ -0,0 +1,98 @@
+package settlement
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// DefaultCSVColumns is the column layout used when none is configured.
+const DefaultCSVColumns = "reference,beneficiary_name,account_number,bank_code,currency,amount,execution_date"
+
+// csvColumns renders every column a payout CSV may contain.
+var csvColumns = map[string]func(batch entity.SettlementBatch, now time.Time) string{
+	"batch_id":         func(b entity.SettlementBatch, _ time.Time) string { return b.ID.String() },
+	"merchant_id":      func(b entity.SettlementBatch, _ time.Time) string { return b.MerchantID.String() },
+	"reference":        func(b entity.SettlementBatch, _ time.Time) string { return compactID(b) },
+	"beneficiary_name": func(b entity.SettlementBatch, _ time.Time) string { return b.PayoutAccount.Name },
+	"account_number":   func(b entity.SettlementBatch, _ time.Time) string { return b.PayoutAccount.Number },
+	"bank_code":        func(b entity.SettlementBatch, _ time.Time) string { return b.PayoutAccount.BankCode },
+	"currency":         func(b entity.SettlementBatch, _ time.Time) string { return b.Currency },
+	"amount":           func(b entity.SettlementBatch, _ time.Time) string { return fixed(b.NetAmount) },
+	"gross_amount":     func(b entity.SettlementBatch, _ time.Time) string { return fixed(b.GrossAmount) },
+	"refund_amount":    func(b entity.SettlementBatch, _ time.Time) string { return fixed(b.RefundAmount) },
+	"fee_amount":       func(b entity.SettlementBatch, _ time.Time) string { return fixed(b.FeeAmount) },
+	"item_count":       func(b entity.SettlementBatch, _ time.Time) string { return strconv.Itoa(b.ItemCount) },
+	"cutoff_date":      func(b entity.SettlementBatch, _ time.Time) string { return b.CutoffAt.UTC().Format(time.DateOnly) },
+	"execution_date":   func(_ entity.SettlementBatch, now time.Time) string { return now.Format(time.DateOnly) },
+}
+
+// CSVFormat is the layout of payout CSV files, which differs from bank to bank.
+type CSVFormat struct {
+	Columns   []string
+	Delimiter rune
+	Header    bool
+}
+
+// ParseCSVFormat validates a comma separated column list, a single character delimiter
+// and whether a header row is written.
+func ParseCSVFormat(columns, delimiter string, header bool) (CSVFormat, error) {
+	f := CSVFormat{Header: header}
+	for _, c := range strings.Split(columns, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if _, ok := csvColumns[c]; !ok {
+			return CSVFormat{}, fmt.Errorf("unknown payout csv column %q", c)
+		}
+		f.Columns = append(f.Columns, c)
+	}
+
+	if delimiter == `\t` {
+		delimiter = "\t"
+	}
+	r, size := utf8.DecodeRuneInString(delimiter)
+	if size == 0 || size != len(delimiter) || r == '"' || r == '\r' || r == '\n' {
+		return CSVFormat{}, fmt.Errorf("invalid payout csv delimiter %q", delimiter)
+	}
+	f.Delimiter = r
+	return f, nil
+}
+
+// Write writes the payout of a batch as one CSV row.
+func (f CSVFormat) Write(w io.Writer, batch entity.SettlementBatch, now time.Time) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = f.Delimiter
+	if f.Header {
+		if err := cw.Write(f.Columns); err != nil {
+			return err
+		}
+	}
+	row := make([]string, len(f.Columns))
+	for i, c := range f.Columns {
+		row[i] = sanitizeCell(csvColumns[c](batch, now))
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// sanitizeCell keeps spreadsheet software from evaluating merchant supplied names as
+// formulas.
+func sanitizeCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func fixed(d valueobject.Decimal) string {
+	return d.StringFixed(2, valueobject.RoundHalfUp)
+}
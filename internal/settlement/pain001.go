@@ -0,0 +1,159 @@
+package settlement
+
+import (
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// Pain001Namespace is the ISO 20022 customer credit transfer initiation version written.
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+
+// bicPattern matches a BIC; other bank codes are written as clearing system member ids.
+var bicPattern = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+
+// Debtor is the account payouts are made from.
+type Debtor struct {
+	Name     string
+	Account  string
+	BankCode string
+}
+
+type pain001Document struct {
+	XMLName xml.Name        `xml:"Document"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Init    pain001Initiate `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiate struct {
+	GroupHeader pain001GroupHeader `xml:"GrpHdr"`
+	PaymentInfo pain001PaymentInfo `xml:"PmtInf"`
+}
+
+type pain001GroupHeader struct {
+	MessageID       string      `xml:"MsgId"`
+	CreatedAt       string      `xml:"CreDtTm"`
+	NumberOfTxs     int         `xml:"NbOfTxs"`
+	ControlSum      string      `xml:"CtrlSum"`
+	InitiatingParty pain001Name `xml:"InitgPty"`
+}
+
+type pain001PaymentInfo struct {
+	PaymentInfoID   string               `xml:"PmtInfId"`
+	PaymentMethod   string               `xml:"PmtMtd"`
+	NumberOfTxs     int                  `xml:"NbOfTxs"`
+	ControlSum      string               `xml:"CtrlSum"`
+	ExecutionDate   string               `xml:"ReqdExctnDt>Dt"`
+	Debtor          pain001Name          `xml:"Dbtr"`
+	DebtorAccount   pain001Account       `xml:"DbtrAcct"`
+	DebtorAgent     pain001Agent         `xml:"DbtrAgt"`
+	CreditTransfers []pain001CreditTxInf `xml:"CdtTrfTxInf"`
+}
+
+type pain001CreditTxInf struct {
+	InstructionID   string         `xml:"PmtId>InstrId"`
+	EndToEndID      string         `xml:"PmtId>EndToEndId"`
+	Amount          pain001Amount  `xml:"Amt>InstdAmt"`
+	CreditorAgent   pain001Agent   `xml:"CdtrAgt"`
+	Creditor        pain001Name    `xml:"Cdtr"`
+	CreditorAccount pain001Account `xml:"CdtrAcct"`
+	Remittance      string         `xml:"RmtInf>Ustrd"`
+}
+
+type pain001Name struct {
+	Name string `xml:"Nm"`
+}
+
+type pain001Account struct {
+	ID string `xml:"Id>Othr>Id"`
+}
+
+type pain001Agent struct {
+	BIC    string           `xml:"FinInstnId>BICFI,omitempty"`
+	Member *pain001Clearing `xml:"FinInstnId>ClrSysMmbId,omitempty"`
+}
+
+type pain001Clearing struct {
+	MemberID string `xml:"MmbId"`
+}
+
+type pain001Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// WritePain001 writes the payout of a batch as a pain.001 credit transfer initiation with
+// a single transaction. The batch ID is the message, payment information and end to end
+// ID, so a bank rejects the same batch sent twice as a duplicate.
+func WritePain001(w io.Writer, debtor Debtor, batch entity.SettlementBatch, now time.Time) error {
+	id := compactID(batch)
+	amount := batch.NetAmount.StringFixed(2, valueobject.RoundHalfUp)
+	doc := pain001Document{
+		Xmlns: Pain001Namespace,
+		Init: pain001Initiate{
+			GroupHeader: pain001GroupHeader{
+				MessageID:       id,
+				CreatedAt:       now.UTC().Format("2006-01-02T15:04:05"),
+				NumberOfTxs:     1,
+				ControlSum:      amount,
+				InitiatingParty: pain001Name{Name: truncate(debtor.Name, 140)},
+			},
+			PaymentInfo: pain001PaymentInfo{
+				PaymentInfoID: id,
+				PaymentMethod: "TRF",
+				NumberOfTxs:   1,
+				ControlSum:    amount,
+				ExecutionDate: now.Format(time.DateOnly),
+				Debtor:        pain001Name{Name: truncate(debtor.Name, 140)},
+				DebtorAccount: pain001Account{ID: truncate(debtor.Account, 34)},
+				DebtorAgent:   agent(debtor.BankCode),
+				CreditTransfers: []pain001CreditTxInf{{
+					InstructionID:   id,
+					EndToEndID:      id,
+					Amount:          pain001Amount{Currency: batch.Currency, Value: amount},
+					CreditorAgent:   agent(batch.PayoutAccount.BankCode),
+					Creditor:        pain001Name{Name: truncate(batch.PayoutAccount.Name, 140)},
+					CreditorAccount: pain001Account{ID: truncate(batch.PayoutAccount.Number, 34)},
+					Remittance:      truncate("Settlement "+batch.CutoffAt.UTC().Format(time.DateOnly)+" "+batch.ID.String(), 140),
+				}},
+			},
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func agent(bankCode string) pain001Agent {
+	code := strings.ToUpper(strings.TrimSpace(bankCode))
+	if bicPattern.MatchString(code) {
+		return pain001Agent{BIC: code}
+	}
+	return pain001Agent{Member: &pain001Clearing{MemberID: truncate(code, 35)}}
+}
+
+// compactID is the batch ID without dashes, which fits the 35 character ISO 20022 IDs.
+func compactID(batch entity.SettlementBatch) string {
+	return strings.ReplaceAll(batch.ID.String(), "-", "")
+}
+
+func truncate(s string, max int) string {
+	r := []rune(strings.TrimSpace(s))
+	if len(r) > max {
+		r = r[:max]
+	}
+	return string(r)
+}
@@ -0,0 +1,41 @@
+package settlement
+
+import (
+	"fmt"
+	"time"
+)
+
+// Schedule is the daily cutoff that closes a settlement day, in the merchants' local time.
+type Schedule struct {
+	hour     int
+	minute   int
+	location *time.Location
+}
+
+// ParseSchedule reads a cutoff in HH:MM form and an IANA time zone name.
+func ParseSchedule(cutoff, timezone string) (Schedule, error) {
+	t, err := time.Parse("15:04", cutoff)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid settlement cutoff %q, want HH:MM", cutoff)
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid settlement time zone %q: %w", timezone, err)
+	}
+	return Schedule{hour: t.Hour(), minute: t.Minute(), location: location}, nil
+}
+
+// LastCutoff returns the most recent cutoff at or before now.
+func (s Schedule) LastCutoff(now time.Time) time.Time {
+	local := now.In(s.location)
+	cutoff := time.Date(local.Year(), local.Month(), local.Day(), s.hour, s.minute, 0, 0, s.location)
+	if cutoff.After(local) {
+		cutoff = cutoff.AddDate(0, 0, -1)
+	}
+	return cutoff
+}
+
+// Local returns t in the schedule's time zone, which payout execution dates are given in.
+func (s Schedule) Local(t time.Time) time.Time {
+	return t.In(s.location)
+}
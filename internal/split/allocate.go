@@ -0,0 +1,133 @@
+package split
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+const (
+	// AmountScale is the number of fractional digits split amounts are allocated in.
+	AmountScale = 2
+	// MaxSplits bounds the number of recipients of one payment.
+	MaxSplits = 50
+
+	maxRecipientIDLength = 64
+	percentageScale      = 6
+)
+
+var (
+	hundred = valueobject.DecimalFromInt(100)
+	cent    = valueobject.Decimal{Rat: big.NewRat(1, 100)}
+)
+
+// Allocate validates the splits of a payment of total and fills in every split's Amount.
+// Fixed amounts are kept as given; percentage shares are rounded down to the cent and the
+// remaining cents go to the largest remainders, ties broken by position in the request.
+// The exact (unrounded) shares must add up to total.
+func Allocate(total valueobject.Decimal, splits []entity.PaymentSplit) error {
+	if len(splits) > MaxSplits {
+		return fmt.Errorf("%w: at most %d splits", entity.ErrInvalidSplits, MaxSplits)
+	}
+
+	seen := make(map[string]bool, len(splits))
+	exact := make([]valueobject.Decimal, len(splits))
+	sum := valueobject.DecimalFromInt(0)
+	for i := range splits {
+		s := &splits[i]
+		s.RecipientID = strings.TrimSpace(s.RecipientID)
+		if s.RecipientID == "" || len(s.RecipientID) > maxRecipientIDLength {
+			return fmt.Errorf("%w: splits[%d]: recipient_id is required and at most %d characters", entity.ErrInvalidSplits, i, maxRecipientIDLength)
+		}
+		if seen[s.RecipientID] {
+			return fmt.Errorf("%w: splits[%d]: duplicate recipient_id %q", entity.ErrInvalidSplits, i, s.RecipientID)
+		}
+		seen[s.RecipientID] = true
+
+		switch {
+		case s.Amount.IsNull() == s.Percentage.IsNull():
+			return fmt.Errorf("%w: splits[%d]: exactly one of amount or percentage is required", entity.ErrInvalidSplits, i)
+		case !s.Amount.IsNull():
+			if s.Amount.Sign() <= 0 || s.Amount.Round(AmountScale, valueobject.RoundDown).Cmp(s.Amount) != 0 {
+				return fmt.Errorf("%w: splits[%d]: amount must be positive with at most %d decimals", entity.ErrInvalidSplits, i, AmountScale)
+			}
+			exact[i] = s.Amount
+		default:
+			if s.Percentage.Sign() <= 0 || s.Percentage.Cmp(hundred) > 0 || s.Percentage.Round(percentageScale, valueobject.RoundDown).Cmp(s.Percentage) != 0 {
+				return fmt.Errorf("%w: splits[%d]: percentage must be greater than 0 and at most 100 with at most %d decimals", entity.ErrInvalidSplits, i, percentageScale)
+			}
+			exact[i] = total.Mul(s.Percentage).Quo(hundred)
+		}
+		sum = sum.Add(exact[i])
+	}
+	if sum.Cmp(total) != 0 {
+		return fmt.Errorf("%w: splits add up to %s, payment amount is %s", entity.ErrInvalidSplits, sum, total)
+	}
+
+	for i, amount := range largestRemainder(total, exact) {
+		splits[i].Amount = amount
+	}
+	return nil
+}
+
+// ProRate spreads a refund over the splits in proportion to what each split has left to
+// refund, so repeated partial refunds end with every split fully refunded. It returns
+// the new refunded amount of every split.
+func ProRate(refund valueobject.Decimal, splits []entity.PaymentSplit) []valueobject.Decimal {
+	remaining := make([]valueobject.Decimal, len(splits))
+	remainingTotal := valueobject.DecimalFromInt(0)
+	for i, s := range splits {
+		remaining[i] = s.Amount.Sub(s.RefundedAmount)
+		remainingTotal = remainingTotal.Add(remaining[i])
+	}
+
+	refunded := make([]valueobject.Decimal, len(splits))
+	if remainingTotal.Sign() <= 0 {
+		for i, s := range splits {
+			refunded[i] = s.RefundedAmount
+		}
+		return refunded
+	}
+
+	exact := make([]valueobject.Decimal, len(splits))
+	for i := range splits {
+		exact[i] = refund.Mul(remaining[i]).Quo(remainingTotal)
+	}
+	for i, share := range largestRemainder(refund, exact) {
+		refunded[i] = splits[i].RefundedAmount.Add(share)
+	}
+	return refunded
+}
+
+// largestRemainder rounds every share down to the cent and hands the cents still missing
+// from total to the shares with the largest remainders; ties go to the earlier share.
+func largestRemainder(total valueobject.Decimal, exact []valueobject.Decimal) []valueobject.Decimal {
+	allocated := make([]valueobject.Decimal, len(exact))
+	remainders := make([]valueobject.Decimal, len(exact))
+	sum := valueobject.DecimalFromInt(0)
+	for i, share := range exact {
+		allocated[i] = share.Round(AmountScale, valueobject.RoundDown)
+		remainders[i] = share.Sub(allocated[i])
+		sum = sum.Add(allocated[i])
+	}
+
+	order := make([]int, len(exact))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for _, i := range order {
+		if sum.Cmp(total) >= 0 {
+			break
+		}
+		allocated[i] = allocated[i].Add(cent)
+		sum = sum.Add(cent)
+	}
+	return allocated
+}
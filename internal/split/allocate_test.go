@@ -0,0 +1,146 @@
+package split
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+func dec(t *testing.T, s string) valueobject.Decimal {
+	t.Helper()
+	if s == "" {
+		return valueobject.Decimal{}
+	}
+	d, err := valueobject.ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return d
+}
+
+// share is a requested split: either a fixed amount or a percentage.
+type share struct {
+	amount     string
+	percentage string
+}
+
+func splitsOf(t *testing.T, shares []share) []entity.PaymentSplit {
+	t.Helper()
+	splits := make([]entity.PaymentSplit, len(shares))
+	for i, s := range shares {
+		splits[i] = entity.PaymentSplit{RecipientID: "rcpt_" + strconv.Itoa(i+1), Amount: dec(t, s.amount), Percentage: dec(t, s.percentage)}
+	}
+	return splits
+}
+
+func TestAllocateLargestRemainder(t *testing.T) {
+	thirds := []share{{percentage: "33.333333"}, {percentage: "33.333333"}, {percentage: "33.333334"}}
+	tests := []struct {
+		name   string
+		total  string
+		shares []share
+		want   []string
+	}{
+		{name: "even split", total: "100", shares: []share{{percentage: "50"}, {percentage: "50"}}, want: []string{"50", "50"}},
+		// 33.33 each leaves one cent, which goes to the largest remainder
+		{name: "thirds", total: "100", shares: thirds, want: []string{"33.33", "33.33", "33.34"}},
+		// Two cents left: the largest remainder first, then the earlier of the tied ones
+		{name: "thirds of an odd amount", total: "10.01", shares: thirds, want: []string{"3.34", "3.33", "3.34"}},
+		// Every exact share is below a cent; the two cents go to the largest remainders
+		{name: "fewer cents than recipients", total: "0.02", shares: thirds, want: []string{"0.01", "0", "0.01"}},
+		// Equal remainders: the earlier split gets the cent
+		{name: "tie goes to the earlier split", total: "0.01", shares: []share{{percentage: "50"}, {percentage: "50"}}, want: []string{"0.01", "0"}},
+		{name: "tie among many", total: "0.03", shares: []share{{percentage: "25"}, {percentage: "25"}, {percentage: "25"}, {percentage: "25"}}, want: []string{"0.01", "0.01", "0.01", "0"}},
+		{name: "fixed amounts are kept", total: "100", shares: []share{{amount: "10.01"}, {percentage: "44.995"}, {percentage: "44.995"}}, want: []string{"10.01", "45", "44.99"}},
+		{name: "single recipient", total: "123.45", shares: []share{{percentage: "100"}}, want: []string{"123.45"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splits := splitsOf(t, tt.shares)
+			if err := Allocate(dec(t, tt.total), splits); err != nil {
+				t.Fatalf("Allocate: %v", err)
+			}
+			sum := valueobject.DecimalFromInt(0)
+			for i, s := range splits {
+				if s.Amount.Cmp(dec(t, tt.want[i])) != 0 {
+					t.Errorf("splits[%d] = %s, want %s", i, s.Amount, tt.want[i])
+				}
+				sum = sum.Add(s.Amount)
+			}
+			if sum.Cmp(dec(t, tt.total)) != 0 {
+				t.Errorf("splits add up to %s, want %s", sum, tt.total)
+			}
+		})
+	}
+}
+
+func TestAllocateRejectsInvalidSplits(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  string
+		shares []share
+	}{
+		{name: "short of the total", total: "100", shares: []share{{percentage: "33.333333"}, {percentage: "33.333333"}, {percentage: "33.333333"}}},
+		{name: "over the total", total: "100", shares: []share{{amount: "60"}, {percentage: "50"}}},
+		{name: "both amount and percentage", total: "100", shares: []share{{amount: "100", percentage: "100"}}},
+		{name: "neither amount nor percentage", total: "100", shares: []share{{}}},
+		{name: "amount below a cent", total: "100", shares: []share{{amount: "99.995"}, {amount: "0.005"}}},
+		{name: "zero percentage", total: "100", shares: []share{{percentage: "100"}, {percentage: "0"}}},
+		{name: "percentage too precise", total: "100", shares: []share{{percentage: "99.9999999"}, {percentage: "0.0000001"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Allocate(dec(t, tt.total), splitsOf(t, tt.shares)); !errors.Is(err, entity.ErrInvalidSplits) {
+				t.Errorf("Allocate: got %v, want ErrInvalidSplits", err)
+			}
+		})
+	}
+
+	t.Run("duplicate recipient", func(t *testing.T) {
+		splits := splitsOf(t, []share{{percentage: "50"}, {percentage: "50"}})
+		splits[1].RecipientID = " " + splits[0].RecipientID
+		if err := Allocate(dec(t, "100"), splits); !errors.Is(err, entity.ErrInvalidSplits) {
+			t.Errorf("Allocate: got %v, want ErrInvalidSplits", err)
+		}
+	})
+}
+
+func TestProRateEndsFullyRefunded(t *testing.T) {
+	splits := splitsOf(t, []share{{amount: "33.33"}, {amount: "33.33"}, {amount: "33.34"}})
+	for i := range splits {
+		splits[i].RefundedAmount = valueobject.DecimalFromInt(0)
+	}
+
+	refunded := valueobject.DecimalFromInt(0)
+	for _, refund := range []string{"0.02", "10", "33.33", "0.01", "56.64"} {
+		before := valueobject.DecimalFromInt(0)
+		for _, s := range splits {
+			before = before.Add(s.RefundedAmount)
+		}
+		after := ProRate(dec(t, refund), splits)
+		sum := valueobject.DecimalFromInt(0)
+		for i := range splits {
+			if after[i].Cmp(splits[i].RefundedAmount) < 0 || after[i].Cmp(splits[i].Amount) > 0 {
+				t.Fatalf("refund %s: splits[%d] refunded %s, had %s of %s", refund, i, after[i], splits[i].RefundedAmount, splits[i].Amount)
+			}
+			splits[i].RefundedAmount = after[i]
+			sum = sum.Add(after[i])
+		}
+		if got := sum.Sub(before); got.Cmp(dec(t, refund)) != 0 {
+			t.Errorf("refund %s spread as %s", refund, got)
+		}
+		refunded = refunded.Add(dec(t, refund))
+	}
+
+	if refunded.Cmp(dec(t, "100")) != 0 {
+		t.Fatalf("test refunds add up to %s, want the full 100", refunded)
+	}
+	for i, s := range splits {
+		if s.RefundedAmount.Cmp(s.Amount) != 0 {
+			t.Errorf("splits[%d] refunded %s of %s", i, s.RefundedAmount, s.Amount)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/apikey"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/pkg/hmacsign"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type APIKeyUseCase interface {
+	GetAll(ctx context.Context, params request.APIKeyListQueryParams) ([]entity.APIKey, error)
+	Issue(ctx context.Context, req *request.IssueAPIKeyRequest) (*entity.IssuedAPIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) (*entity.APIKey, error)
+	Authenticate(ctx context.Context, key string) (*entity.APIKey, error)
+	AuthenticateSigned(ctx context.Context, params hmacsign.Params, stringToSign string) (*entity.APIKey, error)
+}
+
+type apiKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	cache      *apikey.Cache
+	signatures *apikey.SignatureVerifier
+	logger     zerolog.Logger
+}
+
+// NewAPIKeyUseCase manages API keys; signatures may be nil, which disables HMAC keys.
+func NewAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository, cache *apikey.Cache, signatures *apikey.SignatureVerifier, logger zerolog.Logger) APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		cache:      cache,
+		signatures: signatures,
+		logger:     logger,
+	}
+}
+
+func (uc *apiKeyUseCase) GetAll(ctx context.Context, params request.APIKeyListQueryParams) ([]entity.APIKey, error) {
+	uc.logger.Info().Str("usecase", "GetAll").Msg("⚙️ Fetching all api keys")
+	return uc.apiKeyRepo.FetchWithQueryParams(ctx, params)
+}
+
+// Issue issues a key for the merchant of ctx; an admin key can never issue keys for
+// another merchant.
+func (uc *apiKeyUseCase) Issue(ctx context.Context, req *request.IssueAPIKeyRequest) (*entity.IssuedAPIKey, error) {
+	uc.logger.Info().Str("usecase", "Issue").Msg("⚙️ Issue api key")
+	if req.Type == entity.APIKeyTypeHMAC && uc.signatures == nil {
+		return nil, entity.ErrHMACKeysDisabled
+	}
+	generated, err := apikey.Generate()
+	if err != nil {
+		return nil, err
+	}
+	key := entity.APIKey{
+		Name:      req.Name,
+		Type:      req.Type,
+		Prefix:    generated.Prefix,
+		Scopes:    req.Scopes,
+		Salt:      generated.Salt,
+		Hash:      generated.Hash,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	if err := uc.apiKeyRepo.Store(ctx, &key); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store api key")
+		return nil, err
+	}
+	uc.logger.Info().Str("api_key_id", key.ID.String()).Str("prefix", key.Prefix).Str("type", key.Type).Msg("✅ API key issued")
+	issued := &entity.IssuedAPIKey{APIKey: key, Key: generated.Key}
+	if key.Type == entity.APIKeyTypeHMAC {
+		issued.Key = uc.signatures.Secret(key.Prefix)
+	}
+	return issued, nil
+}
+
+func (uc *apiKeyUseCase) Revoke(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
+	uc.logger.Info().Str("usecase", "Revoke").Msg("⚙️ Revoke api key")
+	key, err := uc.apiKeyRepo.ModifyRevoked(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	uc.cache.Remove(key.Prefix)
+	uc.logger.Info().Str("api_key_id", key.ID.String()).Msg("✅ API key revoked")
+	return key, nil
+}
+
+// Authenticate resolves a presented bearer key. Unknown, mismatching, revoked and expired
+// keys all fail with entity.ErrInvalidAPIKey so callers cannot tell them apart.
+func (uc *apiKeyUseCase) Authenticate(ctx context.Context, presented string) (*entity.APIKey, error) {
+	prefix, secret, err := apikey.Parse(presented)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	key, cached, err := uc.lookup(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if key.Type != entity.APIKeyTypeBearer || !apikey.Matches(secret, key.Salt, key.Hash) || !key.IsActive(now) {
+		return nil, entity.ErrInvalidAPIKey
+	}
+	uc.remember(ctx, key, cached, now)
+	return &key, nil
+}
+
+// AuthenticateSigned resolves the HMAC key of a signed request and verifies its
+// timestamp, signature and nonce.
+func (uc *apiKeyUseCase) AuthenticateSigned(ctx context.Context, params hmacsign.Params, stringToSign string) (*entity.APIKey, error) {
+	if uc.signatures == nil {
+		return nil, entity.ErrInvalidAPIKey
+	}
+	now := time.Now()
+	if err := uc.signatures.CheckTimestamp(params, now); err != nil {
+		return nil, err
+	}
+	key, cached, err := uc.lookup(ctx, params.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.Type != entity.APIKeyTypeHMAC || !key.IsActive(now) {
+		return nil, entity.ErrInvalidAPIKey
+	}
+	if err := uc.signatures.Check(key.Prefix, params, stringToSign, now); err != nil {
+		return nil, err
+	}
+	uc.remember(ctx, key, cached, now)
+	return &key, nil
+}
+
+func (uc *apiKeyUseCase) lookup(ctx context.Context, prefix string) (entity.APIKey, bool, error) {
+	if key, ok := uc.cache.Get(prefix, time.Now()); ok {
+		return key, true, nil
+	}
+	stored, err := uc.apiKeyRepo.FetchByPrefix(ctx, prefix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.APIKey{}, false, entity.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return entity.APIKey{}, false, err
+	}
+	return *stored, false, nil
+}
+
+// remember caches a key that just authenticated a request. Last use is recorded when the
+// key enters the cache, so at most once per cache ttl.
+func (uc *apiKeyUseCase) remember(ctx context.Context, key entity.APIKey, cached bool, now time.Time) {
+	if cached {
+		return
+	}
+	if err := uc.apiKeyRepo.ModifyLastUsed(ctx, key.ID); err != nil {
+		uc.logger.Warn().Err(err).Str("api_key_id", key.ID.String()).Msg("⚠️ Failed to record api key use")
+	}
+	uc.cache.Put(key, now)
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type CustomerUseCase interface {
+	GetAll(ctx context.Context, params request.CustomerListQueryParams) ([]entity.Customer, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Customer, error)
+	GetPayments(ctx context.Context, id uuid.UUID, params request.PaymentListQueryParams) ([]entity.Payment, error)
+	Create(ctx context.Context, req *request.CustomerRequest) (*entity.Customer, error)
+	UpdateByID(ctx context.Context, id uuid.UUID, req *request.CustomerRequest) (*entity.Customer, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type customerUseCase struct {
+	customerRepo repository.CustomerRepository
+	paymentRepo  repository.PaymentRepository
+	db           *sql.DB
+	logger       zerolog.Logger
+}
+
+func NewCustomerUseCase(customerRepo repository.CustomerRepository, paymentRepo repository.PaymentRepository, db *sql.DB, logger zerolog.Logger) CustomerUseCase {
+	return &customerUseCase{
+		customerRepo: customerRepo,
+		paymentRepo:  paymentRepo,
+		db:           db,
+		logger:       logger,
+	}
+}
+
+func (uc *customerUseCase) GetAll(ctx context.Context, params request.CustomerListQueryParams) ([]entity.Customer, error) {
+	uc.logger.Info().Str("usecase", "GetAll").Msg("⚙️ Fetching all customers")
+	return uc.customerRepo.FetchWithQueryParams(ctx, params)
+}
+
+func (uc *customerUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Customer, error) {
+	uc.logger.Info().Str("usecase", "GetByID").Msg("⚙️ Fetching customer by ID")
+	return uc.customerRepo.FetchByID(ctx, id)
+}
+
+func (uc *customerUseCase) GetPayments(ctx context.Context, id uuid.UUID, params request.PaymentListQueryParams) ([]entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "GetPayments").Msg("⚙️ Fetching payments of customer")
+	if _, err := uc.customerRepo.FetchByID(ctx, id); err != nil {
+		return nil, err
+	}
+	params.CustomerID = &id
+	return uc.paymentRepo.FetchWithQueryParams(ctx, params)
+}
+
+func (uc *customerUseCase) Create(ctx context.Context, req *request.CustomerRequest) (*entity.Customer, error) {
+	uc.logger.Info().Str("usecase", "Create").Msg("⚙️ Store customer")
+	customer := entity.Customer{
+		Name:              req.Name,
+		Email:             req.Email,
+		Phone:             req.Phone,
+		ExternalReference: req.ExternalReference,
+	}
+	if err := uc.customerRepo.Store(ctx, &customer); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store customer")
+		return nil, err
+	}
+	uc.logger.Info().Str("customer_id", customer.ID.String()).Msg("✅ Customer created")
+	return &customer, nil
+}
+
+func (uc *customerUseCase) UpdateByID(ctx context.Context, id uuid.UUID, req *request.CustomerRequest) (*entity.Customer, error) {
+	uc.logger.Info().Str("usecase", "UpdateByID").Msg("⚙️ Modify customer")
+	return uc.customerRepo.ModifyByID(ctx, id, req)
+}
+
+func (uc *customerUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	uc.logger.Info().Str("usecase", "Delete").Msg("⚙️ Remove customer")
+	return uc.customerRepo.Remove(ctx, id)
+}
@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type DisputeUseCase interface {
+	GetAll(ctx context.Context, params request.DisputeListQueryParams) ([]entity.Dispute, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Dispute, error)
+	Open(ctx context.Context, req *request.OpenDisputeRequest) (*entity.Dispute, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, req *request.UpdateDisputeStatusRequest) (*entity.Dispute, error)
+	AddEvidence(ctx context.Context, id uuid.UUID, req *request.AddDisputeEvidenceRequest) (*entity.DisputeEvidence, error)
+	GetEvidence(ctx context.Context, id, evidenceID uuid.UUID) (*entity.DisputeEvidence, error)
+	FlagApproachingDeadlines(ctx context.Context, within time.Duration) (int, error)
+}
+
+type disputeUseCase struct {
+	disputeRepo         repository.DisputeRepository
+	paymentRepo         repository.PaymentRepository
+	paymentReversalRepo repository.PaymentReversalRepository
+	merchantRepo        repository.MerchantRepository
+	evidenceWindow      time.Duration
+	db                  *sql.DB
+	logger              zerolog.Logger
+}
+
+func NewDisputeUseCase(disputeRepo repository.DisputeRepository, paymentRepo repository.PaymentRepository, paymentReversalRepo repository.PaymentReversalRepository, merchantRepo repository.MerchantRepository, evidenceWindow time.Duration, db *sql.DB, logger zerolog.Logger) DisputeUseCase {
+	return &disputeUseCase{
+		disputeRepo:         disputeRepo,
+		paymentRepo:         paymentRepo,
+		paymentReversalRepo: paymentReversalRepo,
+		merchantRepo:        merchantRepo,
+		evidenceWindow:      evidenceWindow,
+		db:                  db,
+		logger:              logger,
+	}
+}
+
+func (uc *disputeUseCase) GetAll(ctx context.Context, params request.DisputeListQueryParams) ([]entity.Dispute, error) {
+	uc.logger.Info().Str("usecase", "GetAll").Msg("⚙️ Fetching all disputes")
+	return uc.disputeRepo.FetchWithQueryParams(ctx, params)
+}
+
+// GetByID returns a dispute with its evidence and, once lost, the reversal it posted.
+func (uc *disputeUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Dispute, error) {
+	uc.logger.Info().Str("usecase", "GetByID").Msg("⚙️ Fetching dispute by ID")
+	dispute, err := uc.disputeRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dispute.Evidence, err = uc.disputeRepo.FetchEvidence(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status == entity.DisputeStatusLost {
+		dispute.Reversal, err = uc.paymentReversalRepo.FetchByDisputeID(ctx, id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+	return dispute, nil
+}
+
+// Open records a dispute and moves its payment to DISPUTED. The amount defaults to what
+// has not been refunded yet and may not exceed it.
+func (uc *disputeUseCase) Open(ctx context.Context, req *request.OpenDisputeRequest) (*entity.Dispute, error) {
+	uc.logger.Info().Str("usecase", "Open").Msg("⚙️ Open dispute")
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	payment, err := uc.paymentRepo.FetchByIDForUpdate(ctx, tx, req.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if !entity.CanTransitionPayment(payment.Status, entity.PaymentStatusDisputed) {
+		return nil, entity.ErrInvalidStatusTransition
+	}
+
+	disputable := valueobject.DecimalFromBigFloat(payment.Amount).Sub(valueobject.DecimalFromBigFloat(payment.RefundedAmount))
+	amount := disputable
+	if !req.Amount.IsNull() {
+		amount = req.Amount
+	}
+	if amount.Cmp(disputable) > 0 {
+		return nil, entity.ErrDisputeAmountExceeded
+	}
+	dueAt := time.Now().Add(uc.evidenceWindow)
+	if req.EvidenceDueAt != nil {
+		dueAt = *req.EvidenceDueAt
+	}
+
+	dispute := &entity.Dispute{
+		PaymentID:           payment.ID,
+		Status:              entity.DisputeStatusOpened,
+		ReasonCode:          req.ReasonCode,
+		Reason:              req.Reason,
+		Amount:              amount,
+		Currency:            payment.Currency,
+		PaymentStatusBefore: payment.Status,
+		ProviderCaseID:      req.ProviderCaseID,
+		EvidenceDueAt:       dueAt,
+	}
+	if err := uc.disputeRepo.Store(ctx, tx, dispute); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store dispute")
+		return nil, err
+	}
+	if err := uc.movePayment(ctx, tx, payment, entity.PaymentStatusDisputed); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to commit transaction")
+		return nil, err
+	}
+
+	uc.logger.Info().Str("dispute_id", dispute.ID.String()).Str("payment_id", payment.ID.String()).Msg("✅ Dispute opened")
+	return dispute, nil
+}
+
+// UpdateStatus moves a dispute through its lifecycle. Submitting requires evidence before
+// the deadline; winning restores the payment's previous status and losing charges it back
+// with a reversal of the disputed amount.
+func (uc *disputeUseCase) UpdateStatus(ctx context.Context, id uuid.UUID, req *request.UpdateDisputeStatusRequest) (*entity.Dispute, error) {
+	uc.logger.Info().Str("usecase", "UpdateStatus").Msg("⚙️ Update dispute status")
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	dispute, err := uc.disputeRepo.FetchByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entity.CanTransitionDispute(dispute.Status, req.Status) {
+		return nil, entity.ErrInvalidDisputeTransition
+	}
+
+	now := time.Now()
+	var reversal *entity.PaymentReversal
+	switch req.Status {
+	case entity.DisputeStatusEvidenceRequired:
+		if req.EvidenceDueAt != nil {
+			dispute.EvidenceDueAt = *req.EvidenceDueAt
+			dispute.DeadlineFlaggedAt = nil
+		}
+	case entity.DisputeStatusSubmitted:
+		if now.After(dispute.EvidenceDueAt) {
+			return nil, entity.ErrDisputeDeadlinePassed
+		}
+		count, err := uc.disputeRepo.CountEvidence(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, entity.ErrDisputeEvidenceRequired
+		}
+		dispute.SubmittedAt = &now
+	case entity.DisputeStatusWon, entity.DisputeStatusLost:
+		payment, err := uc.paymentRepo.FetchByIDForUpdate(ctx, tx, dispute.PaymentID)
+		if err != nil {
+			return nil, err
+		}
+		target := dispute.PaymentStatusBefore
+		if req.Status == entity.DisputeStatusLost {
+			target = entity.PaymentStatusChargedBack
+			reversal = &entity.PaymentReversal{
+				PaymentID: payment.ID,
+				DisputeID: &dispute.ID,
+				Amount:    dispute.Amount,
+				Currency:  dispute.Currency,
+				Reason:    "dispute lost: " + dispute.ReasonCode,
+			}
+			if err := uc.paymentReversalRepo.Store(ctx, tx, reversal); err != nil {
+				uc.logger.Error().Err(err).Msg("❌ Failed to store payment reversal")
+				return nil, err
+			}
+		}
+		if err := uc.movePayment(ctx, tx, payment, target); err != nil {
+			return nil, err
+		}
+		dispute.ResolvedAt = &now
+	}
+
+	dispute.Status = req.Status
+	if err := uc.disputeRepo.ModifyState(ctx, tx, dispute); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to update dispute")
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to commit transaction")
+		return nil, err
+	}
+
+	dispute.Reversal = reversal
+	uc.logger.Info().Str("dispute_id", dispute.ID.String()).Str("status", dispute.Status).Msg("✅ Dispute status updated")
+	return dispute, nil
+}
+
+func (uc *disputeUseCase) AddEvidence(ctx context.Context, id uuid.UUID, req *request.AddDisputeEvidenceRequest) (*entity.DisputeEvidence, error) {
+	uc.logger.Info().Str("usecase", "AddEvidence").Msg("⚙️ Add dispute evidence")
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Locking the dispute keeps evidence from landing after it was submitted
+	dispute, err := uc.disputeRepo.FetchByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !dispute.AcceptsEvidence() {
+		return nil, entity.ErrDisputeEvidenceClosed
+	}
+	if time.Now().After(dispute.EvidenceDueAt) {
+		return nil, entity.ErrDisputeDeadlinePassed
+	}
+
+	evidence := req.ToEntity(id)
+	if err := uc.disputeRepo.StoreEvidence(ctx, tx, &evidence); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store dispute evidence")
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to commit transaction")
+		return nil, err
+	}
+
+	uc.logger.Info().Str("dispute_id", id.String()).Str("evidence_id", evidence.ID.String()).Msg("✅ Dispute evidence added")
+	return &evidence, nil
+}
+
+func (uc *disputeUseCase) GetEvidence(ctx context.Context, id, evidenceID uuid.UUID) (*entity.DisputeEvidence, error) {
+	uc.logger.Info().Str("usecase", "GetEvidence").Msg("⚙️ Fetching dispute evidence")
+	return uc.disputeRepo.FetchEvidenceByID(ctx, id, evidenceID)
+}
+
+// FlagApproachingDeadlines flags, for every merchant, the disputes still waiting for
+// evidence whose deadline is less than within away. It returns how many were flagged.
+func (uc *disputeUseCase) FlagApproachingDeadlines(ctx context.Context, within time.Duration) (int, error) {
+	merchantIDs, err := uc.merchantRepo.FetchActiveIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	flagged := 0
+	dueBefore := time.Now().Add(within)
+	for _, merchantID := range merchantIDs {
+		disputes, err := uc.disputeRepo.ModifyDeadlineFlagged(tenant.WithMerchantID(ctx, merchantID), dueBefore)
+		if err != nil {
+			uc.logger.Error().Err(err).Str("merchant_id", merchantID.String()).Msg("❌ Failed to flag dispute deadlines")
+			return flagged, err
+		}
+		for _, d := range disputes {
+			uc.logger.Warn().
+				Str("dispute_id", d.ID.String()).
+				Str("merchant_id", merchantID.String()).
+				Time("evidence_due_at", d.EvidenceDueAt).
+				Msg("⏰ Dispute evidence deadline approaching")
+		}
+		flagged += len(disputes)
+	}
+	return flagged, nil
+}
+
+// movePayment changes the payment status inside tx; a concurrent change is reported as an
+// invalid transition, like the gateway flows do.
+func (uc *disputeUseCase) movePayment(ctx context.Context, tx *sql.Tx, payment *entity.Payment, target string) error {
+	if !entity.CanTransitionPayment(payment.Status, target) {
+		return entity.ErrInvalidStatusTransition
+	}
+	fromStatus := payment.Status
+	payment.Status = target
+	if err := uc.paymentRepo.ModifyGatewayStateInTx(ctx, tx, payment, fromStatus); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrInvalidStatusTransition
+		}
+		uc.logger.Error().Err(err).Msg("❌ Failed to update disputed payment")
+		return err
+	}
+	return nil
+}
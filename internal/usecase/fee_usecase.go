@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/fees"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type FeeUseCase interface {
+	GetAllRules(ctx context.Context, params request.FeeRuleListQueryParams) ([]entity.FeeRule, error)
+	CreateRule(ctx context.Context, req *request.FeeRuleRequest) (*entity.FeeRule, error)
+	UpdateRule(ctx context.Context, id uuid.UUID, req *request.FeeRuleRequest) (*entity.FeeRule, error)
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	Quote(ctx context.Context, req *request.FeeQuoteRequest) (*entity.FeeQuote, error)
+}
+
+type feeUseCase struct {
+	feeRuleRepo repository.FeeRuleRepository
+	engine      *fees.Engine
+	logger      zerolog.Logger
+}
+
+func NewFeeUseCase(feeRuleRepo repository.FeeRuleRepository, engine *fees.Engine, logger zerolog.Logger) FeeUseCase {
+	return &feeUseCase{
+		feeRuleRepo: feeRuleRepo,
+		engine:      engine,
+		logger:      logger,
+	}
+}
+
+func (uc *feeUseCase) GetAllRules(ctx context.Context, params request.FeeRuleListQueryParams) ([]entity.FeeRule, error) {
+	uc.logger.Info().Str("usecase", "GetAllRules").Msg("⚙️ Fetching all fee rules")
+	return uc.feeRuleRepo.FetchWithQueryParams(ctx, params)
+}
+
+func (uc *feeUseCase) CreateRule(ctx context.Context, req *request.FeeRuleRequest) (*entity.FeeRule, error) {
+	uc.logger.Info().Str("usecase", "CreateRule").Msg("⚙️ Store fee rule")
+	rule := req.ToEntity()
+	if err := uc.feeRuleRepo.Store(ctx, &rule); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store fee rule")
+		return nil, err
+	}
+	uc.engine.Invalidate()
+	uc.logger.Info().Str("fee_rule_id", rule.ID.String()).Msg("✅ Fee rule created")
+	return &rule, nil
+}
+
+func (uc *feeUseCase) UpdateRule(ctx context.Context, id uuid.UUID, req *request.FeeRuleRequest) (*entity.FeeRule, error) {
+	uc.logger.Info().Str("usecase", "UpdateRule").Msg("⚙️ Update fee rule")
+	rule := req.ToEntity()
+	rule.ID = id
+	if err := uc.feeRuleRepo.ModifyByID(ctx, &rule); err != nil {
+		return nil, err
+	}
+	uc.engine.Invalidate()
+	uc.logger.Info().Str("fee_rule_id", rule.ID.String()).Msg("✅ Fee rule updated")
+	return &rule, nil
+}
+
+func (uc *feeUseCase) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	uc.logger.Info().Str("usecase", "DeleteRule").Msg("⚙️ Remove fee rule")
+	if err := uc.feeRuleRepo.Remove(ctx, id); err != nil {
+		return err
+	}
+	uc.engine.Invalidate()
+	return nil
+}
+
+// Quote previews the fee the caller's merchant would be charged for a payment.
+func (uc *feeUseCase) Quote(ctx context.Context, req *request.FeeQuoteRequest) (*entity.FeeQuote, error) {
+	uc.logger.Info().Str("usecase", "Quote").Msg("⚙️ Quote fee")
+	merchantID, err := tenant.MerchantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return uc.engine.Quote(ctx, merchantID, req.Method, req.Currency, req.Amount)
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+type FXRateUseCase interface {
+	GetAll(ctx context.Context, params request.FXRateListQueryParams) ([]entity.FXRate, error)
+	Load(ctx context.Context, req *request.LoadFXRatesRequest) ([]entity.FXRate, error)
+}
+
+type fxRateUseCase struct {
+	fxRateRepo repository.FXRateRepository
+	logger     zerolog.Logger
+}
+
+func NewFXRateUseCase(fxRateRepo repository.FXRateRepository, logger zerolog.Logger) FXRateUseCase {
+	return &fxRateUseCase{
+		fxRateRepo: fxRateRepo,
+		logger:     logger,
+	}
+}
+
+func (uc *fxRateUseCase) GetAll(ctx context.Context, params request.FXRateListQueryParams) ([]entity.FXRate, error) {
+	uc.logger.Info().Str("usecase", "GetAll").Msg("⚙️ Fetching all fx rates")
+	return uc.fxRateRepo.FetchWithQueryParams(ctx, params)
+}
+
+// Load upserts a batch of rates; a rate for an existing pair and effective time replaces it.
+func (uc *fxRateUseCase) Load(ctx context.Context, req *request.LoadFXRatesRequest) ([]entity.FXRate, error) {
+	uc.logger.Info().Str("usecase", "Load").Int("count", len(req.Rates)).Msg("⚙️ Load fx rates")
+	rates := make([]entity.FXRate, 0, len(req.Rates))
+	for _, item := range req.Rates {
+		rates = append(rates, entity.FXRate{
+			BaseCurrency:  item.BaseCurrency,
+			QuoteCurrency: item.QuoteCurrency,
+			Rate:          item.Rate,
+			EffectiveAt:   item.EffectiveAt,
+			Source:        item.Source,
+		})
+	}
+	if err := uc.fxRateRepo.Store(ctx, rates); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store fx rates")
+		return nil, err
+	}
+	uc.logger.Info().Int("count", len(rates)).Msg("✅ FX rates loaded")
+	return rates, nil
+}
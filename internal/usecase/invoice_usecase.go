@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/invoice"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type InvoiceUseCase interface {
+	GetAll(ctx context.Context, params request.InvoiceListQueryParams) ([]entity.Invoice, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Invoice, error)
+	Create(ctx context.Context, req *request.InvoiceRequest) (*entity.Invoice, error)
+	Update(ctx context.Context, id uuid.UUID, req *request.InvoiceRequest) (*entity.Invoice, error)
+	Finalize(ctx context.Context, id uuid.UUID) (*entity.Invoice, error)
+	Void(ctx context.Context, id uuid.UUID) (*entity.Invoice, error)
+	CreatePayment(ctx context.Context, id uuid.UUID, req *request.CreateInvoicePaymentRequest) (*entity.Payment, error)
+}
+
+type invoiceUseCase struct {
+	invoiceRepo    repository.InvoiceRepository
+	paymentRepo    repository.PaymentRepository
+	customerRepo   repository.CustomerRepository
+	paymentUC      PaymentUseCase
+	defaultTaxRate valueobject.Decimal
+	db             *sql.DB
+	logger         zerolog.Logger
+}
+
+func NewInvoiceUseCase(invoiceRepo repository.InvoiceRepository, paymentRepo repository.PaymentRepository, customerRepo repository.CustomerRepository, paymentUC PaymentUseCase, defaultTaxRate valueobject.Decimal, db *sql.DB, logger zerolog.Logger) InvoiceUseCase {
+	return &invoiceUseCase{
+		invoiceRepo:    invoiceRepo,
+		paymentRepo:    paymentRepo,
+		customerRepo:   customerRepo,
+		paymentUC:      paymentUC,
+		defaultTaxRate: defaultTaxRate,
+		db:             db,
+		logger:         logger,
+	}
+}
+
+func (uc *invoiceUseCase) GetAll(ctx context.Context, params request.InvoiceListQueryParams) ([]entity.Invoice, error) {
+	uc.logger.Info().Str("usecase", "GetAll").Msg("⚙️ Fetching all invoices")
+	invoices, err := uc.invoiceRepo.FetchWithQueryParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range invoices {
+		invoices[i].Overdue = invoices[i].IsOverdue(now)
+	}
+	return invoices, nil
+}
+
+// GetByID returns an invoice with its items and the payments made against it.
+func (uc *invoiceUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Invoice, error) {
+	uc.logger.Info().Str("usecase", "GetByID").Msg("⚙️ Fetching invoice by ID")
+	inv, err := uc.invoiceRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Items, err = uc.invoiceRepo.FetchItems(ctx, id); err != nil {
+		return nil, err
+	}
+	if inv.Payments, err = uc.paymentRepo.FetchByInvoiceID(ctx, id); err != nil {
+		return nil, err
+	}
+	inv.Overdue = inv.IsOverdue(time.Now())
+	return inv, nil
+}
+
+// Create stores a DRAFT invoice with its totals computed from the items.
+func (uc *invoiceUseCase) Create(ctx context.Context, req *request.InvoiceRequest) (*entity.Invoice, error) {
+	uc.logger.Info().Str("usecase", "Create").Msg("⚙️ Store invoice")
+	inv, err := uc.fromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	seq, err := uc.invoiceRepo.NextNumberSequence(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inv.Number = fmt.Sprintf("INV-%08d", seq)
+
+	err = uc.inTx(ctx, inv.CustomerID, func(tx *sql.Tx) error {
+		return uc.invoiceRepo.Store(ctx, tx, inv)
+	})
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store invoice")
+		return nil, err
+	}
+	uc.logger.Info().Str("invoice_id", inv.ID.String()).Str("number", inv.Number).Msg("✅ Invoice created")
+	return inv, nil
+}
+
+// Update replaces the details and items of a DRAFT invoice.
+func (uc *invoiceUseCase) Update(ctx context.Context, id uuid.UUID, req *request.InvoiceRequest) (*entity.Invoice, error) {
+	uc.logger.Info().Str("usecase", "Update").Msg("⚙️ Update draft invoice")
+	inv, err := uc.fromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	inv.ID = id
+
+	err = uc.inTx(ctx, inv.CustomerID, func(tx *sql.Tx) error {
+		return uc.invoiceRepo.ModifyDraft(ctx, tx, inv)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, uc.notEditable(ctx, id)
+	}
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to update invoice")
+		return nil, err
+	}
+	return inv, nil
+}
+
+// Finalize issues a DRAFT invoice so payments can be made against it.
+func (uc *invoiceUseCase) Finalize(ctx context.Context, id uuid.UUID) (*entity.Invoice, error) {
+	uc.logger.Info().Str("usecase", "Finalize").Msg("⚙️ Finalize invoice")
+	inv, err := uc.invoiceRepo.ModifyFinalized(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, uc.notEditable(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc.logger.Info().Str("invoice_id", id.String()).Msg("✅ Invoice finalized")
+	return inv, nil
+}
+
+// Void cancels an invoice that has not received money and has no payment in progress.
+func (uc *invoiceUseCase) Void(ctx context.Context, id uuid.UUID) (*entity.Invoice, error) {
+	uc.logger.Info().Str("usecase", "Void").Msg("⚙️ Void invoice")
+	inv, err := uc.invoiceRepo.ModifyVoided(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing updated: tell a missing invoice apart from one that cannot be voided
+		if _, err := uc.invoiceRepo.FetchByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, entity.ErrInvoiceNotVoidable
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc.logger.Info().Str("invoice_id", id.String()).Msg("✅ Invoice voided")
+	return inv, nil
+}
+
+// CreatePayment opens a PENDING payment for part or all of what is still outstanding on an
+// invoice. Payments already in progress count against the outstanding amount, so an invoice
+// is not collected twice; the invoice status follows once the payment is captured. The
+// amount is checked again with the invoice locked in the transaction that stores the
+// payment, so concurrent requests cannot together collect more than is outstanding.
+func (uc *invoiceUseCase) CreatePayment(ctx context.Context, id uuid.UUID, req *request.CreateInvoicePaymentRequest) (*entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "CreatePayment").Msg("⚙️ Create invoice payment")
+	inv, err := uc.invoiceRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status != entity.InvoiceStatusOpen && inv.Status != entity.InvoiceStatusPartiallyPaid {
+		return nil, entity.ErrInvoiceNotPayable
+	}
+	payments, err := uc.paymentRepo.FetchByInvoiceID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	outstanding := inv.Outstanding()
+	for _, p := range payments {
+		if p.Status == entity.PaymentStatusPending || p.Status == entity.PaymentStatusAuthorized {
+			outstanding = outstanding.Sub(valueobject.DecimalFromBigFloat(p.Amount))
+		}
+	}
+	amount := req.Amount
+	if amount.IsNull() {
+		amount = outstanding
+	}
+	if outstanding.Sign() <= 0 || amount.Cmp(outstanding) > 0 {
+		return nil, entity.ErrInvoicePaymentExceeded
+	}
+
+	payment := entity.Payment{
+		CustomerID:  inv.CustomerID,
+		InvoiceID:   &inv.ID,
+		Tag:         "invoice",
+		Description: "Invoice " + inv.Number,
+		Amount:      valueobject.BigFloatFromDecimal(amount),
+		Currency:    inv.Currency,
+		Method:      req.Method,
+		Metadata:    req.Metadata,
+	}
+	return uc.paymentUC.CreateChecked(ctx, payment, func(tx *sql.Tx) error {
+		locked, err := uc.invoiceRepo.FetchByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if locked.Status != entity.InvoiceStatusOpen && locked.Status != entity.InvoiceStatusPartiallyPaid {
+			return entity.ErrInvoiceNotPayable
+		}
+		inProgress, err := uc.paymentRepo.SumInProgressByInvoiceID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if amount.Cmp(locked.Outstanding().Sub(inProgress)) > 0 {
+			uc.logger.Warn().Str("invoice_id", id.String()).Msg("⚠️ Invoice payment exceeds outstanding once locked")
+			return entity.ErrInvoicePaymentExceeded
+		}
+		return nil
+	})
+}
+
+func (uc *invoiceUseCase) fromRequest(req *request.InvoiceRequest) (*entity.Invoice, error) {
+	inv := req.ToEntity(uc.defaultTaxRate)
+	totals, err := invoice.Compute(inv.Items)
+	if err != nil {
+		return nil, err
+	}
+	inv.Subtotal = totals.Subtotal
+	inv.DiscountTotal = totals.DiscountTotal
+	inv.TaxTotal = totals.TaxTotal
+	inv.Total = totals.Total
+	return &inv, nil
+}
+
+// notEditable tells a missing invoice apart from one that is no longer a draft.
+func (uc *invoiceUseCase) notEditable(ctx context.Context, id uuid.UUID) error {
+	if _, err := uc.invoiceRepo.FetchByID(ctx, id); err != nil {
+		return err
+	}
+	return entity.ErrInvoiceNotEditable
+}
+
+// inTx runs fn in a transaction that first locks the invoice's customer, if any, so it
+// cannot be deleted while the invoice is written.
+func (uc *invoiceUseCase) inTx(ctx context.Context, customerID *uuid.UUID, fn func(tx *sql.Tx) error) error {
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if customerID != nil {
+		if err := uc.customerRepo.LockByID(ctx, tx, *customerID); err != nil {
+			return err
+		}
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// stubInvoices serves one invoice, locked or not; the other methods are never called.
+type stubInvoices struct {
+	repository.InvoiceRepository
+	invoice *entity.Invoice
+	locks   *int
+}
+
+func (s stubInvoices) FetchByID(context.Context, uuid.UUID) (*entity.Invoice, error) {
+	inv := *s.invoice
+	return &inv, nil
+}
+
+func (s stubInvoices) FetchByIDForUpdate(context.Context, *sql.Tx, uuid.UUID) (*entity.Invoice, error) {
+	*s.locks++
+	inv := *s.invoice
+	return &inv, nil
+}
+
+// stubInvoicePayments reports no payments when read without a lock and inProgress once the
+// invoice is locked, as if another request created a payment in between.
+type stubInvoicePayments struct {
+	repository.PaymentRepository
+	inProgress valueobject.Decimal
+}
+
+func (s stubInvoicePayments) FetchByInvoiceID(context.Context, uuid.UUID) ([]entity.Payment, error) {
+	return nil, nil
+}
+
+func (s stubInvoicePayments) SumInProgressByInvoiceID(context.Context, *sql.Tx, uuid.UUID) (valueobject.Decimal, error) {
+	return s.inProgress, nil
+}
+
+// stubCreator runs the check the way the creating transaction does and stores nothing.
+type stubCreator struct {
+	PaymentUseCase
+}
+
+func (stubCreator) CreateChecked(_ context.Context, payment entity.Payment, check func(tx *sql.Tx) error) (*entity.Payment, error) {
+	if err := check(nil); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func TestCreatePaymentRechecksOutstandingUnderLock(t *testing.T) {
+	amount := func(s string) valueobject.Decimal {
+		d, err := valueobject.ParseDecimal(s)
+		if err != nil {
+			t.Fatalf("parse %q: %v", s, err)
+		}
+		return d
+	}
+	invoice := &entity.Invoice{ID: uuid.New(), Number: "INV-1", Currency: "IDR", Status: entity.InvoiceStatusOpen, Total: amount("100000"), AmountPaid: amount("0")}
+
+	tests := []struct {
+		name       string
+		inProgress string
+		amount     string
+		wantErr    error
+	}{
+		{name: "fits what is left", inProgress: "60000", amount: "40000"},
+		{name: "a concurrent payment took the rest", inProgress: "60000", amount: "50000", wantErr: entity.ErrInvoicePaymentExceeded},
+		{name: "nothing left once locked", inProgress: "100000", amount: "1", wantErr: entity.ErrInvoicePaymentExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var locks int
+			uc := NewInvoiceUseCase(stubInvoices{invoice: invoice, locks: &locks}, stubInvoicePayments{inProgress: amount(tt.inProgress)}, nil, stubCreator{}, valueobject.Decimal{}, nil, zerolog.Nop())
+			_, err := uc.CreatePayment(context.Background(), invoice.ID, &request.CreateInvoicePaymentRequest{Amount: amount(tt.amount)})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreatePayment: got %v, want %v", err, tt.wantErr)
+			}
+			if locks != 1 {
+				t.Errorf("invoice locked %d times, want 1", locks)
+			}
+		})
+	}
+}
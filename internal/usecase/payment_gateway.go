@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/fx"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/provider"
+	"github.com/adf-code/beta-payment-api/internal/split"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+)
+
+func (uc *paymentUseCase) Authorize(ctx context.Context, id uuid.UUID, req *request.AuthorizePaymentRequest) (*entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "Authorize").Msg("⚙️ Authorize payment")
+	payment, err := uc.paymentRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entity.CanTransitionPayment(payment.Status, entity.PaymentStatusAuthorized) {
+		return nil, entity.ErrInvalidStatusTransition
+	}
+
+	gateway, err := uc.gateways.Route(provider.RouteRequest{
+		Currency: payment.Currency,
+		Method:   payment.Method,
+		Tag:      payment.Tag,
+	})
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to route payment to provider")
+		return nil, err
+	}
+
+	result, err := gateway.Authorize(ctx, provider.AuthorizeRequest{
+		PaymentID:  payment.ID,
+		Amount:     payment.Amount,
+		Currency:   payment.Currency,
+		Method:     payment.Method,
+		Tag:        payment.Tag,
+		CardNumber: req.CardNumber,
+	})
+	if err != nil {
+		uc.logger.Error().Err(err).Str("provider", gateway.Name()).Msg("❌ Provider authorization failed")
+		return nil, err
+	}
+
+	fromStatus := payment.Status
+	payment.Provider = gateway.Name()
+	payment.ProviderReference = result.Reference
+	payment.Status = paymentStatusFromProvider(result.Status)
+	if err := uc.modifyGatewayState(ctx, payment, fromStatus); err != nil {
+		return nil, err
+	}
+
+	if result.Status == provider.StatusDeclined {
+		uc.logger.Warn().Str("payment_id", payment.ID.String()).Str("code", result.Code).Msg("⚠️ Payment declined by provider")
+		return nil, fmt.Errorf("%w: %s", provider.ErrDeclined, result.Code)
+	}
+	uc.logger.Info().Str("payment_id", payment.ID.String()).Str("status", payment.Status).Msg("✅ Payment authorized")
+	return payment, nil
+}
+
+func (uc *paymentUseCase) Capture(ctx context.Context, id uuid.UUID) (*entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "Capture").Msg("⚙️ Capture payment")
+	payment, gateway, err := uc.fetchWithGateway(ctx, id, entity.PaymentStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the settlement rate first so a missing rate fails before money moves
+	settlement, err := uc.converter.Convert(ctx, valueobject.DecimalFromBigFloat(payment.Amount), payment.Currency, uc.settlementCurrency, time.Now())
+	if err != nil {
+		uc.logger.Error().Err(err).Str("payment_id", payment.ID.String()).Msg("❌ Failed to convert payment to settlement currency")
+		return nil, err
+	}
+	fee, err := uc.quoteFee(ctx, payment)
+	if err != nil {
+		uc.logger.Error().Err(err).Str("payment_id", payment.ID.String()).Msg("❌ Failed to calculate payment fee")
+		return nil, err
+	}
+
+	result, err := gateway.Capture(ctx, provider.CaptureRequest{
+		Reference: payment.ProviderReference,
+		Amount:    payment.Amount,
+	})
+	if err != nil {
+		uc.logger.Error().Err(err).Str("provider", gateway.Name()).Msg("❌ Provider capture failed")
+		return nil, err
+	}
+	if result.Status != provider.StatusCaptured {
+		uc.logger.Warn().Str("payment_id", payment.ID.String()).Str("code", result.Code).Msg("⚠️ Capture declined by provider")
+		return nil, fmt.Errorf("%w: %s", provider.ErrDeclined, result.Code)
+	}
+
+	fromStatus := payment.Status
+	payment.Status = entity.PaymentStatusPaid
+	applySettlement(payment, settlement)
+	if err := uc.persistState(ctx, payment, fromStatus, uc.storeFees(ctx, payment.ID, fee.Lines)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			uc.logger.Warn().Str("payment_id", payment.ID.String()).Msg("⚠️ Payment changed concurrently, state not persisted")
+			return nil, entity.ErrInvalidStatusTransition
+		}
+		uc.logger.Error().Err(err).Msg("❌ Failed to persist provider result")
+		return nil, err
+	}
+	uc.logger.Info().Str("payment_id", payment.ID.String()).Msg("✅ Payment captured")
+	return payment, nil
+}
+
+func (uc *paymentUseCase) Refund(ctx context.Context, id uuid.UUID, req *request.RefundPaymentRequest) (*entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "Refund").Msg("⚙️ Refund payment")
+	payment, gateway, err := uc.fetchWithGateway(ctx, id, entity.PaymentStatusPartiallyRefunded)
+	if err != nil {
+		return nil, err
+	}
+
+	refunded := amountOrZero(payment.RefundedAmount)
+	remaining := roundAmount(new(big.Float).Sub(amountOrZero(payment.Amount), refunded))
+	amount := remaining
+	if req.Amount.Float != nil {
+		amount = roundAmount(req.Amount.Float)
+	}
+	if amount.Sign() <= 0 || amount.Cmp(remaining) > 0 {
+		return nil, entity.ErrRefundAmountExceeded
+	}
+
+	result, err := gateway.Refund(ctx, provider.RefundRequest{
+		Reference: payment.ProviderReference,
+		Amount:    valueobject.BigFloat{Float: amount},
+	})
+	if err != nil {
+		uc.logger.Error().Err(err).Str("provider", gateway.Name()).Msg("❌ Provider refund failed")
+		return nil, err
+	}
+	if result.Status != provider.StatusRefunded {
+		uc.logger.Warn().Str("payment_id", payment.ID.String()).Str("code", result.Code).Msg("⚠️ Refund declined by provider")
+		return nil, fmt.Errorf("%w: %s", provider.ErrDeclined, result.Code)
+	}
+
+	fromStatus := payment.Status
+	payment.RefundedAmount = valueobject.BigFloat{Float: roundAmount(new(big.Float).Add(refunded, amount))}
+	payment.Status = entity.PaymentStatusPartiallyRefunded
+	if amount.Cmp(remaining) == 0 {
+		payment.Status = entity.PaymentStatusRefunded
+	}
+	refund := valueobject.DecimalFromBigFloat(valueobject.BigFloat{Float: amount})
+	if err := uc.persistState(ctx, payment, fromStatus, uc.refundSplits(ctx, payment.ID, refund)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			uc.logger.Warn().Str("payment_id", payment.ID.String()).Msg("⚠️ Payment changed concurrently, state not persisted")
+			return nil, entity.ErrInvalidStatusTransition
+		}
+		uc.logger.Error().Err(err).Msg("❌ Failed to persist provider result")
+		return nil, err
+	}
+	uc.logger.Info().Str("payment_id", payment.ID.String()).Str("status", payment.Status).Msg("✅ Payment refunded")
+	return payment, nil
+}
+
+func (uc *paymentUseCase) Void(ctx context.Context, id uuid.UUID) (*entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "Void").Msg("⚙️ Void payment")
+	payment, gateway, err := uc.fetchWithGateway(ctx, id, entity.PaymentStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := gateway.Void(ctx, provider.VoidRequest{Reference: payment.ProviderReference})
+	if err != nil {
+		uc.logger.Error().Err(err).Str("provider", gateway.Name()).Msg("❌ Provider void failed")
+		return nil, err
+	}
+	if result.Status != provider.StatusVoided {
+		uc.logger.Warn().Str("payment_id", payment.ID.String()).Str("code", result.Code).Msg("⚠️ Void declined by provider")
+		return nil, fmt.Errorf("%w: %s", provider.ErrDeclined, result.Code)
+	}
+
+	fromStatus := payment.Status
+	payment.Status = entity.PaymentStatusCancelled
+	if err := uc.modifyGatewayState(ctx, payment, fromStatus); err != nil {
+		return nil, err
+	}
+	uc.logger.Info().Str("payment_id", payment.ID.String()).Msg("✅ Payment voided")
+	return payment, nil
+}
+
+// ApplyProviderStatus moves a payment to a status reported asynchronously by its provider.
+// It is idempotent and safe against out-of-order delivery: it returns applied=false when the
+// payment already has the reported status, and ErrInvalidStatusTransition when the report is
+// stale, e.g. a late PENDING arriving after the payment was captured.
+func (uc *paymentUseCase) ApplyProviderStatus(ctx context.Context, providerName, reference string, status provider.Status) (*entity.Payment, bool, error) {
+	uc.logger.Info().Str("usecase", "ApplyProviderStatus").Msg("⚙️ Apply provider status to payment")
+	target := paymentStatusFromProvider(status)
+
+	// Callbacks arrive without a merchant; scope the rest of the flow to the payment's owner
+	merchantID, err := uc.paymentRepo.FetchMerchantIDByProviderReference(ctx, providerName, reference)
+	if err != nil {
+		return nil, false, err
+	}
+	ctx = tenant.WithMerchantID(ctx, merchantID)
+
+	// The conditional update fails when another writer moved the payment in between;
+	// re-evaluate against the fresh status a few times before giving up.
+	for attempt := 0; attempt < 3; attempt++ {
+		payment, err := uc.paymentRepo.FetchByProviderReference(ctx, providerName, reference)
+		if err != nil {
+			return nil, false, err
+		}
+		if payment.Status == target {
+			return payment, false, nil
+		}
+		if !entity.CanTransitionPayment(payment.Status, target) {
+			uc.logger.Warn().Str("payment_id", payment.ID.String()).Str("from", payment.Status).Str("to", target).Msg("⚠️ Ignoring stale provider status")
+			return payment, false, entity.ErrInvalidStatusTransition
+		}
+
+		fromStatus := payment.Status
+		payment.Status = target
+		var writes []func(tx *sql.Tx) error
+		if target == entity.PaymentStatusRefunded {
+			remaining := valueobject.DecimalFromBigFloat(payment.Amount).Sub(valueobject.DecimalFromBigFloat(payment.RefundedAmount))
+			payment.RefundedAmount = payment.Amount
+			writes = append(writes, uc.refundSplits(ctx, payment.ID, remaining))
+		}
+		if target == entity.PaymentStatusPaid {
+			if payment.SettlementCurrency == "" {
+				uc.settleAsync(ctx, payment)
+			}
+			fee, err := uc.quoteFee(ctx, payment)
+			if err != nil {
+				uc.logger.Error().Err(err).Str("payment_id", payment.ID.String()).Msg("❌ Failed to calculate payment fee")
+				return nil, false, err
+			}
+			writes = append(writes, uc.storeFees(ctx, payment.ID, fee.Lines))
+		}
+		err = uc.persistState(ctx, payment, fromStatus, writes...)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			uc.logger.Error().Err(err).Msg("❌ Failed to persist provider status")
+			return nil, false, err
+		}
+		uc.logger.Info().Str("payment_id", payment.ID.String()).Str("from", fromStatus).Str("to", target).Msg("✅ Provider status applied")
+		return payment, true, nil
+	}
+	return nil, false, entity.ErrInvalidStatusTransition
+}
+
+// SettleTransfer moves a PENDING payment collected outside a provider, such as a bank
+// transfer into a virtual account, to PAID or EXPIRED. write runs in the same transaction
+// so the record of the transfer commits together with the payment status.
+func (uc *paymentUseCase) SettleTransfer(ctx context.Context, id uuid.UUID, target string, write func(tx *sql.Tx) error) (*entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "SettleTransfer").Msg("⚙️ Settle transfer payment")
+	payment, err := uc.paymentRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != entity.PaymentStatusPending || !entity.CanTransitionPayment(payment.Status, target) {
+		return nil, entity.ErrInvalidStatusTransition
+	}
+
+	fromStatus := payment.Status
+	payment.Status = target
+	var writes []func(tx *sql.Tx) error
+	if target == entity.PaymentStatusPaid {
+		uc.settleAsync(ctx, payment)
+		fee, err := uc.quoteFee(ctx, payment)
+		if err != nil {
+			uc.logger.Error().Err(err).Str("payment_id", payment.ID.String()).Msg("❌ Failed to calculate payment fee")
+			return nil, err
+		}
+		writes = append(writes, uc.storeFees(ctx, payment.ID, fee.Lines))
+	}
+	writes = append(writes, write)
+
+	if err := uc.persistState(ctx, payment, fromStatus, writes...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			uc.logger.Warn().Str("payment_id", payment.ID.String()).Msg("⚠️ Payment changed concurrently, transfer not settled")
+			return nil, entity.ErrInvalidStatusTransition
+		}
+		return nil, err
+	}
+	uc.logger.Info().Str("payment_id", payment.ID.String()).Str("from", fromStatus).Str("to", target).Msg("✅ Transfer payment settled")
+	return payment, nil
+}
+
+// settleAsync records the settlement amount for a payment captured asynchronously. The
+// provider has already captured the funds, so a missing rate must not block the status
+// update; the payment is left without settlement data and the gap is logged.
+func (uc *paymentUseCase) settleAsync(ctx context.Context, payment *entity.Payment) {
+	settlement, err := uc.converter.Convert(ctx, valueobject.DecimalFromBigFloat(payment.Amount), payment.Currency, uc.settlementCurrency, time.Now())
+	if err != nil {
+		uc.logger.Warn().Err(err).Str("payment_id", payment.ID.String()).Msg("⚠️ Payment captured without settlement amount")
+		return
+	}
+	applySettlement(payment, settlement)
+}
+
+// quoteFee prices a payment with the fee rules of its merchant.
+func (uc *paymentUseCase) quoteFee(ctx context.Context, payment *entity.Payment) (*entity.FeeQuote, error) {
+	return uc.feeEngine.Quote(ctx, payment.MerchantID, payment.Method, payment.Currency, valueobject.DecimalFromBigFloat(payment.Amount))
+}
+
+// persistState writes the new payment state together with the records that depend on
+// it, such as fee lines on capture, so neither exists without the other.
+func (uc *paymentUseCase) persistState(ctx context.Context, payment *entity.Payment, fromStatus string, writes ...func(tx *sql.Tx) error) error {
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := uc.paymentRepo.ModifyGatewayStateInTx(ctx, tx, payment, fromStatus); err != nil {
+		return err
+	}
+	for _, write := range writes {
+		if err := write(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (uc *paymentUseCase) storeFees(ctx context.Context, paymentID uuid.UUID, feeLines []entity.PaymentFee) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		if len(feeLines) == 0 {
+			return nil
+		}
+		return uc.paymentFeeRepo.Store(ctx, tx, paymentID, feeLines)
+	}
+}
+
+// refundSplits pro-rates a refund across the payment's splits. The splits are locked
+// after the payment row, so concurrent refunds see each other's shares.
+func (uc *paymentUseCase) refundSplits(ctx context.Context, paymentID uuid.UUID, refund valueobject.Decimal) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		splits, err := uc.paymentSplitRepo.FetchByPaymentIDForUpdate(ctx, tx, paymentID)
+		if err != nil || len(splits) == 0 {
+			return err
+		}
+		for i, refunded := range split.ProRate(refund, splits) {
+			splits[i].RefundedAmount = refunded
+		}
+		return uc.paymentSplitRepo.ModifyRefundedAmounts(ctx, tx, splits)
+	}
+}
+
+func applySettlement(payment *entity.Payment, settlement *fx.Conversion) {
+	payment.SettlementCurrency = settlement.To
+	payment.FXRate = settlement.Rate
+	payment.SettlementAmount = settlement.Amount
+}
+
+// fetchWithGateway loads a payment that has already been sent to a provider and checks
+// that it may move to the target status.
+func (uc *paymentUseCase) fetchWithGateway(ctx context.Context, id uuid.UUID, target string) (*entity.Payment, provider.Gateway, error) {
+	payment, err := uc.paymentRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payment.ProviderReference == "" || !entity.CanTransitionPayment(payment.Status, target) {
+		return nil, nil, entity.ErrInvalidStatusTransition
+	}
+	gateway, err := uc.gateways.Get(payment.Provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	return payment, gateway, nil
+}
+
+func (uc *paymentUseCase) modifyGatewayState(ctx context.Context, payment *entity.Payment, fromStatus string) error {
+	if err := uc.paymentRepo.ModifyGatewayState(ctx, payment, fromStatus); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			uc.logger.Warn().Str("payment_id", payment.ID.String()).Msg("⚠️ Payment changed concurrently, state not persisted")
+			return entity.ErrInvalidStatusTransition
+		}
+		uc.logger.Error().Err(err).Msg("❌ Failed to persist provider result")
+		return err
+	}
+	return nil
+}
+
+func paymentStatusFromProvider(status provider.Status) string {
+	switch status {
+	case provider.StatusAuthorized:
+		return entity.PaymentStatusAuthorized
+	case provider.StatusCaptured:
+		return entity.PaymentStatusPaid
+	case provider.StatusRefunded:
+		return entity.PaymentStatusRefunded
+	case provider.StatusVoided:
+		return entity.PaymentStatusCancelled
+	case provider.StatusDeclined, provider.StatusFailed:
+		return entity.PaymentStatusFailed
+	default:
+		return entity.PaymentStatusPending
+	}
+}
+
+// roundAmount rounds to the two decimal places payments are stored with, so that
+// binary floating point noise does not leak into comparisons.
+func roundAmount(f *big.Float) *big.Float {
+	rounded, _, _ := big.ParseFloat(f.Text('f', 2), 10, 256, big.ToNearestEven)
+	return rounded
+}
+
+func amountOrZero(amount valueobject.BigFloat) *big.Float {
+	if amount.Float == nil {
+		return new(big.Float)
+	}
+	return amount.Float
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/paymentlink"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type PaymentLinkUseCase interface {
+	Create(ctx context.Context, req *request.CreatePaymentLinkRequest) (*entity.PaymentLink, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error)
+	Revoke(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error)
+	Checkout(ctx context.Context, token string) (*entity.PaymentLink, error)
+	Confirm(ctx context.Context, token string, req *request.AuthorizePaymentRequest) (*entity.PaymentLink, error)
+}
+
+type paymentLinkUseCase struct {
+	paymentLinkRepo repository.PaymentLinkRepository
+	paymentUC       PaymentUseCase
+	signer          *paymentlink.Signer
+	ttl             time.Duration
+	baseURL         string
+	logger          zerolog.Logger
+}
+
+func NewPaymentLinkUseCase(paymentLinkRepo repository.PaymentLinkRepository, paymentUC PaymentUseCase, signer *paymentlink.Signer, ttl time.Duration, baseURL string, logger zerolog.Logger) PaymentLinkUseCase {
+	return &paymentLinkUseCase{
+		paymentLinkRepo: paymentLinkRepo,
+		paymentUC:       paymentUC,
+		signer:          signer,
+		ttl:             ttl,
+		baseURL:         baseURL,
+		logger:          logger,
+	}
+}
+
+// Create creates the PENDING payment a link collects and returns the link with its URL.
+func (uc *paymentLinkUseCase) Create(ctx context.Context, req *request.CreatePaymentLinkRequest) (*entity.PaymentLink, error) {
+	uc.logger.Info().Str("usecase", "Create").Msg("⚙️ Store payment link")
+	payment, err := uc.paymentUC.Create(ctx, req.ToPayment())
+	if err != nil {
+		return nil, err
+	}
+
+	link := &entity.PaymentLink{PaymentID: payment.ID, ExpiresAt: time.Now().Add(uc.ttl)}
+	if req.ExpiresAt != nil {
+		link.ExpiresAt = *req.ExpiresAt
+	}
+	if err := uc.paymentLinkRepo.Store(ctx, link); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store payment link")
+		// Nobody can reach the payment without its link, so do not leave it behind
+		if delErr := uc.paymentUC.Delete(ctx, payment.ID); delErr != nil {
+			uc.logger.Warn().Err(delErr).Str("payment_id", payment.ID.String()).Msg("⚠️ Failed to remove payment of unstored link")
+		}
+		return nil, err
+	}
+
+	link.Payment = payment
+	uc.withURL(link)
+	uc.logger.Info().Str("payment_link_id", link.ID.String()).Str("payment_id", payment.ID.String()).Msg("✅ Payment link created")
+	return link, nil
+}
+
+func (uc *paymentLinkUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error) {
+	uc.logger.Info().Str("usecase", "GetByID").Msg("⚙️ Fetching payment link by ID")
+	link, err := uc.paymentLinkRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	link.Payment, err = uc.paymentUC.GetByID(ctx, link.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	uc.withURL(link)
+	return link, nil
+}
+
+// Revoke disables an active link; its token stops working immediately.
+func (uc *paymentLinkUseCase) Revoke(ctx context.Context, id uuid.UUID) (*entity.PaymentLink, error) {
+	uc.logger.Info().Str("usecase", "Revoke").Msg("⚙️ Revoke payment link")
+	link, err := uc.paymentLinkRepo.ModifyRevoked(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing updated: tell a missing link apart from one that is no longer active
+		if _, err := uc.paymentLinkRepo.FetchByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, entity.ErrPaymentLinkUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc.logger.Info().Str("payment_link_id", link.ID.String()).Msg("✅ Payment link revoked")
+	return link, nil
+}
+
+// Checkout resolves a public link token to the link and the payment it collects.
+func (uc *paymentLinkUseCase) Checkout(ctx context.Context, token string) (*entity.PaymentLink, error) {
+	uc.logger.Info().Str("usecase", "Checkout").Msg("⚙️ Resolve payment link")
+	ctx, link, err := uc.resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	link.Payment, err = uc.paymentUC.GetByID(ctx, link.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// Confirm claims the link and pays its payment through the provider: authorize, then
+// capture. A link is released again when the attempt fails before the provider moved the
+// payment, so the customer can retry; otherwise it stays used.
+func (uc *paymentLinkUseCase) Confirm(ctx context.Context, token string, req *request.AuthorizePaymentRequest) (*entity.PaymentLink, error) {
+	uc.logger.Info().Str("usecase", "Confirm").Msg("⚙️ Confirm payment link")
+	ctx, link, err := uc.resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	link, err = uc.paymentLinkRepo.ModifyClaimed(ctx, link.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrPaymentLinkUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := uc.paymentUC.Authorize(ctx, link.PaymentID, req)
+	if err != nil {
+		uc.releaseIfUntouched(ctx, link)
+		return nil, err
+	}
+	if payment.Status == entity.PaymentStatusAuthorized {
+		payment, err = uc.paymentUC.Capture(ctx, link.PaymentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	link.Payment = payment
+	uc.logger.Info().Str("payment_link_id", link.ID.String()).Str("status", payment.Status).Msg("✅ Payment link confirmed")
+	return link, nil
+}
+
+// resolve verifies a token and returns the link with a context scoped to its merchant.
+func (uc *paymentLinkUseCase) resolve(ctx context.Context, token string) (context.Context, *entity.PaymentLink, error) {
+	now := time.Now()
+	linkID, err := uc.signer.Verify(token, now)
+	if err != nil {
+		return ctx, nil, err
+	}
+	merchantID, err := uc.paymentLinkRepo.FetchMerchantIDByID(ctx, linkID)
+	if err != nil {
+		return ctx, nil, err
+	}
+	ctx = tenant.WithMerchantID(ctx, merchantID)
+
+	link, err := uc.paymentLinkRepo.FetchByID(ctx, linkID)
+	if err != nil {
+		return ctx, nil, err
+	}
+	if !link.IsUsable(now) {
+		if link.Status == entity.PaymentLinkStatusActive {
+			return ctx, nil, entity.ErrPaymentLinkExpired
+		}
+		return ctx, nil, entity.ErrPaymentLinkUnavailable
+	}
+	return ctx, link, nil
+}
+
+func (uc *paymentLinkUseCase) releaseIfUntouched(ctx context.Context, link *entity.PaymentLink) {
+	payment, err := uc.paymentUC.GetByID(ctx, link.PaymentID)
+	if err != nil || payment.Status != entity.PaymentStatusPending {
+		return
+	}
+	if err := uc.paymentLinkRepo.ModifyReleased(ctx, link.ID); err != nil {
+		uc.logger.Warn().Err(err).Str("payment_link_id", link.ID.String()).Msg("⚠️ Failed to release payment link")
+	}
+}
+
+// withURL sets the checkout URL while the link can still be used.
+func (uc *paymentLinkUseCase) withURL(link *entity.PaymentLink) {
+	if link.IsUsable(time.Now()) {
+		link.URL = uc.baseURL + "/pay/" + uc.signer.Sign(link.ID, link.ExpiresAt)
+	}
+}
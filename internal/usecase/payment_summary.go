@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// Summary totals payments per currency and status, and split shares per recipient. With a
+// reporting currency every group is also converted at the rate effective at params.AsOf, and
+// the converted group totals are summed.
+func (uc *paymentUseCase) Summary(ctx context.Context, params request.PaymentSummaryQueryParams) (*entity.PaymentSummary, error) {
+	uc.logger.Info().Str("usecase", "Summary").Msg("⚙️ Summarize payments")
+	groups, err := uc.paymentRepo.FetchSummary(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, err := uc.paymentSplitRepo.FetchRecipientSummary(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &entity.PaymentSummary{Groups: groups, Recipients: recipients}
+	if summary.Groups == nil {
+		summary.Groups = []entity.PaymentSummaryGroup{}
+	}
+	if summary.Recipients == nil {
+		summary.Recipients = []entity.PaymentSummaryRecipient{}
+	}
+	if params.ReportingCurrency == "" {
+		return summary, nil
+	}
+
+	summary.ReportingCurrency = params.ReportingCurrency
+	summary.Rates = make(map[string]valueobject.Decimal)
+	summary.ReportingTotal = valueobject.DecimalFromInt(0)
+	summary.ReportingRefundedTotal = valueobject.DecimalFromInt(0)
+	rateFor := func(currency string) (valueobject.Decimal, error) {
+		if rate, ok := summary.Rates[currency]; ok {
+			return rate, nil
+		}
+		rate, err := uc.converter.Rate(ctx, currency, params.ReportingCurrency, params.AsOf)
+		if err != nil {
+			uc.logger.Warn().Err(err).Str("currency", currency).Msg("⚠️ No rate for reporting currency")
+			return valueobject.Decimal{}, err
+		}
+		summary.Rates[currency] = rate
+		return rate, nil
+	}
+	for i := range summary.Groups {
+		g := &summary.Groups[i]
+		rate, err := rateFor(g.Currency)
+		if err != nil {
+			return nil, err
+		}
+		g.ReportingTotal = uc.converter.Apply(g.Total, rate)
+		g.ReportingRefundedTotal = uc.converter.Apply(g.RefundedTotal, rate)
+		summary.ReportingTotal = summary.ReportingTotal.Add(g.ReportingTotal)
+		summary.ReportingRefundedTotal = summary.ReportingRefundedTotal.Add(g.ReportingRefundedTotal)
+	}
+	for i := range summary.Recipients {
+		rec := &summary.Recipients[i]
+		rate, err := rateFor(rec.Currency)
+		if err != nil {
+			return nil, err
+		}
+		rec.ReportingTotal = uc.converter.Apply(rec.Total, rate)
+		rec.ReportingRefundedTotal = uc.converter.Apply(rec.RefundedTotal, rate)
+	}
+	return summary, nil
+}
@@ -5,7 +5,13 @@ import (
 	"database/sql"
 	"github.com/adf-code/beta-payment-api/internal/delivery/request"
 	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/fees"
+	"github.com/adf-code/beta-payment-api/internal/fx"
+	"github.com/adf-code/beta-payment-api/internal/provider"
 	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/risk"
+	"github.com/adf-code/beta-payment-api/internal/split"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
@@ -14,21 +20,50 @@ type PaymentUseCase interface {
 	GetAll(ctx context.Context, params request.PaymentListQueryParams) ([]entity.Payment, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Payment, error)
 	UpdateByID(ctx context.Context, id uuid.UUID, req *request.UpdatePaymentRequest) (*entity.Payment, error)
+	Patch(ctx context.Context, id uuid.UUID, req *request.PatchPaymentRequest) (*entity.Payment, error)
 	Create(ctx context.Context, payment entity.Payment) (*entity.Payment, error)
+	CreateChecked(ctx context.Context, payment entity.Payment, check func(tx *sql.Tx) error) (*entity.Payment, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	Authorize(ctx context.Context, id uuid.UUID, req *request.AuthorizePaymentRequest) (*entity.Payment, error)
+	Capture(ctx context.Context, id uuid.UUID) (*entity.Payment, error)
+	Refund(ctx context.Context, id uuid.UUID, req *request.RefundPaymentRequest) (*entity.Payment, error)
+	Void(ctx context.Context, id uuid.UUID) (*entity.Payment, error)
+	Review(ctx context.Context, id uuid.UUID, req *request.ReviewPaymentRequest) (*entity.Payment, error)
+	ApplyProviderStatus(ctx context.Context, providerName, reference string, status provider.Status) (*entity.Payment, bool, error)
+	SettleTransfer(ctx context.Context, id uuid.UUID, target string, write func(tx *sql.Tx) error) (*entity.Payment, error)
+	Summary(ctx context.Context, params request.PaymentSummaryQueryParams) (*entity.PaymentSummary, error)
+	GetFees(ctx context.Context, id uuid.UUID) ([]entity.PaymentFee, error)
 }
 
 type paymentUseCase struct {
-	paymentRepo repository.PaymentRepository
-	db          *sql.DB
-	logger      zerolog.Logger
+	paymentRepo        repository.PaymentRepository
+	customerRepo       repository.CustomerRepository
+	paymentFeeRepo     repository.PaymentFeeRepository
+	paymentSplitRepo   repository.PaymentSplitRepository
+	riskRepo           repository.RiskRepository
+	riskEngine         *risk.Engine
+	gateways           *provider.Router
+	converter          *fx.Converter
+	feeEngine          *fees.Engine
+	settlementCurrency string
+	db                 *sql.DB
+	logger             zerolog.Logger
 }
 
-func NewPaymentUseCase(paymentRepo repository.PaymentRepository, db *sql.DB, logger zerolog.Logger) PaymentUseCase {
+func NewPaymentUseCase(paymentRepo repository.PaymentRepository, customerRepo repository.CustomerRepository, paymentFeeRepo repository.PaymentFeeRepository, paymentSplitRepo repository.PaymentSplitRepository, riskRepo repository.RiskRepository, riskEngine *risk.Engine, gateways *provider.Router, converter *fx.Converter, feeEngine *fees.Engine, settlementCurrency string, db *sql.DB, logger zerolog.Logger) PaymentUseCase {
 	return &paymentUseCase{
-		paymentRepo: paymentRepo,
-		db:          db,
-		logger:      logger,
+		paymentRepo:        paymentRepo,
+		customerRepo:       customerRepo,
+		paymentFeeRepo:     paymentFeeRepo,
+		paymentSplitRepo:   paymentSplitRepo,
+		riskRepo:           riskRepo,
+		riskEngine:         riskEngine,
+		gateways:           gateways,
+		converter:          converter,
+		feeEngine:          feeEngine,
+		settlementCurrency: settlementCurrency,
+		db:                 db,
+		logger:             logger,
 	}
 }
 
@@ -39,7 +74,15 @@ func (uc *paymentUseCase) GetAll(ctx context.Context, params request.PaymentList
 
 func (uc *paymentUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Payment, error) {
 	uc.logger.Info().Str("usecase", "GetByID").Msg("⚙️ Fetching payment by ID")
-	return uc.paymentRepo.FetchByID(ctx, id)
+	payment, err := uc.paymentRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	payment.Splits, err = uc.paymentSplitRepo.FetchByPaymentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
 }
 
 func (uc *paymentUseCase) UpdateByID(ctx context.Context, id uuid.UUID, req *request.UpdatePaymentRequest) (*entity.Payment, error) {
@@ -47,14 +90,92 @@ func (uc *paymentUseCase) UpdateByID(ctx context.Context, id uuid.UUID, req *req
 	return uc.paymentRepo.ModifyByID(ctx, id, req)
 }
 
+func (uc *paymentUseCase) Patch(ctx context.Context, id uuid.UUID, req *request.PatchPaymentRequest) (*entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "Patch").Msg("⚙️ Patch payment")
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	payment, err := uc.paymentRepo.FetchByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Description != nil {
+		payment.Description = *req.Description
+	}
+	if req.Metadata != nil {
+		payment.Metadata = req.ApplyMetadata(payment.Metadata)
+		// Limits apply to the merged result, not only to the keys sent in this request
+		if err := payment.Metadata.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.paymentRepo.ModifyDetails(ctx, tx, payment); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to patch payment")
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to commit transaction")
+		return nil, err
+	}
+
+	uc.logger.Info().Str("payment_id", payment.ID.String()).Msg("✅ Payment patched")
+	return payment, nil
+}
+
 func (uc *paymentUseCase) Create(ctx context.Context, payment entity.Payment) (*entity.Payment, error) {
+	return uc.CreateChecked(ctx, payment, nil)
+}
+
+// CreateChecked creates a payment like Create, running check in the creating transaction
+// before the payment is stored; an error from check aborts the creation and is returned.
+func (uc *paymentUseCase) CreateChecked(ctx context.Context, payment entity.Payment, check func(tx *sql.Tx) error) (*entity.Payment, error) {
 	uc.logger.Info().Str("usecase", "Create").Msg("⚙️ Store payment")
+	if payment.Currency == "" {
+		payment.Currency = entity.DefaultPaymentCurrency
+	}
+	if payment.Method == "" {
+		payment.Method = entity.PaymentMethodCard
+	}
+	if err := payment.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+	if len(payment.Splits) > 0 {
+		if err := split.Allocate(valueobject.DecimalFromBigFloat(payment.Amount), payment.Splits); err != nil {
+			return nil, err
+		}
+	}
+
+	decision, err := uc.assessRisk(ctx, &payment)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := uc.db.Begin()
 	if err != nil {
 		uc.logger.Error().Err(err).Msg("❌ Failed to begin transaction")
 		return nil, err
 	}
 
+	if payment.CustomerID != nil {
+		if err := uc.customerRepo.LockByID(ctx, tx, *payment.CustomerID); err != nil {
+			tx.Rollback()
+			uc.logger.Error().Err(err).Msg("❌ Failed to store payment, customer not available")
+			return nil, err
+		}
+	}
+
+	if check != nil {
+		if err := check(tx); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	err = uc.paymentRepo.Store(ctx, tx, &payment)
 	if err != nil {
 		tx.Rollback()
@@ -62,6 +183,21 @@ func (uc *paymentUseCase) Create(ctx context.Context, payment entity.Payment) (*
 		return nil, err
 	}
 
+	if len(payment.Splits) > 0 {
+		if err := uc.paymentSplitRepo.Store(ctx, tx, payment.ID, payment.Splits); err != nil {
+			tx.Rollback()
+			uc.logger.Error().Err(err).Msg("❌ Failed to store payment splits, rolling back")
+			return nil, err
+		}
+	}
+
+	decision.PaymentID = &payment.ID
+	if err := uc.riskRepo.StoreDecision(ctx, tx, decision); err != nil {
+		tx.Rollback()
+		uc.logger.Error().Err(err).Msg("❌ Failed to store risk decision, rolling back")
+		return nil, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		uc.logger.Error().Err(err).Msg("❌ Failed to commit transaction")
@@ -72,6 +208,83 @@ func (uc *paymentUseCase) Create(ctx context.Context, payment entity.Payment) (*
 	return &payment, nil
 }
 
+// assessRisk runs the risk rules on a payment about to be created and sets the status it
+// is created in. Every decision is logged; a denial is also stored right away, as no
+// payment will carry it, and returned as an *entity.RiskDeniedError.
+func (uc *paymentUseCase) assessRisk(ctx context.Context, payment *entity.Payment) (*entity.RiskDecision, error) {
+	decision, err := uc.riskEngine.Evaluate(ctx, risk.Input{
+		Tag:        payment.Tag,
+		CustomerID: payment.CustomerID,
+		ClientIP:   payment.ClientIP,
+		Amount:     valueobject.DecimalFromBigFloat(payment.Amount),
+		Currency:   payment.Currency,
+	})
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to evaluate risk rules")
+		return nil, err
+	}
+
+	event := uc.logger.Info()
+	if decision.Action != entity.RiskActionAllow {
+		event = uc.logger.Warn()
+	}
+	event.Str("action", decision.Action).
+		Int("score", decision.Score).
+		Str("reason_code", decision.ReasonCode).
+		Interface("reasons", decision.Reasons).
+		Str("tag", payment.Tag).
+		Str("client_ip", payment.ClientIP).
+		Msg("🛡️ Risk decision")
+
+	switch decision.Action {
+	case entity.RiskActionDeny:
+		if err := uc.riskRepo.StoreDecision(ctx, nil, &decision); err != nil {
+			uc.logger.Error().Err(err).Msg("❌ Failed to store risk decision")
+			return nil, err
+		}
+		return nil, &entity.RiskDeniedError{Decision: decision}
+	case entity.RiskActionReview:
+		payment.Status = entity.PaymentStatusReview
+	default:
+		payment.Status = entity.PaymentStatusPending
+	}
+	return &decision, nil
+}
+
+// Review releases a payment held for review by the risk rules: approved payments become
+// PENDING and can be paid, rejected ones are CANCELLED.
+func (uc *paymentUseCase) Review(ctx context.Context, id uuid.UUID, req *request.ReviewPaymentRequest) (*entity.Payment, error) {
+	uc.logger.Info().Str("usecase", "Review").Msg("⚙️ Review payment")
+	payment, err := uc.paymentRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	target := entity.PaymentStatusPending
+	if req.Decision == entity.RiskReviewReject {
+		target = entity.PaymentStatusCancelled
+	}
+	if !entity.CanTransitionPayment(payment.Status, target) {
+		return nil, entity.ErrInvalidStatusTransition
+	}
+
+	fromStatus := payment.Status
+	payment.Status = target
+	if err := uc.modifyGatewayState(ctx, payment, fromStatus); err != nil {
+		return nil, err
+	}
+	uc.logger.Info().Str("payment_id", payment.ID.String()).Str("decision", req.Decision).Msg("✅ Payment reviewed")
+	return payment, nil
+}
+
+// GetFees returns the fee lines charged when the payment was captured.
+func (uc *paymentUseCase) GetFees(ctx context.Context, id uuid.UUID) ([]entity.PaymentFee, error) {
+	uc.logger.Info().Str("usecase", "GetFees").Msg("⚙️ Fetching fees of payment")
+	if _, err := uc.paymentRepo.FetchByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.paymentFeeRepo.FetchByPaymentID(ctx, id)
+}
+
 func (uc *paymentUseCase) Delete(ctx context.Context, id uuid.UUID) error {
 	uc.logger.Info().Str("usecase", "Delete").Msg("⚙️ Remove payment")
 	return uc.paymentRepo.Remove(ctx, id)
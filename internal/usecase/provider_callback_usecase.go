@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/provider"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+type ProviderCallbackUseCase interface {
+	GetAll(ctx context.Context, params request.ProviderCallbackListQueryParams) ([]entity.ProviderCallback, error)
+	Handle(ctx context.Context, providerName string, header http.Header, body []byte) (*entity.ProviderCallback, error)
+}
+
+type providerCallbackUseCase struct {
+	callbackRepo repository.ProviderCallbackRepository
+	paymentUC    PaymentUseCase
+	gateways     *provider.Router
+	logger       zerolog.Logger
+}
+
+func NewProviderCallbackUseCase(callbackRepo repository.ProviderCallbackRepository, paymentUC PaymentUseCase, gateways *provider.Router, logger zerolog.Logger) ProviderCallbackUseCase {
+	return &providerCallbackUseCase{
+		callbackRepo: callbackRepo,
+		paymentUC:    paymentUC,
+		gateways:     gateways,
+		logger:       logger,
+	}
+}
+
+// redactedCallbackHeaders are never persisted with a callback.
+var redactedCallbackHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+}
+
+func (uc *providerCallbackUseCase) GetAll(ctx context.Context, params request.ProviderCallbackListQueryParams) ([]entity.ProviderCallback, error) {
+	uc.logger.Info().Str("usecase", "GetAll").Msg("⚙️ Fetching all provider callbacks")
+	return uc.callbackRepo.FetchWithQueryParams(ctx, params)
+}
+
+// Handle verifies, deduplicates and applies a provider callback. Every callback that
+// reaches a known provider is stored, including unverified ones, for later inspection.
+// The returned callback carries the outcome even when an error is returned.
+func (uc *providerCallbackUseCase) Handle(ctx context.Context, providerName string, header http.Header, body []byte) (*entity.ProviderCallback, error) {
+	uc.logger.Info().Str("usecase", "Handle").Str("provider", providerName).Msg("⚙️ Handle provider callback")
+	handler, err := uc.gateways.CallbackHandler(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	callback := &entity.ProviderCallback{
+		Provider: providerName,
+		Headers:  marshalCallbackHeaders(header),
+		Payload:  string(body),
+	}
+
+	if err := handler.VerifyCallback(header, body); err != nil {
+		uc.logger.Warn().Err(err).Str("provider", providerName).Msg("⚠️ Callback signature verification failed")
+		return callback, uc.storeRejected(ctx, callback, entity.CallbackOutcomeRejected, err)
+	}
+	callback.SignatureValid = true
+
+	event, err := handler.ParseCallback(body)
+	if err != nil {
+		uc.logger.Warn().Err(err).Str("provider", providerName).Msg("⚠️ Callback payload is invalid")
+		return callback, uc.storeRejected(ctx, callback, entity.CallbackOutcomeInvalid, err)
+	}
+	status := string(event.Status)
+	callback.EventID = &event.EventID
+	callback.Reference = &event.Reference
+	callback.Status = &status
+
+	isNew, err := uc.callbackRepo.StoreVerified(ctx, callback)
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store provider callback")
+		return nil, err
+	}
+	if !isNew {
+		uc.logger.Info().Str("event_id", event.EventID).Msg("✅ Duplicate provider callback ignored")
+		callback.Outcome = entity.CallbackOutcomeDuplicate
+		return callback, nil
+	}
+
+	payment, applied, err := uc.paymentUC.ApplyProviderStatus(ctx, providerName, event.Reference, event.Status)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		callback.Outcome = entity.CallbackOutcomeUnmatched
+	case errors.Is(err, entity.ErrInvalidStatusTransition):
+		callback.Outcome = entity.CallbackOutcomeStale
+		err = nil
+	case err != nil:
+		// Leave the callback RECEIVED so the provider's retry processes it again.
+		return callback, err
+	case applied:
+		callback.Outcome = entity.CallbackOutcomeApplied
+	default:
+		callback.Outcome = entity.CallbackOutcomeUnchanged
+	}
+	if payment != nil {
+		callback.PaymentID = &payment.ID
+		callback.MerchantID = &payment.MerchantID
+	}
+
+	if mErr := uc.callbackRepo.ModifyOutcome(ctx, callback); mErr != nil {
+		uc.logger.Error().Err(mErr).Msg("❌ Failed to record provider callback outcome")
+		return callback, mErr
+	}
+	uc.logger.Info().Str("event_id", event.EventID).Str("outcome", callback.Outcome).Msg("✅ Provider callback processed")
+	return callback, err
+}
+
+func (uc *providerCallbackUseCase) storeRejected(ctx context.Context, callback *entity.ProviderCallback, outcome string, cause error) error {
+	msg := cause.Error()
+	callback.Outcome = outcome
+	callback.Error = &msg
+	if err := uc.callbackRepo.Store(ctx, callback); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store rejected provider callback")
+		return err
+	}
+	return cause
+}
+
+func marshalCallbackHeaders(header http.Header) json.RawMessage {
+	kept := make(map[string][]string, len(header))
+	for k, v := range header {
+		if !redactedCallbackHeaders[http.CanonicalHeaderKey(k)] {
+			kept[k] = v
+		}
+	}
+	raw, err := json.Marshal(kept)
+	if err != nil {
+		return json.RawMessage("{}")
+	}
+	return raw
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/qr"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// stubPayments serves GetByID from payments; the other methods are never called.
+type stubPayments struct {
+	PaymentUseCase
+	payments map[uuid.UUID]*entity.Payment
+}
+
+func (s stubPayments) GetByID(_ context.Context, id uuid.UUID) (*entity.Payment, error) {
+	if p, ok := s.payments[id]; ok {
+		return p, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+// stubMerchants serves FetchQRISProfile from profiles; the other methods are never called.
+type stubMerchants struct {
+	repository.MerchantRepository
+	profiles map[uuid.UUID]*entity.MerchantQRISProfile
+}
+
+func (s stubMerchants) FetchQRISProfile(_ context.Context, merchantID uuid.UUID) (*entity.MerchantQRISProfile, error) {
+	if p, ok := s.profiles[merchantID]; ok {
+		return p, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func TestGetPaymentPayloadUsesTheMerchantProfile(t *testing.T) {
+	warung, toko, unprofiled := uuid.New(), uuid.New(), uuid.New()
+	merchants := stubMerchants{profiles: map[uuid.UUID]*entity.MerchantQRISProfile{
+		warung: {MerchantID: warung, Name: "Warung Sayur", City: "Demak", CategoryCode: "5812", NMID: "ID1020017611473", Criteria: "UMI"},
+		toko:   {MerchantID: toko, Name: "Toko Buku", City: "Bandung", CategoryCode: "5942", NMID: "ID1020021234567", Criteria: "UKE"},
+	}}
+	payment := func(merchantID uuid.UUID) *entity.Payment {
+		return &entity.Payment{
+			ID:         uuid.New(),
+			MerchantID: merchantID,
+			Amount:     valueobject.BigFloat{Float: big.NewFloat(15000)},
+			Currency:   "IDR",
+			Method:     entity.PaymentMethodQRIS,
+			Status:     entity.PaymentStatusPending,
+		}
+	}
+	payments := map[uuid.UUID]*entity.Payment{}
+	for _, p := range []*entity.Payment{payment(warung), payment(toko), payment(unprofiled)} {
+		payments[p.ID] = p
+	}
+	uc := NewQRISUseCase(stubPayments{payments: payments}, merchants, zerolog.Nop())
+
+	for _, p := range payments {
+		encoded, err := uc.GetPaymentPayload(context.Background(), p.ID)
+		profile, ok := merchants.profiles[p.MerchantID]
+		if !ok {
+			if !errors.Is(err, entity.ErrQRISProfileMissing) {
+				t.Errorf("merchant without profile: got %v, want ErrQRISProfileMissing", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("GetPaymentPayload: %v", err)
+		}
+		payload, err := qr.Parse(encoded)
+		if err != nil {
+			t.Fatalf("parse payload: %v", err)
+		}
+		for tag, want := range map[string]string{
+			qr.TagMerchantName:     profile.Name,
+			qr.TagMerchantCity:     profile.City,
+			qr.TagMerchantCategory: profile.CategoryCode,
+			qr.TagAmount:           "15000",
+			qr.TagCurrency:         "360",
+		} {
+			if got := payload.Value(tag); got != want {
+				t.Errorf("merchant %s: tag %s = %q, want %q", profile.Name, tag, got, want)
+			}
+		}
+		if got := payload.SubValue(qr.TagQRISAccount, qr.TagAccountID); got != profile.NMID {
+			t.Errorf("merchant %s: NMID %q, want %q", profile.Name, got, profile.NMID)
+		}
+		if got := payload.SubValue(qr.TagAdditionalData, qr.TagReferenceLabel); got != qr.EncodeReference(p.ID) {
+			t.Errorf("merchant %s: reference %q, want the payment ID", profile.Name, got)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/settlement"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type SettlementUseCase interface {
+	GetAll(ctx context.Context, params request.SettlementBatchListQueryParams) ([]entity.SettlementBatch, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.SettlementBatch, error)
+	Generate(ctx context.Context, req *request.GenerateSettlementRequest) ([]entity.SettlementBatch, error)
+	GenerateAll(ctx context.Context) (int, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, req *request.UpdateSettlementStatusRequest) (*entity.SettlementBatch, error)
+	PayoutFile(ctx context.Context, id uuid.UUID, format string) ([]byte, error)
+}
+
+type settlementUseCase struct {
+	settlementRepo repository.SettlementRepository
+	merchantRepo   repository.MerchantRepository
+	schedule       settlement.Schedule
+	debtor         settlement.Debtor
+	csvFormat      settlement.CSVFormat
+	db             *sql.DB
+	logger         zerolog.Logger
+}
+
+func NewSettlementUseCase(settlementRepo repository.SettlementRepository, merchantRepo repository.MerchantRepository, schedule settlement.Schedule, debtor settlement.Debtor, csvFormat settlement.CSVFormat, db *sql.DB, logger zerolog.Logger) SettlementUseCase {
+	return &settlementUseCase{
+		settlementRepo: settlementRepo,
+		merchantRepo:   merchantRepo,
+		schedule:       schedule,
+		debtor:         debtor,
+		csvFormat:      csvFormat,
+		db:             db,
+		logger:         logger,
+	}
+}
+
+func (uc *settlementUseCase) GetAll(ctx context.Context, params request.SettlementBatchListQueryParams) ([]entity.SettlementBatch, error) {
+	uc.logger.Info().Str("usecase", "GetAll").Msg("⚙️ Fetching all settlement batches")
+	return uc.settlementRepo.FetchWithQueryParams(ctx, params)
+}
+
+// GetByID returns a settlement batch with the payments it settles.
+func (uc *settlementUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entity.SettlementBatch, error) {
+	uc.logger.Info().Str("usecase", "GetByID").Msg("⚙️ Fetching settlement batch by ID")
+	batch, err := uc.settlementRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	batch.Items, err = uc.settlementRepo.FetchItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// Generate batches the merchant's payments that are due for settlement, one batch per
+// currency. Payments already in a batch are skipped, so running it again for the same
+// cutoff only picks up what changed since.
+func (uc *settlementUseCase) Generate(ctx context.Context, req *request.GenerateSettlementRequest) ([]entity.SettlementBatch, error) {
+	uc.logger.Info().Str("usecase", "Generate").Msg("⚙️ Generate settlement batches")
+	cutoff := uc.schedule.LastCutoff(time.Now())
+	if req.CutoffAt != nil {
+		cutoff = *req.CutoffAt
+	}
+	cutoff = cutoff.UTC()
+
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	account, err := uc.settlementRepo.FetchPayoutAccount(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if account.Name == "" || account.Number == "" || account.BankCode == "" {
+		return nil, entity.ErrPayoutAccountMissing
+	}
+	candidates, err := uc.settlementRepo.FetchCandidatesForUpdate(ctx, tx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	batches := settlement.Build(candidates)
+	for i := range batches {
+		batches[i].CutoffAt = cutoff
+		batches[i].PayoutAccount = account
+		if err := uc.settlementRepo.Store(ctx, tx, &batches[i]); err != nil {
+			uc.logger.Error().Err(err).Msg("❌ Failed to store settlement batch, rolling back")
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to commit transaction")
+		return nil, err
+	}
+
+	for _, b := range batches {
+		uc.logger.Info().
+			Str("batch_id", b.ID.String()).
+			Str("currency", b.Currency).
+			Int("items", b.ItemCount).
+			Str("net_amount", b.NetAmount.String()).
+			Msg("✅ Settlement batch generated")
+	}
+	return batches, nil
+}
+
+// GenerateAll runs Generate for every active merchant at the last cutoff. Merchants
+// without a payout account are skipped; their payments wait for the next run. It returns
+// how many batches were generated.
+func (uc *settlementUseCase) GenerateAll(ctx context.Context) (int, error) {
+	merchantIDs, err := uc.merchantRepo.FetchActiveIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for _, merchantID := range merchantIDs {
+		batches, err := uc.Generate(tenant.WithMerchantID(ctx, merchantID), &request.GenerateSettlementRequest{})
+		if errors.Is(err, entity.ErrPayoutAccountMissing) {
+			uc.logger.Warn().Str("merchant_id", merchantID.String()).Msg("⚠️ Merchant has no payout account, settlement skipped")
+			continue
+		}
+		if err != nil {
+			uc.logger.Error().Err(err).Str("merchant_id", merchantID.String()).Msg("❌ Failed to generate settlement batches")
+			return generated, err
+		}
+		generated += len(batches)
+	}
+	return generated, nil
+}
+
+// UpdateStatus records that a batch's payout was sent to the bank or confirmed by it.
+func (uc *settlementUseCase) UpdateStatus(ctx context.Context, id uuid.UUID, req *request.UpdateSettlementStatusRequest) (*entity.SettlementBatch, error) {
+	uc.logger.Info().Str("usecase", "UpdateStatus").Msg("⚙️ Update settlement batch status")
+	batch, err := uc.settlementRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entity.CanTransitionSettlement(batch.Status, req.Status) {
+		return nil, entity.ErrInvalidSettlementTransition
+	}
+	batch, err = uc.settlementRepo.ModifyStatus(ctx, id, batch.Status, req.Status)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Moved on by a concurrent request since it was read
+		return nil, entity.ErrInvalidSettlementTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc.logger.Info().Str("batch_id", id.String()).Str("status", batch.Status).Msg("✅ Settlement batch status updated")
+	return batch, nil
+}
+
+// PayoutFile renders the payout instruction of a batch as pain.001 XML or CSV.
+func (uc *settlementUseCase) PayoutFile(ctx context.Context, id uuid.UUID, format string) ([]byte, error) {
+	uc.logger.Info().Str("usecase", "PayoutFile").Msg("⚙️ Render settlement payout file")
+	batch, err := uc.settlementRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	now := uc.schedule.Local(time.Now())
+	if format == entity.PayoutFormatCSV {
+		err = uc.csvFormat.Write(&buf, *batch, now)
+	} else {
+		err = settlement.WritePain001(&buf, uc.debtor, *batch, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/delivery/request"
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/pkg/tenant"
+	"github.com/adf-code/beta-payment-api/internal/repository"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+	"github.com/adf-code/beta-payment-api/internal/virtualaccount"
+	"github.com/rs/zerolog"
+)
+
+type VirtualAccountUseCase interface {
+	Issue(ctx context.Context, req *request.IssueVirtualAccountRequest) (*entity.VirtualAccount, error)
+	GetByNumber(ctx context.Context, number string) (*entity.VirtualAccount, error)
+	Credit(ctx context.Context, number string, req *request.CreditVirtualAccountRequest) (*entity.VirtualAccountCredit, bool, error)
+	ExpireOverdue(ctx context.Context) (int, error)
+}
+
+type virtualAccountUseCase struct {
+	virtualAccountRepo repository.VirtualAccountRepository
+	merchantRepo       repository.MerchantRepository
+	paymentUC          PaymentUseCase
+	issuer             *virtualaccount.Issuer
+	policy             virtualaccount.Policy
+	ttl                time.Duration
+	db                 *sql.DB
+	logger             zerolog.Logger
+}
+
+func NewVirtualAccountUseCase(virtualAccountRepo repository.VirtualAccountRepository, merchantRepo repository.MerchantRepository, paymentUC PaymentUseCase, issuer *virtualaccount.Issuer, policy virtualaccount.Policy, ttl time.Duration, db *sql.DB, logger zerolog.Logger) VirtualAccountUseCase {
+	return &virtualAccountUseCase{
+		virtualAccountRepo: virtualAccountRepo,
+		merchantRepo:       merchantRepo,
+		paymentUC:          paymentUC,
+		issuer:             issuer,
+		policy:             policy,
+		ttl:                ttl,
+		db:                 db,
+		logger:             logger,
+	}
+}
+
+// Issue assigns a new virtual account number to a PENDING bank transfer payment. The
+// account expires after the configured TTL, and the payment expires with it.
+func (uc *virtualAccountUseCase) Issue(ctx context.Context, req *request.IssueVirtualAccountRequest) (*entity.VirtualAccount, error) {
+	uc.logger.Info().Str("usecase", "Issue").Msg("⚙️ Issue virtual account")
+	payment, err := uc.paymentUC.GetByID(ctx, req.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Method != entity.PaymentMethodBankTransfer || payment.Status != entity.PaymentStatusPending {
+		return nil, entity.ErrVirtualAccountUnavailable
+	}
+
+	seq, err := uc.virtualAccountRepo.NextNumberSequence(ctx)
+	if err != nil {
+		return nil, err
+	}
+	number, err := uc.issuer.Number(req.Bank, seq)
+	if err != nil {
+		return nil, err
+	}
+
+	va := &entity.VirtualAccount{
+		PaymentID:      payment.ID,
+		Bank:           req.Bank,
+		Number:         number,
+		ExpectedAmount: valueobject.DecimalFromBigFloat(payment.Amount).Round(2, valueobject.RoundHalfUp),
+		Currency:       payment.Currency,
+		ExpiresAt:      time.Now().Add(uc.ttl),
+	}
+	if err := uc.virtualAccountRepo.Store(ctx, va); err != nil {
+		uc.logger.Error().Err(err).Msg("❌ Failed to store virtual account")
+		return nil, err
+	}
+	uc.logger.Info().Str("payment_id", payment.ID.String()).Str("bank", va.Bank).Msg("✅ Virtual account issued")
+	return va, nil
+}
+
+func (uc *virtualAccountUseCase) GetByNumber(ctx context.Context, number string) (*entity.VirtualAccount, error) {
+	uc.logger.Info().Str("usecase", "GetByNumber").Msg("⚙️ Fetching virtual account by number")
+	va, err := uc.virtualAccountRepo.FetchByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	va.Credits, err = uc.virtualAccountRepo.FetchCredits(ctx, va.ID)
+	if err != nil {
+		return nil, err
+	}
+	return va, nil
+}
+
+// Credit applies an incoming transfer to the account it was sent to, following the over-,
+// under- and late payment policy. Transfers are idempotent on their reference: a replay
+// returns the recorded credit with applied=false.
+func (uc *virtualAccountUseCase) Credit(ctx context.Context, number string, req *request.CreditVirtualAccountRequest) (*entity.VirtualAccountCredit, bool, error) {
+	uc.logger.Info().Str("usecase", "Credit").Msg("⚙️ Credit virtual account")
+	if !uc.issuer.Valid(number) {
+		return nil, false, entity.ErrInvalidVirtualAccount
+	}
+
+	// Another transfer or the expiry job may change the account or the payment between the
+	// read and the write; the conditional writes fail then and the transfer is re-evaluated.
+	for attempt := 0; attempt < 3; attempt++ {
+		va, err := uc.virtualAccountRepo.FetchByNumber(ctx, number)
+		if err != nil {
+			return nil, false, err
+		}
+		existing, err := uc.virtualAccountRepo.FetchCreditByReference(ctx, va.ID, req.TransferReference)
+		if err == nil {
+			return existing, false, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, false, err
+		}
+		payment, err := uc.paymentUC.GetByID(ctx, va.PaymentID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		now := time.Now()
+		decision := uc.policy.Decide(va, payment.Status == entity.PaymentStatusPending, req.Amount, now)
+		credit := &entity.VirtualAccountCredit{
+			VirtualAccountID:  va.ID,
+			TransferReference: req.TransferReference,
+			Amount:            req.Amount,
+			Outcome:           decision.Outcome,
+			RejectReason:      decision.RejectReason,
+		}
+		record := uc.recordCredit(ctx, va, decision, credit, now)
+
+		if decision.Paid {
+			_, err = uc.paymentUC.SettleTransfer(ctx, va.PaymentID, entity.PaymentStatusPaid, record)
+		} else {
+			err = uc.inTx(ctx, record)
+		}
+		if errors.Is(err, entity.ErrVirtualAccountChanged) || errors.Is(err, entity.ErrInvalidStatusTransition) {
+			continue
+		}
+		if err != nil {
+			uc.logger.Error().Err(err).Msg("❌ Failed to credit virtual account")
+			return nil, false, err
+		}
+
+		logEvent := uc.logger.Info()
+		if decision.Outcome == entity.VirtualAccountCreditRejected {
+			logEvent = uc.logger.Warn()
+		}
+		logEvent.Str("payment_id", va.PaymentID.String()).Str("outcome", credit.Outcome).Str("reason", credit.RejectReason).Msg("✅ Virtual account credited")
+		return credit, true, nil
+	}
+	return nil, false, entity.ErrVirtualAccountChanged
+}
+
+// recordCredit writes the account's new state, if the transfer changed it, and the credit.
+func (uc *virtualAccountUseCase) recordCredit(ctx context.Context, va *entity.VirtualAccount, decision virtualaccount.Decision, credit *entity.VirtualAccountCredit, now time.Time) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		switch {
+		case decision.Outcome != entity.VirtualAccountCreditRejected:
+			previousPaid := va.PaidAmount
+			va.PaidAmount = decision.PaidAmount
+			if decision.Paid {
+				va.Status = entity.VirtualAccountStatusPaid
+				va.PaidAt = &now
+			}
+			if err := uc.virtualAccountRepo.ModifyCredited(ctx, tx, va, previousPaid); err != nil {
+				return err
+			}
+		case decision.RejectReason == entity.VirtualAccountRejectClosed && va.Status == entity.VirtualAccountStatusActive:
+			// The payment was settled or cancelled some other way; stop accepting transfers
+			va.Status = entity.VirtualAccountStatusClosed
+			if err := uc.virtualAccountRepo.ModifyStatus(ctx, tx, va); err != nil {
+				return err
+			}
+		}
+		return uc.virtualAccountRepo.StoreCredit(ctx, tx, credit)
+	}
+}
+
+// ExpireOverdue expires, for every merchant, the active accounts past their expiry and
+// late payment grace together with their payments. It returns how many were expired.
+func (uc *virtualAccountUseCase) ExpireOverdue(ctx context.Context) (int, error) {
+	merchantIDs, err := uc.merchantRepo.FetchActiveIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	cutoff := time.Now().Add(-uc.policy.LateGrace)
+	for _, merchantID := range merchantIDs {
+		mctx := tenant.WithMerchantID(ctx, merchantID)
+		accounts, err := uc.virtualAccountRepo.FetchExpired(mctx, cutoff)
+		if err != nil {
+			uc.logger.Error().Err(err).Str("merchant_id", merchantID.String()).Msg("❌ Failed to fetch expired virtual accounts")
+			return expired, err
+		}
+		for i := range accounts {
+			va := &accounts[i]
+			va.Status = entity.VirtualAccountStatusExpired
+			closeAccount := func(tx *sql.Tx) error { return uc.virtualAccountRepo.ModifyStatus(mctx, tx, va) }
+
+			_, err := uc.paymentUC.SettleTransfer(mctx, va.PaymentID, entity.PaymentStatusExpired, closeAccount)
+			if errors.Is(err, entity.ErrInvalidStatusTransition) {
+				// The payment already left PENDING; only the account is left to close
+				va.Status = entity.VirtualAccountStatusClosed
+				err = uc.inTx(mctx, closeAccount)
+			}
+			if errors.Is(err, entity.ErrVirtualAccountChanged) {
+				continue // a transfer settled the account in the meantime
+			}
+			if err != nil {
+				uc.logger.Error().Err(err).Str("virtual_account_id", va.ID.String()).Msg("❌ Failed to expire virtual account")
+				return expired, err
+			}
+
+			expired++
+			if va.PaidAmount.Sign() > 0 {
+				uc.logger.Warn().Str("virtual_account_id", va.ID.String()).Str("paid_amount", va.PaidAmount.String()).Msg("⚠️ Virtual account expired with partial transfers to return")
+			} else {
+				uc.logger.Info().Str("virtual_account_id", va.ID.String()).Str("status", va.Status).Msg("✅ Virtual account expired")
+			}
+		}
+	}
+	return expired, nil
+}
+
+func (uc *virtualAccountUseCase) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
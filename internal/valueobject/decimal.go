@@ -0,0 +1,249 @@
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+)
+
+// RoundingMode selects how Decimal.Round resolves digits beyond the requested scale.
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "HALF_UP"
+	RoundHalfEven RoundingMode = "HALF_EVEN"
+	RoundDown     RoundingMode = "DOWN"
+	RoundUp       RoundingMode = "UP"
+)
+
+var ErrInvalidDecimal = apperror.Validation("invalid_decimal", "invalid decimal")
+
+// maxDecimalScale bounds the digits written for values without an exact decimal form,
+// such as the inverse of an exchange rate.
+const maxDecimalScale = 18
+
+// decimalPattern keeps big.Rat from accepting fractions, exponents and base prefixes.
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// ParseRoundingMode validates a configured rounding mode.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToUpper(s)); mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q", s)
+	}
+}
+
+// Decimal is an exact decimal number backed by big.Rat. Unlike BigFloat it never
+// introduces binary rounding, so it is used wherever money is multiplied or divided.
+// A nil Rat is the SQL/JSON null.
+type Decimal struct {
+	*big.Rat
+}
+
+// ParseDecimal parses a plain decimal string such as "15600.25".
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	return Decimal{Rat: r}, nil
+}
+
+// DecimalFromInt returns the decimal for an integer.
+func DecimalFromInt(i int64) Decimal {
+	return Decimal{Rat: new(big.Rat).SetInt64(i)}
+}
+
+// DecimalFromBigFloat converts a stored amount. Amounts are parsed from decimal text,
+// so the shortest representation of the float is the amount that was stored.
+func DecimalFromBigFloat(bf BigFloat) Decimal {
+	if bf.Float == nil {
+		return Decimal{}
+	}
+	d, err := ParseDecimal(bf.Text('f', -1))
+	if err != nil {
+		return Decimal{}
+	}
+	return d
+}
+
+// BigFloatFromDecimal converts a decimal to the representation payment amounts use, at the
+// precision BigFloat parses amounts with.
+func BigFloatFromDecimal(d Decimal) BigFloat {
+	if d.Rat == nil {
+		return BigFloat{}
+	}
+	f, _, err := big.ParseFloat(d.String(), 10, 256, big.ToNearestEven)
+	if err != nil {
+		return BigFloat{}
+	}
+	return BigFloat{Float: f}
+}
+
+// IsNull reports whether the decimal holds no value.
+func (d Decimal) IsNull() bool {
+	return d.Rat == nil
+}
+
+func (d Decimal) rat() *big.Rat {
+	if d.Rat == nil {
+		return new(big.Rat)
+	}
+	return d.Rat
+}
+
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{Rat: new(big.Rat).Add(d.rat(), o.rat())}
+}
+
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{Rat: new(big.Rat).Sub(d.rat(), o.rat())}
+}
+
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{Rat: new(big.Rat).Mul(d.rat(), o.rat())}
+}
+
+// Quo divides d by o; o must not be zero.
+func (d Decimal) Quo(o Decimal) Decimal {
+	return Decimal{Rat: new(big.Rat).Quo(d.rat(), o.rat())}
+}
+
+func (d Decimal) Cmp(o Decimal) int {
+	return d.rat().Cmp(o.rat())
+}
+
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// Round rounds to scale fractional digits using mode.
+func (d Decimal) Round(scale int, mode RoundingMode) Decimal {
+	if d.Rat == nil {
+		return d
+	}
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	scaled := new(big.Rat).Mul(d.Rat, new(big.Rat).SetInt(pow))
+
+	// Truncate toward zero, then decide whether to step away from zero
+	q, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		twiceRem := new(big.Int).Abs(rem)
+		twiceRem.Lsh(twiceRem, 1)
+		half := twiceRem.Cmp(scaled.Denom())
+
+		awayFromZero := false
+		switch mode {
+		case RoundUp:
+			awayFromZero = true
+		case RoundHalfUp:
+			awayFromZero = half >= 0
+		case RoundHalfEven:
+			awayFromZero = half > 0 || (half == 0 && q.Bit(0) == 1)
+		}
+		if awayFromZero {
+			if scaled.Sign() < 0 {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+	return Decimal{Rat: new(big.Rat).SetFrac(q, pow)}
+}
+
+// String returns the exact decimal text, or at most maxDecimalScale digits when the
+// value has no finite decimal form.
+func (d Decimal) String() string {
+	if d.Rat == nil {
+		return ""
+	}
+	if d.IsInt() {
+		return d.Num().String()
+	}
+	for scale := 1; scale <= maxDecimalScale; scale++ {
+		if d.Round(scale, RoundDown).Cmp(d) == 0 {
+			return d.FloatString(scale)
+		}
+	}
+	return d.FloatString(maxDecimalScale)
+}
+
+// StringFixed rounds to scale and always writes scale fractional digits.
+func (d Decimal) StringFixed(scale int, mode RoundingMode) string {
+	if d.Rat == nil {
+		return ""
+	}
+	return d.Round(scale, mode).FloatString(scale)
+}
+
+//
+// 👇 JSON SUPPORT
+//
+
+// MarshalJSON writes the exact value as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.Rat == nil {
+		return []byte("null"), nil
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts both number and string (e.g. 123.45 or "123.45").
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		d.Rat = nil
+		return nil
+	}
+	str := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseDecimal(str)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+//
+// 👇 DATABASE SUPPORT
+//
+
+func (d Decimal) Value() (driver.Value, error) {
+	if d.Rat == nil {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+func (d *Decimal) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return d.UnmarshalJSON([]byte(`"` + v + `"`))
+	case []byte:
+		return d.UnmarshalJSON([]byte(`"` + string(v) + `"`))
+	case int64:
+		*d = DecimalFromInt(v)
+		return nil
+	case nil:
+		d.Rat = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported type for Decimal Scan: %T", value)
+	}
+}
@@ -0,0 +1,125 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mustDecimal(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("ParseDecimal(%q): %v", s, err)
+	}
+	return d
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		value string
+		scale int
+		want  map[RoundingMode]string
+	}{
+		// Halfway cases tell the modes apart
+		{"2.345", 2, map[RoundingMode]string{RoundHalfUp: "2.35", RoundHalfEven: "2.34", RoundDown: "2.34", RoundUp: "2.35"}},
+		{"2.355", 2, map[RoundingMode]string{RoundHalfUp: "2.36", RoundHalfEven: "2.36", RoundDown: "2.35", RoundUp: "2.36"}},
+		{"-2.345", 2, map[RoundingMode]string{RoundHalfUp: "-2.35", RoundHalfEven: "-2.34", RoundDown: "-2.34", RoundUp: "-2.35"}},
+		{"-2.355", 2, map[RoundingMode]string{RoundHalfUp: "-2.36", RoundHalfEven: "-2.36", RoundDown: "-2.35", RoundUp: "-2.36"}},
+		// Below and above half
+		{"2.3449", 2, map[RoundingMode]string{RoundHalfUp: "2.34", RoundHalfEven: "2.34", RoundDown: "2.34", RoundUp: "2.35"}},
+		{"2.3451", 2, map[RoundingMode]string{RoundHalfUp: "2.35", RoundHalfEven: "2.35", RoundDown: "2.34", RoundUp: "2.35"}},
+		{"-2.3451", 2, map[RoundingMode]string{RoundHalfUp: "-2.35", RoundHalfEven: "-2.35", RoundDown: "-2.34", RoundUp: "-2.35"}},
+		// Exact values are left alone
+		{"2.34", 2, map[RoundingMode]string{RoundHalfUp: "2.34", RoundHalfEven: "2.34", RoundDown: "2.34", RoundUp: "2.34"}},
+		{"0", 2, map[RoundingMode]string{RoundHalfUp: "0", RoundHalfEven: "0", RoundDown: "0", RoundUp: "0"}},
+		// Scale 0 and halves of whole units, where HALF_EVEN picks the even neighbour
+		{"0.5", 0, map[RoundingMode]string{RoundHalfUp: "1", RoundHalfEven: "0", RoundDown: "0", RoundUp: "1"}},
+		{"1.5", 0, map[RoundingMode]string{RoundHalfUp: "2", RoundHalfEven: "2", RoundDown: "1", RoundUp: "2"}},
+		{"2.5", 0, map[RoundingMode]string{RoundHalfUp: "3", RoundHalfEven: "2", RoundDown: "2", RoundUp: "3"}},
+		{"-0.5", 0, map[RoundingMode]string{RoundHalfUp: "-1", RoundHalfEven: "0", RoundDown: "0", RoundUp: "-1"}},
+		{"0.001", 2, map[RoundingMode]string{RoundHalfUp: "0", RoundHalfEven: "0", RoundDown: "0", RoundUp: "0.01"}},
+	}
+	for _, tt := range tests {
+		for mode, want := range tt.want {
+			got := mustDecimal(t, tt.value).Round(tt.scale, mode)
+			if got.Cmp(mustDecimal(t, want)) != 0 {
+				t.Errorf("Round(%s, %d, %s) = %s, want %s", tt.value, tt.scale, mode, got, want)
+			}
+		}
+	}
+}
+
+// A third has no finite decimal form, so the modes differ even without a halfway case.
+func TestRoundRepeatingFraction(t *testing.T) {
+	third := DecimalFromInt(1).Quo(DecimalFromInt(3))
+	for mode, want := range map[RoundingMode]string{RoundHalfUp: "0.33", RoundHalfEven: "0.33", RoundDown: "0.33", RoundUp: "0.34"} {
+		if got := third.Round(2, mode); got.String() != want {
+			t.Errorf("Round(1/3, 2, %s) = %s, want %s", mode, got, want)
+		}
+	}
+	twoThirds := DecimalFromInt(-2).Quo(DecimalFromInt(3))
+	for mode, want := range map[RoundingMode]string{RoundHalfUp: "-0.67", RoundHalfEven: "-0.67", RoundDown: "-0.66", RoundUp: "-0.67"} {
+		if got := twoThirds.Round(2, mode); got.String() != want {
+			t.Errorf("Round(-2/3, 2, %s) = %s, want %s", mode, got, want)
+		}
+	}
+}
+
+func TestStringFixed(t *testing.T) {
+	tests := []struct {
+		value string
+		mode  RoundingMode
+		want  string
+	}{
+		{"15000", RoundHalfEven, "15000.00"},
+		{"0.125", RoundHalfEven, "0.12"},
+		{"0.125", RoundHalfUp, "0.13"},
+		{"-0.125", RoundDown, "-0.12"},
+	}
+	for _, tt := range tests {
+		if got := mustDecimal(t, tt.value).StringFixed(2, tt.mode); got != tt.want {
+			t.Errorf("StringFixed(%s, 2, %s) = %s, want %s", tt.value, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	for _, s := range []string{"HALF_UP", "half_even", "Down", "UP"} {
+		if _, err := ParseRoundingMode(s); err != nil {
+			t.Errorf("ParseRoundingMode(%q): %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "CEILING", "HALF_DOWN"} {
+		if _, err := ParseRoundingMode(s); err == nil {
+			t.Errorf("ParseRoundingMode(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	for _, s := range []string{"1", "-1.50", "+0.001", ".5", "5."} {
+		if _, err := ParseDecimal(s); err != nil {
+			t.Errorf("ParseDecimal(%q): %v", s, err)
+		}
+	}
+	// big.Rat alone would accept these
+	for _, s := range []string{"", "1/3", "1e3", "0x10", "1,000", "NaN"} {
+		if _, err := ParseDecimal(s); err == nil {
+			t.Errorf("ParseDecimal(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var d Decimal
+	if err := json.Unmarshal([]byte(`"15600.25"`), &d); err != nil {
+		t.Fatalf("unmarshal string: %v", err)
+	}
+	if d.Cmp(mustDecimal(t, "15600.25")) != 0 {
+		t.Errorf("unmarshalled %s", d)
+	}
+	out, err := json.Marshal(d)
+	if err != nil || string(out) != "15600.25" {
+		t.Errorf("marshal = %s, %v", out, err)
+	}
+}
@@ -0,0 +1,97 @@
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/adf-code/beta-payment-api/internal/apperror"
+)
+
+const (
+	MaxMetadataKeys        = 50
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 500
+)
+
+var ErrInvalidMetadata = apperror.Validation("invalid_metadata", "invalid metadata")
+
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// Metadata is a set of caller supplied string key/value pairs stored as JSONB.
+// Values are strings only so that equality filters map onto JSONB containment.
+type Metadata map[string]string
+
+// Validate enforces the key count, key length and value size limits.
+func (m Metadata) Validate() error {
+	if len(m) > MaxMetadataKeys {
+		return fmt.Errorf("%w: at most %d keys are allowed", ErrInvalidMetadata, MaxMetadataKeys)
+	}
+	for k, v := range m {
+		if err := ValidateMetadataKey(k); err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(v) > MaxMetadataValueLength {
+			return fmt.Errorf("%w: value of %q exceeds %d characters", ErrInvalidMetadata, k, MaxMetadataValueLength)
+		}
+	}
+	return nil
+}
+
+func ValidateMetadataKey(k string) error {
+	if k == "" || utf8.RuneCountInString(k) > MaxMetadataKeyLength {
+		return fmt.Errorf("%w: key %q must be 1 to %d characters", ErrInvalidMetadata, k, MaxMetadataKeyLength)
+	}
+	if !metadataKeyPattern.MatchString(k) {
+		return fmt.Errorf("%w: key %q may only contain letters, digits, '_', '-' and '.'", ErrInvalidMetadata, k)
+	}
+	return nil
+}
+
+//
+// 👇 JSON SUPPORT
+//
+
+// Encode nil as an empty object so responses always carry a metadata object
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(m))
+}
+
+//
+// 👇 DATABASE SUPPORT
+//
+
+// Value converts Metadata to JSONB for INSERT/UPDATE
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(m))
+}
+
+// Scan converts a JSONB value into Metadata for SELECT
+func (m *Metadata) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	case nil:
+		*m = Metadata{}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type for Metadata Scan: %T", value)
+	}
+	decoded := Metadata{}
+	if err := json.Unmarshal(raw, (*map[string]string)(&decoded)); err != nil {
+		return err
+	}
+	*m = decoded
+	return nil
+}
@@ -0,0 +1,112 @@
+package virtualaccount
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+)
+
+// Number lengths accepted by Indonesian banks for virtual accounts.
+const (
+	MinNumberLength = 10
+	MaxNumberLength = 20
+
+	// minSequenceDigits keeps enough room after the prefix for a useful number of accounts
+	minSequenceDigits = 6
+)
+
+var ErrSequenceExhausted = errors.New("virtual account sequence exhausted for bank prefix")
+
+// Issuer builds virtual account numbers as bank prefix + zero padded sequence value + Luhn
+// check digit. The sequence comes from the database, so numbers never repeat across instances.
+type Issuer struct {
+	prefixes map[string]string
+	length   int
+}
+
+// NewIssuer validates the per-bank prefixes against the fixed number length.
+func NewIssuer(prefixes map[string]string, length int) (*Issuer, error) {
+	if length < MinNumberLength || length > MaxNumberLength {
+		return nil, fmt.Errorf("number length must be between %d and %d", MinNumberLength, MaxNumberLength)
+	}
+	if len(prefixes) == 0 {
+		return nil, errors.New("at least one bank prefix is required")
+	}
+	for bank, prefix := range prefixes {
+		if !isDigits(prefix) {
+			return nil, fmt.Errorf("prefix of %s must be digits", bank)
+		}
+		if length-len(prefix)-1 < minSequenceDigits {
+			return nil, fmt.Errorf("prefix of %s leaves fewer than %d sequence digits", bank, minSequenceDigits)
+		}
+	}
+	return &Issuer{prefixes: prefixes, length: length}, nil
+}
+
+// ParsePrefixes reads "BANK:prefix" pairs separated by commas, e.g. "BCA:39358,BNI:8808".
+func ParsePrefixes(s string) (map[string]string, error) {
+	prefixes := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		bank, prefix, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid bank prefix %q", pair)
+		}
+		prefixes[strings.ToUpper(strings.TrimSpace(bank))] = strings.TrimSpace(prefix)
+	}
+	return prefixes, nil
+}
+
+// Number returns the virtual account number of bank for sequence value seq.
+func (i *Issuer) Number(bank string, seq int64) (string, error) {
+	prefix, ok := i.prefixes[bank]
+	if !ok {
+		return "", entity.ErrUnknownVirtualAccountBank
+	}
+	digits := i.length - len(prefix) - 1
+	body := strconv.FormatInt(seq, 10)
+	if seq <= 0 || len(body) > digits {
+		return "", ErrSequenceExhausted
+	}
+	partial := prefix + strings.Repeat("0", digits-len(body)) + body
+	return partial + string(rune('0'+luhnCheckDigit(partial))), nil
+}
+
+// Valid reports whether number has the issuer's length and a correct check digit, which
+// catches mistyped numbers before any lookup.
+func (i *Issuer) Valid(number string) bool {
+	return len(number) == i.length && isDigits(number) && luhnCheckDigit(number[:len(number)-1]) == int(number[len(number)-1]-'0')
+}
+
+// luhnCheckDigit returns the digit that makes partial + digit pass the Luhn check.
+func luhnCheckDigit(partial string) int {
+	sum := 0
+	double := true
+	for i := len(partial) - 1; i >= 0; i-- {
+		d := int(partial[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,78 @@
+package virtualaccount
+
+import (
+	"errors"
+	"time"
+
+	"github.com/adf-code/beta-payment-api/internal/entity"
+	"github.com/adf-code/beta-payment-api/internal/valueobject"
+)
+
+// What to do with transfers that do not match the expected amount.
+const (
+	OverpaymentAccept = "ACCEPT" // mark the payment paid; the excess is due back to the customer
+	OverpaymentReject = "REJECT"
+
+	UnderpaymentReject     = "REJECT"
+	UnderpaymentAccumulate = "ACCUMULATE" // keep the account open until the transfers add up
+)
+
+// Policy decides how a transfer into a virtual account is handled.
+type Policy struct {
+	Overpayment  string
+	Underpayment string
+	// LateGrace accepts transfers arriving this long after the account expired
+	LateGrace time.Duration
+}
+
+func (p Policy) Validate() error {
+	if p.Overpayment != OverpaymentAccept && p.Overpayment != OverpaymentReject {
+		return errors.New("overpayment policy must be ACCEPT or REJECT")
+	}
+	if p.Underpayment != UnderpaymentReject && p.Underpayment != UnderpaymentAccumulate {
+		return errors.New("underpayment policy must be REJECT or ACCUMULATE")
+	}
+	if p.LateGrace < 0 {
+		return errors.New("late payment grace must not be negative")
+	}
+	return nil
+}
+
+// Decision is the result of applying a transfer to a virtual account.
+type Decision struct {
+	Outcome      string
+	RejectReason string
+	// PaidAmount is the account's total after the transfer; Paid is set when it settles the payment
+	PaidAmount valueobject.Decimal
+	Paid       bool
+}
+
+// Decide applies a transfer of amount at now to va, whose payment is still payable when
+// paymentPending is set.
+func (p Policy) Decide(va *entity.VirtualAccount, paymentPending bool, amount valueobject.Decimal, now time.Time) Decision {
+	reject := func(reason string) Decision {
+		return Decision{Outcome: entity.VirtualAccountCreditRejected, RejectReason: reason, PaidAmount: va.PaidAmount}
+	}
+	if va.Status != entity.VirtualAccountStatusActive || !paymentPending {
+		return reject(entity.VirtualAccountRejectClosed)
+	}
+	if now.After(va.ExpiresAt.Add(p.LateGrace)) {
+		return reject(entity.VirtualAccountRejectLate)
+	}
+
+	total := va.PaidAmount.Add(amount)
+	switch total.Cmp(va.ExpectedAmount) {
+	case 0:
+		return Decision{Outcome: entity.VirtualAccountCreditAccepted, PaidAmount: total, Paid: true}
+	case 1:
+		if p.Overpayment == OverpaymentReject {
+			return reject(entity.VirtualAccountRejectOverpaid)
+		}
+		return Decision{Outcome: entity.VirtualAccountCreditOverpaid, PaidAmount: total, Paid: true}
+	default:
+		if p.Underpayment == UnderpaymentReject {
+			return reject(entity.VirtualAccountRejectUnderpaid)
+		}
+		return Decision{Outcome: entity.VirtualAccountCreditPartial, PaidAmount: total}
+	}
+}
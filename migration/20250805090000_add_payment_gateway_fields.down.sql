@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_payments_provider_reference;
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS refunded_amount,
+    DROP COLUMN IF EXISTS provider_reference,
+    DROP COLUMN IF EXISTS provider,
+    DROP COLUMN IF EXISTS method,
+    DROP COLUMN IF EXISTS currency;
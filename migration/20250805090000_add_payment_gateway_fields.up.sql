@@ -0,0 +1,14 @@
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'IDR',
+    ADD COLUMN IF NOT EXISTS method TEXT NOT NULL DEFAULT 'CARD',
+    ADD COLUMN IF NOT EXISTS provider TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS provider_reference TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS refunded_amount NUMERIC(12, 2) NOT NULL DEFAULT 0;
+
+-- Create index on payments.provider_reference if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_provider_reference') THEN
+CREATE INDEX idx_payments_provider_reference ON payments(provider, provider_reference);
+END IF;
+END$$;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_provider_callbacks_received_at;
+DROP INDEX IF EXISTS idx_provider_callbacks_event;
+
+DROP TABLE IF EXISTS provider_callbacks;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS provider_callbacks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    provider TEXT NOT NULL,
+    event_id TEXT,
+    reference TEXT,
+    payment_id UUID REFERENCES payments(id),
+    status TEXT,
+    signature_valid BOOLEAN NOT NULL,
+    outcome TEXT NOT NULL DEFAULT 'RECEIVED',
+    error TEXT,
+    headers JSONB NOT NULL DEFAULT '{}'::jsonb,
+    payload TEXT NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP
+    );
+
+-- Deduplicate verified callbacks by provider event ID
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_provider_callbacks_event') THEN
+CREATE UNIQUE INDEX idx_provider_callbacks_event ON provider_callbacks(provider, event_id) WHERE signature_valid;
+END IF;
+END$$;
+
+-- Create index on provider_callbacks.received_at if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_provider_callbacks_received_at') THEN
+CREATE INDEX idx_provider_callbacks_received_at ON provider_callbacks(received_at);
+END IF;
+END$$;
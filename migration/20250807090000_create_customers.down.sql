@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_payments_customer_id;
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS customer_id;
+
+DROP INDEX IF EXISTS idx_customers_email;
+DROP INDEX IF EXISTS idx_customers_external_reference;
+
+DROP TABLE IF EXISTS customers;
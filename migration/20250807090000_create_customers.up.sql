@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    name TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    external_reference TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+    );
+
+-- External references are unique among customers that are not deleted
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_customers_external_reference') THEN
+CREATE UNIQUE INDEX idx_customers_external_reference ON customers(external_reference) WHERE deleted_at IS NULL AND external_reference IS NOT NULL;
+END IF;
+END$$;
+
+-- Create index on customers.email if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_customers_email') THEN
+CREATE INDEX idx_customers_email ON customers(email);
+END IF;
+END$$;
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS customer_id UUID REFERENCES customers(id) ON DELETE RESTRICT;
+
+-- Create index on payments.customer_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_customer_id') THEN
+CREATE INDEX idx_payments_customer_id ON payments(customer_id);
+END IF;
+END$$;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payments_metadata;
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS metadata;
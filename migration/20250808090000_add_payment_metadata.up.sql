@@ -0,0 +1,11 @@
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb
+    CONSTRAINT chk_payments_metadata_object CHECK (jsonb_typeof(metadata) = 'object');
+
+-- GIN index backing metadata containment filters (metadata @> '{"order_id":"123"}')
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_metadata') THEN
+CREATE INDEX idx_payments_metadata ON payments USING GIN (metadata jsonb_path_ops);
+END IF;
+END$$;
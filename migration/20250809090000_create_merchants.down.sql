@@ -0,0 +1,20 @@
+DROP FUNCTION IF EXISTS payment_merchant_by_provider_reference(TEXT, TEXT);
+
+DROP POLICY IF EXISTS customers_tenant_isolation ON customers;
+ALTER TABLE customers DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS payments_tenant_isolation ON payments;
+ALTER TABLE payments DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_customers_merchant_id;
+DROP INDEX IF EXISTS idx_payments_merchant_id;
+
+DROP INDEX IF EXISTS idx_customers_external_reference;
+CREATE UNIQUE INDEX idx_customers_external_reference ON customers(external_reference) WHERE deleted_at IS NULL AND external_reference IS NOT NULL;
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS fk_payments_customer_merchant;
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS uq_customers_id_merchant;
+
+ALTER TABLE customers DROP COLUMN IF EXISTS merchant_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS merchant_id;
+
+DROP TABLE IF EXISTS merchants;
@@ -0,0 +1,79 @@
+CREATE TABLE IF NOT EXISTS merchants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    name TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'ACTIVE',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Existing data belongs to the default merchant
+INSERT INTO merchants (id, name)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default Merchant')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS merchant_id UUID REFERENCES merchants(id);
+UPDATE payments SET merchant_id = '00000000-0000-0000-0000-000000000001' WHERE merchant_id IS NULL;
+ALTER TABLE payments
+    ALTER COLUMN merchant_id SET NOT NULL;
+
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS merchant_id UUID REFERENCES merchants(id);
+UPDATE customers SET merchant_id = '00000000-0000-0000-0000-000000000001' WHERE merchant_id IS NULL;
+ALTER TABLE customers
+    ALTER COLUMN merchant_id SET NOT NULL;
+
+-- A payment may only reference a customer of the same merchant
+ALTER TABLE customers
+    ADD CONSTRAINT uq_customers_id_merchant UNIQUE (id, merchant_id);
+ALTER TABLE payments
+    ADD CONSTRAINT fk_payments_customer_merchant FOREIGN KEY (customer_id, merchant_id) REFERENCES customers(id, merchant_id);
+
+-- External references are unique per merchant
+DROP INDEX IF EXISTS idx_customers_external_reference;
+CREATE UNIQUE INDEX idx_customers_external_reference ON customers(merchant_id, external_reference) WHERE deleted_at IS NULL AND external_reference IS NOT NULL;
+
+-- Create index on payments.merchant_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_merchant_id') THEN
+CREATE INDEX idx_payments_merchant_id ON payments(merchant_id, created_at);
+END IF;
+END$$;
+
+-- Create index on customers.merchant_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_customers_merchant_id') THEN
+CREATE INDEX idx_customers_merchant_id ON customers(merchant_id, created_at);
+END IF;
+END$$;
+
+-- Row level security, scoped by the per-transaction app.merchant_id setting.
+-- Policies bind roles that do not own the tables: run the API as a dedicated role
+-- (or FORCE ROW LEVEL SECURITY) together with DB_ROW_LEVEL_SECURITY=true.
+ALTER TABLE payments ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS payments_tenant_isolation ON payments;
+CREATE POLICY payments_tenant_isolation ON payments
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+ALTER TABLE customers ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS customers_tenant_isolation ON customers;
+CREATE POLICY customers_tenant_isolation ON customers
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+-- Provider callbacks arrive without a merchant; this resolves the owning merchant of a
+-- provider reference. SECURITY DEFINER lets it see past the row level policies.
+CREATE OR REPLACE FUNCTION payment_merchant_by_provider_reference(p_provider TEXT, p_reference TEXT)
+RETURNS UUID
+LANGUAGE sql
+STABLE
+SECURITY DEFINER
+SET search_path = public
+AS $$
+    SELECT merchant_id FROM payments
+    WHERE provider = p_provider AND provider_reference = p_reference AND deleted_at IS NULL
+    LIMIT 1
+$$;
@@ -0,0 +1,6 @@
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS settlement_amount,
+    DROP COLUMN IF EXISTS fx_rate,
+    DROP COLUMN IF EXISTS settlement_currency;
+
+DROP TABLE IF EXISTS fx_rates;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    base_currency TEXT NOT NULL,
+    quote_currency TEXT NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+    effective_at TIMESTAMP NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_fx_rates_pair_effective_at UNIQUE (base_currency, quote_currency, effective_at)
+    );
+
+-- Settlement amount recorded at capture time
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS settlement_currency TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS fx_rate NUMERIC(24, 12),
+    ADD COLUMN IF NOT EXISTS settlement_amount NUMERIC(18, 2);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payment_fees;
+DROP TABLE IF EXISTS fee_rules;
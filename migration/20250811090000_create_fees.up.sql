@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID REFERENCES merchants(id),
+    name TEXT NOT NULL,
+    method TEXT NOT NULL DEFAULT '',
+    currency TEXT NOT NULL DEFAULT '',
+    min_amount NUMERIC(18, 2),
+    max_amount NUMERIC(18, 2),
+    type TEXT NOT NULL,
+    fixed_amount NUMERIC(18, 2),
+    percentage NUMERIC(9, 6),
+    tiers JSONB NOT NULL DEFAULT '[]'::jsonb,
+    min_fee NUMERIC(18, 2),
+    max_fee NUMERIC(18, 2),
+    priority INTEGER NOT NULL DEFAULT 100,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE TABLE IF NOT EXISTS payment_fees (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    fee_rule_id UUID REFERENCES fee_rules(id) ON DELETE SET NULL,
+    type TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    amount NUMERIC(18, 2) NOT NULL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Create index on payment_fees.payment_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payment_fees_payment_id') THEN
+CREATE INDEX idx_payment_fees_payment_id ON payment_fees(payment_id);
+END IF;
+END$$;
+
+ALTER TABLE payment_fees ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS payment_fees_tenant_isolation ON payment_fees;
+CREATE POLICY payment_fees_tenant_isolation ON payment_fees
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
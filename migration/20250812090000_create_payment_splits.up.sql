@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS payment_splits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    position INTEGER NOT NULL,
+    recipient_id TEXT NOT NULL,
+    percentage NUMERIC(9, 6),
+    amount NUMERIC(18, 2) NOT NULL,
+    refunded_amount NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (payment_id, recipient_id),
+    CHECK (refunded_amount >= 0 AND refunded_amount <= amount)
+    );
+
+-- Create index on payment_splits.recipient_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payment_splits_merchant_recipient') THEN
+CREATE INDEX idx_payment_splits_merchant_recipient ON payment_splits(merchant_id, recipient_id);
+END IF;
+END$$;
+
+ALTER TABLE payment_splits ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS payment_splits_tenant_isolation ON payment_splits;
+CREATE POLICY payment_splits_tenant_isolation ON payment_splits
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS payment_reversals;
+DROP TABLE IF EXISTS dispute_evidence;
+DROP TABLE IF EXISTS disputes;
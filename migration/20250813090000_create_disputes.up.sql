@@ -0,0 +1,92 @@
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    status TEXT NOT NULL DEFAULT 'OPENED',
+    reason_code TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    amount NUMERIC(18, 2) NOT NULL,
+    currency TEXT NOT NULL,
+    payment_status_before TEXT NOT NULL,
+    provider_case_id TEXT NOT NULL DEFAULT '',
+    evidence_due_at TIMESTAMP NOT NULL,
+    deadline_flagged_at TIMESTAMP,
+    submitted_at TIMESTAMP,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- A payment has at most one open dispute
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'uq_disputes_open_payment') THEN
+CREATE UNIQUE INDEX uq_disputes_open_payment ON disputes(payment_id) WHERE status NOT IN ('WON', 'LOST');
+END IF;
+END$$;
+
+-- Serves the deadline job, which only looks at disputes still waiting for evidence
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_disputes_evidence_due_at') THEN
+CREATE INDEX idx_disputes_evidence_due_at ON disputes(evidence_due_at) WHERE deadline_flagged_at IS NULL AND status IN ('OPENED', 'EVIDENCE_REQUIRED');
+END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS dispute_evidence (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    dispute_id UUID NOT NULL REFERENCES disputes(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    file_name TEXT NOT NULL DEFAULT '',
+    content_type TEXT NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    content BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Create index on dispute_evidence.dispute_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_dispute_evidence_dispute_id') THEN
+CREATE INDEX idx_dispute_evidence_dispute_id ON dispute_evidence(dispute_id);
+END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS payment_reversals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    dispute_id UUID REFERENCES disputes(id) ON DELETE RESTRICT,
+    amount NUMERIC(18, 2) NOT NULL,
+    currency TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Create index on payment_reversals.payment_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payment_reversals_payment_id') THEN
+CREATE INDEX idx_payment_reversals_payment_id ON payment_reversals(payment_id);
+END IF;
+END$$;
+
+ALTER TABLE disputes ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS disputes_tenant_isolation ON disputes;
+CREATE POLICY disputes_tenant_isolation ON disputes
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+ALTER TABLE dispute_evidence ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS dispute_evidence_tenant_isolation ON dispute_evidence;
+CREATE POLICY dispute_evidence_tenant_isolation ON dispute_evidence
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+ALTER TABLE payment_reversals ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS payment_reversals_tenant_isolation ON payment_reversals;
+CREATE POLICY payment_reversals_tenant_isolation ON payment_reversals
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS payment_link_merchant(UUID);
+DROP TABLE IF EXISTS payment_links;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS payment_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    status TEXT NOT NULL DEFAULT 'ACTIVE',
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Create index on payment_links.payment_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payment_links_payment_id') THEN
+CREATE INDEX idx_payment_links_payment_id ON payment_links(payment_id);
+END IF;
+END$$;
+
+ALTER TABLE payment_links ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS payment_links_tenant_isolation ON payment_links;
+CREATE POLICY payment_links_tenant_isolation ON payment_links
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+-- Checkout pages are opened without a merchant; this resolves the owner of a link whose
+-- token signature was already verified. SECURITY DEFINER lets it see past the policies.
+CREATE OR REPLACE FUNCTION payment_link_merchant(p_link_id UUID)
+RETURNS UUID
+LANGUAGE sql
+STABLE
+SECURITY DEFINER
+SET search_path = public
+AS $$
+    SELECT merchant_id FROM payment_links WHERE id = p_link_id
+$$;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS virtual_account_credits;
+DROP TABLE IF EXISTS virtual_accounts;
+DROP SEQUENCE IF EXISTS virtual_account_number_seq;
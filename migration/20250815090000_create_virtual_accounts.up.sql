@@ -0,0 +1,58 @@
+-- Shared by every bank prefix; the number format leaves room for the whole range in use
+CREATE SEQUENCE IF NOT EXISTS virtual_account_number_seq;
+
+CREATE TABLE IF NOT EXISTS virtual_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    bank TEXT NOT NULL,
+    number TEXT NOT NULL UNIQUE,
+    expected_amount NUMERIC(12, 2) NOT NULL CHECK (expected_amount > 0),
+    paid_amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'ACTIVE',
+    expires_at TIMESTAMP NOT NULL,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- One active virtual account per payment
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_virtual_accounts_active_payment') THEN
+CREATE UNIQUE INDEX idx_virtual_accounts_active_payment ON virtual_accounts(payment_id) WHERE status = 'ACTIVE';
+END IF;
+END$$;
+
+-- Create index on virtual_accounts.expires_at if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_virtual_accounts_active_expires_at') THEN
+CREATE INDEX idx_virtual_accounts_active_expires_at ON virtual_accounts(expires_at) WHERE status = 'ACTIVE';
+END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS virtual_account_credits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    virtual_account_id UUID NOT NULL REFERENCES virtual_accounts(id) ON DELETE CASCADE,
+    transfer_reference TEXT NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    outcome TEXT NOT NULL,
+    reject_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (virtual_account_id, transfer_reference)
+    );
+
+ALTER TABLE virtual_accounts ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS virtual_accounts_tenant_isolation ON virtual_accounts;
+CREATE POLICY virtual_accounts_tenant_isolation ON virtual_accounts
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+ALTER TABLE virtual_account_credits ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS virtual_account_credits_tenant_isolation ON virtual_account_credits;
+CREATE POLICY virtual_account_credits_tenant_isolation ON virtual_account_credits
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS payments_sync_invoice ON payments;
+DROP FUNCTION IF EXISTS payments_sync_invoice();
+DROP FUNCTION IF EXISTS refresh_invoice_payment_state(UUID);
+ALTER TABLE payments DROP COLUMN IF EXISTS invoice_id;
+DROP TABLE IF EXISTS invoice_items;
+DROP TABLE IF EXISTS invoices;
+DROP SEQUENCE IF EXISTS invoice_number_seq;
@@ -0,0 +1,124 @@
+CREATE SEQUENCE IF NOT EXISTS invoice_number_seq;
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    customer_id UUID REFERENCES customers(id) ON DELETE RESTRICT,
+    number TEXT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'DRAFT',
+    notes TEXT NOT NULL DEFAULT '',
+    subtotal NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    discount_total NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    tax_total NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    total NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    amount_paid NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    due_date DATE NOT NULL,
+    issued_at TIMESTAMP,
+    paid_at TIMESTAMP,
+    voided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (merchant_id, number)
+    );
+
+-- Create index on invoices.status if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_invoices_merchant_status') THEN
+CREATE INDEX idx_invoices_merchant_status ON invoices(merchant_id, status);
+END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS invoice_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    description TEXT NOT NULL,
+    quantity NUMERIC(12, 4) NOT NULL CHECK (quantity > 0),
+    unit_price NUMERIC(12, 2) NOT NULL CHECK (unit_price >= 0),
+    discount NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (discount >= 0),
+    tax_rate NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (tax_rate >= 0 AND tax_rate <= 100),
+    subtotal NUMERIC(12, 2) NOT NULL,
+    tax_amount NUMERIC(12, 2) NOT NULL,
+    total NUMERIC(12, 2) NOT NULL,
+    UNIQUE (invoice_id, position)
+    );
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS invoice_id UUID REFERENCES invoices(id) ON DELETE RESTRICT;
+
+-- Create index on payments.invoice_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_invoice_id') THEN
+CREATE INDEX idx_payments_invoice_id ON payments(invoice_id) WHERE invoice_id IS NOT NULL;
+END IF;
+END$$;
+
+ALTER TABLE invoices ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS invoices_tenant_isolation ON invoices;
+CREATE POLICY invoices_tenant_isolation ON invoices
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+ALTER TABLE invoice_items ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS invoice_items_tenant_isolation ON invoice_items;
+CREATE POLICY invoice_items_tenant_isolation ON invoice_items
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+-- Payments change status on many paths (capture, provider callbacks, refunds, disputes,
+-- bank transfers), so the invoice they settle is kept in step here rather than in each one.
+-- Money still held counts as paid: captured, partially refunded and disputed payments, net
+-- of refunds. DRAFT and VOID invoices keep their status.
+CREATE OR REPLACE FUNCTION refresh_invoice_payment_state(p_invoice_id UUID)
+RETURNS VOID
+LANGUAGE sql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+    UPDATE invoices i
+    SET amount_paid = paid.total,
+        status = CASE
+            WHEN i.status IN ('DRAFT', 'VOID') THEN i.status
+            WHEN paid.total >= i.total THEN 'PAID'
+            WHEN paid.total > 0 THEN 'PARTIALLY_PAID'
+            ELSE 'OPEN'
+        END,
+        paid_at = CASE
+            WHEN i.status IN ('DRAFT', 'VOID') THEN i.paid_at
+            WHEN paid.total >= i.total THEN COALESCE(i.paid_at, NOW())
+        END,
+        updated_at = NOW()
+    FROM (
+        SELECT COALESCE(SUM(amount - refunded_amount), 0) AS total
+        FROM payments
+        WHERE invoice_id = p_invoice_id
+          AND deleted_at IS NULL
+          AND status IN ('PAID', 'PARTIALLY_REFUNDED', 'DISPUTED')
+    ) paid
+    WHERE i.id = p_invoice_id
+$$;
+
+CREATE OR REPLACE FUNCTION payments_sync_invoice()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.invoice_id IS NOT NULL AND OLD.invoice_id IS DISTINCT FROM NEW.invoice_id THEN
+        PERFORM refresh_invoice_payment_state(OLD.invoice_id);
+    END IF;
+    IF NEW.invoice_id IS NOT NULL THEN
+        PERFORM refresh_invoice_payment_state(NEW.invoice_id);
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS payments_sync_invoice ON payments;
+CREATE TRIGGER payments_sync_invoice
+    AFTER INSERT OR UPDATE OF status, amount, refunded_amount, invoice_id, deleted_at ON payments
+    FOR EACH ROW EXECUTE FUNCTION payments_sync_invoice();
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS settlement_batches_immutable ON settlement_batches;
+DROP FUNCTION IF EXISTS settlement_batches_immutable();
+DROP TRIGGER IF EXISTS settlement_batch_items_immutable ON settlement_batch_items;
+DROP FUNCTION IF EXISTS settlement_batch_items_immutable();
+DROP INDEX IF EXISTS idx_payments_unsettled;
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS settled_refunded_amount,
+    DROP COLUMN IF EXISTS settlement_batch_id;
+DROP TABLE IF EXISTS settlement_batch_items;
+DROP TABLE IF EXISTS settlement_batches;
+ALTER TABLE merchants
+    DROP COLUMN IF EXISTS payout_bank_code,
+    DROP COLUMN IF EXISTS payout_account_number,
+    DROP COLUMN IF EXISTS payout_account_name;
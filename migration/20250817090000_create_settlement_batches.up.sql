@@ -0,0 +1,128 @@
+-- Bank account merchants are paid out to
+ALTER TABLE merchants
+    ADD COLUMN IF NOT EXISTS payout_account_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS payout_account_number TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS payout_bank_code TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS settlement_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    currency TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'GENERATED',
+    cutoff_at TIMESTAMP NOT NULL,
+    item_count INTEGER NOT NULL,
+    gross_amount NUMERIC(14, 2) NOT NULL,
+    refund_amount NUMERIC(14, 2) NOT NULL,
+    fee_amount NUMERIC(14, 2) NOT NULL,
+    net_amount NUMERIC(14, 2) NOT NULL CHECK (net_amount > 0),
+    payout_account_name TEXT NOT NULL,
+    payout_account_number TEXT NOT NULL,
+    payout_bank_code TEXT NOT NULL,
+    sent_at TIMESTAMP,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE TABLE IF NOT EXISTS settlement_batch_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    batch_id UUID NOT NULL REFERENCES settlement_batches(id) ON DELETE RESTRICT,
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    type TEXT NOT NULL,
+    gross_amount NUMERIC(12, 2) NOT NULL,
+    refund_amount NUMERIC(12, 2) NOT NULL,
+    fee_amount NUMERIC(12, 2) NOT NULL,
+    net_amount NUMERIC(12, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- settlement_batch_id is the batch that paid the payment out; settled_refunded_amount is
+-- how much of its refunds have been deducted so far, so later refunds are deducted once.
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS settlement_batch_id UUID REFERENCES settlement_batches(id) ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS settled_refunded_amount NUMERIC(12, 2) NOT NULL DEFAULT 0;
+
+-- Create index on settlement_batches.merchant_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_settlement_batches_merchant_id') THEN
+CREATE INDEX idx_settlement_batches_merchant_id ON settlement_batches(merchant_id, created_at);
+END IF;
+END$$;
+
+-- Create index on settlement_batch_items.batch_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_settlement_batch_items_batch_id') THEN
+CREATE INDEX idx_settlement_batch_items_batch_id ON settlement_batch_items(batch_id);
+END IF;
+END$$;
+
+-- A payment is paid out in exactly one batch; refund adjustments may follow in others
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_settlement_batch_items_payment') THEN
+CREATE UNIQUE INDEX idx_settlement_batch_items_payment ON settlement_batch_items(payment_id) WHERE type = 'PAYMENT';
+END IF;
+END$$;
+
+-- Create index on unsettled captured payments if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_unsettled') THEN
+CREATE INDEX idx_payments_unsettled ON payments(merchant_id, updated_at) WHERE settlement_batch_id IS NULL AND deleted_at IS NULL;
+END IF;
+END$$;
+
+ALTER TABLE settlement_batches ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS settlement_batches_tenant_isolation ON settlement_batches;
+CREATE POLICY settlement_batches_tenant_isolation ON settlement_batches
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+ALTER TABLE settlement_batch_items ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS settlement_batch_items_tenant_isolation ON settlement_batch_items;
+CREATE POLICY settlement_batch_items_tenant_isolation ON settlement_batch_items
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+
+-- Payout files are rendered from the stored batch, so once generated its contents may not
+-- change: items are insert only and a batch may only move through its status.
+CREATE OR REPLACE FUNCTION settlement_batch_items_immutable()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'settlement batch items are immutable';
+END
+$$;
+
+DROP TRIGGER IF EXISTS settlement_batch_items_immutable ON settlement_batch_items;
+CREATE TRIGGER settlement_batch_items_immutable
+    BEFORE UPDATE OR DELETE ON settlement_batch_items
+    FOR EACH ROW EXECUTE FUNCTION settlement_batch_items_immutable();
+
+CREATE OR REPLACE FUNCTION settlement_batches_immutable()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        RAISE EXCEPTION 'settlement batches cannot be deleted';
+    END IF;
+    IF (NEW.merchant_id, NEW.currency, NEW.cutoff_at, NEW.item_count, NEW.gross_amount, NEW.refund_amount,
+        NEW.fee_amount, NEW.net_amount, NEW.payout_account_name, NEW.payout_account_number, NEW.payout_bank_code, NEW.created_at)
+        IS DISTINCT FROM
+       (OLD.merchant_id, OLD.currency, OLD.cutoff_at, OLD.item_count, OLD.gross_amount, OLD.refund_amount,
+        OLD.fee_amount, OLD.net_amount, OLD.payout_account_name, OLD.payout_account_number, OLD.payout_bank_code, OLD.created_at) THEN
+        RAISE EXCEPTION 'settlement batch contents are immutable';
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS settlement_batches_immutable ON settlement_batches;
+CREATE TRIGGER settlement_batches_immutable
+    BEFORE UPDATE OR DELETE ON settlement_batches
+    FOR EACH ROW EXECUTE FUNCTION settlement_batches_immutable();
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS risk_decisions;
+DROP INDEX IF EXISTS idx_payments_merchant_client_ip_created;
+DROP INDEX IF EXISTS idx_payments_merchant_customer_created;
+DROP INDEX IF EXISTS idx_payments_merchant_tag_created;
+ALTER TABLE payments DROP COLUMN IF EXISTS client_ip;
@@ -0,0 +1,62 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS client_ip TEXT NOT NULL DEFAULT '';
+
+-- Velocity rules count recent payments per tag, customer and client IP
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_merchant_tag_created') THEN
+CREATE INDEX idx_payments_merchant_tag_created ON payments(merchant_id, tag, created_at);
+END IF;
+END$$;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_merchant_customer_created') THEN
+CREATE INDEX idx_payments_merchant_customer_created ON payments(merchant_id, customer_id, created_at) WHERE customer_id IS NOT NULL;
+END IF;
+END$$;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payments_merchant_client_ip_created') THEN
+CREATE INDEX idx_payments_merchant_client_ip_created ON payments(merchant_id, client_ip, created_at) WHERE client_ip <> '';
+END IF;
+END$$;
+
+-- Every risk decision, including denials which leave no payment behind
+CREATE TABLE IF NOT EXISTS risk_decisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    payment_id UUID REFERENCES payments(id) ON DELETE RESTRICT,
+    action TEXT NOT NULL,
+    score INTEGER NOT NULL,
+    reason_code TEXT NOT NULL DEFAULT '',
+    reasons JSONB NOT NULL DEFAULT '[]'::jsonb,
+    tag TEXT NOT NULL DEFAULT '',
+    customer_id UUID,
+    client_ip TEXT NOT NULL DEFAULT '',
+    amount NUMERIC(12, 2) NOT NULL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Create index on risk_decisions.merchant_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_risk_decisions_merchant_id') THEN
+CREATE INDEX idx_risk_decisions_merchant_id ON risk_decisions(merchant_id, created_at);
+END IF;
+END$$;
+
+-- Create index on risk_decisions.payment_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_risk_decisions_payment_id') THEN
+CREATE INDEX idx_risk_decisions_payment_id ON risk_decisions(payment_id) WHERE payment_id IS NOT NULL;
+END IF;
+END$$;
+
+ALTER TABLE risk_decisions ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS risk_decisions_tenant_isolation ON risk_decisions;
+CREATE POLICY risk_decisions_tenant_isolation ON risk_decisions
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
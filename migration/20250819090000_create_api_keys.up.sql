@@ -0,0 +1,31 @@
+-- API keys are looked up before the caller's merchant is known, so the table has no
+-- row level policy; only the API and the admin command read it
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    merchant_id UUID NOT NULL REFERENCES merchants(id),
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    salt BYTEA NOT NULL,
+    key_hash BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP
+    );
+
+-- Create unique index on api_keys.prefix if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_api_keys_prefix') THEN
+CREATE UNIQUE INDEX idx_api_keys_prefix ON api_keys(prefix);
+END IF;
+END$$;
+
+-- Create index on api_keys.merchant_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_api_keys_merchant_id') THEN
+CREATE INDEX idx_api_keys_merchant_id ON api_keys(merchant_id, created_at);
+END IF;
+END$$;
@@ -0,0 +1,3 @@
+-- HMAC keys sign requests; their secret is derived from a server secret, so salt and
+-- key_hash only ever authenticate BEARER keys
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'BEARER';
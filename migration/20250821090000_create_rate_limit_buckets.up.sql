@@ -0,0 +1,16 @@
+-- Token buckets shared by every replica. They can be rebuilt from nothing, so the table
+-- skips the write-ahead log; buckets are lost on a crash and simply start full again.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+    );
+
+-- Create index on rate_limit_buckets.updated_at if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_rate_limit_buckets_updated_at') THEN
+CREATE INDEX idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
+END IF;
+END$$;
@@ -0,0 +1,13 @@
+-- Customer name, email and phone and payment descriptions can be stored encrypted, which
+-- hides them from equality search. Email lookups use a keyed hash of the address instead;
+-- it stays empty for customers stored in plaintext.
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS email_bidx TEXT NOT NULL DEFAULT '';
+
+-- Create index on customers.email_bidx if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_customers_email_bidx') THEN
+CREATE INDEX idx_customers_email_bidx ON customers(email_bidx);
+END IF;
+END$$;
@@ -0,0 +1,8 @@
+DROP POLICY IF EXISTS provider_callbacks_ingest ON provider_callbacks;
+DROP POLICY IF EXISTS provider_callbacks_tenant_isolation ON provider_callbacks;
+ALTER TABLE provider_callbacks DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_provider_callbacks_merchant_id;
+
+ALTER TABLE provider_callbacks
+    DROP COLUMN IF EXISTS merchant_id;
@@ -0,0 +1,27 @@
+-- Callbacks arrive before their merchant is known; the merchant is recorded once the
+-- callback is matched to a payment, and unmatched callbacks belong to no merchant.
+ALTER TABLE provider_callbacks
+    ADD COLUMN IF NOT EXISTS merchant_id UUID REFERENCES merchants(id);
+UPDATE provider_callbacks c SET merchant_id = p.merchant_id
+FROM payments p
+WHERE c.payment_id = p.id AND c.merchant_id IS NULL;
+
+-- Create index on provider_callbacks.merchant_id if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_provider_callbacks_merchant_id') THEN
+CREATE INDEX idx_provider_callbacks_merchant_id ON provider_callbacks(merchant_id, received_at);
+END IF;
+END$$;
+
+-- Tenant transactions only see the callbacks of their merchant. Callbacks are received
+-- without a merchant set, so that path can store and deduplicate every callback.
+ALTER TABLE provider_callbacks ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS provider_callbacks_tenant_isolation ON provider_callbacks;
+CREATE POLICY provider_callbacks_tenant_isolation ON provider_callbacks
+    USING (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid)
+    WITH CHECK (merchant_id = NULLIF(current_setting('app.merchant_id', true), '')::uuid);
+DROP POLICY IF EXISTS provider_callbacks_ingest ON provider_callbacks;
+CREATE POLICY provider_callbacks_ingest ON provider_callbacks
+    USING (NULLIF(current_setting('app.merchant_id', true), '') IS NULL)
+    WITH CHECK (NULLIF(current_setting('app.merchant_id', true), '') IS NULL);
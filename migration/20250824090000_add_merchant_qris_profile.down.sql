@@ -0,0 +1,12 @@
+ALTER TABLE merchants DROP CONSTRAINT IF EXISTS chk_merchants_qris_profile;
+
+ALTER TABLE merchants
+    DROP COLUMN IF EXISTS qris_merchant_id,
+    DROP COLUMN IF EXISTS qris_merchant_pan,
+    DROP COLUMN IF EXISTS qris_acquirer_guid,
+    DROP COLUMN IF EXISTS qris_merchant_criteria,
+    DROP COLUMN IF EXISTS qris_nmid,
+    DROP COLUMN IF EXISTS qris_merchant_category_code,
+    DROP COLUMN IF EXISTS qris_postal_code,
+    DROP COLUMN IF EXISTS qris_merchant_city,
+    DROP COLUMN IF EXISTS qris_merchant_name;
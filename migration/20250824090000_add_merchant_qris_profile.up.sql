@@ -0,0 +1,23 @@
+-- The identity each merchant prints into its QRIS payloads. A merchant without a name,
+-- city and category code has no profile and is refused QR codes. Set one with e.g.
+--   UPDATE merchants SET qris_merchant_name = 'Warung Sayur', qris_merchant_city = 'Demak',
+--       qris_merchant_category_code = '5812', qris_nmid = 'ID1020017611473' WHERE id = '...';
+ALTER TABLE merchants
+    ADD COLUMN IF NOT EXISTS qris_merchant_name TEXT,
+    ADD COLUMN IF NOT EXISTS qris_merchant_city TEXT,
+    ADD COLUMN IF NOT EXISTS qris_postal_code TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS qris_merchant_category_code TEXT,
+    ADD COLUMN IF NOT EXISTS qris_nmid TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS qris_merchant_criteria TEXT NOT NULL DEFAULT 'UMI',
+    ADD COLUMN IF NOT EXISTS qris_acquirer_guid TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS qris_merchant_pan TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS qris_merchant_id TEXT NOT NULL DEFAULT '';
+
+-- A profile is complete or absent, within the EMVCo field limits
+ALTER TABLE merchants
+    ADD CONSTRAINT chk_merchants_qris_profile CHECK (
+        (qris_merchant_name IS NULL AND qris_merchant_city IS NULL AND qris_merchant_category_code IS NULL)
+        OR (char_length(qris_merchant_name) BETWEEN 1 AND 25
+            AND char_length(qris_merchant_city) BETWEEN 1 AND 15
+            AND qris_merchant_category_code ~ '^[0-9]{4}$')
+    );
@@ -0,0 +1,2 @@
+-- The removed scopes are not restored: which keys held them is not recorded
+SELECT 1;
@@ -0,0 +1,4 @@
+-- fx_rates:write and fees:write let any merchant change data every merchant shares; those
+-- routes now require the operator scope, which merchant keys are never granted
+UPDATE api_keys SET scopes = array_remove(array_remove(scopes, 'fx_rates:write'), 'fees:write')
+WHERE scopes && ARRAY['fx_rates:write', 'fees:write'];
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_payment_reversals_unsettled;
+ALTER TABLE payment_reversals
+    DROP COLUMN IF EXISTS settlement_batch_id;
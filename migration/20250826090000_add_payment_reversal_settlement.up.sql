@@ -0,0 +1,12 @@
+-- settlement_batch_id is the batch that deducted the reversal from a payout. Reversals of
+-- payments that were already paid out are deducted once, by the next batch.
+ALTER TABLE payment_reversals
+    ADD COLUMN IF NOT EXISTS settlement_batch_id UUID REFERENCES settlement_batches(id) ON DELETE RESTRICT;
+
+-- Create index on unsettled payment reversals if not exists
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_payment_reversals_unsettled') THEN
+CREATE INDEX idx_payment_reversals_unsettled ON payment_reversals(payment_id) WHERE settlement_batch_id IS NULL;
+END IF;
+END$$;
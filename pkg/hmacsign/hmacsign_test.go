@@ -0,0 +1,109 @@
+package hmacsign
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// The expected values were computed independently of this package, with Python's hashlib
+// and hmac modules.
+func TestKnownAnswer(t *testing.T) {
+	body := []byte(`{"amount":"15000"}`)
+	digest := BodyDigest(body)
+	if want := "0dd3bebeced9fa940234814a2e891bb926c1480952ab6dcf9e611db440cb9206"; digest != want {
+		t.Fatalf("BodyDigest = %s, want %s", digest, want)
+	}
+
+	sts := StringToSign("post", "/api/v1/payments", "b=2&a=3&a=1", digest, 1724112000, "n0nce")
+	wantSTS := "HMAC-SHA256\nPOST\n/api/v1/payments\na=1&a=3&b=2\n" + digest + "\n1724112000\nn0nce"
+	if sts != wantSTS {
+		t.Fatalf("StringToSign = %q, want %q", sts, wantSTS)
+	}
+	if got, want := Signature("test-signing-secret", sts), "3UGg2gepUzNcHWakIXUmdThySSGHMuoSDfQZg1YR7l8="; got != want {
+		t.Errorf("Signature = %s, want %s", got, want)
+	}
+}
+
+func TestBodyDigestOfEmptyBody(t *testing.T) {
+	if got, want := BodyDigest(nil), "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"; got != want {
+		t.Errorf("BodyDigest(nil) = %s, want %s", got, want)
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"", ""},
+		{"a=1", "a=1"},
+		{"b=2&a=1", "a=1&b=2"},
+		{"a=3&b=2&a=1", "a=1&a=3&b=2"},
+		{"q=hello%20world&p=a%2Bb", "p=a%2Bb&q=hello+world"},
+		{"empty=&a=1", "a=1&empty="},
+	}
+	for _, tt := range tests {
+		if got := CanonicalQuery(tt.raw); got != tt.want {
+			t.Errorf("CanonicalQuery(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestParseAuthorization(t *testing.T) {
+	want := Params{KeyID: "key_1", Timestamp: 1724112000, Nonce: "n0nce", Signature: "3UGg2gepUzNcHWakIXUmdThySSGHMuoSDfQZg1YR7l8="}
+	got, err := ParseAuthorization(want.String())
+	if err != nil {
+		t.Fatalf("ParseAuthorization(%q): %v", want.String(), err)
+	}
+	if got != want {
+		t.Errorf("ParseAuthorization round trip = %+v, want %+v", got, want)
+	}
+
+	// Fields may come in any order with spaces after the commas
+	got, err = ParseAuthorization("HMAC-SHA256 nonce=n0nce, signature=3UGg2gepUzNcHWakIXUmdThySSGHMuoSDfQZg1YR7l8=, keyId=key_1, timestamp=1724112000")
+	if err != nil || got != want {
+		t.Errorf("reordered fields: got %+v, %v", got, err)
+	}
+
+	for _, value := range []string{
+		"",
+		"Bearer abc",
+		"HMAC-SHA256",
+		"HMAC-SHA256 keyId=key_1,timestamp=1724112000,nonce=n0nce",
+		"HMAC-SHA256 keyId=key_1,timestamp=soon,nonce=n0nce,signature=abc",
+		"HMAC-SHA256 keyId=key_1,timestamp=1724112000,nonce=,signature=abc",
+		"HMAC-SHA256 keyId=key_1,keyId=key_2,timestamp=1724112000,nonce=n0nce,signature=abc",
+		"HMAC-SHA256 keyId=key_1,timestamp=1724112000,nonce=n0nce,signature=abc,extra=1",
+		"HMAC-SHA256 keyId=key_1,timestamp=1724112000,nonce,signature=abc",
+	} {
+		if _, err := ParseAuthorization(value); !errors.Is(err, ErrMalformed) {
+			t.Errorf("ParseAuthorization(%q): got %v, want ErrMalformed", value, err)
+		}
+	}
+}
+
+func TestSignRequestKeepsBodyAndVerifies(t *testing.T) {
+	body := `{"amount":"15000"}`
+	req, err := http.NewRequest(http.MethodPost, "https://api.example.com/api/v1/payments?b=2&a=1", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignRequest(req, "key_1", "test-signing-secret"); err != nil {
+		t.Fatalf("SignRequest: %v", err)
+	}
+
+	sent, err := io.ReadAll(req.Body)
+	if err != nil || string(sent) != body {
+		t.Fatalf("body after signing = %q, %v; want %q", sent, err, body)
+	}
+	p, err := ParseAuthorization(req.Header.Get("Authorization"))
+	if err != nil {
+		t.Fatalf("ParseAuthorization: %v", err)
+	}
+	want := Signature("test-signing-secret", StringToSign(http.MethodPost, "/api/v1/payments", "a=1&b=2", BodyDigest([]byte(body)), p.Timestamp, p.Nonce))
+	if p.KeyID != "key_1" || p.Signature != want {
+		t.Errorf("signed %+v, want key_1 with signature %s", p, want)
+	}
+}